@@ -0,0 +1,25 @@
+// Command openapi writes the OpenAPI document of the handler annotations to
+// internal/openapi/openapi.json, run it from the repository root.
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/api"
+)
+
+func main() {
+	doc, err := api.GenerateOpenAPI(".")
+	if err != nil {
+		log.Fatal(err)
+	}
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("internal/openapi/openapi.json", append(out, '\n'), 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rstoltzm-profile/video-rental-api/internal/customer"
+	"github.com/rstoltzm-profile/video-rental-api/internal/db"
+	"github.com/rstoltzm-profile/video-rental-api/internal/etag"
+	"github.com/rstoltzm-profile/video-rental-api/internal/inventory"
+	"github.com/rstoltzm-profile/video-rental-api/internal/pagination"
+	"github.com/rstoltzm-profile/video-rental-api/internal/rental"
+	"github.com/rstoltzm-profile/video-rental-api/internal/report"
+	"github.com/rstoltzm-profile/video-rental-api/internal/reservation"
+	"github.com/rstoltzm-profile/video-rental-api/internal/staff"
+	"github.com/rstoltzm-profile/video-rental-api/internal/webhook"
+	"github.com/rstoltzm-profile/video-rental-api/pkg/client"
+)
+
+// backend is what the customers, rentals, inventory and reports commands
+// need. *client.Client serves it over the API, database over the services
+// the API runs on.
+type backend interface {
+	ListCustomers(ctx context.Context, filter client.CustomerFilter) ([]client.Customer, int, error)
+	GetCustomer(ctx context.Context, id int) (client.Customer, error)
+	GetCustomerSummary(ctx context.Context, id int) (client.CustomerSummary, error)
+	ListCustomerRentals(ctx context.Context, id int, filter client.RentalHistoryFilter) ([]client.CustomerRental, int, error)
+	DeleteCustomer(ctx context.Context, id int, lastUpdate time.Time) error
+	ReactivateCustomer(ctx context.Context, id int, lastUpdate time.Time) (client.Customer, error)
+
+	ListRentals(ctx context.Context, late bool) ([]client.Rental, error)
+	CreateRental(ctx context.Context, req client.CreateRentalRequest) (int, error)
+	ReturnRental(ctx context.Context, id int) error
+
+	ListInventory(ctx context.Context, storeID *int) ([]client.Inventory, error)
+	GetInventoryAvailable(ctx context.Context, storeID, filmID int) (client.InventoryAvailability, error)
+	GetFilmAvailability(ctx context.Context, id int) (client.FilmAvailability, error)
+
+	GetRevenue(ctx context.Context, filter client.ReportFilter) ([]client.StoreRevenue, error)
+	GetTopFilms(ctx context.Context, filter client.ReportFilter) ([]client.FilmRank, error)
+	GetTopCustomers(ctx context.Context, filter client.ReportFilter) ([]client.CustomerRank, error)
+	GetCategoryPopularity(ctx context.Context, filter client.ReportFilter) ([]client.CategoryPopularity, error)
+	GetLateReturnRates(ctx context.Context, filter client.ReportFilter) ([]client.LateReturnRate, error)
+	ListCopyUtilization(ctx context.Context, filter client.ReportFilter) ([]client.CopyUtilization, int, error)
+}
+
+var _ backend = (*client.Client)(nil)
+
+// database runs commands on the services directly, without the API's
+// validation, for when the API is down or a fix has to skip it
+type database struct {
+	customers customer.Service
+	rentals   rental.Service
+	inventory inventory.Service
+	reports   report.Service
+}
+
+// holdDuration is how long a returned copy is held for the next reservation
+func newDatabase(pool *pgxpool.Pool, holdDuration time.Duration) *database {
+	customerRepo := customer.NewRepository(pool)
+	inventoryRepo := inventory.NewRepository(pool)
+	rentalRepo := rental.NewRepository(pool)
+	reservationRepo := reservation.NewRepository(pool)
+	staffRepo := staff.NewRepository(pool)
+	webhookRepo := webhook.NewRepository(pool)
+
+	events := webhook.NewService(webhookRepo, webhookRepo)
+	holds := reservation.NewService(reservationRepo, reservationRepo, reservationRepo, holdDuration)
+	return &database{
+		customers: customer.NewService(customerRepo, customerRepo, customerRepo),
+		rentals:   rental.NewService(rentalRepo, rentalRepo, rentalRepo, holds, staff.NewService(staffRepo, staffRepo, staffRepo), events),
+		inventory: inventory.NewService(inventoryRepo, inventoryRepo),
+		reports:   report.NewService(report.NewRepository(pool)),
+	}
+}
+
+// page applies the API's default page size
+func page(p pagination.Page) pagination.Page {
+	if p.Limit == 0 {
+		p.Limit = pagination.DefaultLimit
+	}
+	return p
+}
+
+// top applies the API's default number of ranked rows
+func top(filter report.ReportFilter) report.ReportFilter {
+	if filter.Top == 0 {
+		filter.Top = report.DefaultTop
+	}
+	return filter
+}
+
+func (d *database) ListCustomers(ctx context.Context, filter customer.CustomerFilter) ([]customer.Customer, int, error) {
+	filter.Page = page(filter.Page)
+	seq, total, err := d.customers.GetCustomers(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	customers, err := db.Collect(seq)
+	return customers, total, err
+}
+
+func (d *database) GetCustomer(ctx context.Context, id int) (customer.Customer, error) {
+	return d.customers.GetCustomerByID(ctx, id)
+}
+
+func (d *database) GetCustomerSummary(ctx context.Context, id int) (customer.CustomerSummary, error) {
+	return d.customers.GetCustomerSummaryByID(ctx, id)
+}
+
+func (d *database) ListCustomerRentals(ctx context.Context, id int, filter customer.RentalHistoryFilter) ([]customer.CustomerRentals, int, error) {
+	filter.Page = page(filter.Page)
+	return d.customers.GetCustomerRentalsByID(ctx, id, filter)
+}
+
+func (d *database) DeleteCustomer(ctx context.Context, id int, lastUpdate time.Time) error {
+	return d.customers.DeleteCustomerByID(ctx, id, etag.Match(lastUpdate))
+}
+
+func (d *database) ReactivateCustomer(ctx context.Context, id int, lastUpdate time.Time) (customer.Customer, error) {
+	return d.customers.ReactivateCustomerByID(ctx, id, etag.Match(lastUpdate))
+}
+
+func (d *database) ListRentals(ctx context.Context, late bool) ([]rental.Rental, error) {
+	return db.Collect(d.rentals.EachRental(ctx, late))
+}
+
+func (d *database) CreateRental(ctx context.Context, req rental.CreateRentalRequest) (int, error) {
+	return d.rentals.CreateRental(ctx, req)
+}
+
+func (d *database) ReturnRental(ctx context.Context, id int) error {
+	return d.rentals.ReturnRentalByID(ctx, id)
+}
+
+func (d *database) ListInventory(ctx context.Context, storeID *int) ([]inventory.Inventory, error) {
+	return db.Collect(d.inventory.EachInventory(ctx, storeID))
+}
+
+func (d *database) GetInventoryAvailable(ctx context.Context, storeID, filmID int) (inventory.InventoryAvailability, error) {
+	return d.inventory.GetInventoryAvailable(ctx, storeID, filmID)
+}
+
+func (d *database) GetFilmAvailability(ctx context.Context, id int) (inventory.FilmAvailability, error) {
+	return d.inventory.GetFilmAvailability(ctx, id)
+}
+
+func (d *database) GetRevenue(ctx context.Context, filter report.ReportFilter) ([]report.StoreRevenue, error) {
+	return db.Collect(d.reports.EachStoreRevenue(ctx, filter))
+}
+
+func (d *database) GetTopFilms(ctx context.Context, filter report.ReportFilter) ([]report.FilmRank, error) {
+	return db.Collect(d.reports.EachTopFilm(ctx, top(filter)))
+}
+
+func (d *database) GetTopCustomers(ctx context.Context, filter report.ReportFilter) ([]report.CustomerRank, error) {
+	return db.Collect(d.reports.EachTopCustomer(ctx, top(filter)))
+}
+
+func (d *database) GetCategoryPopularity(ctx context.Context, filter report.ReportFilter) ([]report.CategoryPopularity, error) {
+	return db.Collect(d.reports.EachCategory(ctx, filter))
+}
+
+func (d *database) GetLateReturnRates(ctx context.Context, filter report.ReportFilter) ([]report.LateReturnRate, error) {
+	return db.Collect(d.reports.EachLateReturnRate(ctx, filter))
+}
+
+func (d *database) ListCopyUtilization(ctx context.Context, filter report.ReportFilter) ([]report.CopyUtilization, int, error) {
+	filter.Page = page(filter.Page)
+	total, err := d.reports.CountCopies(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	rows, err := db.Collect(d.reports.EachCopyUtilization(ctx, filter))
+	return rows, total, err
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/daterange"
+	"github.com/rstoltzm-profile/video-rental-api/pkg/client"
+)
+
+var customerCommands = group{
+	name:  "customers",
+	about: "find, inspect, deactivate and reactivate customers",
+	commands: []command{
+		{name: "list", args: "[-q text] [-store id] [-active true|false|all] [-limit n] [-offset n]", about: "list or search customers", run: listCustomers},
+		{name: "get", args: "<id>", about: "show a customer", run: getCustomer},
+		{name: "summary", args: "<id>", about: "show a customer's rentals, spend and balance", run: customerSummary},
+		{name: "rentals", args: "[-status open|returned|late|all] [-from date] [-to date] [-limit n] [-offset n] <id>", about: "list a customer's rental history", run: customerRentals},
+		{name: "deactivate", args: "<id>", about: "deactivate a customer", run: deactivateCustomer},
+		{name: "reactivate", args: "<id>", about: "reactivate a customer", run: reactivateCustomer},
+	},
+}
+
+// pageFlags adds -limit and -offset
+func pageFlags(fs *flag.FlagSet, page *client.Page) {
+	fs.IntVar(&page.Limit, "limit", 0, "page size, the API's default when 0")
+	fs.IntVar(&page.Offset, "offset", 0, "rows to skip")
+}
+
+func listCustomers(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	var filter client.CustomerFilter
+	var store optionalInt
+	fs.StringVar(&filter.Query, "q", "", "name, email, phone or address to search for")
+	fs.Var(&store, "store", "only customers of this store")
+	active := fs.String("active", "true", "true, false or all")
+	pageFlags(fs, &filter.Page)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	filter.StoreID = store.v
+	switch *active {
+	case "true", "false":
+		v := *active == "true"
+		filter.Active = &v
+	case "all":
+	default:
+		return fmt.Errorf("-active must be true, false or all")
+	}
+
+	b, err := e.backend()
+	if err != nil {
+		return err
+	}
+	customers, _, err := b.ListCustomers(ctx, filter)
+	if err != nil {
+		return err
+	}
+	return e.print(customers)
+}
+
+func getCustomer(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	id, err := argID(fs)
+	if err != nil {
+		return err
+	}
+
+	b, err := e.backend()
+	if err != nil {
+		return err
+	}
+	c, err := b.GetCustomer(ctx, id)
+	if err != nil {
+		return err
+	}
+	return e.print(c)
+}
+
+func customerSummary(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	id, err := argID(fs)
+	if err != nil {
+		return err
+	}
+
+	b, err := e.backend()
+	if err != nil {
+		return err
+	}
+	summary, err := b.GetCustomerSummary(ctx, id)
+	if err != nil {
+		return err
+	}
+	return e.print(summary)
+}
+
+func customerRentals(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	var filter client.RentalHistoryFilter
+	fs.StringVar(&filter.Status, "status", "", "open (default), returned, late or all")
+	from := fs.String("from", "", "rented on or after, YYYY-MM-DD or RFC3339")
+	to := fs.String("to", "", "rented on or before, YYYY-MM-DD or RFC3339")
+	pageFlags(fs, &filter.Page)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	id, err := argID(fs)
+	if err != nil {
+		return err
+	}
+	if filter.From, err = daterange.ParseDate(*from, false); err != nil {
+		return fmt.Errorf("invalid -from: %w", err)
+	}
+	if filter.To, err = daterange.ParseDate(*to, true); err != nil {
+		return fmt.Errorf("invalid -to: %w", err)
+	}
+
+	b, err := e.backend()
+	if err != nil {
+		return err
+	}
+	rentals, _, err := b.ListCustomerRentals(ctx, id, filter)
+	if err != nil {
+		return err
+	}
+	return e.print(rentals)
+}
+
+// deactivateCustomer and reactivateCustomer write the version they just
+// read, a change made in between fails the write
+func deactivateCustomer(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	id, err := argID(fs)
+	if err != nil {
+		return err
+	}
+
+	b, err := e.backend()
+	if err != nil {
+		return err
+	}
+	c, err := b.GetCustomer(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := b.DeleteCustomer(ctx, id, c.LastUpdate); err != nil {
+		return err
+	}
+	return e.print(result{ID: id, Status: "deactivated"})
+}
+
+func reactivateCustomer(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	id, err := argID(fs)
+	if err != nil {
+		return err
+	}
+
+	b, err := e.backend()
+	if err != nil {
+		return err
+	}
+	c, err := b.GetCustomer(ctx, id)
+	if err != nil {
+		return err
+	}
+	if c, err = b.ReactivateCustomer(ctx, id, c.LastUpdate); err != nil {
+		return err
+	}
+	return e.print(c)
+}
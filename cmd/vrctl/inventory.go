@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+)
+
+var inventoryCommands = group{
+	name:  "inventory",
+	about: "list copies and check availability",
+	commands: []command{
+		{name: "list", args: "[-store id]", about: "list copies", run: listInventory},
+		{name: "available", args: "-store id -film id", about: "show the copies of a film a store can rent", run: inventoryAvailable},
+		{name: "film", args: "<film id>", about: "show a film's availability at every store", run: filmAvailability},
+	},
+}
+
+func listInventory(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	var store optionalInt
+	fs.Var(&store, "store", "only copies at this store")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	b, err := e.backend()
+	if err != nil {
+		return err
+	}
+	inventory, err := b.ListInventory(ctx, store.v)
+	if err != nil {
+		return err
+	}
+	return e.print(inventory)
+}
+
+func inventoryAvailable(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	storeID := fs.Int("store", 0, "store")
+	filmID := fs.Int("film", 0, "film")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *storeID < 1 || *filmID < 1 || fs.NArg() > 0 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	b, err := e.backend()
+	if err != nil {
+		return err
+	}
+	availability, err := b.GetInventoryAvailable(ctx, *storeID, *filmID)
+	if err != nil {
+		return err
+	}
+	return e.print(availability)
+}
+
+func filmAvailability(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	id, err := argID(fs)
+	if err != nil {
+		return err
+	}
+
+	b, err := e.backend()
+	if err != nil {
+		return err
+	}
+	availability, err := b.GetFilmAvailability(ctx, id)
+	if err != nil {
+		return err
+	}
+	if e.format == formatTable {
+		return e.print(availability.Stores) // a row per store, the totals are their sums
+	}
+	return e.print(availability)
+}
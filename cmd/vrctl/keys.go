@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/apikey"
+)
+
+var keyCommands = group{
+	name:  "keys",
+	about: "issue and revoke API keys, on the database",
+	commands: []command{
+		{name: "create", args: "<name>", about: "issue a key, it is shown once", run: createKey},
+		{name: "list", about: "list issued keys", run: listKeys},
+		{name: "revoke", args: "<id>", about: "revoke a key", run: revokeKey},
+	},
+}
+
+func keys(e *env) (apikey.Service, error) {
+	pool, err := e.database()
+	if err != nil {
+		return nil, err
+	}
+	return apikey.NewService(apikey.NewRepository(pool)), nil
+}
+
+func createKey(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	svc, err := keys(e)
+	if err != nil {
+		return err
+	}
+	k, err := svc.CreateKey(ctx, strings.Join(fs.Args(), " "))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(e.errOut, "Store the key now, only its hash is kept.")
+	return e.print(k)
+}
+
+func listKeys(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	svc, err := keys(e)
+	if err != nil {
+		return err
+	}
+	list, err := svc.GetKeys(ctx)
+	if err != nil {
+		return err
+	}
+	return e.print(list)
+}
+
+func revokeKey(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	id, err := argID(fs)
+	if err != nil {
+		return err
+	}
+
+	svc, err := keys(e)
+	if err != nil {
+		return err
+	}
+	k, err := svc.RevokeKey(ctx, id)
+	if err != nil {
+		return err
+	}
+	return e.print(k)
+}
@@ -0,0 +1,258 @@
+// Command vrctl runs store operations from the command line: customers,
+// rentals, inventory and reports over the API or straight on the database,
+// and API keys, staff passwords, migrations and payment partitions on the
+// database.
+//
+//	vrctl [flags] <group> <command> [command flags] [args]
+//	vrctl customers get 1
+//	vrctl -db -o json rentals return 42
+//	vrctl migrations add-partitions -from 2026-11 -months 3
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rstoltzm-profile/video-rental-api/internal/config"
+	"github.com/rstoltzm-profile/video-rental-api/internal/db"
+	"github.com/rstoltzm-profile/video-rental-api/pkg/client"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "vrctl:", err)
+		}
+		stop()
+		os.Exit(1)
+	}
+}
+
+// group is a set of commands on one resource, vrctl <group> <command>
+type group struct {
+	name     string
+	about    string
+	commands []command
+}
+
+type command struct {
+	name  string
+	args  string // the flags and arguments, for usage
+	about string
+	run   func(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error
+}
+
+func groups() []group {
+	return []group{
+		customerCommands,
+		rentalCommands,
+		inventoryCommands,
+		reportCommands,
+		keyCommands,
+		staffCommands,
+		migrationCommands,
+	}
+}
+
+// env is what commands share: the input and output, and the API or database
+// they talk to, connected on first use
+type env struct {
+	in     io.Reader
+	out    io.Writer
+	errOut io.Writer
+	format string
+	apiURL string
+	apiKey string
+	direct bool
+	cfg    config.Config
+	pool   *pgxpool.Pool
+}
+
+func (e *env) print(v any) error {
+	return render(e.out, e.format, v)
+}
+
+// database connects to DATABASE_URL
+func (e *env) database() (*pgxpool.Pool, error) {
+	if e.pool != nil {
+		return e.pool, nil
+	}
+	if e.cfg.DatabaseURL == "" {
+		return nil, errors.New("DATABASE_URL is not set")
+	}
+	pool, err := db.ConnectPool(e.cfg.DatabaseURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.HealthCheck(pool); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("database health check failed: %w", err)
+	}
+	e.pool = pool
+	return pool, nil
+}
+
+// backend is the API, or the database with -db
+func (e *env) backend() (backend, error) {
+	if !e.direct {
+		return client.New(e.apiURL, client.WithAPIKey(e.apiKey)), nil
+	}
+	pool, err := e.database()
+	if err != nil {
+		return nil, err
+	}
+	return newDatabase(pool, e.cfg.HoldDuration), nil
+}
+
+// flags is the flag set a command adds its flags to, its errors and -h go to
+// errOut
+func (e *env) flags(g group, c command) *flag.FlagSet {
+	fs := flag.NewFlagSet(g.name+" "+c.name, flag.ContinueOnError)
+	fs.SetOutput(e.errOut)
+	fs.Usage = func() {
+		fmt.Fprintf(e.errOut, "usage: vrctl %s %s %s\n\n%s\n", g.name, c.name, c.args, c.about)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	e := &env{in: os.Stdin, out: stdout, errOut: stderr, cfg: config.LoadConfig()}
+
+	fs := flag.NewFlagSet("vrctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&e.apiURL, "api", getEnvOrDefault("VRCTL_API_URL", "http://localhost:8080"), "API base URL, $VRCTL_API_URL")
+	fs.StringVar(&e.apiKey, "key", e.cfg.APIKey, "API key, $API_KEY")
+	fs.BoolVar(&e.direct, "db", false, "talk to the database at $DATABASE_URL instead of the API")
+	fs.StringVar(&e.format, "o", formatTable, "output, table or json")
+	fs.Usage = func() { usage(stderr, fs) }
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if e.format != formatTable && e.format != formatJSON {
+		return fmt.Errorf("-o must be %s or %s", formatTable, formatJSON)
+	}
+
+	args = fs.Args()
+	if len(args) == 0 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+	g, ok := findGroup(args[0])
+	if !ok {
+		return fmt.Errorf("unknown group %q, run vrctl -h for the list", args[0])
+	}
+	if len(args) == 1 {
+		groupUsage(stderr, g)
+		return flag.ErrHelp
+	}
+	c, ok := findCommand(g, args[1])
+	if !ok {
+		return fmt.Errorf("unknown command %q, run vrctl %s for the list", args[1], g.name)
+	}
+
+	defer func() {
+		if e.pool != nil {
+			e.pool.Close()
+		}
+	}()
+	return c.run(ctx, e, e.flags(g, c), args[2:])
+}
+
+func findGroup(name string) (group, bool) {
+	for _, g := range groups() {
+		if g.name == name {
+			return g, true
+		}
+	}
+	return group{}, false
+}
+
+func findCommand(g group, name string) (command, bool) {
+	for _, c := range g.commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+func usage(w io.Writer, fs *flag.FlagSet) {
+	fmt.Fprintln(w, "usage: vrctl [flags] <group> <command> [command flags] [args]")
+	fmt.Fprintln(w)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, g := range groups() {
+		fmt.Fprintf(tw, "  %s\t%s\n", g.name, g.about)
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+	fs.PrintDefaults()
+}
+
+func groupUsage(w io.Writer, g group) {
+	fmt.Fprintf(w, "usage: vrctl %s <command>\n\n", g.name)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, c := range g.commands {
+		fmt.Fprintf(tw, "  %s %s\t%s\n", c.name, c.args, c.about)
+	}
+	tw.Flush()
+}
+
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// argID parses the one argument a command takes, an id
+func argID(fs *flag.FlagSet) (int, error) {
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 0, flag.ErrHelp
+	}
+	n, err := strconv.Atoi(fs.Arg(0))
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid id %q", fs.Arg(0))
+	}
+	return n, nil
+}
+
+// optionalInt is an int flag that can be left unset
+type optionalInt struct {
+	v *int
+}
+
+func (o *optionalInt) String() string {
+	if o.v == nil {
+		return ""
+	}
+	return strconv.Itoa(*o.v)
+}
+
+func (o *optionalInt) Set(s string) error {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return err
+	}
+	o.v = &n
+	return nil
+}
+
+// result is the outcome of a command that changes something and has nothing
+// else to print
+type result struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rstoltzm-profile/video-rental-api/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// vrctl runs the command line against handler as the API
+func vrctl(t *testing.T, handler http.HandlerFunc, args ...string) (string, error) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	var out bytes.Buffer
+	args = append([]string{"-api", srv.URL, "-key", "secret"}, args...)
+	err := run(context.Background(), args, &out, new(bytes.Buffer))
+	return out.String(), err
+}
+
+func TestRun_Table(t *testing.T) {
+	var got *http.Request
+	out, err := vrctl(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Header().Set("X-Total-Count", "2")
+		json.NewEncoder(w).Encode([]client.Customer{
+			{ID: 1, FirstName: "MARY", LastName: "SMITH", StoreID: 1, Active: true},
+			{ID: 2, FirstName: "PATRICIA", LastName: "JOHNSON", StoreID: 1, Active: true},
+		})
+	}, "customers", "list", "-store", "1", "-limit", "2")
+
+	require.NoError(t, err)
+	assert.Equal(t, "/v1/customers", got.URL.Path)
+	assert.Equal(t, "1", got.URL.Query().Get("store_id"))
+	assert.Equal(t, "true", got.URL.Query().Get("active"))
+	assert.Equal(t, "secret", got.Header.Get("X-API-Key"))
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, []string{"ID", "FIRST_NAME", "LAST_NAME", "EMAIL", "PHONE", "STORE_ID", "ACTIVE", "LAST_UPDATE"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"2", "PATRICIA", "JOHNSON", "1", "true"}, strings.Fields(lines[2]))
+}
+
+func TestRun_JSON(t *testing.T) {
+	var got *http.Request
+	out, err := vrctl(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.WriteHeader(http.StatusNoContent)
+	}, "-o", "json", "rentals", "return", "42")
+
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPost, got.Method)
+	assert.Equal(t, "/v1/rentals/42/return", got.URL.Path)
+	assert.JSONEq(t, `{"id": 42, "status": "returned"}`, out)
+}
+
+func TestRun_DeactivateSendsTheVersionItRead(t *testing.T) {
+	lastUpdate := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+	var ifMatch string
+	out, err := vrctl(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			json.NewEncoder(w).Encode(client.Customer{ID: 5, LastUpdate: lastUpdate})
+			return
+		}
+		ifMatch = r.Header.Get("If-Match")
+		w.WriteHeader(http.StatusNoContent)
+	}, "customers", "deactivate", "5")
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, ifMatch)
+	assert.Equal(t, []string{"ID", "5", "STATUS", "deactivated"}, strings.Fields(out))
+}
+
+func TestRun_APIError(t *testing.T) {
+	_, err := vrctl(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Customer not found", http.StatusNotFound)
+	}, "customers", "get", "999")
+
+	assert.ErrorIs(t, err, client.ErrNotFound)
+}
+
+func TestRun_Usage(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"films"}, `unknown group "films"`},
+		{[]string{"rentals", "renew"}, `unknown command "renew"`},
+		{[]string{"-o", "yaml", "rentals", "list"}, "-o must be table or json"},
+		{[]string{"customers", "get", "abc"}, `invalid id "abc"`},
+		{[]string{"migrations", "add-partitions", "-from", "2026-13"}, `invalid -from "2026-13"`},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			_, err := vrctl(t, func(w http.ResponseWriter, r *http.Request) {
+				t.Error("no request expected")
+			}, tt.args...)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	type row struct {
+		ID       int        `json:"id"`
+		Name     string     `json:"name"`
+		Tags     []string   `json:"tags"`
+		Revoked  *time.Time `json:"revoked_at"`
+		internal string
+	}
+	revoked := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+	rows := []row{{ID: 1, Name: "front desk", Tags: []string{"a", "b"}}, {ID: 2, Name: "old", Revoked: &revoked}}
+
+	var out bytes.Buffer
+	require.NoError(t, render(&out, formatTable, rows))
+	assert.Equal(t, ""+
+		"ID  NAME        TAGS       REVOKED_AT\n"+
+		"1   front desk  [\"a\",\"b\"]  \n"+
+		"2   old                    2026-10-19 09:30:00\n", out.String())
+
+	out.Reset()
+	require.NoError(t, render(&out, formatTable, rows[1]))
+	assert.Equal(t, ""+
+		"ID          2\n"+
+		"NAME        old\n"+
+		"TAGS        \n"+
+		"REVOKED_AT  2026-10-19 09:30:00\n", out.String())
+
+	out.Reset()
+	require.NoError(t, render(&out, formatJSON, rows[:1]))
+	assert.JSONEq(t, `[{"id": 1, "name": "front desk", "tags": ["a", "b"], "revoked_at": null}]`, out.String())
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/db"
+)
+
+var migrationCommands = group{
+	name:  "migrations",
+	about: "schema migrations and payment partitions, on the database",
+	commands: []command{
+		{name: "status", about: "list applied migrations", run: migrationStatus},
+		{name: "up", about: "apply pending migrations, as the server does on start", run: migrateUp},
+		{name: "partitions", about: "list the partitions of the payment table", run: listPartitions},
+		{name: "add-partitions", args: "[-from YYYY-MM] [-months n]", about: "create monthly payment partitions", run: addPartitions},
+	},
+}
+
+func migrationStatus(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	pool, err := e.database()
+	if err != nil {
+		return err
+	}
+
+	migrations, err := db.ListMigrations(pool)
+	if err != nil {
+		return err
+	}
+	return e.print(migrations)
+}
+
+func migrateUp(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	pool, err := e.database()
+	if err != nil {
+		return err
+	}
+
+	if err := db.RunMigrations(pool); err != nil {
+		return fmt.Errorf("migration failed: %w", err)
+	}
+	migrations, err := db.ListMigrations(pool)
+	if err != nil {
+		return err
+	}
+	return e.print(migrations)
+}
+
+func listPartitions(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	pool, err := e.database()
+	if err != nil {
+		return err
+	}
+
+	partitions, err := db.ListPaymentPartitions(pool)
+	if err != nil {
+		return err
+	}
+	return e.print(partitions)
+}
+
+func addPartitions(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	from := fs.String("from", time.Now().UTC().Format("2006-01"), "first month, YYYY-MM")
+	months := fs.Int("months", 3, "months to cover")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	start, err := time.Parse("2006-01", *from)
+	if err != nil {
+		return fmt.Errorf("invalid -from %q, want YYYY-MM", *from)
+	}
+	if *months < 1 {
+		return fmt.Errorf("-months must be 1 or more")
+	}
+	pool, err := e.database()
+	if err != nil {
+		return err
+	}
+
+	partitions, err := db.CreatePaymentPartitions(pool, start, *months)
+	if err != nil {
+		return err
+	}
+	return e.print(partitions)
+}
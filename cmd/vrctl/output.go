@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+// render writes v as indented JSON, or as a table: a slice of structs is one
+// row per element under its JSON field names, a struct one row per field.
+func render(w io.Writer, format string, v any) error {
+	if format == formatJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	switch {
+	case rv.Kind() == reflect.Slice && isStruct(rv.Type().Elem()):
+		fields := columns(rv.Type().Elem())
+		names := make([]string, len(fields))
+		for i, f := range fields {
+			names[i] = strings.ToUpper(f.name)
+		}
+		fmt.Fprintln(tw, strings.Join(names, "\t"))
+		for i := range rv.Len() {
+			row := reflect.Indirect(rv.Index(i))
+			cells := make([]string, len(fields))
+			for j, f := range fields {
+				cells[j] = cell(row.FieldByIndex(f.index))
+			}
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+	case rv.Kind() == reflect.Struct && !isTime(rv.Type()):
+		for _, f := range columns(rv.Type()) {
+			fmt.Fprintf(tw, "%s\t%s\n", strings.ToUpper(f.name), cell(rv.FieldByIndex(f.index)))
+		}
+	default:
+		fmt.Fprintln(tw, cell(rv))
+	}
+	return tw.Flush()
+}
+
+type column struct {
+	name  string
+	index []int
+}
+
+// columns are the fields of a struct that JSON encodes, embedded structs
+// flattened like encoding/json does
+func columns(t reflect.Type) []column {
+	var cols []column
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || (f.Anonymous && isStruct(f.Type)) {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		cols = append(cols, column{name: name, index: f.Index})
+	}
+	return cols
+}
+
+func isStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && !isTime(t)
+}
+
+func isTime(t reflect.Type) bool {
+	return t == reflect.TypeFor[time.Time]()
+}
+
+// cell formats one value, nested structs, slices and maps as compact JSON
+func cell(v reflect.Value) string {
+	if !v.IsValid() {
+		return ""
+	}
+	if v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	switch {
+	case isTime(v.Type()):
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.DateTime)
+	case v.Kind() == reflect.Struct || v.Kind() == reflect.Slice || v.Kind() == reflect.Map:
+		if (v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.Len() == 0 {
+			return ""
+		}
+		b, err := json.Marshal(v.Interface())
+		if err != nil {
+			return fmt.Sprint(v.Interface())
+		}
+		return string(b)
+	}
+	return fmt.Sprint(v.Interface())
+}
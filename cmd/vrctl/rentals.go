@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/rstoltzm-profile/video-rental-api/pkg/client"
+)
+
+var rentalCommands = group{
+	name:  "rentals",
+	about: "list, create and return rentals",
+	commands: []command{
+		{name: "list", args: "[-late]", about: "list open rentals", run: listRentals},
+		{name: "create", args: "-inventory id -customer id -staff id", about: "rent a copy to a customer", run: createRental},
+		{name: "return", args: "<rental id>", about: "mark a rental returned, whatever its state", run: returnRental},
+	},
+}
+
+func listRentals(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	late := fs.Bool("late", false, "only rentals past their due date")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	b, err := e.backend()
+	if err != nil {
+		return err
+	}
+	rentals, err := b.ListRentals(ctx, *late)
+	if err != nil {
+		return err
+	}
+	return e.print(rentals)
+}
+
+func createRental(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	var req client.CreateRentalRequest
+	fs.IntVar(&req.InventoryID, "inventory", 0, "copy to rent")
+	fs.IntVar(&req.CustomerID, "customer", 0, "customer renting it")
+	fs.IntVar(&req.StaffID, "staff", 0, "staff member at the counter, of the copy's store")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if req.InventoryID < 1 || req.CustomerID < 1 || req.StaffID < 1 || fs.NArg() > 0 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	b, err := e.backend()
+	if err != nil {
+		return err
+	}
+	id, err := b.CreateRental(ctx, req)
+	if err != nil {
+		return err
+	}
+	return e.print(result{ID: id, Status: "rented"})
+}
+
+// returnRental records the return even when the rental was already returned,
+// moving its return date to now, for a copy checked in by hand
+func returnRental(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	id, err := argID(fs)
+	if err != nil {
+		return err
+	}
+
+	b, err := e.backend()
+	if err != nil {
+		return err
+	}
+	if err := b.ReturnRental(ctx, id); err != nil {
+		return err
+	}
+	return e.print(result{ID: id, Status: "returned"})
+}
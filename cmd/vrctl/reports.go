@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/daterange"
+	"github.com/rstoltzm-profile/video-rental-api/pkg/client"
+)
+
+const reportArgs = "[-from date] [-to date] [-store id]"
+
+var reportCommands = group{
+	name:  "reports",
+	about: "revenue, rankings and utilization",
+	commands: []command{
+		{name: "revenue", args: reportArgs, about: "revenue by store and month", run: revenueReport},
+		{name: "top-films", args: reportArgs + " [-top n]", about: "most rented films", run: topFilmsReport},
+		{name: "top-customers", args: reportArgs + " [-top n]", about: "customers who spent the most", run: topCustomersReport},
+		{name: "categories", args: reportArgs, about: "rentals by category", run: categoriesReport},
+		{name: "late-returns", args: reportArgs, about: "late return rate by store", run: lateReturnsReport},
+		{name: "utilization", args: reportArgs + " [-limit n] [-offset n]", about: "how often each copy is out", run: utilizationReport},
+	},
+}
+
+// reportFlags adds the date range and store flags every report takes, call
+// the returned func after parsing to fill in the filter
+func reportFlags(fs *flag.FlagSet, filter *client.ReportFilter) func() error {
+	var store optionalInt
+	from := fs.String("from", "", "start date, YYYY-MM-DD or RFC3339, inclusive")
+	to := fs.String("to", "", "end date, YYYY-MM-DD or RFC3339, inclusive")
+	fs.Var(&store, "store", "only this store")
+	return func() error {
+		var err error
+		if filter.From, err = daterange.ParseDate(*from, false); err != nil {
+			return fmt.Errorf("invalid -from: %w", err)
+		}
+		if filter.To, err = daterange.ParseDate(*to, true); err != nil {
+			return fmt.Errorf("invalid -to: %w", err)
+		}
+		filter.StoreID = store.v
+		return nil
+	}
+}
+
+// runReport parses the flags, runs get on the backend and prints its rows
+func runReport[T any](ctx context.Context, e *env, fs *flag.FlagSet, args []string, filter *client.ReportFilter, get func(backend, context.Context, client.ReportFilter) ([]T, error)) error {
+	parsed := reportFlags(fs, filter)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := parsed(); err != nil {
+		return err
+	}
+
+	b, err := e.backend()
+	if err != nil {
+		return err
+	}
+	rows, err := get(b, ctx, *filter)
+	if err != nil {
+		return err
+	}
+	return e.print(rows)
+}
+
+func revenueReport(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	return runReport(ctx, e, fs, args, &client.ReportFilter{}, backend.GetRevenue)
+}
+
+func topFilmsReport(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	var filter client.ReportFilter
+	fs.IntVar(&filter.Top, "top", 0, "rows, the API's default when 0")
+	return runReport(ctx, e, fs, args, &filter, backend.GetTopFilms)
+}
+
+func topCustomersReport(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	var filter client.ReportFilter
+	fs.IntVar(&filter.Top, "top", 0, "rows, the API's default when 0")
+	return runReport(ctx, e, fs, args, &filter, backend.GetTopCustomers)
+}
+
+func categoriesReport(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	return runReport(ctx, e, fs, args, &client.ReportFilter{}, backend.GetCategoryPopularity)
+}
+
+func lateReturnsReport(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	return runReport(ctx, e, fs, args, &client.ReportFilter{}, backend.GetLateReturnRates)
+}
+
+func utilizationReport(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	var filter client.ReportFilter
+	pageFlags(fs, &filter.Page)
+	return runReport(ctx, e, fs, args, &filter, func(b backend, ctx context.Context, filter client.ReportFilter) ([]client.CopyUtilization, error) {
+		rows, _, err := b.ListCopyUtilization(ctx, filter)
+		return rows, err
+	})
+}
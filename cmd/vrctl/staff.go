@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/staff"
+)
+
+var staffCommands = group{
+	name:  "staff",
+	about: "staff passwords, on the database",
+	commands: []command{
+		{name: "set-password", args: "<staff id>", about: "set a password read from stdin, for the first login", run: setStaffPassword},
+	},
+}
+
+// minPassword and maxPassword are the limits of staff.SetPasswordRequest,
+// bcrypt only uses the first 72 bytes
+const (
+	minPassword = 8
+	maxPassword = 72
+)
+
+func setStaffPassword(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	id, err := argID(fs)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(e.errOut, "Password:")
+	line, err := bufio.NewReader(e.in).ReadString('\n')
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" && err != nil {
+		return fmt.Errorf("read password: %w", err)
+	}
+	if len(password) < minPassword || len(password) > maxPassword {
+		return fmt.Errorf("password must be %d to %d bytes", minPassword, maxPassword)
+	}
+
+	pool, err := e.database()
+	if err != nil {
+		return err
+	}
+	repo := staff.NewRepository(pool)
+	if err := staff.NewService(repo, repo, repo).ResetPassword(ctx, id, password); err != nil {
+		return err
+	}
+	return e.print(result{ID: id, Status: "password set"})
+}
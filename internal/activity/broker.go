@@ -0,0 +1,155 @@
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+var ErrStoreNotFound = errors.New("store not found")
+
+const (
+	subscriberBuffer = 64   // events a slow client can fall behind before it is dropped
+	maxReplay        = 1000 // events resent to a client resuming with Last-Event-ID
+	reconnectDelay   = 5 * time.Second
+)
+
+type Service interface {
+	// Subscribe returns the store's events as they commit until ctx is
+	// cancelled. The channel is closed early when the subscriber falls
+	// behind or the listener reconnects, the client resumes from its last
+	// event.
+	Subscribe(ctx context.Context, storeID int) (<-chan Event, error)
+	// Replay returns the store's events after lastEventID, oldest first
+	Replay(ctx context.Context, storeID, lastEventID int) ([]Event, error)
+}
+
+// Broker is the Service for every stream in the process. One LISTEN
+// connection feeds all subscribers, an event is read once and handed to each
+// subscriber of its store. The listener starts with the first subscriber.
+type Broker struct {
+	repo  Repository
+	start sync.Once
+
+	mu   sync.Mutex
+	subs map[int]map[chan Event]struct{} // by store
+}
+
+func NewBroker(repo Repository) *Broker {
+	return &Broker{
+		repo: repo,
+		subs: map[int]map[chan Event]struct{}{},
+	}
+}
+
+func (b *Broker) Subscribe(ctx context.Context, storeID int) (<-chan Event, error) {
+	exists, err := b.repo.StoreExists(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrStoreNotFound
+	}
+	b.start.Do(func() { go b.Run(context.Background()) })
+
+	ch := make(chan Event, subscriberBuffer)
+	b.mu.Lock()
+	if b.subs[storeID] == nil {
+		b.subs[storeID] = map[chan Event]struct{}{}
+	}
+	b.subs[storeID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(storeID, ch)
+	}()
+	return ch, nil
+}
+
+func (b *Broker) Replay(ctx context.Context, storeID, lastEventID int) ([]Event, error) {
+	return b.repo.GetEventsAfter(ctx, storeID, lastEventID, maxReplay)
+}
+
+// Run listens until ctx is cancelled, reconnecting when the connection is
+// lost. Subscribers are dropped on reconnect since they may have missed
+// events while it was down.
+func (b *Broker) Run(ctx context.Context) {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			b.dropAll()
+		}
+		err := b.repo.Listen(ctx, func(payload string) { b.notify(ctx, payload) })
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Store activity listener failed: %v, reconnecting in %s", err, reconnectDelay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+// notify reads the announced event, unless nobody is watching its store
+func (b *Broker) notify(ctx context.Context, payload string) {
+	var n notification
+	if err := json.Unmarshal([]byte(payload), &n); err != nil {
+		log.Printf("Invalid store activity notification %q: %v", payload, err)
+		return
+	}
+	b.mu.Lock()
+	watched := len(b.subs[n.StoreID]) > 0
+	b.mu.Unlock()
+	if !watched {
+		return
+	}
+
+	event, err := b.repo.GetEventByID(ctx, n.EventID)
+	if err != nil {
+		log.Printf("Failed to read store activity event %d: %v", n.EventID, err)
+		return
+	}
+	b.publish(event)
+}
+
+func (b *Broker) publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs[event.StoreID] {
+		select {
+		case ch <- event:
+		default:
+			b.remove(event.StoreID, ch)
+		}
+	}
+}
+
+func (b *Broker) dropAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for storeID, subs := range b.subs {
+		for ch := range subs {
+			b.remove(storeID, ch)
+		}
+	}
+}
+
+// remove closes a subscriber's channel once, b.mu must be held
+func (b *Broker) remove(storeID int, ch chan Event) {
+	if _, ok := b.subs[storeID][ch]; !ok {
+		return
+	}
+	delete(b.subs[storeID], ch)
+	if len(b.subs[storeID]) == 0 {
+		delete(b.subs, storeID)
+	}
+	close(ch)
+}
@@ -0,0 +1,120 @@
+package activity
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepo has stores 1 and 2, and hands the payloads sent on notes to the
+// broker's listener
+type fakeRepo struct {
+	notes  chan string
+	events map[int]Event
+}
+
+func newFakeRepo(events ...Event) *fakeRepo {
+	r := &fakeRepo{notes: make(chan string), events: map[int]Event{}}
+	for _, e := range events {
+		r.events[e.ID] = e
+	}
+	return r
+}
+
+func (r *fakeRepo) StoreExists(ctx context.Context, storeID int) (bool, error) {
+	return storeID == 1 || storeID == 2, nil
+}
+
+func (r *fakeRepo) GetEventByID(ctx context.Context, id int) (Event, error) {
+	return r.events[id], nil
+}
+
+func (r *fakeRepo) GetEventsAfter(ctx context.Context, storeID, afterID, limit int) ([]Event, error) {
+	return nil, nil
+}
+
+func (r *fakeRepo) Listen(ctx context.Context, notify func(payload string)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case payload := <-r.notes:
+			notify(payload)
+		}
+	}
+}
+
+func receive(t *testing.T, ch <-chan Event) Event {
+	select {
+	case e, ok := <-ch:
+		require.True(t, ok, "channel closed")
+		return e
+	case <-time.After(time.Second):
+		t.Fatal("no event")
+		return Event{}
+	}
+}
+
+func TestBroker_DeliversToStoreSubscribers(t *testing.T) {
+	repo := newFakeRepo(
+		Event{ID: 7, Type: "rental.created", StoreID: 1},
+		Event{ID: 8, Type: "payment.created", StoreID: 2},
+	)
+	b := NewBroker(repo)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	one, err := b.Subscribe(ctx, 1)
+	require.NoError(t, err)
+	two, err := b.Subscribe(ctx, 2)
+	require.NoError(t, err)
+
+	repo.notes <- `{"store_id": 2, "event_id": 8}`
+	repo.notes <- `{"store_id": 1, "event_id": 7}`
+
+	assert.Equal(t, 7, receive(t, one).ID)
+	assert.Equal(t, 8, receive(t, two).ID)
+}
+
+func TestBroker_UnknownStore(t *testing.T) {
+	b := NewBroker(newFakeRepo())
+
+	_, err := b.Subscribe(context.Background(), 99)
+
+	assert.ErrorIs(t, err, ErrStoreNotFound)
+}
+
+func TestBroker_ClosesOnCancel(t *testing.T) {
+	b := NewBroker(newFakeRepo())
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch, err := b.Subscribe(ctx, 1)
+	require.NoError(t, err)
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("channel not closed")
+	}
+}
+
+func TestBroker_DropsSlowSubscriber(t *testing.T) {
+	b := NewBroker(newFakeRepo())
+	ch, err := b.Subscribe(context.Background(), 1)
+	require.NoError(t, err)
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		b.publish(Event{ID: i, StoreID: 1})
+	}
+
+	n := 0
+	for range ch {
+		n++
+	}
+	assert.Equal(t, subscriberBuffer, n)
+}
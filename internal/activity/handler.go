@@ -0,0 +1,128 @@
+package activity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// retryMillis is how long EventSource waits before reconnecting
+const retryMillis = 3000
+
+type Handler struct {
+	service   Service
+	heartbeat time.Duration
+}
+
+func NewHandler(service Service, heartbeat time.Duration) *Handler {
+	return &Handler{service: service, heartbeat: heartbeat}
+}
+
+// writeError maps service errors to a status code, anything unknown is a 500
+// with the fallback message.
+func writeError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, ErrStoreNotFound):
+		http.Error(w, "Store not found", http.StatusNotFound)
+	default:
+		http.Error(w, fallback, http.StatusInternalServerError)
+	}
+}
+
+// StoreEvents godoc
+// @Summary      Live store activity
+// @Description  Server-Sent Events stream of rental.created, rental.returned, rental.overdue and payment.created events at a store. Last-Event-ID resends the events after it, a comment is sent as a heartbeat when the store is quiet.
+// @Tags         stores
+// @Produce      text/event-stream
+// @Param        id             path    int  true   "Store ID"
+// @Param        Last-Event-ID  header  int  false  "Resume after this event"
+// @Success      200  {string}  string  "event stream"
+// @Failure      400  {string}  string  "Invalid store ID"
+// @Failure      404  {string}  string  "Store not found"
+// @Failure      500  {string}  string  "Failed to subscribe to store events"
+// @Security     ApiKeyAuth
+// @Router       /v1/stores/{id}/events [get]
+func (h *Handler) StoreEvents(w http.ResponseWriter, r *http.Request) {
+	storeID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid store ID", http.StatusBadRequest)
+		return
+	}
+	after, resume := 0, false
+	if v := r.Header.Get("Last-Event-ID"); v != "" {
+		after, err = strconv.Atoi(v)
+		if err != nil || after < 0 {
+			http.Error(w, "Invalid Last-Event-ID", http.StatusBadRequest)
+			return
+		}
+		resume = true
+	}
+
+	// subscribe before replaying so nothing committed in between is missed
+	events, err := h.service.Subscribe(r.Context(), storeID)
+	if err != nil {
+		writeError(w, err, "Failed to subscribe to store events")
+		return
+	}
+	var replay []Event
+	if resume {
+		replay, err = h.service.Replay(r.Context(), storeID, after)
+		if err != nil {
+			writeError(w, err, "Failed to fetch store events")
+			return
+		}
+	}
+
+	// the stream outlives the server's WriteTimeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Printf("Failed to clear write deadline: %v", err)
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", retryMillis)
+	sent := map[int]bool{}
+	for _, e := range replay {
+		writeEvent(w, e)
+		sent[e.ID] = true
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				return // dropped, the client comes back with Last-Event-ID
+			}
+			if sent[e.ID] {
+				continue
+			}
+			writeEvent(w, e)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeEvent(w io.Writer, e Event) {
+	data, _ := json.Marshal(e)
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+}
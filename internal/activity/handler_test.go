@@ -0,0 +1,109 @@
+package activity
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeService streams whatever the test sends on live
+type fakeService struct {
+	live      chan Event
+	replay    []Event
+	replayed  int
+	subscribe error
+}
+
+func (s *fakeService) Subscribe(ctx context.Context, storeID int) (<-chan Event, error) {
+	return s.live, s.subscribe
+}
+
+func (s *fakeService) Replay(ctx context.Context, storeID, lastEventID int) ([]Event, error) {
+	s.replayed = lastEventID
+	return s.replay, nil
+}
+
+func serve(t *testing.T, svc Service, heartbeat time.Duration) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /stores/{id}/events", NewHandler(svc, heartbeat).StoreEvents)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// readUntil reads the stream until a line with prefix, returning every line
+func readUntil(t *testing.T, r *bufio.Reader, prefix string) []string {
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+		if strings.HasPrefix(line, prefix) {
+			return lines
+		}
+	}
+}
+
+func TestHandler_StoreEvents(t *testing.T) {
+	svc := &fakeService{
+		live:   make(chan Event, 2),
+		replay: []Event{{ID: 5, Type: "rental.created", StoreID: 1}, {ID: 6, Type: "payment.created", StoreID: 1}},
+	}
+	server := serve(t, svc, time.Hour)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/stores/1/events", nil)
+	req.Header.Set("Last-Event-ID", "4")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Equal(t, 4, svc.replayed)
+
+	body := bufio.NewReader(resp.Body)
+	lines := readUntil(t, body, "data:")
+	assert.Contains(t, lines, "id: 5")
+	assert.Contains(t, lines, "event: rental.created")
+	assert.Contains(t, readUntil(t, body, "data:"), "id: 6")
+
+	// a replayed event that also arrives live is sent once
+	svc.live <- Event{ID: 6, Type: "payment.created", StoreID: 1}
+	svc.live <- Event{ID: 7, Type: "rental.returned", StoreID: 1}
+	lines = readUntil(t, body, "data:")
+	assert.Contains(t, lines, "id: 7")
+	assert.NotContains(t, lines, "id: 6")
+}
+
+func TestHandler_Heartbeat(t *testing.T) {
+	server := serve(t, &fakeService{live: make(chan Event)}, 10*time.Millisecond)
+
+	resp, err := http.Get(server.URL + "/stores/1/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	readUntil(t, bufio.NewReader(resp.Body), ": heartbeat")
+}
+
+func TestHandler_StoreEventsErrors(t *testing.T) {
+	server := serve(t, &fakeService{subscribe: ErrStoreNotFound}, time.Hour)
+
+	resp, err := http.Get(server.URL + "/stores/99/events")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/stores/1/events", nil)
+	req.Header.Set("Last-Event-ID", "abc")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
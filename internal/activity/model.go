@@ -0,0 +1,23 @@
+package activity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event is a rental, return or payment at a store. It is a webhook outbox
+// event tagged with its store, and its ID is the SSE event id clients resume
+// from.
+type Event struct {
+	ID        int             `json:"id"`
+	Type      string          `json:"type"`
+	StoreID   int             `json:"store_id"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// notification is the payload of a store_activity NOTIFY
+type notification struct {
+	StoreID int `json:"store_id"`
+	EventID int `json:"event_id"`
+}
@@ -0,0 +1,99 @@
+package activity
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// channel is the NOTIFY channel the webhook_event trigger announces on
+const channel = "store_activity"
+
+const eventSelect = `
+	SELECT event_id, type, store_id, created_at, payload
+	FROM webhook_event
+`
+
+type ActivityReader interface {
+	StoreExists(ctx context.Context, storeID int) (bool, error)
+	GetEventByID(ctx context.Context, id int) (Event, error)
+	GetEventsAfter(ctx context.Context, storeID, afterID, limit int) ([]Event, error)
+}
+
+// Listener calls notify with the payload of every store_activity
+// notification until ctx is cancelled or the connection fails.
+type Listener interface {
+	Listen(ctx context.Context, notify func(payload string)) error
+}
+
+type Repository interface {
+	ActivityReader
+	Listener
+}
+
+type repository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRepository(pool *pgxpool.Pool) Repository {
+	return &repository{pool: pool}
+}
+
+func (r *repository) StoreExists(ctx context.Context, storeID int) (bool, error) {
+	var exists bool
+	err := r.pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM store WHERE store_id = $1)`, storeID).Scan(&exists)
+	return exists, err
+}
+
+func scanEvent(row pgx.Row) (Event, error) {
+	var e Event
+	err := row.Scan(&e.ID, &e.Type, &e.StoreID, &e.CreatedAt, &e.Data)
+	return e, err
+}
+
+func (r *repository) GetEventByID(ctx context.Context, id int) (Event, error) {
+	return scanEvent(r.pool.QueryRow(ctx, eventSelect+` WHERE event_id = $1`, id))
+}
+
+// GetEventsAfter returns the newest limit events of the store after afterID,
+// oldest first
+func (r *repository) GetEventsAfter(ctx context.Context, storeID, afterID, limit int) ([]Event, error) {
+	query := `
+	SELECT * FROM (` + eventSelect + `
+		WHERE store_id = $1 AND event_id > $2
+		ORDER BY event_id DESC
+		LIMIT $3
+	) newest
+	ORDER BY event_id
+	`
+	rows, err := r.pool.Query(ctx, query, storeID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (Event, error) {
+		return scanEvent(row)
+	})
+}
+
+// Listen takes a connection out of the pool for good, a connection that has
+// run LISTEN shouldn't go back for other queries.
+func (r *repository) Listen(ctx context.Context, notify func(payload string)) error {
+	pooled, err := r.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+channel); err != nil {
+		return err
+	}
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		notify(n.Payload)
+	}
+}
@@ -5,9 +5,17 @@ import (
 	"net/http"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rstoltzm-profile/video-rental-api/internal/cache"
 	"github.com/rstoltzm-profile/video-rental-api/internal/db"
 )
 
+// healthHandler godoc
+// @Summary      Health check
+// @Description  Reports the server is up
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  map[string]string
+// @Router       /health [get]
 func healthHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	resp := map[string]string{
@@ -18,6 +26,14 @@ func healthHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// healthHandlerWithPool godoc
+// @Summary      Database health
+// @Description  Pings the database and reports the connection pool's counts
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  object
+// @Failure      503  {object}  object "Database unhealthy"
+// @Router       /health/pool [get]
 func healthHandlerWithPool(pool *pgxpool.Pool) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -43,6 +59,20 @@ func healthHandlerWithPool(pool *pgxpool.Pool) http.HandlerFunc {
 	}
 }
 
+// healthHandlerWithCache godoc
+// @Summary      Catalog cache statistics
+// @Description  The catalog cache's backend, hits and misses since the server started
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  cache.Stats
+// @Router       /health/cache [get]
+func healthHandlerWithCache(catalog *cache.Metered) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(catalog.Stats())
+	}
+}
+
 func landingPage(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html")
 	w.Write([]byte(`
@@ -94,6 +124,7 @@ func landingPage(w http.ResponseWriter, r *http.Request) {
 				<p>Select an option below:</p>
 				<ul>
 					<li><a href="/swagger/index.html">📜 Swagger API Docs</a></li>
+					<li><a href="/openapi.json">📘 OpenAPI Document</a></li>
 					<li><a href="/health">✅ Health Check</a></li>
 				</ul>
 			</div>
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/auth"
+	"github.com/rstoltzm-profile/video-rental-api/internal/cache"
+	"github.com/rstoltzm-profile/video-rental-api/internal/customer"
+	"github.com/rstoltzm-profile/video-rental-api/internal/film"
+	"github.com/rstoltzm-profile/video-rental-api/internal/imports"
+	"github.com/rstoltzm-profile/video-rental-api/internal/inventory"
+	"github.com/rstoltzm-profile/video-rental-api/internal/job"
+	"github.com/rstoltzm-profile/video-rental-api/internal/openapi"
+	"github.com/rstoltzm-profile/video-rental-api/internal/payment"
+	"github.com/rstoltzm-profile/video-rental-api/internal/rental"
+	"github.com/rstoltzm-profile/video-rental-api/internal/report"
+	"github.com/rstoltzm-profile/video-rental-api/internal/reservation"
+	"github.com/rstoltzm-profile/video-rental-api/internal/staff"
+	"github.com/rstoltzm-profile/video-rental-api/internal/store"
+	"github.com/rstoltzm-profile/video-rental-api/internal/webhook"
+)
+
+// openAPIModels are the types the handler annotations name
+var openAPIModels = []any{
+	auth.LoginRequest{},
+	cache.Stats{},
+	customer.Customer{},
+	customer.CustomerRentals{},
+	customer.CustomerSummary{},
+	customer.CreateCustomerRequest{},
+	customer.UpdateCustomerRequest{},
+	film.Film{},
+	film.FilmWithActorsCategories{},
+	imports.Report{},
+	inventory.Inventory{},
+	inventory.InventoryAvailability{},
+	inventory.FilmAvailability{},
+	job.Job{},
+	payment.Payment{},
+	payment.PaymentRecord{},
+	rental.Rental{},
+	rental.CreateRentalRequest{},
+	report.StoreRevenue{},
+	report.FilmRank{},
+	report.CustomerRank{},
+	report.CategoryPopularity{},
+	report.LateReturnRate{},
+	report.CopyUtilization{},
+	reservation.Reservation{},
+	reservation.CreateReservationRequest{},
+	staff.Staff{},
+	staff.CreateStaffRequest{},
+	staff.UpdateStaffRequest{},
+	staff.MoveStaffRequest{},
+	staff.SetPasswordRequest{},
+	staff.LoginRequest{},
+	staff.Session{},
+	store.Store{},
+	store.StaffMember{},
+	store.StoreInventorySummary{},
+	store.UpdateStoreRequest{},
+	store.ReassignManagerRequest{},
+	webhook.Subscription{},
+	webhook.Delivery{},
+	webhook.CreateSubscriptionRequest{},
+	webhook.UpdateSubscriptionRequest{},
+}
+
+// GenerateOpenAPI builds the OpenAPI document from the handler annotations
+// of the packages under root/internal, root being the repository.
+func GenerateOpenAPI(root string) (*openapi.Document, error) {
+	files, err := filepath.Glob(filepath.Join(root, "internal", "*", "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	var sources []string
+	for _, f := range files {
+		if !strings.HasSuffix(f, "_test.go") {
+			sources = append(sources, f)
+		}
+	}
+
+	info := openapi.Info{
+		Title:       "Video Rental API",
+		Version:     "1.0",
+		Description: "Rentals, payments and the catalog of a chain of video stores. Send the key from /v1/login in X-API-Key.",
+	}
+	return openapi.Generate(info, sources, openAPIModels...)
+}
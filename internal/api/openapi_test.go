@@ -0,0 +1,102 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/config"
+	"github.com/rstoltzm-profile/video-rental-api/internal/openapi"
+)
+
+// undocumented are root patterns that are not API routes: the landing page,
+// the Swagger UI and the /v1 mount
+var undocumented = map[string]bool{"/": true, "/swagger/": true, "/v1/": true}
+
+func TestOpenAPI_SpecIsCurrent(t *testing.T) {
+	doc, err := GenerateOpenAPI("../..")
+	if err != nil {
+		t.Fatalf("failed to generate the OpenAPI document: %v", err)
+	}
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(append(out, '\n')) != string(openapi.Spec()) {
+		t.Error("internal/openapi/openapi.json is out of date, run make docs-openapi")
+	}
+}
+
+func TestOpenAPI_CoversRoutes(t *testing.T) {
+	var doc openapi.Document
+	if err := json.Unmarshal(openapi.Spec(), &doc); err != nil {
+		t.Fatalf("failed to decode the OpenAPI document: %v", err)
+	}
+
+	// registered maps a path to its methods, "" for a root route that
+	// answers any method
+	registered := map[string]map[string]bool{}
+	add := func(prefix, pattern string) {
+		method, path, found := strings.Cut(pattern, " ")
+		if !found {
+			method, path = "", pattern
+		}
+		if registered[prefix+path] == nil {
+			registered[prefix+path] = map[string]bool{}
+		}
+		registered[prefix+path][strings.ToLower(method)] = true
+	}
+	root, v1 := newRouter(nil, config.Config{APIKey: "nil"}, NewCatalog(nil, config.Config{}))
+	for _, pattern := range root.patterns {
+		if !undocumented[pattern] {
+			add("", pattern)
+		}
+	}
+	for _, pattern := range v1.patterns {
+		add("/v1", pattern)
+	}
+
+	for path, methods := range registered {
+		for method := range methods {
+			if method == "" {
+				if len(doc.Paths[path]) == 0 {
+					t.Errorf("%s is registered but not in the OpenAPI document", path)
+				}
+				continue
+			}
+			if doc.Paths[path][method] == nil {
+				t.Errorf("%s %s is registered but not in the OpenAPI document", strings.ToUpper(method), path)
+			}
+		}
+	}
+	for path, item := range doc.Paths {
+		for method := range item {
+			if !registered[path][method] && !registered[path][""] {
+				t.Errorf("%s %s is in the OpenAPI document but not registered", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+func TestRouter_OpenAPIRoute(t *testing.T) {
+	router := testRouter(config.Config{APIKey: "nil"})
+
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var doc openapi.Document
+	if err := json.NewDecoder(rr.Body).Decode(&doc); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if doc.OpenAPI != "3.1.0" {
+		t.Errorf("expected openapi 3.1.0, got %q", doc.OpenAPI)
+	}
+}
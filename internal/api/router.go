@@ -1,22 +1,68 @@
 package api
 
 import (
+	"log"
+	"maps"
 	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rstoltzm-profile/video-rental-api/internal/activity"
+	"github.com/rstoltzm-profile/video-rental-api/internal/apikey"
 	"github.com/rstoltzm-profile/video-rental-api/internal/auth"
+	"github.com/rstoltzm-profile/video-rental-api/internal/cache"
+	"github.com/rstoltzm-profile/video-rental-api/internal/config"
 	"github.com/rstoltzm-profile/video-rental-api/internal/customer"
 	"github.com/rstoltzm-profile/video-rental-api/internal/film"
+	"github.com/rstoltzm-profile/video-rental-api/internal/graph"
+	"github.com/rstoltzm-profile/video-rental-api/internal/idempotency"
+	"github.com/rstoltzm-profile/video-rental-api/internal/imports"
 	"github.com/rstoltzm-profile/video-rental-api/internal/inventory"
+	"github.com/rstoltzm-profile/video-rental-api/internal/job"
 	"github.com/rstoltzm-profile/video-rental-api/internal/middleware"
+	"github.com/rstoltzm-profile/video-rental-api/internal/openapi"
 	"github.com/rstoltzm-profile/video-rental-api/internal/payment"
 	"github.com/rstoltzm-profile/video-rental-api/internal/rental"
+	"github.com/rstoltzm-profile/video-rental-api/internal/report"
+	"github.com/rstoltzm-profile/video-rental-api/internal/reservation"
+	"github.com/rstoltzm-profile/video-rental-api/internal/rpc"
+	"github.com/rstoltzm-profile/video-rental-api/internal/staff"
 	"github.com/rstoltzm-profile/video-rental-api/internal/store"
+	"github.com/rstoltzm-profile/video-rental-api/internal/webhook"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"google.golang.org/grpc"
 )
 
-func NewRouter(pool *pgxpool.Pool, apiKey string) http.Handler {
-	mux := http.NewServeMux()
+func NewRouter(pool *pgxpool.Pool, cfg config.Config, catalog Catalog) http.Handler {
+	mux, _ := newRouter(pool, cfg, catalog)
+	return mux
+}
+
+// routeMux is a ServeMux that remembers its patterns, so a test can check
+// each route is in the OpenAPI document
+type routeMux struct {
+	*http.ServeMux
+	patterns []string
+}
+
+func newRouteMux() *routeMux {
+	return &routeMux{ServeMux: http.NewServeMux()}
+}
+
+func (m *routeMux) Handle(pattern string, handler http.Handler) {
+	m.patterns = append(m.patterns, pattern)
+	m.ServeMux.Handle(pattern, handler)
+}
+
+func (m *routeMux) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	m.patterns = append(m.patterns, pattern)
+	m.ServeMux.HandleFunc(pattern, handler)
+}
+
+// newRouter returns the root mux and the /v1 mux mounted on it
+func newRouter(pool *pgxpool.Pool, cfg config.Config, catalog Catalog) (*routeMux, *routeMux) {
+	mux := newRouteMux()
 
 	// landing page
 	mux.HandleFunc("/", landingPage)
@@ -29,73 +75,305 @@ func NewRouter(pool *pgxpool.Pool, apiKey string) http.Handler {
 	// health check
 	mux.HandleFunc("/health", healthHandler)
 	mux.HandleFunc("/health/pool", healthHandlerWithPool(pool))
+	mux.HandleFunc("/health/cache", healthHandlerWithCache(catalog.Cache))
 	mux.Handle("/swagger/", httpSwagger.WrapHandler)
+	mux.HandleFunc("GET /openapi.json", openapi.ServeSpec)
+
+	keys := apiKeys(pool, cfg)
 
 	// v1 routes
-	v1 := http.NewServeMux()
-	registerCustomerRoutes(v1, pool)
-	registerRentalRoutes(v1, pool)
-	registerInventoryRoutes(v1, pool)
-	registerStoreRoutes(v1, pool)
-	registerFilmRoutes(v1, pool)
-	registerPaymentRoutes(v1, pool)
+	v1 := newRouteMux()
+	jobs := registerJobRoutes(v1, pool)
+	events := registerWebhookRoutes(v1, pool, webhook.Guard{AllowPrivate: cfg.WebhookAllowPrivate})
+	customers := registerCustomerRoutes(v1, pool)
+	holds := registerReservationRoutes(v1, pool, cfg.HoldDuration)
+	staffService := registerStaffRoutes(v1, pool)
+	registerRentalRoutes(v1, pool, holds, staffService, events)
+	inventoryService := registerInventoryRoutes(v1, pool)
+	stores := registerStoreRoutes(v1, pool, staffService)
+	registerActivityRoutes(v1, pool, cfg.EventsHeartbeat)
+	registerFilmRoutes(v1, catalog.Films)
+	payments := registerPaymentRoutes(v1, pool, staffService, jobs, events)
+	registerReportRoutes(v1, pool, jobs)
+	registerImportRoutes(v1, pool, jobs)
 
+	// requests are checked against the OpenAPI document before the handlers
+	validator, err := openapi.NewValidator(openapi.Spec(), "/v1")
+	if err != nil {
+		panic(err) // the embedded document is checked by the api tests
+	}
 	mux.Handle("/v1/", http.StripPrefix("/v1",
-		middleware.CORSMiddleware(
+		middleware.CORSMiddleware(cfg.CORS, routeMethods(v1.ServeMux),
 			middleware.RequestSizeMiddleware(
-				middleware.ApiKeyMiddleware(apiKey,
-					middleware.ErrorMiddleware(v1.ServeHTTP))))))
-	return mux
+				middleware.ApiKeyMiddleware(keys,
+					validator.Middleware(
+						idempotency.Middleware(idempotency.NewRepository(pool), cfg.IdempotencyTTL,
+							middleware.ErrorMiddleware(v1.ServeHTTP))))))))
+
+	// graphql resolves through the /v1 services, behind the same API key
+	graphql := graph.NewHandler(graph.Services{
+		Customers: customers,
+		Films:     catalog.Films,
+		Inventory: inventoryService,
+		Payments:  payments,
+		Stores:    stores,
+	})
+	mux.Handle("/graphql", middleware.CORSMiddleware(cfg.CORS, postOnly,
+		middleware.RequestSizeMiddleware(
+			middleware.ApiKeyMiddleware(keys,
+				middleware.ErrorMiddleware(graphql.ServeHTTP)))))
+	return mux, v1
+}
+
+// apiKeys accepts the configured API_KEY and the keys issued with vrctl, only
+// the configured key when there is no database
+func apiKeys(pool *pgxpool.Pool, cfg config.Config) *apikey.Checker {
+	if pool == nil {
+		return apikey.NewChecker(cfg.APIKey, nil)
+	}
+	return apikey.NewChecker(cfg.APIKey, apikey.NewRepository(pool))
+}
+
+func postOnly(path string) []string {
+	return []string{http.MethodPost}
+}
+
+// Catalog is the film catalog cache and the cached film service reading
+// through it. Build one with NewCatalog and pass it to both NewRouter and
+// NewGRPCServer, so /v1/films, GraphQL and gRPC share the cache and
+// Films.Invalidate clears it for all of them. Nothing in the API writes
+// film, film_actor or film_category yet, changes made in the database show
+// up once CACHE_TTL runs out; a write added later must call Films.Invalidate
+// after it commits.
+type Catalog struct {
+	Cache *cache.Metered
+	Films *film.CachedService
+}
+
+func NewCatalog(pool *pgxpool.Pool, cfg config.Config) Catalog {
+	catalog := newCatalogCache(cfg)
+	repo := film.NewRepository(pool)
+	return Catalog{
+		Cache: catalog,
+		Films: film.NewCachedService(film.NewService(repo, repo), catalog, cfg.CacheTTL),
+	}
+}
+
+// newCatalogCache falls back to memory when the Redis URL is invalid, the
+// cache only saves database reads.
+func newCatalogCache(cfg config.Config) *cache.Metered {
+	catalog, err := cache.New(cfg.CacheSize, cfg.CacheRedisURL)
+	if err != nil {
+		log.Printf("Catalog cache: %v, caching in memory instead", err)
+		return cache.NewMetered(cache.NewMemory(cfg.CacheSize), "memory")
+	}
+	return catalog
 }
 
-func registerCustomerRoutes(mux *http.ServeMux, pool *pgxpool.Pool) {
+// routeMethods reports which methods have a handler registered on mux for a
+// given path, used to answer CORS preflight and OPTIONS requests.
+func routeMethods(mux *http.ServeMux) func(path string) []string {
+	candidates := []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	return func(path string) []string {
+		var methods []string
+		for _, method := range candidates {
+			r := &http.Request{Method: method, URL: &url.URL{Path: path}}
+			if _, pattern := mux.Handler(r); pattern != "" {
+				methods = append(methods, method)
+			}
+		}
+		return methods
+	}
+}
+
+// registerCustomerRoutes and the other catalog registrations return their
+// service so /graphql can resolve through it.
+func registerCustomerRoutes(mux *routeMux, pool *pgxpool.Pool) customer.Service {
 	repo := customer.NewRepository(pool)
 	svc := customer.NewService(repo, repo, repo)
 	handler := customer.NewHandler(svc)
 	mux.HandleFunc("GET /customers", handler.GetCustomers)
 	mux.HandleFunc("GET /customers/{id}", handler.GetCustomerByID)
 	mux.HandleFunc("GET /customers/{id}/rentals", handler.GetCustomerRentalsByID)
+	mux.HandleFunc("GET /customers/{id}/summary", handler.GetCustomerSummaryByID)
 	mux.HandleFunc("POST /customers", handler.CreateCustomer)
+	mux.HandleFunc("PATCH /customers/{id}", handler.UpdateCustomer)
 	mux.HandleFunc("DELETE /customers/{id}", handler.DeleteCustomerByID)
+	mux.HandleFunc("POST /customers/{id}/reactivate", handler.ReactivateCustomerByID)
+	return svc
+}
+
+// registerReservationRoutes returns the reservation service so renting and
+// returning copies can honor holds.
+func registerReservationRoutes(mux *routeMux, pool *pgxpool.Pool, holdDuration time.Duration) reservation.Service {
+	repo := reservation.NewRepository(pool)
+	svc := reservation.NewService(repo, repo, repo, holdDuration)
+	handler := reservation.NewHandler(svc)
+	mux.HandleFunc("GET /reservations", handler.GetReservations)
+	mux.HandleFunc("GET /reservations/{id}", handler.GetReservationByID)
+	mux.HandleFunc("POST /reservations", handler.CreateReservation)
+	mux.HandleFunc("DELETE /reservations/{id}", handler.CancelReservation)
+	return svc
+}
+
+// registerStaffRoutes returns the staff service so rental and payment writes
+// can check the staff member recording them, and store writes the session of
+// the manager making them.
+func registerStaffRoutes(mux *routeMux, pool *pgxpool.Pool) staff.Service {
+	repo := staff.NewRepository(pool)
+	svc := staff.NewService(repo, repo, repo)
+	handler := staff.NewHandler(svc)
+	mux.HandleFunc("GET /staff", handler.GetStaff)
+	mux.HandleFunc("GET /staff/{id}", handler.GetStaffByID)
+	mux.HandleFunc("POST /staff", handler.CreateStaff)
+	mux.HandleFunc("PATCH /staff/{id}", handler.UpdateStaff)
+	mux.HandleFunc("DELETE /staff/{id}", handler.DeactivateStaff)
+	mux.HandleFunc("POST /staff/{id}/reactivate", handler.ReactivateStaff)
+	mux.HandleFunc("PUT /staff/{id}/store", handler.MoveStaff)
+	mux.HandleFunc("PUT /staff/{id}/password", handler.SetPassword)
+	mux.HandleFunc("POST /staff/sessions", handler.OpenSession)
+	mux.HandleFunc("GET /staff/{id}/picture", handler.GetPicture)
+	mux.HandleFunc("PUT /staff/{id}/picture", handler.SetPicture)
+	return svc
 }
 
-func registerRentalRoutes(mux *http.ServeMux, pool *pgxpool.Pool) {
+func registerRentalRoutes(mux *routeMux, pool *pgxpool.Pool, holds rental.Holds, staff rental.StaffChecker, events rental.Events) {
 	repo := rental.NewRepository(pool)
-	svc := rental.NewService(repo, repo, repo)
+	svc := rental.NewService(repo, repo, repo, holds, staff, events)
 	handler := rental.NewHandler(svc)
 	mux.HandleFunc("GET /rentals", handler.GetRentals)
 	mux.HandleFunc("POST /rentals", handler.CreateRental)
 	mux.HandleFunc("POST /rentals/{id}/return", handler.ReturnRental)
 }
 
-func registerInventoryRoutes(mux *http.ServeMux, pool *pgxpool.Pool) {
+func registerInventoryRoutes(mux *routeMux, pool *pgxpool.Pool) inventory.Service {
 	repo := inventory.NewRepository(pool)
 	svc := inventory.NewService(repo, repo)
 	handler := inventory.NewHandler(svc)
 	mux.HandleFunc("GET /inventory", handler.GetInventory)
 	mux.HandleFunc("GET /inventory/available", handler.GetInventoryAvailable)
+	mux.HandleFunc("GET /films/{id}/availability", handler.GetFilmAvailability)
+	return svc
 }
 
-func registerStoreRoutes(mux *http.ServeMux, pool *pgxpool.Pool) {
+func registerStoreRoutes(mux *routeMux, pool *pgxpool.Pool, sessions auth.Sessions) store.Service {
 	repo := store.NewRepository(pool)
-	svc := store.NewService(repo, repo)
-	handler := store.NewHandler(svc)
+	svc := store.NewService(repo, repo, repo)
+	handler := store.NewHandler(svc, sessions)
+	mux.HandleFunc("GET /stores", handler.GetStores)
+	mux.HandleFunc("GET /stores/{id}", handler.GetStoreByID)
+	mux.HandleFunc("GET /stores/{id}/staff", handler.GetStoreStaff)
 	mux.HandleFunc("GET /stores/{id}/inventory/summary", handler.GetStoreInventorySummary)
+	mux.HandleFunc("PATCH /stores/{id}", handler.UpdateStore)
+	mux.HandleFunc("PUT /stores/{id}/manager", handler.ReassignManager)
+	return svc
 }
 
-func registerFilmRoutes(mux *http.ServeMux, pool *pgxpool.Pool) {
-	repo := film.NewRepository(pool)
-	svc := film.NewService(repo, repo)
-	handler := film.NewHandler(svc)
+func registerActivityRoutes(mux *routeMux, pool *pgxpool.Pool, heartbeat time.Duration) {
+	broker := activity.NewBroker(activity.NewRepository(pool))
+	handler := activity.NewHandler(broker, heartbeat)
+	mux.HandleFunc("GET /stores/{id}/events", handler.StoreEvents)
+}
+
+func registerFilmRoutes(mux *routeMux, films film.Service) {
+	handler := film.NewHandler(films)
 	mux.HandleFunc("GET /films", handler.GetFilms)
 	mux.HandleFunc("GET /films/{id}", handler.GetFilmByID)
 	mux.HandleFunc("GET /films/search", handler.SearchFilm)
-	mux.HandleFunc("GET /films/", handler.GetFilmWithActorsAndCategoriesByID)
+	mux.HandleFunc("GET /films/{id}/with-actors-categories", handler.GetFilmWithActorsAndCategoriesByID)
 }
 
-func registerPaymentRoutes(mux *http.ServeMux, pool *pgxpool.Pool) {
+func registerPaymentRoutes(mux *routeMux, pool *pgxpool.Pool, staff payment.StaffChecker, jobs job.Enqueuer, events payment.Events) payment.Service {
 	repo := payment.NewRepository(pool)
-	svc := payment.NewService(repo)
-	handler := payment.NewHandler(svc)
+	svc := payment.NewService(repo, repo, repo, staff, events)
+	handler := payment.NewHandler(svc, jobs)
+	mux.HandleFunc("GET /payments", handler.GetPayments)
 	mux.HandleFunc("POST /payments", handler.MakePayment)
+	return svc
+}
+
+func registerReportRoutes(mux *routeMux, pool *pgxpool.Pool, jobs job.Enqueuer) {
+	repo := report.NewRepository(pool)
+	svc := report.NewService(repo)
+	handler := report.NewHandler(svc, jobs)
+	mux.HandleFunc("GET /reports/revenue", handler.GetRevenue)
+	mux.HandleFunc("GET /reports/top-films", handler.GetTopFilms)
+	mux.HandleFunc("GET /reports/top-customers", handler.GetTopCustomers)
+	mux.HandleFunc("GET /reports/categories", handler.GetCategoryPopularity)
+	mux.HandleFunc("GET /reports/late-returns", handler.GetLateReturnRates)
+	mux.HandleFunc("GET /reports/utilization", handler.GetCopyUtilization)
+}
+
+func registerImportRoutes(mux *routeMux, pool *pgxpool.Pool, jobs job.Enqueuer) {
+	repo := imports.NewRepository(pool)
+	svc := imports.NewService(repo, repo, repo)
+	handler := imports.NewHandler(svc, jobs)
+	mux.HandleFunc("POST /imports/customers", handler.ImportCustomers)
+	mux.HandleFunc("POST /imports/inventory", handler.ImportInventory)
+}
+
+// registerJobRoutes returns the job service so handlers can queue work that
+// would outlast the server's WriteTimeout.
+func registerJobRoutes(mux *routeMux, pool *pgxpool.Pool) job.Service {
+	repo := job.NewRepository(pool)
+	svc := job.NewService(repo, repo)
+	handler := job.NewHandler(svc)
+	mux.HandleFunc("GET /jobs/{id}", handler.GetJob)
+	mux.HandleFunc("GET /jobs/{id}/result", handler.GetResult)
+	mux.HandleFunc("DELETE /jobs/{id}", handler.CancelJob)
+	return svc
+}
+
+// registerWebhookRoutes returns the webhook service so rentals and payments
+// can publish events.
+func registerWebhookRoutes(mux *routeMux, pool *pgxpool.Pool, guard webhook.Guard) webhook.Service {
+	repo := webhook.NewRepository(pool)
+	svc := webhook.NewService(repo, repo)
+	handler := webhook.NewHandler(svc, guard)
+	mux.HandleFunc("GET /webhooks", handler.GetSubscriptions)
+	mux.HandleFunc("GET /webhooks/{id}", handler.GetSubscriptionByID)
+	mux.HandleFunc("POST /webhooks", handler.CreateSubscription)
+	mux.HandleFunc("PATCH /webhooks/{id}", handler.UpdateSubscription)
+	mux.HandleFunc("DELETE /webhooks/{id}", handler.DeleteSubscription)
+	mux.HandleFunc("GET /webhooks/{id}/deliveries", handler.GetDeliveries)
+	return svc
+}
+
+// JobTasks is every kind of job the workers can run, keyed by kind.
+func JobTasks(pool *pgxpool.Pool) map[string]job.Task {
+	staffRepo := staff.NewRepository(pool)
+	paymentRepo := payment.NewRepository(pool)
+	importRepo := imports.NewRepository(pool)
+	webhookRepo := webhook.NewRepository(pool)
+	events := webhook.NewService(webhookRepo, webhookRepo)
+
+	tasks := map[string]job.Task{}
+	maps.Copy(tasks, imports.Tasks(imports.NewService(importRepo, importRepo, importRepo)))
+	maps.Copy(tasks, payment.Tasks(payment.NewService(paymentRepo, paymentRepo, paymentRepo, staff.NewService(staffRepo, staffRepo, staffRepo), events)))
+	maps.Copy(tasks, report.Tasks(report.NewService(report.NewRepository(pool))))
+	return tasks
+}
+
+// NewGRPCServer serves the gRPC API over the same services as /v1, checking
+// the same API key. Films are read through the catalog NewRouter uses.
+func NewGRPCServer(pool *pgxpool.Pool, cfg config.Config, catalog Catalog) *grpc.Server {
+	customerRepo := customer.NewRepository(pool)
+	inventoryRepo := inventory.NewRepository(pool)
+	paymentRepo := payment.NewRepository(pool)
+	rentalRepo := rental.NewRepository(pool)
+	reservationRepo := reservation.NewRepository(pool)
+	staffRepo := staff.NewRepository(pool)
+	webhookRepo := webhook.NewRepository(pool)
+
+	events := webhook.NewService(webhookRepo, webhookRepo)
+	holds := reservation.NewService(reservationRepo, reservationRepo, reservationRepo, cfg.HoldDuration)
+	staffService := staff.NewService(staffRepo, staffRepo, staffRepo)
+
+	return rpc.NewServer(rpc.Services{
+		Customers: customer.NewService(customerRepo, customerRepo, customerRepo),
+		Films:     catalog.Films,
+		Inventory: inventory.NewService(inventoryRepo, inventoryRepo),
+		Payments:  payment.NewService(paymentRepo, paymentRepo, paymentRepo, staffService, events),
+		Rentals:   rental.NewService(rentalRepo, rentalRepo, rentalRepo, holds, staffService, events),
+	}, apiKeys(pool, cfg))
 }
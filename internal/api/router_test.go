@@ -1,12 +1,32 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/cache"
+	"github.com/rstoltzm-profile/video-rental-api/internal/config"
+	"github.com/rstoltzm-profile/video-rental-api/internal/film"
+	"github.com/rstoltzm-profile/video-rental-api/internal/openapi"
+	pb "github.com/rstoltzm-profile/video-rental-api/pkg/pb/videorental/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
 )
 
+// testRouter is NewRouter without a database, with a catalog of its own
+func testRouter(cfg config.Config) http.Handler {
+	return NewRouter(nil, cfg, NewCatalog(nil, cfg))
+}
+
 func TestHealthHandler(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
 	rr := httptest.NewRecorder()
@@ -30,7 +50,7 @@ func TestHealthHandler(t *testing.T) {
 }
 
 func TestRouter_HealthRoute(t *testing.T) {
-	router := NewRouter(nil, "nil") // nil is fine as long as handler doesn't panic
+	router := testRouter(config.Config{APIKey: "nil"}) // nil is fine as long as handler doesn't panic
 
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
 	rr := httptest.NewRecorder()
@@ -41,3 +61,218 @@ func TestRouter_HealthRoute(t *testing.T) {
 		t.Errorf("expected 200, got %d", rr.Code)
 	}
 }
+
+func TestRouter_CORSPreflight(t *testing.T) {
+	router := testRouter(config.Config{
+		APIKey: "nil",
+		CORS: config.CORSConfig{
+			AllowedOrigins: []string{"https://*.example.com"},
+			AllowedHeaders: []string{"Content-Type", "X-API-Key"},
+			MaxAge:         600,
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodOptions, "/v1/customers/1", nil)
+	req.Header.Set("Origin", "https://portal.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodDelete)
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rr.Code)
+	}
+	if got := rr.Header().Get("Access-Control-Allow-Origin"); got != "https://portal.example.com" {
+		t.Errorf("expected origin to be echoed, got '%s'", got)
+	}
+	if got := rr.Header().Get("Access-Control-Allow-Methods"); got != "GET, PATCH, DELETE, OPTIONS" {
+		t.Errorf("expected route methods, got '%s'", got)
+	}
+	if got := rr.Header().Get("Access-Control-Max-Age"); got != "600" {
+		t.Errorf("expected max age 600, got '%s'", got)
+	}
+}
+
+func TestRouter_CORSPreflightUnknownOrigin(t *testing.T) {
+	router := testRouter(config.Config{
+		APIKey: "nil",
+		CORS:   config.CORSConfig{AllowedOrigins: []string{"https://*.example.com"}},
+	})
+
+	req := httptest.NewRequest(http.MethodOptions, "/v1/customers", nil)
+	req.Header.Set("Origin", "https://example.org")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rr.Code)
+	}
+	if got := rr.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("expected no allow origin header, got '%s'", got)
+	}
+}
+
+func TestRouter_GraphQLRequiresAPIKey(t *testing.T) {
+	router := testRouter(config.Config{APIKey: "nil"})
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query":"{ stores { id } }"}`))
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rr.Code)
+	}
+}
+
+func TestRouter_ValidatesRequests(t *testing.T) {
+	router := testRouter(config.Config{APIKey: "nil"})
+
+	body := `{"inventory_id": 1, "customer_id": 0, "staff_id": 1, "store_id": 1}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/rentals", strings.NewReader(body))
+	req.Header.Set("X-API-Key", "nil")
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rr.Code)
+	}
+	var resp openapi.ValidationError
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	want := []openapi.FieldError{
+		{In: "body", Field: "customer_id", Message: "must be greater than 0"},
+		{In: "body", Field: "store_id", Message: "is not a known field"},
+	}
+	if len(resp.Fields) != len(want) {
+		t.Fatalf("expected %v, got %v", want, resp.Fields)
+	}
+	for i := range want {
+		if resp.Fields[i] != want[i] {
+			t.Errorf("expected %v, got %v", want[i], resp.Fields[i])
+		}
+	}
+}
+
+// TestRouter_DryRunMustBeTrueOrFalse checks ?dry_run=1 is refused, the
+// import handler only dry runs for "true" and would have committed the file
+func TestRouter_DryRunMustBeTrueOrFalse(t *testing.T) {
+	router := testRouter(config.Config{APIKey: "nil"})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/imports/customers?dry_run=1", strings.NewReader("first_name\n"))
+	req.Header.Set("X-API-Key", "nil")
+	req.Header.Set("Content-Type", "text/csv")
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), `"field":"dry_run"`) {
+		t.Errorf("expected a dry_run error, got %s", rr.Body)
+	}
+}
+
+// TestRouter_StaffWritesNeedSession checks the API key alone can't change a
+// staff member, or reset the password a manager logs in with
+func TestRouter_StaffWritesNeedSession(t *testing.T) {
+	router := testRouter(config.Config{APIKey: "nil"})
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodPut, "/v1/staff/1/password", `{"password": "correct horse"}`},
+		{http.MethodPatch, "/v1/staff/1", `{"first_name": "Mike"}`},
+		{http.MethodDelete, "/v1/staff/1", ""},
+		{http.MethodPost, "/v1/staff/1/reactivate", ""},
+		{http.MethodPut, "/v1/staff/1/store", `{"store_id": 2}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("X-API-Key", "nil")
+			req.Header.Set("If-Match", `"v1"`)
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusUnauthorized {
+				t.Errorf("expected 401, got %d: %s", rr.Code, rr.Body)
+			}
+		})
+	}
+}
+
+func TestRouter_ValidatesPathParameters(t *testing.T) {
+	router := testRouter(config.Config{APIKey: "nil"})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/customers/abc", nil)
+	req.Header.Set("X-API-Key", "nil")
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), `"field":"id"`) {
+		t.Errorf("expected an error for id, got %s", rr.Body.String())
+	}
+}
+
+func TestCatalog_SharedByRouterAndGRPC(t *testing.T) {
+	cfg := config.Config{APIKey: "nil", CacheSize: 10}
+	catalog := NewCatalog(nil, cfg)
+	router := NewRouter(nil, cfg, catalog)
+
+	lis := bufconn.Listen(1 << 20)
+	server := NewGRPCServer(nil, cfg, catalog)
+	go server.Serve(lis)
+	defer server.Stop()
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// there is no database, both can only answer from the cache
+	details, _ := json.Marshal(film.FilmWithActorsCategories{ID: 1, Title: "ACADEMY DINOSAUR"})
+	if err := catalog.Cache.Set(context.Background(), "film:1:details", details, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/films/1/with-actors-categories", nil)
+	req.Header.Set("X-API-Key", "nil")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "ACADEMY DINOSAUR") {
+		t.Fatalf("expected the cached film from /v1, got %d %s", rr.Code, rr.Body.String())
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "nil")
+	f, err := pb.NewFilmServiceClient(conn).GetFilm(ctx, &pb.GetFilmRequest{Id: 1})
+	if err != nil {
+		t.Fatalf("expected the cached film from gRPC, got %v", err)
+	}
+	if f.Title != "ACADEMY DINOSAUR" {
+		t.Errorf("expected ACADEMY DINOSAUR, got %q", f.Title)
+	}
+
+	// one Invalidate clears it for both
+	if err := catalog.Films.Invalidate(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := catalog.Cache.Get(context.Background(), "film:1:details"); !errors.Is(err, cache.ErrMiss) {
+		t.Errorf("expected a miss after Invalidate, got %v", err)
+	}
+}
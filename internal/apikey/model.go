@@ -0,0 +1,13 @@
+package apikey
+
+import "time"
+
+// Key is an API key issued to a client. Only a hash of the key is stored, the
+// key itself is returned once when it is created.
+type Key struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	Key       string     `json:"key,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
@@ -0,0 +1,84 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository stores issued keys by the SHA-256 of the key
+type Repository interface {
+	InsertKey(ctx context.Context, name string, hash []byte) (Key, error)
+	GetKeys(ctx context.Context) ([]Key, error)
+	RevokeKey(ctx context.Context, id int) (Key, error)
+	// KeyID returns the ID of the unrevoked key with hash, false when there
+	// is none
+	KeyID(ctx context.Context, hash []byte) (int, bool, error)
+}
+
+type repository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRepository(pool *pgxpool.Pool) Repository {
+	return &repository{pool: pool}
+}
+
+const keyColumns = `key_id, name, created_at, revoked_at`
+
+func scanKey(row pgx.Row) (Key, error) {
+	var k Key
+	err := row.Scan(&k.ID, &k.Name, &k.CreatedAt, &k.RevokedAt)
+	return k, err
+}
+
+func (r *repository) InsertKey(ctx context.Context, name string, hash []byte) (Key, error) {
+	return scanKey(r.pool.QueryRow(ctx, `
+		INSERT INTO api_key (name, key_hash)
+		VALUES ($1, $2)
+		RETURNING `+keyColumns, name, hash))
+}
+
+func (r *repository) GetKeys(ctx context.Context) ([]Key, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+keyColumns+` FROM api_key ORDER BY key_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []Key
+	for rows.Next() {
+		k, err := scanKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// RevokeKey stamps revoked_at once, revoking a revoked key keeps the first time
+func (r *repository) RevokeKey(ctx context.Context, id int) (Key, error) {
+	k, err := scanKey(r.pool.QueryRow(ctx, `
+		UPDATE api_key
+		SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP)
+		WHERE key_id = $1
+		RETURNING `+keyColumns, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return k, ErrKeyNotFound
+	}
+	return k, err
+}
+
+func (r *repository) KeyID(ctx context.Context, hash []byte) (int, bool, error) {
+	var id int
+	err := r.pool.QueryRow(ctx, `
+		SELECT key_id FROM api_key WHERE key_hash = $1 AND revoked_at IS NULL
+	`, hash).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	return id, err == nil, err
+}
@@ -0,0 +1,97 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrKeyNotFound = errors.New("API key not found")
+	ErrNameEmpty   = errors.New("API key name is required")
+)
+
+type Service interface {
+	CreateKey(ctx context.Context, name string) (Key, error)
+	GetKeys(ctx context.Context) ([]Key, error)
+	RevokeKey(ctx context.Context, id int) (Key, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+// CreateKey issues a random key, returned in Key.Key, and stores its hash
+func (s *service) CreateKey(ctx context.Context, name string) (Key, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Key{}, ErrNameEmpty
+	}
+	secret := rand.Text()
+	k, err := s.repo.InsertKey(ctx, name, hash(secret))
+	if err != nil {
+		return Key{}, fmt.Errorf("failed to create API key, %w", err)
+	}
+	k.Key = secret
+	return k, nil
+}
+
+func (s *service) GetKeys(ctx context.Context) ([]Key, error) {
+	return s.repo.GetKeys(ctx)
+}
+
+func (s *service) RevokeKey(ctx context.Context, id int) (Key, error) {
+	return s.repo.RevokeKey(ctx, id)
+}
+
+func hash(key string) []byte {
+	sum := sha256.Sum256([]byte(key))
+	return sum[:]
+}
+
+// ConfiguredKeyID is the ID of the API_KEY of the config, issued keys start
+// at 1
+const ConfiguredKeyID = 0
+
+type idKey struct{}
+
+// WithID returns ctx carrying the ID of the API key the request was made with
+func WithID(ctx context.Context, id int) context.Context {
+	return context.WithValue(ctx, idKey{}, id)
+}
+
+// IDFrom returns the ID stored by WithID, ConfiguredKeyID when there is none
+func IDFrom(ctx context.Context) int {
+	id, _ := ctx.Value(idKey{}).(int)
+	return id
+}
+
+// Checker accepts the API_KEY of the config and any unrevoked key in the
+// api_key table. Without a repository only the configured key is accepted.
+type Checker struct {
+	apiKey string
+	repo   Repository
+}
+
+func NewChecker(apiKey string, repo Repository) *Checker {
+	return &Checker{apiKey: apiKey, repo: repo}
+}
+
+// Check reports whether key may call the API and, when it may, its ID
+func (c *Checker) Check(ctx context.Context, key string) (int, bool, error) {
+	if c.apiKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(c.apiKey)) == 1 {
+		return ConfiguredKeyID, true, nil
+	}
+	if c.repo == nil {
+		return 0, false, nil
+	}
+	return c.repo.KeyID(ctx, hash(key))
+}
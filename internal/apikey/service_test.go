@@ -0,0 +1,97 @@
+package apikey
+
+import (
+	"context"
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRepository struct {
+	mock.Mock
+}
+
+func (m *mockRepository) InsertKey(ctx context.Context, name string, hash []byte) (Key, error) {
+	args := m.Called(ctx, name, hash)
+	return args.Get(0).(Key), args.Error(1)
+}
+
+func (m *mockRepository) GetKeys(ctx context.Context) ([]Key, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]Key), args.Error(1)
+}
+
+func (m *mockRepository) RevokeKey(ctx context.Context, id int) (Key, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Key), args.Error(1)
+}
+
+func (m *mockRepository) KeyID(ctx context.Context, hash []byte) (int, bool, error) {
+	args := m.Called(ctx, hash)
+	return args.Int(0), args.Bool(1), args.Error(2)
+}
+
+func TestService_CreateKey(t *testing.T) {
+	repo := new(mockRepository)
+	svc := NewService(repo)
+
+	created := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	var stored []byte
+	repo.On("InsertKey", mock.Anything, "front desk", mock.Anything).
+		Run(func(args mock.Arguments) { stored = args.Get(2).([]byte) }).
+		Return(Key{ID: 1, Name: "front desk", CreatedAt: created}, nil)
+
+	k, err := svc.CreateKey(context.Background(), "  front desk ")
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, k.ID)
+	assert.NotEmpty(t, k.Key)
+	sum := sha256.Sum256([]byte(k.Key))
+	assert.Equal(t, sum[:], stored, "only the hash is stored")
+}
+
+func TestService_CreateKeyNeedsAName(t *testing.T) {
+	svc := NewService(new(mockRepository))
+
+	_, err := svc.CreateKey(context.Background(), " ")
+
+	assert.ErrorIs(t, err, ErrNameEmpty)
+}
+
+func TestChecker(t *testing.T) {
+	repo := new(mockRepository)
+	repo.On("KeyID", mock.Anything, hash("issued")).Return(4, true, nil)
+	repo.On("KeyID", mock.Anything, mock.Anything).Return(0, false, nil)
+	checker := NewChecker("configured", repo)
+
+	for key, want := range map[string]struct {
+		id int
+		ok bool
+	}{
+		"configured": {ConfiguredKeyID, true},
+		"issued":     {4, true},
+		"revoked":    {0, false},
+	} {
+		id, ok, err := checker.Check(context.Background(), key)
+		require.NoError(t, err)
+		assert.Equal(t, want.ok, ok, key)
+		assert.Equal(t, want.id, id, key)
+	}
+	repo.AssertNotCalled(t, "KeyID", mock.Anything, hash("configured"))
+}
+
+func TestChecker_WithoutRepository(t *testing.T) {
+	checker := NewChecker("configured", nil)
+
+	_, ok, err := checker.Check(context.Background(), "configured")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	_, ok, err = checker.Check(context.Background(), "other")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
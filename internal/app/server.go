@@ -1,17 +1,36 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rstoltzm-profile/video-rental-api/internal/api"
 	"github.com/rstoltzm-profile/video-rental-api/internal/config"
 	"github.com/rstoltzm-profile/video-rental-api/internal/db"
+	"github.com/rstoltzm-profile/video-rental-api/internal/idempotency"
+	"github.com/rstoltzm-profile/video-rental-api/internal/job"
+	"github.com/rstoltzm-profile/video-rental-api/internal/reservation"
+	"github.com/rstoltzm-profile/video-rental-api/internal/webhook"
+	"google.golang.org/grpc"
 )
 
+// shutdownTimeout is how long requests in flight get to finish on shutdown,
+// store event streams don't end on their own and are cut off after it
+const shutdownTimeout = 20 * time.Second
+
+// Run serves until either server fails or the process gets SIGINT or SIGTERM,
+// then stops both servers and waits for the background workers. Running
+// jobs go back to the queue.
 func Run() error {
 	// load configs
 	cfg := config.LoadConfig()
@@ -41,18 +60,108 @@ func Run() error {
 		log.Printf("Database schema version: %s (applied at %s)", version, appliedAt)
 	}
 
+	if slices.Contains(cfg.CORS.AllowedOrigins, "*") {
+		log.Printf("CORS allows any origin without credentials, set CORS_ALLOWED_ORIGINS to restrict it")
+	}
+
+	// gRPC runs on its own port over the same services
+	lis, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		return fmt.Errorf("failed to listen on gRPC port: %w", err)
+	}
+
+	// the background workers stop after the servers, requests in flight may
+	// still queue jobs and webhook events
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var workers sync.WaitGroup
+	start := func(run func()) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run()
+		}()
+	}
+
+	// release expired reservation holds in the background
+	reservationRepo := reservation.NewRepository(pool)
+	reservationService := reservation.NewService(reservationRepo, reservationRepo, reservationRepo, cfg.HoldDuration)
+	start(func() { reservation.RunSweeper(ctx, reservationService, cfg.HoldSweepInterval) })
+
+	// run queued jobs in the background
+	jobRunner := job.NewRunner(job.NewRepository(pool), api.JobTasks(pool), cfg.JobWorkers, cfg.JobPollInterval, cfg.JobRetention)
+	start(func() { jobRunner.Run(ctx) })
+
+	// send webhook deliveries in the background
+	dispatcher := webhook.NewDispatcher(webhook.NewRepository(pool), cfg.WebhookInterval,
+		webhook.Guard{AllowPrivate: cfg.WebhookAllowPrivate})
+	start(func() { dispatcher.Run(ctx) })
+
+	// delete expired idempotency keys in the background
+	start(func() { idempotency.RunSweeper(ctx, idempotency.NewRepository(pool)) })
+
+	// /v1, GraphQL and gRPC read films through one cache
+	catalog := api.NewCatalog(pool, cfg)
+
 	// configure server
 	server := &http.Server{
 		Addr:         ":" + cfg.Port,
-		Handler:      api.NewRouter(pool, cfg.APIKey),
+		Handler:      api.NewRouter(pool, cfg, catalog),
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
 
-	// start the server, pass in conn to NewRouter so api can use it.
-	log.Printf("Starting server on port %s...", cfg.Port)
-	return server.ListenAndServe()
+	grpcServer := api.NewGRPCServer(pool, cfg, catalog)
+
+	signals, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// start both servers, whichever stops first ends Run
+	errs := make(chan error, 2)
+	go func() {
+		log.Printf("Starting gRPC server on port %s...", cfg.GRPCPort)
+		errs <- grpcServer.Serve(lis)
+	}()
+	go func() {
+		log.Printf("Starting server on port %s...", cfg.Port)
+		errs <- server.ListenAndServe()
+	}()
+
+	var serveErr error
+	select {
+	case serveErr = <-errs:
+	case <-signals.Done():
+		log.Printf("Shutting down...")
+	}
+	shutdown(server, grpcServer)
+	cancel()
+	workers.Wait()
+	return serveErr
+}
+
+// shutdown stops both servers taking new requests and waits up to
+// shutdownTimeout for the ones in flight before closing what is left
+func shutdown(server *http.Server, grpcServer *grpc.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("HTTP server did not stop in %s: %v", shutdownTimeout, err)
+		server.Close()
+	}
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		grpcServer.Stop()
+		<-stopped
+	}
 }
 
 func connectWithRetry(url string, maxRetries int) (*pgxpool.Pool, error) {
@@ -1,9 +1,15 @@
 package app
 
 import (
+	"errors"
+	"net"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 )
 
 func TestEmpty(t *testing.T) {
@@ -11,3 +17,38 @@ func TestEmpty(t *testing.T) {
 	want := 1
 	assert.Equal(t, want, got)
 }
+
+func TestShutdown_StopsBothServers(t *testing.T) {
+	httpLis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcLis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &http.Server{Handler: http.NotFoundHandler()}
+	grpcServer := grpc.NewServer()
+	errs := make(chan error, 2)
+	go func() { errs <- server.Serve(httpLis) }()
+	go func() { errs <- grpcServer.Serve(grpcLis) }()
+
+	done := make(chan struct{})
+	go func() {
+		shutdown(server, grpcServer)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown did not return for idle servers")
+	}
+	for range 2 {
+		select {
+		case err := <-errs:
+			if err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+				assert.ErrorIs(t, err, http.ErrServerClosed)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("a server is still serving")
+		}
+	}
+}
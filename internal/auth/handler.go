@@ -13,11 +13,25 @@ func NewHandler(service Service) *Handler {
 	return &Handler{service: service}
 }
 
+// LoginRequest is the staff credentials exchanged for an API key
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// Login godoc
+// @Summary      Log in
+// @Description  Exchange staff credentials for the API key to send in X-API-Key
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        credentials  body      auth.LoginRequest  true  "Staff credentials"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {string}  string "Invalid request"
+// @Failure      401  {string}  string "Unauthorized"
+// @Router       /v1/login [post]
 func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
-	var creds struct {
-		Username string `json:"username"`
-		Password string `json:"password"`
-	}
+	var creds LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
 		http.Error(w, "Invalid request", http.StatusBadRequest)
 		return
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// StaffHeader carries the token of a staff session from
+// POST /v1/staff/sessions. The API key authenticates the client, the session
+// says who is at the counter.
+const StaffHeader = "X-Staff-Token"
+
+var ErrMissingStaff = errors.New("missing, invalid or expired " + StaffHeader + " header")
+
+// ErrSessionNotFound is what Sessions returns for a token that is unknown or
+// has expired
+var ErrSessionNotFound = errors.New("staff session not found or expired")
+
+// Sessions looks up the staff member a session token was issued to
+type Sessions interface {
+	SessionStaff(ctx context.Context, token string) (int, error)
+}
+
+// StaffID returns the acting staff member from the session in StaffHeader.
+func StaffID(r *http.Request, sessions Sessions) (int, error) {
+	token := r.Header.Get(StaffHeader)
+	if token == "" {
+		return 0, ErrMissingStaff
+	}
+	id, err := sessions.SessionStaff(r.Context(), token)
+	if errors.Is(err, ErrSessionNotFound) {
+		return 0, ErrMissingStaff
+	}
+	return id, err
+}
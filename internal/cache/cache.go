@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+)
+
+// ErrMiss is returned by Get when the key is not cached or has expired
+var ErrMiss = errors.New("cache miss")
+
+// Cache stores encoded values under string keys until their TTL runs out.
+// Memory and Redis implement it, Metered counts its hits and misses.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Fetch returns the value cached under key, or loads it and caches it for
+// ttl. The cache only ever saves work, when it fails the value is loaded and
+// the error logged. Errors from load are returned and never cached.
+func Fetch[T any](ctx context.Context, c Cache, key string, ttl time.Duration, load func(ctx context.Context) (T, error)) (T, error) {
+	cached, err := c.Get(ctx, key)
+	if err == nil {
+		var v T
+		if err := json.Unmarshal(cached, &v); err == nil {
+			return v, nil
+		}
+		log.Printf("cache: dropping undecodable %s: %v", key, err)
+	} else if !errors.Is(err, ErrMiss) {
+		log.Printf("cache: get %s: %v", key, err)
+	}
+
+	v, err := load(ctx)
+	if err != nil {
+		return v, err
+	}
+
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("cache: encode %s: %v", key, err)
+		return v, nil
+	}
+	if err := c.Set(ctx, key, encoded, ttl); err != nil {
+		log.Printf("cache: set %s: %v", key, err)
+	}
+	return v, nil
+}
+
+// New is the Metered cache for the config, Redis when redisURL is set and an
+// in-memory LRU of size entries otherwise.
+func New(size int, redisURL string) (*Metered, error) {
+	if redisURL == "" {
+		return NewMetered(NewMemory(size), "memory"), nil
+	}
+	r, err := NewRedis(redisURL)
+	if err != nil {
+		return nil, err
+	}
+	return NewMetered(r, "redis"), nil
+}
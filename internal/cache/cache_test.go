@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type film struct {
+	Title string `json:"title"`
+}
+
+// broken is a backend that is down
+type broken struct{}
+
+func (broken) Get(ctx context.Context, key string) ([]byte, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (broken) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return errors.New("connection refused")
+}
+
+func (broken) Delete(ctx context.Context, keys ...string) error {
+	return errors.New("connection refused")
+}
+
+func TestFetch(t *testing.T) {
+	c := NewMetered(NewMemory(10), "memory")
+	loads := 0
+	load := func(ctx context.Context) (film, error) {
+		loads++
+		return film{Title: "ACADEMY DINOSAUR"}, nil
+	}
+
+	for range 3 {
+		f, err := Fetch(context.Background(), c, "film:1", time.Minute, load)
+		assert.NoError(t, err)
+		assert.Equal(t, "ACADEMY DINOSAUR", f.Title)
+	}
+
+	assert.Equal(t, 1, loads)
+	assert.Equal(t, Stats{Backend: "memory", Hits: 2, Misses: 1, HitRate: 2.0 / 3}, c.Stats())
+}
+
+func TestFetch_LoadErrorNotCached(t *testing.T) {
+	c := NewMetered(NewMemory(10), "memory")
+	notFound := errors.New("film not found")
+
+	_, err := Fetch(context.Background(), c, "film:999", time.Minute, func(ctx context.Context) (film, error) {
+		return film{}, notFound
+	})
+
+	assert.ErrorIs(t, err, notFound)
+	_, err = c.Get(context.Background(), "film:999")
+	assert.ErrorIs(t, err, ErrMiss)
+}
+
+func TestFetch_BackendDown(t *testing.T) {
+	c := NewMetered(broken{}, "redis")
+
+	f, err := Fetch(context.Background(), c, "film:1", time.Minute, func(ctx context.Context) (film, error) {
+		return film{Title: "ACADEMY DINOSAUR"}, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ACADEMY DINOSAUR", f.Title)
+	assert.Equal(t, uint64(2), c.Stats().Errors) // the get and the set
+}
+
+func TestNew(t *testing.T) {
+	c, err := New(10, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "memory", c.Stats().Backend)
+
+	c, err = New(10, "redis://localhost:6379/0")
+	assert.NoError(t, err)
+	assert.Equal(t, "redis", c.Stats().Backend)
+
+	_, err = New(10, "http://localhost:6379")
+	assert.Error(t, err)
+}
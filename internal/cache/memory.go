@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// Memory is an in-process LRU cache. Entries expire after their TTL and the
+// least recently used entry is evicted once it holds size entries.
+type Memory struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // front is most recently used
+	entries map[string]*list.Element
+	now     func() time.Time
+}
+
+func NewMemory(size int) *Memory {
+	return &Memory{
+		size:    max(size, 1),
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+		now:     time.Now,
+	}
+}
+
+func (m *Memory) Get(ctx context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.entries[key]
+	if !ok {
+		return nil, ErrMiss
+	}
+	e := el.Value.(*entry)
+	if !m.now().Before(e.expiresAt) {
+		m.remove(el)
+		return nil, ErrMiss
+	}
+	m.order.MoveToFront(el)
+	return e.value, nil
+}
+
+func (m *Memory) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	expiresAt := m.now().Add(ttl)
+	if el, ok := m.entries[key]; ok {
+		e := el.Value.(*entry)
+		e.value, e.expiresAt = value, expiresAt
+		m.order.MoveToFront(el)
+		return nil
+	}
+
+	m.entries[key] = m.order.PushFront(&entry{key: key, value: value, expiresAt: expiresAt})
+	for m.order.Len() > m.size {
+		m.remove(m.order.Back())
+	}
+	return nil
+}
+
+func (m *Memory) Delete(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		if el, ok := m.entries[key]; ok {
+			m.remove(el)
+		}
+	}
+	return nil
+}
+
+// Len is the number of entries held, expired ones included until they are
+// read or evicted.
+func (m *Memory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
+
+func (m *Memory) remove(el *list.Element) {
+	m.order.Remove(el)
+	delete(m.entries, el.Value.(*entry).key)
+}
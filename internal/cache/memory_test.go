@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemory_GetSet(t *testing.T) {
+	m := NewMemory(10)
+	ctx := context.Background()
+
+	_, err := m.Get(ctx, "film:1")
+	assert.ErrorIs(t, err, ErrMiss)
+
+	assert.NoError(t, m.Set(ctx, "film:1", []byte("academy"), time.Minute))
+	value, err := m.Get(ctx, "film:1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("academy"), value)
+}
+
+func TestMemory_Expiry(t *testing.T) {
+	m := NewMemory(10)
+	ctx := context.Background()
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	m.Set(ctx, "film:1", []byte("academy"), time.Minute)
+	now = now.Add(time.Minute)
+
+	_, err := m.Get(ctx, "film:1")
+	assert.ErrorIs(t, err, ErrMiss)
+	assert.Equal(t, 0, m.Len())
+}
+
+func TestMemory_EvictsLeastRecentlyUsed(t *testing.T) {
+	m := NewMemory(2)
+	ctx := context.Background()
+
+	m.Set(ctx, "film:1", []byte("1"), time.Minute)
+	m.Set(ctx, "film:2", []byte("2"), time.Minute)
+	m.Get(ctx, "film:1") // film:2 is now the least recently used
+	m.Set(ctx, "film:3", []byte("3"), time.Minute)
+
+	_, err := m.Get(ctx, "film:2")
+	assert.ErrorIs(t, err, ErrMiss)
+	_, err = m.Get(ctx, "film:1")
+	assert.NoError(t, err)
+	_, err = m.Get(ctx, "film:3")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, m.Len())
+}
+
+func TestMemory_Delete(t *testing.T) {
+	m := NewMemory(10)
+	ctx := context.Background()
+
+	m.Set(ctx, "films", []byte("[]"), time.Minute)
+	m.Set(ctx, "film:1", []byte("1"), time.Minute)
+
+	assert.NoError(t, m.Delete(ctx, "films", "film:1", "film:2"))
+	assert.Equal(t, 0, m.Len())
+}
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// Stats are the counters of a Metered cache since the process started
+type Stats struct {
+	Backend string  `json:"backend"`
+	Hits    uint64  `json:"hits"`
+	Misses  uint64  `json:"misses"`
+	Errors  uint64  `json:"errors"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+// Metered counts hits, misses and backend errors of the Cache it wraps
+type Metered struct {
+	Cache
+	backend string
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+	errors  atomic.Uint64
+}
+
+// NewMetered wraps c, backend names it in Stats
+func NewMetered(c Cache, backend string) *Metered {
+	return &Metered{Cache: c, backend: backend}
+}
+
+func (m *Metered) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := m.Cache.Get(ctx, key)
+	switch {
+	case err == nil:
+		m.hits.Add(1)
+	case errors.Is(err, ErrMiss):
+		m.misses.Add(1)
+	default:
+		m.errors.Add(1)
+	}
+	return value, err
+}
+
+func (m *Metered) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	err := m.Cache.Set(ctx, key, value, ttl)
+	if err != nil {
+		m.errors.Add(1)
+	}
+	return err
+}
+
+func (m *Metered) Delete(ctx context.Context, keys ...string) error {
+	err := m.Cache.Delete(ctx, keys...)
+	if err != nil {
+		m.errors.Add(1)
+	}
+	return err
+}
+
+func (m *Metered) Stats() Stats {
+	s := Stats{
+		Backend: m.backend,
+		Hits:    m.hits.Load(),
+		Misses:  m.misses.Load(),
+		Errors:  m.errors.Load(),
+	}
+	if reads := s.Hits + s.Misses; reads > 0 {
+		s.HitRate = float64(s.Hits) / float64(reads)
+	}
+	return s
+}
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefix keeps our keys apart from anything else sharing the Redis database
+const keyPrefix = "video-rental:"
+
+// Redis is a Cache shared by every API instance using the same Redis
+type Redis struct {
+	client *redis.Client
+}
+
+// NewRedis connects to a redis:// or rediss:// URL
+func NewRedis(url string) (*Redis, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("parse cache redis url: %w", err)
+	}
+	return &Redis{client: redis.NewClient(opts)}, nil
+}
+
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := r.client.Get(ctx, keyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrMiss
+	}
+	return value, err
+}
+
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return r.client.Set(ctx, keyPrefix+key, value, ttl).Err()
+}
+
+func (r *Redis) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = keyPrefix + key
+	}
+	return r.client.Del(ctx, prefixed...).Err()
+}
+
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
@@ -1,18 +1,87 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type Config struct {
 	DatabaseURL string
 	Port        string
+	GRPCPort    string
 	APIKey      string
+	CORS        CORSConfig
+
+	// How long a returned copy is held for the next reservation in line, and
+	// how often expired holds are released.
+	HoldDuration      time.Duration
+	HoldSweepInterval time.Duration
+
+	// Background job workers in this process, how often an idle worker polls
+	// the queue, and how long finished jobs and their results are kept.
+	JobWorkers      int
+	JobPollInterval time.Duration
+	JobRetention    time.Duration
+
+	// How often the webhook dispatcher sends new events and due retries, and
+	// whether subscribers may be on loopback, link-local or private addresses
+	WebhookInterval     time.Duration
+	WebhookAllowPrivate bool
+
+	// How long a stored Idempotency-Key response is replayed
+	IdempotencyTTL time.Duration
+
+	// Catalog reads are cached for CacheTTL, in Redis when CacheRedisURL is
+	// set and otherwise in memory, holding up to CacheSize entries.
+	CacheTTL      time.Duration
+	CacheSize     int
+	CacheRedisURL string
+
+	// How often a quiet /stores/{id}/events stream sends a heartbeat
+	EventsHeartbeat time.Duration
+}
+
+// CORSConfig controls which browser origins may call the /v1 API.
+// AllowedOrigins entries are exact origins ("https://app.example.com"),
+// wildcard subdomains ("https://*.example.com") or "*" for any origin.
+// AllowCredentials only applies to origins matched by an entry other than
+// "*", any origin is let in without credentials. Unset, AllowedOrigins is
+// "*" like the hardcoded policy it replaced, deploys should list their own.
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           int // seconds
 }
 
 func LoadConfig() Config {
 	return Config{
 		DatabaseURL: os.Getenv("DATABASE_URL"),
 		Port:        getEnvOrDefault("PORT", "8080"),
+		GRPCPort:    getEnvOrDefault("GRPC_PORT", "9090"),
 		APIKey:      getEnvOrDefault("API_KEY", "default-dev-key-123"),
+		CORS: CORSConfig{
+			AllowedOrigins:   getEnvList("CORS_ALLOWED_ORIGINS", []string{"*"}),
+			AllowedHeaders:   getEnvList("CORS_ALLOWED_HEADERS", []string{"Content-Type", "X-API-Key", "X-Staff-Token", "Idempotency-Key", "If-Match", "If-None-Match"}),
+			ExposedHeaders:   getEnvList("CORS_EXPOSED_HEADERS", []string{"Location", "X-Total-Count", "Idempotent-Replayed", "ETag", "Last-Modified"}),
+			AllowCredentials: getEnvBool("CORS_ALLOW_CREDENTIALS", false),
+			MaxAge:           getEnvInt("CORS_MAX_AGE", 600),
+		},
+		HoldDuration:        getEnvDuration("RESERVATION_HOLD_DURATION", 48*time.Hour),
+		HoldSweepInterval:   getEnvDuration("RESERVATION_SWEEP_INTERVAL", time.Minute),
+		JobWorkers:          getEnvInt("JOB_WORKERS", 2),
+		JobPollInterval:     getEnvDuration("JOB_POLL_INTERVAL", time.Second),
+		JobRetention:        getEnvDuration("JOB_RETENTION", 7*24*time.Hour),
+		WebhookInterval:     getEnvDuration("WEBHOOK_INTERVAL", 5*time.Second),
+		WebhookAllowPrivate: getEnvBool("WEBHOOK_ALLOW_PRIVATE_URLS", false),
+		IdempotencyTTL:      getEnvDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+		CacheTTL:            getEnvDuration("CACHE_TTL", 10*time.Minute),
+		CacheSize:           getEnvInt("CACHE_SIZE", 1000),
+		CacheRedisURL:       os.Getenv("CACHE_REDIS_URL"),
+		EventsHeartbeat:     getEnvDuration("EVENTS_HEARTBEAT_INTERVAL", 15*time.Second),
 	}
 }
 
@@ -22,3 +91,40 @@ func getEnvOrDefault(key, fallback string) string {
 	}
 	return fallback
 }
+
+// getEnvList splits a comma separated env var, dropping empty entries.
+func getEnvList(key string, fallback []string) []string {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	var list []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	if b, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return b
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) int {
+	if i, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return i
+	}
+	return fallback
+}
+
+// getEnvDuration parses Go durations like "48h" or "90s"
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(key)); err == nil && d > 0 {
+		return d
+	}
+	return fallback
+}
@@ -2,11 +2,16 @@ package customer
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/rstoltzm-profile/video-rental-api/internal/daterange"
+	"github.com/rstoltzm-profile/video-rental-api/internal/etag"
+	"github.com/rstoltzm-profile/video-rental-api/internal/export"
+	"github.com/rstoltzm-profile/video-rental-api/internal/pagination"
 )
 
 var validate = validator.New()
@@ -19,33 +24,103 @@ func NewHandler(service Service) *Handler {
 	return &Handler{service: service}
 }
 
+// writeError maps service errors to a status code, anything unknown is a 500
+// with the fallback message.
+func writeError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, ErrCustomerNotFound):
+		http.Error(w, "Customer not found", http.StatusNotFound)
+	case errors.Is(err, ErrUnknownCity), errors.Is(err, ErrAmbiguousCity), errors.Is(err, ErrInvalidStore):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrDuplicateEmail):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, etag.ErrPreconditionFailed):
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+	default:
+		http.Error(w, fallback, http.StatusInternalServerError)
+	}
+}
+
 // GetCustomers godoc
-// @Summary      List customers
-// @Description  get all customers
+// @Summary      List and search customers
+// @Description  Page through customers, best match first when q is set. Matches name, email and address by prefix, phone digits anywhere, and names/emails by similarity for typos. Total matches are in X-Total-Count. CSV and NDJSON exports stream every match unless limit is given.
 // @Tags         customers
-// @Produce      json
+// @Produce      json,text/csv,application/x-ndjson
+// @Param        q                 query  string  false  "Name, email, phone or address to search for"
+// @Param        store_id          query  int     false  "Only customers of this store"
+// @Param        active            query  string  false  "true (default), false or all"
+// @Param        include_inactive  query  bool    false  "Same as active=all"
+// @Param        limit             query  int     false  "Page size, default 20, max 100, exports default to every match"
+// @Param        offset            query  int     false  "Rows to skip"
 // @Success      200  {array}  customer.Customer
+// @Failure      400  {string}  string  "Invalid query parameter"
 // @Security     ApiKeyAuth
 // @Router       /v1/customers [get]
 func (h *Handler) GetCustomers(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	customers, err := h.service.GetCustomers(r.Context())
+	format := export.Negotiate(r)
+	filter, err := parseCustomerFilter(r, format)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	customers, total, err := h.service.GetCustomers(r.Context(), filter)
 	if err != nil {
 		http.Error(w, "Failed to fetch customers", http.StatusInternalServerError)
 		return
 	}
 
-	json.NewEncoder(w).Encode(customers)
+	pagination.WriteTotal(w, total)
+	export.Stream(w, format, customers, func(err error) {
+		http.Error(w, "Failed to fetch customers", http.StatusInternalServerError)
+	})
+}
+
+func parseCustomerFilter(r *http.Request, format export.Format) (CustomerFilter, error) {
+	q := r.URL.Query()
+	filter := CustomerFilter{Query: q.Get("q")}
+
+	if v := q.Get("store_id"); v != "" {
+		storeID, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, fmt.Errorf("Invalid store_id")
+		}
+		filter.StoreID = &storeID
+	}
+
+	status := q.Get("active")
+	if q.Get("include_inactive") == "true" {
+		status = "all"
+	}
+	switch status {
+	case "", "true", "false":
+		active := status != "false"
+		filter.Active = &active
+	case "all":
+	default:
+		return filter, fmt.Errorf("active must be true, false or all")
+	}
+
+	page, err := export.Page(r, format)
+	if err != nil {
+		return filter, err
+	}
+	filter.Page = page
+
+	return filter, nil
 }
 
 // GetCustomerByID godoc
 // @Summary      Get customer by ID
-// @Description  Get a customer by their ID
+// @Description  Get a customer by their ID. The ETag is the version to send in If-Match when changing the customer, If-None-Match with it answers 304 while the customer is unchanged.
 // @Tags         customers
 // @Produce      json
-// @Param        id   path      int  true  "Customer ID"
+// @Param        id             path      int     true   "Customer ID"
+// @Param        If-None-Match  header    string  false  "ETag of the copy the client has"
 // @Success      200  {object}  customer.Customer
+// @Success      304  {string}  string  "Not Modified"
 // @Failure      400  {string}  string  "Invalid customer ID"
 // @Failure      404  {string}  string  "Customer not found"
 // @Security     ApiKeyAuth
@@ -60,7 +135,10 @@ func (h *Handler) GetCustomerByID(w http.ResponseWriter, r *http.Request) {
 	}
 	customer, err := h.service.GetCustomerByID(r.Context(), id)
 	if err != nil {
-		http.Error(w, "Failed to fetch customers", http.StatusInternalServerError)
+		writeError(w, err, "Failed to fetch customers")
+		return
+	}
+	if etag.NotModified(w, r, customer.LastUpdate) {
 		return
 	}
 
@@ -76,6 +154,7 @@ func (h *Handler) GetCustomerByID(w http.ResponseWriter, r *http.Request) {
 // @Param        customer  body      customer.CreateCustomerRequest  true  "Customer data"
 // @Success      201  {object}  customer.Customer
 // @Failure      400  {string}  string  "Invalid input"
+// @Failure      409  {string}  string  "Email already belongs to a customer"
 // @Failure      500  {string}  string  "Failed to create customer"
 // @Security     ApiKeyAuth
 // @Router       /v1/customers [post]
@@ -97,21 +176,82 @@ func (h *Handler) CreateCustomer(w http.ResponseWriter, r *http.Request) {
 
 	customer, err := h.service.CreateCustomer(r.Context(), req)
 	if err != nil {
-		http.Error(w, "Failed to create customer", http.StatusInternalServerError)
+		writeError(w, err, "Failed to create customer")
 		return
 	}
 	w.Header().Set("Location", fmt.Sprintf("/v1/customers/%d", customer.ID))
+	etag.Set(w, customer.LastUpdate)
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(customer)
 }
 
+// UpdateCustomer godoc
+// @Summary      Update customer
+// @Description  Partially update a customer's names, email, store and address
+// @Tags         customers
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int                             true  "Customer ID"
+// @Param        If-Match  header    string                          true  "ETag from GET /v1/customers/{id}"
+// @Param        customer  body      customer.UpdateCustomerRequest  true  "Fields to change"
+// @Success      200  {object}  customer.Customer
+// @Failure      400  {string}  string  "Invalid input"
+// @Failure      404  {string}  string  "Customer not found"
+// @Failure      409  {string}  string  "Email already belongs to a customer"
+// @Failure      412  {string}  string  "Customer changed since it was read"
+// @Failure      428  {string}  string  "If-Match is required"
+// @Failure      500  {string}  string  "Failed to update customer"
+// @Security     ApiKeyAuth
+// @Router       /v1/customers/{id} [patch]
+func (h *Handler) UpdateCustomer(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+	match, err := etag.IfMatch(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusPreconditionRequired)
+		return
+	}
+
+	var req UpdateCustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		http.Error(w, fmt.Sprintf("Validation error: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.IsEmpty() {
+		http.Error(w, "Validation error: no fields to update", http.StatusBadRequest)
+		return
+	}
+
+	customer, err := h.service.UpdateCustomer(r.Context(), id, match, req)
+	if err != nil {
+		writeError(w, err, "Failed to update customer")
+		return
+	}
+	etag.Set(w, customer.LastUpdate)
+
+	json.NewEncoder(w).Encode(customer)
+}
+
 // DeleteCustomerByID godoc
-// @Summary      Delete customer
-// @Description  Delete a customer by their ID
+// @Summary      Deactivate customer
+// @Description  Soft delete a customer by their ID, rental and payment history is kept
 // @Tags         customers
-// @Param        id   path      int  true  "Customer ID"
+// @Param        id        path      int     true  "Customer ID"
+// @Param        If-Match  header    string  true  "ETag from GET /v1/customers/{id}"
 // @Success      204  {string}  string  "No Content"
 // @Failure      400  {string}  string  "Invalid customer ID"
+// @Failure      404  {string}  string  "Customer not found"
+// @Failure      412  {string}  string  "Customer changed since it was read"
+// @Failure      428  {string}  string  "If-Match is required"
 // @Failure      500  {string}  string  "Failed to delete customer"
 // @Security     ApiKeyAuth
 // @Router       /v1/customers/{id} [delete]
@@ -123,21 +263,71 @@ func (h *Handler) DeleteCustomerByID(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
 		return
 	}
-	err = h.service.DeleteCustomerByID(r.Context(), id)
+	match, err := etag.IfMatch(r)
 	if err != nil {
-		http.Error(w, "Failed to delete customer", http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusPreconditionRequired)
+		return
+	}
+	err = h.service.DeleteCustomerByID(r.Context(), id, match)
+	if err != nil {
+		writeError(w, err, "Failed to delete customer")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent) // 204
 }
 
+// ReactivateCustomerByID godoc
+// @Summary      Reactivate customer
+// @Description  Reactivate a soft deleted customer
+// @Tags         customers
+// @Produce      json
+// @Param        id        path      int     true  "Customer ID"
+// @Param        If-Match  header    string  true  "ETag from GET /v1/customers/{id}"
+// @Success      200  {object}  customer.Customer
+// @Failure      400  {string}  string  "Invalid customer ID"
+// @Failure      404  {string}  string  "Customer not found"
+// @Failure      412  {string}  string  "Customer changed since it was read"
+// @Failure      428  {string}  string  "If-Match is required"
+// @Failure      500  {string}  string  "Failed to reactivate customer"
+// @Security     ApiKeyAuth
+// @Router       /v1/customers/{id}/reactivate [post]
+func (h *Handler) ReactivateCustomerByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+	match, err := etag.IfMatch(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusPreconditionRequired)
+		return
+	}
+
+	customer, err := h.service.ReactivateCustomerByID(r.Context(), id, match)
+	if err != nil {
+		writeError(w, err, "Failed to reactivate customer")
+		return
+	}
+	etag.Set(w, customer.LastUpdate)
+
+	json.NewEncoder(w).Encode(customer)
+}
+
 // GetCustomerRentalsByID godoc
 // @Summary      Get Customer Rentals
-// @Description  Get Customer Rentals By ID
+// @Description  Rental history for a customer, newest first. Total matches are in X-Total-Count.
 // @Tags         customers
-// @Param        id   path      int  true  "Customer ID"
-// @Success      200  {object}  customer.Customer
+// @Produce      json
+// @Param        id      path      int     true   "Customer ID"
+// @Param        status  query     string  false  "open (default), returned, late or all"
+// @Param        late    query     bool    false  "Same as status=late"
+// @Param        from    query     string  false  "Rented on or after, YYYY-MM-DD or RFC3339"
+// @Param        to      query     string  false  "Rented on or before, YYYY-MM-DD or RFC3339"
+// @Param        limit   query     int     false  "Page size, default 20, max 100"
+// @Param        offset  query     int     false  "Rows to skip"
+// @Success      200  {array}   customer.CustomerRentals
 // @Failure      400  {string}  string  "Invalid customer ID"
 // @Failure      404  {string}  string  "Customer not found"
 // @Security     ApiKeyAuth
@@ -147,25 +337,80 @@ func (h *Handler) GetCustomerRentalsByID(w http.ResponseWriter, r *http.Request)
 
 	// Parameters
 	idStr := r.PathValue("id")
-	late := r.URL.Query().Get("late")
-	var customerRentals []CustomerRentals
-
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
 		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
 		return
 	}
 
-	if late == "true" {
-		customerRentals, err = h.service.GetLateCustomerRentalsByID(r.Context(), id)
-	} else {
-		customerRentals, err = h.service.GetCustomerRentalsByID(r.Context(), id)
+	filter, err := parseRentalHistoryFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
+	customerRentals, total, err := h.service.GetCustomerRentalsByID(r.Context(), id, filter)
 	if err != nil {
-		http.Error(w, "Failed to fetch customers", http.StatusInternalServerError)
+		writeError(w, err, "Failed to fetch customers")
 		return
 	}
 
+	pagination.WriteTotal(w, total)
+	if customerRentals == nil {
+		customerRentals = []CustomerRentals{}
+	}
 	json.NewEncoder(w).Encode(customerRentals)
 }
+
+func parseRentalHistoryFilter(r *http.Request) (RentalHistoryFilter, error) {
+	q := r.URL.Query()
+	filter := RentalHistoryFilter{Status: q.Get("status")}
+
+	if q.Get("late") == "true" {
+		filter.Status = RentalStatusLate
+	}
+	switch filter.Status {
+	case "":
+		filter.Status = RentalStatusOpen
+	case RentalStatusAll, RentalStatusOpen, RentalStatusReturned, RentalStatusLate:
+	default:
+		return filter, fmt.Errorf("status must be all, open, returned or late")
+	}
+
+	rng, err := daterange.Parse(r)
+	if err != nil {
+		return filter, err
+	}
+	filter.From, filter.To = rng.From, rng.To
+
+	filter.Page, err = pagination.Parse(r)
+	return filter, err
+}
+
+// GetCustomerSummaryByID godoc
+// @Summary      Get Customer Summary
+// @Description  Lifetime rentals, total spend, favorite categories and outstanding balance. Balance is rental fees plus $1 per day late minus payments.
+// @Tags         customers
+// @Produce      json
+// @Param        id   path      int  true  "Customer ID"
+// @Success      200  {object}  customer.CustomerSummary
+// @Failure      400  {string}  string  "Invalid customer ID"
+// @Failure      404  {string}  string  "Customer not found"
+// @Security     ApiKeyAuth
+// @Router       /v1/customers/{id}/summary [get]
+func (h *Handler) GetCustomerSummaryByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	summary, err := h.service.GetCustomerSummaryByID(r.Context(), id)
+	if err != nil {
+		writeError(w, err, "Failed to fetch customer summary")
+		return
+	}
+
+	json.NewEncoder(w).Encode(summary)
+}
@@ -1,12 +1,29 @@
 package customer
 
-import "time"
+import (
+	"time"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/pagination"
+)
 
 type Customer struct {
-	ID        int    `json:"id"`
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Email     string `json:"email"`
+	ID         int       `json:"id"`
+	FirstName  string    `json:"first_name"`
+	LastName   string    `json:"last_name"`
+	Email      string    `json:"email"`
+	Phone      string    `json:"phone"`
+	StoreID    int       `json:"store_id"`
+	Active     bool      `json:"active"`
+	LastUpdate time.Time `json:"last_update"`
+}
+
+// CustomerFilter narrows the customer list. Query matches name, email, phone
+// and address by prefix or similarity and orders results by best match.
+type CustomerFilter struct {
+	Query   string
+	StoreID *int
+	Active  *bool // nil returns active and inactive customers
+	Page    pagination.Page
 }
 
 type CreateCustomerRequest struct {
@@ -22,16 +39,78 @@ type AddressInput struct {
 	Address2   string `json:"address2" validate:"max=100"`
 	District   string `json:"district" validate:"required,min=1,max=50"`
 	CityName   string `json:"city_name" validate:"required,min=1,max=50"`
+	Country    string `json:"country" validate:"omitempty,min=2,max=50"`
 	PostalCode string `json:"postal_code" validate:"required,min=4,max=6"`
 	Phone      string `json:"phone" validate:"required,e164"`
 }
 
+// UpdateCustomerRequest is a partial update, nil fields are left unchanged.
+type UpdateCustomerRequest struct {
+	FirstName *string        `json:"first_name" validate:"omitempty,min=1,max=50"`
+	LastName  *string        `json:"last_name" validate:"omitempty,min=1,max=50"`
+	Email     *string        `json:"email" validate:"omitempty,email"`
+	StoreID   *int           `json:"store_id" validate:"omitempty,gt=0"`
+	Address   *AddressUpdate `json:"address" validate:"omitempty"`
+}
+
+type AddressUpdate struct {
+	Address    *string `json:"address" validate:"omitempty,min=1,max=100"`
+	Address2   *string `json:"address2" validate:"omitempty,max=100"`
+	District   *string `json:"district" validate:"omitempty,min=1,max=50"`
+	CityName   *string `json:"city_name" validate:"required_with=Country,omitempty,min=1,max=50"`
+	Country    *string `json:"country" validate:"omitempty,min=2,max=50"`
+	PostalCode *string `json:"postal_code" validate:"omitempty,min=4,max=6"`
+	Phone      *string `json:"phone" validate:"omitempty,e164"`
+}
+
+func (r UpdateCustomerRequest) IsEmpty() bool {
+	return r.FirstName == nil && r.LastName == nil && r.Email == nil && r.StoreID == nil && r.Address == nil
+}
+
 type CustomerRentals struct {
-	FirstName     string    `json:"first_name"`
-	LastName      string    `json:"last_name"`
-	Phone         string    `json:"phone"`
-	RentalDate    time.Time `json:"rental_date"`
-	Title         string    `json:"title"`
-	RentalDueDate time.Time `json:"rental_due_date"`
-	Overdue       bool      `json:"overdue"`
+	RentalID      int        `json:"rental_id"`
+	InventoryID   int        `json:"inventory_id"`
+	FilmID        int        `json:"film_id"`
+	StoreID       int        `json:"store_id"`
+	FirstName     string     `json:"first_name"`
+	LastName      string     `json:"last_name"`
+	Phone         string     `json:"phone"`
+	RentalDate    time.Time  `json:"rental_date"`
+	Title         string     `json:"title"`
+	RentalDueDate time.Time  `json:"rental_due_date"`
+	ReturnDate    *time.Time `json:"return_date"`
+	Overdue       bool       `json:"overdue"`
+}
+
+const (
+	RentalStatusAll      = "all"
+	RentalStatusOpen     = "open"
+	RentalStatusReturned = "returned"
+	RentalStatusLate     = "late"
+)
+
+// RentalHistoryFilter selects a customer's rentals. From and To bound the
+// rental date, To is exclusive.
+type RentalHistoryFilter struct {
+	Status string
+	From   *time.Time
+	To     *time.Time
+	Page   pagination.Page
+}
+
+type CustomerSummary struct {
+	CustomerID         int             `json:"customer_id"`
+	LifetimeRentals    int             `json:"lifetime_rentals"`
+	OpenRentals        int             `json:"open_rentals"`
+	LateRentals        int             `json:"late_rentals"`
+	FirstRentalDate    *time.Time      `json:"first_rental_date"`
+	LastRentalDate     *time.Time      `json:"last_rental_date"`
+	TotalSpend         float64         `json:"total_spend"`
+	OutstandingBalance float64         `json:"outstanding_balance"`
+	FavoriteCategories []CategoryCount `json:"favorite_categories"`
+}
+
+type CategoryCount struct {
+	Category string `json:"category"`
+	Rentals  int    `json:"rentals"`
 }
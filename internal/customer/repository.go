@@ -2,24 +2,110 @@ package customer
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"iter"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rstoltzm-profile/video-rental-api/internal/db"
 )
 
+// customerSelect reads customers with their address phone. Queries that
+// return updated rows alias their CTE as c and reuse the same join.
+const customerSelect = `
+	SELECT c.customer_id, c.first_name, c.last_name, c.email, COALESCE(a.phone, ''), c.store_id, c.activebool, c.last_update
+	FROM %s c
+	LEFT JOIN address a ON a.address_id = c.address_id
+`
+
+// customerCandidates narrows a search down to the customers that can score
+// 0.3. The name and email conditions are answered from the gin trigram
+// indexes, <% needs a word similarity of pg_trgm.word_similarity_threshold,
+// 0.5 for every connection (see db.ConnectPool), the lowest a name can have
+// and still score 0.3. Phone and address matches come from address, which
+// has no trigram index.
+const customerCandidates = `
+	candidates AS (
+		SELECT customer_id FROM customer
+		WHERE lower(first_name || ' ' || last_name) LIKE '%' || $2 || '%'
+			OR lower(email) LIKE $2 || '%'
+			OR $1 <% lower(first_name || ' ' || last_name)
+			OR $1 <% lower(email)
+		UNION
+		SELECT c.customer_id FROM customer c
+		JOIN address a ON a.address_id = c.address_id
+		WHERE ($3 <> '' AND regexp_replace(a.phone, '\D', '', 'g') LIKE '%' || $3 || '%')
+			OR lower(a.address) LIKE '%' || $2 || '%'
+			OR lower(a.postal_code) LIKE $2 || '%'
+	),`
+
+// customerMatches scores each customer against the search text:
+// exact email 1.0, name/email prefix 0.8-0.9, phone/address 0.6-0.7, then
+// pg_trgm word similarity for typos. $1 search text, $2 LIKE-escaped search
+// text, $3 phone digits, $4 store, $5 active. %s is where the customers come
+// from, all of them or the candidates of a search.
+const customerMatches = `
+	matches AS (
+		SELECT c.customer_id, c.first_name, c.last_name, c.email, COALESCE(a.phone, '') AS phone, c.store_id, c.activebool, c.last_update,
+			CASE WHEN $1 = '' THEN 0 ELSE GREATEST(
+				CASE
+					WHEN lower(c.email) = $1 THEN 1.0
+					WHEN lower(c.first_name || ' ' || c.last_name) LIKE $2 || '%' THEN 0.9
+					WHEN lower(c.first_name) LIKE $2 || '%' OR lower(c.last_name) LIKE $2 || '%' THEN 0.8
+					WHEN lower(c.email) LIKE $2 || '%' THEN 0.8
+					WHEN $3 <> '' AND regexp_replace(a.phone, '\D', '', 'g') LIKE '%' || $3 || '%' THEN 0.7
+					WHEN lower(a.address) LIKE '%' || $2 || '%' OR lower(a.postal_code) LIKE $2 || '%' THEN 0.6
+					ELSE 0
+				END,
+				word_similarity($1, lower(c.first_name || ' ' || c.last_name)) * 0.6,
+				word_similarity($1, lower(c.email)) * 0.5
+			) END AS score
+		FROM %s
+		LEFT JOIN address a ON a.address_id = c.address_id
+		WHERE ($4::int IS NULL OR c.store_id = $4)
+			AND ($5::bool IS NULL OR c.activebool = $5)
+	)
+`
+
+// customerSearch is the WITH clause that defines matches for filter, a
+// search only scores its candidates
+func customerSearch(filter CustomerFilter) string {
+	if searchText(filter) == "" {
+		return `WITH ` + strings.Replace(customerMatches, "%s", "customer c", 1)
+	}
+	return `WITH ` + customerCandidates +
+		strings.Replace(customerMatches, "%s", "candidates JOIN customer c USING (customer_id)", 1)
+}
+
+const customerSearchMatches = ` FROM matches WHERE $1 = '' OR score >= 0.3`
+
 type CustomerReader interface {
-	GetAll(ctx context.Context) ([]Customer, error)
+	CountCustomers(ctx context.Context, filter CustomerFilter) (int, error)
+	EachCustomer(ctx context.Context, filter CustomerFilter) iter.Seq2[Customer, error]
 	GetByID(ctx context.Context, id int) (Customer, error)
-	GetCityIDByName(ctx context.Context, cityName string) (int, error)
-	FindCustomerRentalsByID(ctx context.Context, id int) ([]CustomerRentals, error)
-	FindLateCustomerRentalsByID(ctx context.Context, id int) ([]CustomerRentals, error)
+	GetByIDs(ctx context.Context, ids []int) ([]Customer, error)
+	FindCityIDs(ctx context.Context, cityName string, countryID *int) ([]int, error)
+	GetCountryIDByName(ctx context.Context, country string) (int, error)
+	EmailExists(ctx context.Context, email string, excludeID int) (bool, error)
+	FindCustomerRentalsByID(ctx context.Context, id int, filter RentalHistoryFilter) ([]CustomerRentals, int, error)
+	FindCustomerSummaryByID(ctx context.Context, id int) (CustomerSummary, error)
 }
 
 type CustomerWriter interface {
+	InsertCountry(ctx context.Context, country string) (int, error)
+	InsertCity(ctx context.Context, cityName string, countryID int) (int, error)
 	InsertAddress(ctx context.Context, address AddressInput, cityID int) (int, error)
 	InsertCustomer(ctx context.Context, req CreateCustomerRequest, addressID int) (*Customer, error)
+	UpdateCustomer(ctx context.Context, id int, req UpdateCustomerRequest) (Customer, error)
+	UpdateCustomerAddress(ctx context.Context, customerID int, address AddressUpdate, cityID *int) error
 	DeleteCustomerByID(ctx context.Context, id int) error
+	ReactivateCustomerByID(ctx context.Context, id int) (Customer, error)
+	LockCustomer(ctx context.Context, id int) (time.Time, error)
 }
 
 type Repository interface {
@@ -30,6 +116,8 @@ type Repository interface {
 
 type TransactionManager interface {
 	BeginTx(ctx context.Context) (pgx.Tx, error)
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+	Lock(ctx context.Context, key string) error
 }
 
 type repository struct {
@@ -44,51 +132,153 @@ func (r *repository) BeginTx(ctx context.Context) (pgx.Tx, error) {
 	return r.pool.Begin(ctx)
 }
 
-func (r *repository) GetAll(ctx context.Context) ([]Customer, error) {
-	rows, err := r.pool.Query(ctx, `SELECT customer_id, first_name, last_name, email FROM customer`)
+func (r *repository) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return db.WithTx(ctx, r.pool, fn)
+}
+
+// Lock takes a transaction scoped advisory lock, serializing check-then-insert
+// steps on the same key until the transaction started by WithTx ends.
+func (r *repository) Lock(ctx context.Context, key string) error {
+	return db.Lock(ctx, r.conn(ctx), key)
+}
+
+// conn returns the transaction started by WithTx, or the pool
+func (r *repository) conn(ctx context.Context) db.DBTX {
+	return db.Conn(ctx, r.pool)
+}
+
+func scanCustomer(row pgx.Row) (Customer, error) {
+	var c Customer
+	err := row.Scan(&c.ID, &c.FirstName, &c.LastName, &c.Email, &c.Phone, &c.StoreID, &c.Active, &c.LastUpdate)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c, ErrCustomerNotFound
+	}
+	return c, err
+}
+
+// CountCustomers returns the number of customers matching filter, ignoring
+// the page.
+func (r *repository) CountCustomers(ctx context.Context, filter CustomerFilter) (int, error) {
+	var total int
+	err := r.conn(ctx).QueryRow(ctx, customerSearch(filter)+`SELECT count(*)`+customerSearchMatches, searchArgs(filter)...).Scan(&total)
+	return total, err
+}
+
+// EachCustomer iterates the customers matching filter, best match first. A
+// zero page limit returns every match.
+func (r *repository) EachCustomer(ctx context.Context, filter CustomerFilter) iter.Seq2[Customer, error] {
+	query := customerSearch(filter) +
+		`SELECT customer_id, first_name, last_name, email, phone, store_id, activebool, last_update` +
+		customerSearchMatches + `
+		ORDER BY score DESC, customer_id
+		LIMIT NULLIF($6, 0) OFFSET $7
+	`
+	return db.Seq(ctx, r.conn(ctx), func(row pgx.CollectableRow) (Customer, error) {
+		return scanCustomer(row)
+	}, query, append(searchArgs(filter), filter.Page.Limit, filter.Page.Offset)...)
+}
+
+func searchText(filter CustomerFilter) string {
+	return strings.ToLower(strings.TrimSpace(filter.Query))
+}
+
+// searchArgs are $1 to $5 of customerSearch
+func searchArgs(filter CustomerFilter) []any {
+	q := searchText(filter)
+	return []any{q, escapeLike(q), phoneDigits(q), filter.StoreID, filter.Active}
+}
+
+func (r *repository) GetByID(ctx context.Context, id int) (Customer, error) {
+	query := fmt.Sprintf(customerSelect, `customer`) + ` WHERE c.customer_id = $1`
+	return scanCustomer(r.conn(ctx).QueryRow(ctx, query, id))
+}
+
+// GetByIDs loads many customers in one query, ids that don't exist are left
+// out.
+func (r *repository) GetByIDs(ctx context.Context, ids []int) ([]Customer, error) {
+	query := fmt.Sprintf(customerSelect, `customer`) + ` WHERE c.customer_id = ANY($1)`
+	rows, err := r.conn(ctx).Query(ctx, query, ids)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (Customer, error) {
+		return scanCustomer(row)
+	})
+}
 
-	var customers []Customer
-	for rows.Next() {
-		var c Customer
-		if err := rows.Scan(&c.ID, &c.FirstName, &c.LastName, &c.Email); err != nil {
-			return nil, err
+// escapeLike escapes LIKE wildcards in user input
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// phoneDigits returns the digits of a search that looks like part of a phone
+// number, or "" so short numbers don't match every phone.
+func phoneDigits(s string) string {
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
 		}
-		customers = append(customers, c)
+		return -1
+	}, s)
+	if len(digits) < 4 {
+		return ""
 	}
-	return customers, nil
+	return digits
 }
 
-func (r *repository) GetByID(ctx context.Context, id int) (Customer, error) {
-	var c Customer
-	err := r.pool.QueryRow(ctx,
-		`SELECT customer_id, first_name, last_name, email FROM customer WHERE customer_id = $1`,
-		id,
-	).Scan(&c.ID, &c.FirstName, &c.LastName, &c.Email)
+// FindCityIDs matches city names case-insensitively, optionally within one
+// country. Pagila has cities with the same name in different countries.
+func (r *repository) FindCityIDs(ctx context.Context, cityName string, countryID *int) ([]int, error) {
+	rows, err := r.conn(ctx).Query(ctx, `
+		SELECT city_id FROM city
+		WHERE lower(city) = lower($1) AND ($2::int IS NULL OR country_id = $2)
+		ORDER BY city_id
+	`, cityName, countryID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[int])
+}
 
-	return c, err
+func (r *repository) GetCountryIDByName(ctx context.Context, country string) (int, error) {
+	var countryID int
+	err := r.conn(ctx).QueryRow(ctx,
+		`SELECT country_id FROM country WHERE lower(country) = lower($1) ORDER BY country_id LIMIT 1`,
+		country,
+	).Scan(&countryID)
+	return countryID, err
 }
 
-func (r *repository) GetCityIDByName(ctx context.Context, cityName string) (int, error) {
-	var cityID int
-	err := r.pool.QueryRow(ctx,
-		`SELECT city_id FROM city where city = $1`,
-		cityName,
-	).Scan(&cityID)
+func (r *repository) EmailExists(ctx context.Context, email string, excludeID int) (bool, error) {
+	var exists bool
+	err := r.conn(ctx).QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM customer WHERE lower(email) = lower($1) AND customer_id <> $2)`,
+		email, excludeID,
+	).Scan(&exists)
+	return exists, err
+}
 
-	if err != nil {
-		return 0, err
-	}
+func (r *repository) InsertCountry(ctx context.Context, country string) (int, error) {
+	var id int
+	err := r.conn(ctx).QueryRow(ctx,
+		`INSERT INTO country (country, last_update) VALUES ($1, CURRENT_TIMESTAMP) RETURNING country_id`,
+		country,
+	).Scan(&id)
+	return id, err
+}
 
-	return cityID, err
+func (r *repository) InsertCity(ctx context.Context, cityName string, countryID int) (int, error) {
+	var id int
+	err := r.conn(ctx).QueryRow(ctx,
+		`INSERT INTO city (city, country_id, last_update) VALUES ($1, $2, CURRENT_TIMESTAMP) RETURNING city_id`,
+		cityName, countryID,
+	).Scan(&id)
+	return id, err
 }
 
 func (r *repository) InsertAddress(ctx context.Context, address AddressInput, cityID int) (int, error) {
 	var id int
-	err := r.pool.QueryRow(ctx, `
+	err := r.conn(ctx).QueryRow(ctx, `
 	INSERT INTO address (address, address2, district, city_id, postal_code, phone)
 	VALUES ($1, $2, $3, $4, $5, $6)
 	RETURNING address_id
@@ -105,55 +295,143 @@ func (r *repository) InsertAddress(ctx context.Context, address AddressInput, ci
 
 func (r *repository) InsertCustomer(ctx context.Context, req CreateCustomerRequest, addressID int) (*Customer, error) {
 	var id int
+	var lastUpdate time.Time
 	query := `
 		INSERT INTO customer (store_id, first_name, last_name, email, address_id, activebool, create_date, last_update, active)
 		VALUES ($1, $2, $3, $4, $5, TRUE, CURRENT_DATE, CURRENT_TIMESTAMP, 1)
-		RETURNING customer_id
+		RETURNING customer_id, last_update
 	`
-	err := r.pool.QueryRow(ctx, query,
+	err := r.conn(ctx).QueryRow(ctx, query,
 		req.StoreID,
 		req.FirstName,
 		req.LastName,
 		req.Email,
 		addressID,
-	).Scan(&id)
+	).Scan(&id, &lastUpdate)
 
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" { // foreign_key_violation
+		return nil, ErrInvalidStore
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	return &Customer{
-		ID:        id,
-		FirstName: req.FirstName,
-		LastName:  req.LastName,
-		Email:     req.Email,
+		ID:         id,
+		FirstName:  req.FirstName,
+		LastName:   req.LastName,
+		Email:      req.Email,
+		Phone:      req.Address.Phone,
+		StoreID:    req.StoreID,
+		Active:     true,
+		LastUpdate: lastUpdate,
 	}, nil
 }
 
+func (r *repository) UpdateCustomer(ctx context.Context, id int, req UpdateCustomerRequest) (Customer, error) {
+	query := `
+		WITH updated AS (
+			UPDATE customer SET
+				first_name = COALESCE($2, first_name),
+				last_name = COALESCE($3, last_name),
+				email = COALESCE($4, email),
+				store_id = COALESCE($5, store_id),
+				last_update = CURRENT_TIMESTAMP
+			WHERE customer_id = $1
+			RETURNING *
+		)` + fmt.Sprintf(customerSelect, `updated`)
+	c, err := scanCustomer(r.conn(ctx).QueryRow(ctx, query,
+		id,
+		req.FirstName,
+		req.LastName,
+		req.Email,
+		req.StoreID,
+	))
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" { // foreign_key_violation
+		return c, ErrInvalidStore
+	}
+	return c, err
+}
+
+func (r *repository) UpdateCustomerAddress(ctx context.Context, customerID int, address AddressUpdate, cityID *int) error {
+	query := `
+		UPDATE address SET
+			address = COALESCE($2, address),
+			address2 = COALESCE($3, address2),
+			district = COALESCE($4, district),
+			city_id = COALESCE($5, city_id),
+			postal_code = COALESCE($6, postal_code),
+			phone = COALESCE($7, phone),
+			last_update = CURRENT_TIMESTAMP
+		WHERE address_id = (SELECT address_id FROM customer WHERE customer_id = $1)
+	`
+	cmdTag, err := r.conn(ctx).Exec(ctx, query,
+		customerID,
+		address.Address,
+		address.Address2,
+		address.District,
+		cityID,
+		address.PostalCode,
+		address.Phone,
+	)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ErrCustomerNotFound
+	}
+	return nil
+}
+
+// DeleteCustomerByID soft deletes a customer, rentals and payments keep
+// referencing the row.
 func (r *repository) DeleteCustomerByID(ctx context.Context, id int) error {
-	cmdTag, err := r.pool.Exec(ctx,
-		`DELETE FROM customer WHERE customer_id = $1`,
+	cmdTag, err := r.conn(ctx).Exec(ctx,
+		`UPDATE customer SET activebool = FALSE, active = 0, last_update = CURRENT_TIMESTAMP WHERE customer_id = $1`,
 		id,
 	)
 	if err != nil {
 		return err
 	}
 	if cmdTag.RowsAffected() == 0 {
-		return fmt.Errorf("no customer found with ID %d", id)
+		return fmt.Errorf("no customer found with ID %d: %w", id, ErrCustomerNotFound)
 	}
 	return nil
 }
 
-func (r *repository) FindCustomerRentalsByID(ctx context.Context, id int) ([]CustomerRentals, error) {
+func (r *repository) ReactivateCustomerByID(ctx context.Context, id int) (Customer, error) {
 	query := `
-	SELECT
-		customer.first_name,
-		customer.last_name, 
-		address.phone,
-		rental.rental_date,
-		film.title,
-		rental.rental_date + (film.rental_duration || ' days')::interval AS rental_due_date,
-		CURRENT_DATE > rental.rental_date + (film.rental_duration || ' days')::interval as overdue
+		WITH updated AS (
+			UPDATE customer SET activebool = TRUE, active = 1, last_update = CURRENT_TIMESTAMP
+			WHERE customer_id = $1
+			RETURNING *
+		)` + fmt.Sprintf(customerSelect, `updated`)
+	return scanCustomer(r.conn(ctx).QueryRow(ctx, query, id))
+}
+
+// LockCustomer locks the customer's row until the transaction ends and
+// returns its last_update, the version If-Match is checked against.
+func (r *repository) LockCustomer(ctx context.Context, id int) (time.Time, error) {
+	var lastUpdate time.Time
+	err := r.conn(ctx).QueryRow(ctx,
+		`SELECT last_update FROM customer WHERE customer_id = $1 FOR UPDATE`, id,
+	).Scan(&lastUpdate)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return lastUpdate, ErrCustomerNotFound
+	}
+	return lastUpdate, err
+}
+
+// lateRental is the one rule for a late rental, status=late selects exactly
+// the rentals flagged overdue
+var lateRental = db.IsLate("rental", "film")
+
+// customerRentalsFrom is shared by the history page and count queries.
+// $1 customer, $2 status, $3 from, $4 to.
+var customerRentalsFrom = `
 	FROM
 		rental
 		INNER JOIN customer ON rental.customer_id = customer.customer_id
@@ -161,66 +439,138 @@ func (r *repository) FindCustomerRentalsByID(ctx context.Context, id int) ([]Cus
 		INNER JOIN inventory ON rental.inventory_id = inventory.inventory_id
 		INNER JOIN film ON inventory.film_id = film.film_id
 	WHERE
-		rental.return_date IS NULL
-		and customer.customer_id = $1
+		customer.customer_id = $1
+		AND CASE $2
+			WHEN 'open' THEN rental.return_date IS NULL
+			WHEN 'returned' THEN rental.return_date IS NOT NULL
+			WHEN 'late' THEN ` + lateRental + `
+			ELSE TRUE
+		END
+		AND ($3::timestamptz IS NULL OR rental.rental_date >= $3)
+		AND ($4::timestamptz IS NULL OR rental.rental_date < $4)
+`
+
+// customerRentalsSelect is the history page's columns, overdue is lateRental
+var customerRentalsSelect = `
+	SELECT
+		rental.rental_id,
+		rental.inventory_id,
+		inventory.film_id,
+		inventory.store_id,
+		customer.first_name,
+		customer.last_name,
+		address.phone,
+		rental.rental_date,
+		film.title,
+		rental.rental_date + (film.rental_duration || ' days')::interval AS rental_due_date,
+		rental.return_date,
+		` + lateRental + ` AS overdue
+`
+
+func (r *repository) FindCustomerRentalsByID(ctx context.Context, id int, filter RentalHistoryFilter) ([]CustomerRentals, int, error) {
+	args := []any{id, filter.Status, filter.From, filter.To}
+
+	var total int
+	err := r.conn(ctx).QueryRow(ctx, `SELECT count(*)`+customerRentalsFrom, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := customerRentalsSelect + customerRentalsFrom + `
 	ORDER BY
-		rental.rental_date
+		rental.rental_date DESC
+	LIMIT $5 OFFSET $6
 	`
-	rows, err := r.pool.Query(ctx, query, id)
+	rows, err := r.conn(ctx).Query(ctx, query, append(args, filter.Page.Limit, filter.Page.Offset)...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
 	var customerRentals []CustomerRentals
 	for rows.Next() {
 		var c CustomerRentals
-		if err := rows.Scan(&c.FirstName, &c.LastName, &c.Phone, &c.RentalDate, &c.Title, &c.RentalDueDate, &c.Overdue); err != nil {
-			return nil, err
+		if err := rows.Scan(&c.RentalID, &c.InventoryID, &c.FilmID, &c.StoreID, &c.FirstName, &c.LastName, &c.Phone, &c.RentalDate, &c.Title, &c.RentalDueDate, &c.ReturnDate, &c.Overdue); err != nil {
+			return nil, 0, err
 		}
 		customerRentals = append(customerRentals, c)
 	}
-	fmt.Println(customerRentals)
-	return customerRentals, nil
+	return customerRentals, total, rows.Err()
 }
 
-func (r *repository) FindLateCustomerRentalsByID(ctx context.Context, id int) ([]CustomerRentals, error) {
+// FindCustomerSummaryByID totals a customer's rentals and payments. The
+// balance is rental fees plus $1 per day late (up to today for open rentals)
+// minus everything paid, the same rule as Pagila's get_customer_balance. Late
+// rentals are the ones status=late lists, returned or not.
+func (r *repository) FindCustomerSummaryByID(ctx context.Context, id int) (CustomerSummary, error) {
+	summary := CustomerSummary{CustomerID: id, FavoriteCategories: []CategoryCount{}}
 	query := `
+	WITH rentals AS (
+		SELECT
+			rental.rental_date,
+			rental.return_date,
+			film.rental_rate,
+			` + db.DaysLate("rental", "film") + ` AS days_late
+		FROM
+			rental
+			INNER JOIN inventory ON rental.inventory_id = inventory.inventory_id
+			INNER JOIN film ON inventory.film_id = film.film_id
+		WHERE
+			rental.customer_id = $1
+	), paid AS (
+		SELECT COALESCE(SUM(amount), 0) AS total FROM payment WHERE customer_id = $1
+	)
 	SELECT
-		customer.first_name,
-		customer.last_name, 
-		address.phone,
-		rental.rental_date,
-		film.title,
-		rental.rental_date + (film.rental_duration || ' days')::interval AS rental_due_date,
-		CURRENT_DATE > rental.rental_date + (film.rental_duration || ' days')::interval as overdue
+		COUNT(rentals.rental_date),
+		COUNT(rentals.rental_date) FILTER (WHERE rentals.return_date IS NULL),
+		COUNT(rentals.rental_date) FILTER (WHERE rentals.days_late > 0),
+		MIN(rentals.rental_date),
+		MAX(rentals.rental_date),
+		paid.total::float8,
+		(COALESCE(SUM(rentals.rental_rate + rentals.days_late), 0) - paid.total)::float8
+	FROM paid
+	LEFT JOIN rentals ON TRUE
+	GROUP BY paid.total
+	`
+	err := r.conn(ctx).QueryRow(ctx, query, id).Scan(
+		&summary.LifetimeRentals,
+		&summary.OpenRentals,
+		&summary.LateRentals,
+		&summary.FirstRentalDate,
+		&summary.LastRentalDate,
+		&summary.TotalSpend,
+		&summary.OutstandingBalance,
+	)
+	if err != nil {
+		return summary, err
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, `
+	SELECT
+		category.name,
+		COUNT(*) AS rentals
 	FROM
 		rental
-		INNER JOIN customer ON rental.customer_id = customer.customer_id
-		INNER JOIN address ON customer.address_id = address.address_id
 		INNER JOIN inventory ON rental.inventory_id = inventory.inventory_id
-		INNER JOIN film ON inventory.film_id = film.film_id
+		INNER JOIN film_category ON inventory.film_id = film_category.film_id
+		INNER JOIN category ON film_category.category_id = category.category_id
 	WHERE
-		rental.return_date IS NULL
-		and customer.customer_id = $1
-		and CURRENT_DATE > rental.rental_date + (film.rental_duration || ' days')::interval
-	ORDER BY
-		rental.rental_date
-	`
-	rows, err := r.pool.Query(ctx, query, id)
+		rental.customer_id = $1
+	GROUP BY category.name
+	ORDER BY rentals DESC, category.name
+	LIMIT 3
+	`, id)
 	if err != nil {
-		return nil, err
+		return summary, err
 	}
 	defer rows.Close()
 
-	var customerRentals []CustomerRentals
 	for rows.Next() {
-		var c CustomerRentals
-		if err := rows.Scan(&c.FirstName, &c.LastName, &c.Phone, &c.RentalDate, &c.Title, &c.RentalDueDate, &c.Overdue); err != nil {
-			return nil, err
+		var c CategoryCount
+		if err := rows.Scan(&c.Category, &c.Rentals); err != nil {
+			return summary, err
 		}
-		customerRentals = append(customerRentals, c)
+		summary.FavoriteCategories = append(summary.FavoriteCategories, c)
 	}
-	fmt.Println(customerRentals)
-	return customerRentals, nil
+	return summary, rows.Err()
 }
@@ -0,0 +1,24 @@
+package customer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhoneDigits(t *testing.T) {
+	assert.Equal(t, "15551234", phoneDigits("+1 (555) 1234"))
+	assert.Equal(t, "", phoneDigits("smith"))
+	assert.Equal(t, "", phoneDigits("r2d2")) // too short to be a phone search
+}
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, `100\%\_off\\`, escapeLike(`100%_off\`))
+}
+
+func TestCustomerRentals_LateFilterMatchesOverdueFlag(t *testing.T) {
+	assert.Contains(t, customerRentalsFrom, "WHEN 'late' THEN "+lateRental+"\n")
+	assert.Contains(t, customerRentalsSelect, lateRental+" AS overdue\n")
+	// returned rentals count, not only open ones
+	assert.NotContains(t, lateRental, "IS NULL")
+}
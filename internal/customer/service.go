@@ -2,16 +2,33 @@ package customer
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"iter"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rstoltzm-profile/video-rental-api/internal/etag"
+)
+
+var (
+	ErrCustomerNotFound = errors.New("customer not found")
+	ErrUnknownCity      = errors.New("unknown city name, add a country to create it")
+	ErrAmbiguousCity    = errors.New("city name exists in more than one country, add a country")
+	ErrInvalidStore     = errors.New("invalid store id")
+	ErrDuplicateEmail   = errors.New("email already belongs to a customer")
 )
 
 type Service interface {
-	GetCustomers(ctx context.Context) ([]Customer, error)
+	GetCustomers(ctx context.Context, filter CustomerFilter) (iter.Seq2[Customer, error], int, error)
 	GetCustomerByID(ctx context.Context, id int) (Customer, error)
-	GetCustomerRentalsByID(ctx context.Context, id int) ([]CustomerRentals, error)
-	GetLateCustomerRentalsByID(ctx context.Context, id int) ([]CustomerRentals, error)
+	GetCustomersByIDs(ctx context.Context, ids []int) ([]Customer, error)
+	GetCustomerRentalsByID(ctx context.Context, id int, filter RentalHistoryFilter) ([]CustomerRentals, int, error)
+	GetCustomerSummaryByID(ctx context.Context, id int) (CustomerSummary, error)
 	CreateCustomer(ctx context.Context, req CreateCustomerRequest) (*Customer, error)
-	DeleteCustomerByID(ctx context.Context, id int) error
+	UpdateCustomer(ctx context.Context, id int, match etag.Precondition, req UpdateCustomerRequest) (Customer, error)
+	DeleteCustomerByID(ctx context.Context, id int, match etag.Precondition) error
+	ReactivateCustomerByID(ctx context.Context, id int, match etag.Precondition) (Customer, error)
 }
 
 type service struct {
@@ -28,48 +45,200 @@ func NewService(reader CustomerReader, writer CustomerWriter, tx TransactionMana
 	}
 }
 
-func (s *service) GetCustomers(ctx context.Context) ([]Customer, error) {
-	return s.reader.GetAll(ctx)
+// GetCustomers counts the matches straight away and returns an iterator over
+// the page that runs its query when ranged over.
+func (s *service) GetCustomers(ctx context.Context, filter CustomerFilter) (iter.Seq2[Customer, error], int, error) {
+	total, err := s.reader.CountCustomers(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.reader.EachCustomer(ctx, filter), total, nil
 }
 
 func (s *service) GetCustomerByID(ctx context.Context, id int) (Customer, error) {
-	customer, err := s.reader.GetByID(ctx, id)
+	return s.reader.GetByID(ctx, id)
+}
+
+func (s *service) GetCustomersByIDs(ctx context.Context, ids []int) ([]Customer, error) {
+	return s.reader.GetByIDs(ctx, ids)
+}
+
+func (s *service) CreateCustomer(ctx context.Context, req CreateCustomerRequest) (*Customer, error) {
+	var customer *Customer
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.checkEmail(ctx, req.Email, 0); err != nil {
+			return err
+		}
+
+		// Get or create city
+		cityID, err := s.resolveCityID(ctx, req.Address.CityName, req.Address.Country)
+		if err != nil {
+			return err
+		}
+
+		// Insert Address
+		addressID, err := s.writer.InsertAddress(ctx, req.Address, cityID)
+		if err != nil {
+			return err
+		}
+
+		// Insert Customer
+		customer, err = s.writer.InsertCustomer(ctx, req, addressID)
+		return err
+	})
 	if err != nil {
-		return Customer{}, fmt.Errorf("Customer not found")
+		return nil, err
 	}
+
 	return customer, nil
 }
 
-func (s *service) CreateCustomer(ctx context.Context, req CreateCustomerRequest) (*Customer, error) {
-	// Get CityID
-	cityID, err := s.reader.GetCityIDByName(ctx, req.Address.CityName)
+func (s *service) UpdateCustomer(ctx context.Context, id int, match etag.Precondition, req UpdateCustomerRequest) (Customer, error) {
+	var customer Customer
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.checkVersion(ctx, id, match); err != nil {
+			return err
+		}
+
+		if req.Email != nil {
+			if err := s.checkEmail(ctx, *req.Email, id); err != nil {
+				return err
+			}
+		}
+
+		if req.Address != nil {
+			var cityID *int
+			if req.Address.CityName != nil {
+				var country string
+				if req.Address.Country != nil {
+					country = *req.Address.Country
+				}
+				found, err := s.resolveCityID(ctx, *req.Address.CityName, country)
+				if err != nil {
+					return err
+				}
+				cityID = &found
+			}
+
+			if err := s.writer.UpdateCustomerAddress(ctx, id, *req.Address, cityID); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		customer, err = s.writer.UpdateCustomer(ctx, id, req)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("invalid city name: %w", err)
+		return Customer{}, err
 	}
+	return customer, nil
+}
 
-	// Insert Address
-	addressID, err := s.writer.InsertAddress(ctx, req.Address, cityID)
+// checkVersion locks the customer and fails with etag.ErrPreconditionFailed
+// unless it is the version the client read. Must run inside WithTx.
+func (s *service) checkVersion(ctx context.Context, id int, match etag.Precondition) error {
+	lastUpdate, err := s.writer.LockCustomer(ctx, id)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	return match.Check(lastUpdate)
+}
 
-	// Insert Customer
-	customer, err := s.writer.InsertCustomer(ctx, req, addressID)
+// checkEmail fails with ErrDuplicateEmail if another customer has the email.
+// Must run inside WithTx, the lock is held until the insert or update commits.
+func (s *service) checkEmail(ctx context.Context, email string, customerID int) error {
+	if err := s.tx.Lock(ctx, "customer-email:"+strings.ToLower(email)); err != nil {
+		return err
+	}
+	exists, err := s.reader.EmailExists(ctx, email, customerID)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	if exists {
+		return ErrDuplicateEmail
+	}
+	return nil
+}
 
-	return customer, nil
+// resolveCityID finds a city, creating the city and country when a country is
+// given and they don't exist yet. Without a country the name has to match
+// exactly one city. Must run inside WithTx.
+func (s *service) resolveCityID(ctx context.Context, cityName, country string) (int, error) {
+	if country == "" {
+		ids, err := s.reader.FindCityIDs(ctx, cityName, nil)
+		if err != nil {
+			return 0, err
+		}
+		switch len(ids) {
+		case 0:
+			return 0, ErrUnknownCity
+		case 1:
+			return ids[0], nil
+		default:
+			return 0, ErrAmbiguousCity
+		}
+	}
+
+	// Get or create country
+	if err := s.tx.Lock(ctx, "country:"+strings.ToLower(country)); err != nil {
+		return 0, err
+	}
+	countryID, err := s.reader.GetCountryIDByName(ctx, country)
+	if errors.Is(err, pgx.ErrNoRows) {
+		countryID, err = s.writer.InsertCountry(ctx, country)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// Get or create city in that country
+	if err := s.tx.Lock(ctx, "city:"+strconv.Itoa(countryID)+":"+strings.ToLower(cityName)); err != nil {
+		return 0, err
+	}
+	ids, err := s.reader.FindCityIDs(ctx, cityName, &countryID)
+	if err != nil {
+		return 0, err
+	}
+	if len(ids) > 0 {
+		return ids[0], nil
+	}
+	return s.writer.InsertCity(ctx, cityName, countryID)
 }
 
-func (s *service) DeleteCustomerByID(ctx context.Context, id int) error {
-	return s.writer.DeleteCustomerByID(ctx, id)
+func (s *service) DeleteCustomerByID(ctx context.Context, id int, match etag.Precondition) error {
+	return s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.checkVersion(ctx, id, match); err != nil {
+			return err
+		}
+		return s.writer.DeleteCustomerByID(ctx, id)
+	})
 }
 
-func (s *service) GetCustomerRentalsByID(ctx context.Context, id int) ([]CustomerRentals, error) {
-	return s.reader.FindCustomerRentalsByID(ctx, id)
+func (s *service) ReactivateCustomerByID(ctx context.Context, id int, match etag.Precondition) (Customer, error) {
+	var customer Customer
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.checkVersion(ctx, id, match); err != nil {
+			return err
+		}
+
+		var err error
+		customer, err = s.writer.ReactivateCustomerByID(ctx, id)
+		return err
+	})
+	return customer, err
 }
 
-func (s *service) GetLateCustomerRentalsByID(ctx context.Context, id int) ([]CustomerRentals, error) {
-	return s.reader.FindLateCustomerRentalsByID(ctx, id)
+func (s *service) GetCustomerRentalsByID(ctx context.Context, id int, filter RentalHistoryFilter) ([]CustomerRentals, int, error) {
+	if _, err := s.reader.GetByID(ctx, id); err != nil {
+		return nil, 0, err
+	}
+	return s.reader.FindCustomerRentalsByID(ctx, id, filter)
+}
+
+func (s *service) GetCustomerSummaryByID(ctx context.Context, id int) (CustomerSummary, error) {
+	if _, err := s.reader.GetByID(ctx, id); err != nil {
+		return CustomerSummary{}, err
+	}
+	return s.reader.FindCustomerSummaryByID(ctx, id)
 }
@@ -2,10 +2,16 @@ package customer
 
 import (
 	"context"
-	"fmt"
+	"iter"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/rstoltzm-profile/video-rental-api/internal/db"
+	"github.com/rstoltzm-profile/video-rental-api/internal/etag"
+	"github.com/rstoltzm-profile/video-rental-api/internal/pagination"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -14,9 +20,20 @@ type mockCustomerReader struct {
 	mock.Mock
 }
 
-func (m *mockCustomerReader) GetAll(ctx context.Context) ([]Customer, error) {
-	args := m.Called(ctx)
-	return args.Get(0).([]Customer), args.Error(1)
+func (m *mockCustomerReader) CountCustomers(ctx context.Context, filter CustomerFilter) (int, error) {
+	args := m.Called(ctx, filter)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockCustomerReader) EachCustomer(ctx context.Context, filter CustomerFilter) iter.Seq2[Customer, error] {
+	args := m.Called(ctx, filter)
+	return func(yield func(Customer, error) bool) {
+		for _, c := range args.Get(0).([]Customer) {
+			if !yield(c, nil) {
+				return
+			}
+		}
+	}
 }
 
 func (m *mockCustomerReader) GetByID(ctx context.Context, id int) (Customer, error) {
@@ -24,21 +41,36 @@ func (m *mockCustomerReader) GetByID(ctx context.Context, id int) (Customer, err
 	return args.Get(0).(Customer), args.Error(1)
 }
 
-func (m *mockCustomerReader) FindCustomerRentalsByID(ctx context.Context, id int) ([]CustomerRentals, error) {
-	args := m.Called(ctx, id)
-	return args.Get(0).([]CustomerRentals), args.Error(1)
+func (m *mockCustomerReader) GetByIDs(ctx context.Context, ids []int) ([]Customer, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]Customer), args.Error(1)
 }
 
-func (m *mockCustomerReader) FindLateCustomerRentalsByID(ctx context.Context, id int) ([]CustomerRentals, error) {
+func (m *mockCustomerReader) FindCustomerRentalsByID(ctx context.Context, id int, filter RentalHistoryFilter) ([]CustomerRentals, int, error) {
+	args := m.Called(ctx, id, filter)
+	return args.Get(0).([]CustomerRentals), args.Int(1), args.Error(2)
+}
+
+func (m *mockCustomerReader) FindCustomerSummaryByID(ctx context.Context, id int) (CustomerSummary, error) {
 	args := m.Called(ctx, id)
-	return args.Get(0).([]CustomerRentals), args.Error(1)
+	return args.Get(0).(CustomerSummary), args.Error(1)
+}
+
+func (m *mockCustomerReader) FindCityIDs(ctx context.Context, cityName string, countryID *int) ([]int, error) {
+	args := m.Called(ctx, cityName, countryID)
+	return args.Get(0).([]int), args.Error(1)
 }
 
-func (m *mockCustomerReader) GetCityIDByName(ctx context.Context, cityName string) (int, error) {
-	args := m.Called(ctx, cityName)
+func (m *mockCustomerReader) GetCountryIDByName(ctx context.Context, country string) (int, error) {
+	args := m.Called(ctx, country)
 	return args.Int(0), args.Error(1)
 }
 
+func (m *mockCustomerReader) EmailExists(ctx context.Context, email string, excludeID int) (bool, error) {
+	args := m.Called(ctx, email, excludeID)
+	return args.Bool(0), args.Error(1)
+}
+
 type mockWriter struct {
 	mock.Mock
 }
@@ -46,6 +78,16 @@ type mockTxManager struct {
 	mock.Mock
 }
 
+func (m *mockWriter) InsertCountry(ctx context.Context, country string) (int, error) {
+	args := m.Called(ctx, country)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockWriter) InsertCity(ctx context.Context, cityName string, countryID int) (int, error) {
+	args := m.Called(ctx, cityName, countryID)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *mockWriter) InsertAddress(ctx context.Context, address AddressInput, cityID int) (int, error) {
 	args := m.Called(ctx, address, cityID)
 	return args.Int(0), args.Error(1)
@@ -61,16 +103,55 @@ func (m *mockWriter) InsertCustomer(ctx context.Context, req CreateCustomerReque
 	return customer, args.Error(1)
 }
 
+func (m *mockWriter) UpdateCustomer(ctx context.Context, id int, req UpdateCustomerRequest) (Customer, error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(Customer), args.Error(1)
+}
+
+func (m *mockWriter) UpdateCustomerAddress(ctx context.Context, customerID int, address AddressUpdate, cityID *int) error {
+	args := m.Called(ctx, customerID, address, cityID)
+	return args.Error(0)
+}
+
 func (m *mockWriter) DeleteCustomerByID(ctx context.Context, id int) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *mockWriter) ReactivateCustomerByID(ctx context.Context, id int) (Customer, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Customer), args.Error(1)
+}
+
+func (m *mockWriter) LockCustomer(ctx context.Context, id int) (time.Time, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
 func (m *mockTxManager) BeginTx(ctx context.Context) (pgx.Tx, error) {
 	args := m.Called(ctx)
 	return args.Get(0).(pgx.Tx), args.Error(1)
 }
 
+// WithTx runs fn straight away, there is no database behind the mocks
+func (m *mockTxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (m *mockTxManager) Lock(ctx context.Context, key string) error {
+	return nil
+}
+
+var version = time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+
+// ifMatch is the precondition of a write made against version
+func ifMatch(version time.Time) etag.Precondition {
+	r := httptest.NewRequest(http.MethodPatch, "/customers/1", nil)
+	r.Header.Set("If-Match", etag.Of(version))
+	match, _ := etag.IfMatch(r)
+	return match
+}
+
 func TestService_GetCustomerByID(t *testing.T) {
 	// Step 1: Create a mock that implements CustomerReader
 	mockReader := new(mockCustomerReader)
@@ -91,15 +172,20 @@ func TestService_GetCustomerByID(t *testing.T) {
 	mockReader.AssertExpectations(t)
 }
 
-func TestService_GetAll(t *testing.T) {
+func TestService_GetCustomers(t *testing.T) {
 	mockReader := new(mockCustomerReader)
 	svc := NewService(mockReader, &mockWriter{}, &mockTxManager{})
 
 	expected := []Customer{{ID: 1, FirstName: "Test"}}
-	mockReader.On("GetAll", mock.Anything).Return(expected, nil)
+	filter := CustomerFilter{Query: "tes", Page: pagination.Page{Limit: 20}}
+	mockReader.On("CountCustomers", mock.Anything, filter).Return(1, nil)
+	mockReader.On("EachCustomer", mock.Anything, filter).Return(expected)
 
-	got, err := svc.GetCustomers(context.Background())
+	customers, total, err := svc.GetCustomers(context.Background(), filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
 
+	got, err := db.Collect(customers)
 	assert.NoError(t, err)
 	assert.Equal(t, expected, got)
 
@@ -110,13 +196,16 @@ func TestService_GetCustomerRentalsByID(t *testing.T) {
 	mockReader := new(mockCustomerReader)
 	svc := NewService(mockReader, &mockWriter{}, &mockTxManager{})
 
+	filter := RentalHistoryFilter{Status: RentalStatusOpen, Page: pagination.Page{Limit: 20}}
 	expected := []CustomerRentals{{FirstName: "John", LastName: "House"}}
-	mockReader.On("FindCustomerRentalsByID", mock.Anything, 1).Return(expected, nil)
+	mockReader.On("GetByID", mock.Anything, 1).Return(Customer{ID: 1}, nil)
+	mockReader.On("FindCustomerRentalsByID", mock.Anything, 1, filter).Return(expected, 1, nil)
 
-	got, err := svc.GetCustomerRentalsByID(context.Background(), 1)
+	got, total, err := svc.GetCustomerRentalsByID(context.Background(), 1, filter)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, got)
+	assert.Equal(t, 1, total)
 
 	mockReader.AssertExpectations(t)
 }
@@ -125,10 +214,12 @@ func TestService_GetLateCustomerRentalsByIDD(t *testing.T) {
 	mockReader := new(mockCustomerReader)
 	svc := NewService(mockReader, &mockWriter{}, &mockTxManager{})
 
-	expected := []CustomerRentals{{FirstName: "John", LastName: "House"}}
-	mockReader.On("FindLateCustomerRentalsByID", mock.Anything, 1).Return(expected, nil)
+	filter := RentalHistoryFilter{Status: RentalStatusLate, Page: pagination.Page{Limit: 20}}
+	expected := []CustomerRentals{{FirstName: "John", LastName: "House", Overdue: true}}
+	mockReader.On("GetByID", mock.Anything, 1).Return(Customer{ID: 1}, nil)
+	mockReader.On("FindCustomerRentalsByID", mock.Anything, 1, filter).Return(expected, 1, nil)
 
-	got, err := svc.GetLateCustomerRentalsByID(context.Background(), 1)
+	got, _, err := svc.GetCustomerRentalsByID(context.Background(), 1, filter)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, got)
@@ -136,13 +227,41 @@ func TestService_GetLateCustomerRentalsByIDD(t *testing.T) {
 	mockReader.AssertExpectations(t)
 }
 
+func TestService_GetCustomerRentalsByID_NotFound(t *testing.T) {
+	mockReader := new(mockCustomerReader)
+	svc := NewService(mockReader, &mockWriter{}, &mockTxManager{})
+
+	mockReader.On("GetByID", mock.Anything, 999).Return(Customer{}, ErrCustomerNotFound)
+
+	_, _, err := svc.GetCustomerRentalsByID(context.Background(), 999, RentalHistoryFilter{Status: RentalStatusAll})
+
+	assert.ErrorIs(t, err, ErrCustomerNotFound)
+	mockReader.AssertNotCalled(t, "FindCustomerRentalsByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_GetCustomerSummaryByID(t *testing.T) {
+	mockReader := new(mockCustomerReader)
+	svc := NewService(mockReader, &mockWriter{}, &mockTxManager{})
+
+	expected := CustomerSummary{CustomerID: 1, LifetimeRentals: 32, TotalSpend: 118.68}
+	mockReader.On("GetByID", mock.Anything, 1).Return(Customer{ID: 1}, nil)
+	mockReader.On("FindCustomerSummaryByID", mock.Anything, 1).Return(expected, nil)
+
+	got, err := svc.GetCustomerSummaryByID(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, got)
+	mockReader.AssertExpectations(t)
+}
+
 func TestService_DeleteCustomerByID(t *testing.T) {
 	mockWriter := new(mockWriter)
 	svc := NewService(&mockCustomerReader{}, mockWriter, &mockTxManager{})
 
+	mockWriter.On("LockCustomer", mock.Anything, 1).Return(version, nil)
 	mockWriter.On("DeleteCustomerByID", mock.Anything, 1).Return(nil)
 
-	err := svc.DeleteCustomerByID(context.Background(), 1)
+	err := svc.DeleteCustomerByID(context.Background(), 1, ifMatch(version))
 
 	assert.NoError(t, err)
 
@@ -153,20 +272,19 @@ func TestService_DeleteCustomerByID_NotFound(t *testing.T) {
 	mockWriter := new(mockWriter)
 	svc := NewService(&mockCustomerReader{}, mockWriter, &mockTxManager{})
 
-	mockWriter.On("DeleteCustomerByID", mock.Anything, 999).Return(fmt.Errorf("no customer found with ID 999"))
+	mockWriter.On("LockCustomer", mock.Anything, 999).Return(time.Time{}, ErrCustomerNotFound)
 
-	err := svc.DeleteCustomerByID(context.Background(), 999)
+	err := svc.DeleteCustomerByID(context.Background(), 999, ifMatch(version))
 
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "no customer found")
-	mockWriter.AssertExpectations(t)
+	assert.ErrorIs(t, err, ErrCustomerNotFound)
+	mockWriter.AssertNotCalled(t, "DeleteCustomerByID", mock.Anything, mock.Anything)
 }
 
 func TestService_CreateCustomer(t *testing.T) {
 	mockReader := new(mockCustomerReader)
 	mockWriter := new(mockWriter)
 
-	svc := NewService(mockReader, mockWriter, nil) // no txManager needed now
+	svc := NewService(mockReader, mockWriter, &mockTxManager{})
 
 	newCustomer := CreateCustomerRequest{
 		StoreID:   1,
@@ -186,8 +304,11 @@ func TestService_CreateCustomer(t *testing.T) {
 		Email:     "JohnHouse@rental.com",
 	}
 
-	// Mock GetCityIDByName to return a cityID (e.g., 42)
-	mockReader.On("GetCityIDByName", mock.Anything, "TestCity").Return(42, nil)
+	// Email is not taken yet
+	mockReader.On("EmailExists", mock.Anything, "JohnHouse@rental.com", 0).Return(false, nil)
+
+	// City name matches a single city (e.g., 42)
+	mockReader.On("FindCityIDs", mock.Anything, "TestCity", (*int)(nil)).Return([]int{42}, nil)
 
 	// Mock InsertAddress to return an addressID (e.g., 10)
 	mockWriter.On("InsertAddress", mock.Anything, newCustomer.Address, 42).Return(10, nil)
@@ -203,3 +324,138 @@ func TestService_CreateCustomer(t *testing.T) {
 	mockReader.AssertExpectations(t)
 	mockWriter.AssertExpectations(t)
 }
+
+func TestService_UpdateCustomer(t *testing.T) {
+	mockReader := new(mockCustomerReader)
+	mockWriter := new(mockWriter)
+	svc := NewService(mockReader, mockWriter, &mockTxManager{})
+
+	email := "new@rental.com"
+	city := "TestCity"
+	req := UpdateCustomerRequest{
+		Email:   &email,
+		Address: &AddressUpdate{CityName: &city},
+	}
+	cityID := 42
+	expected := Customer{ID: 1, FirstName: "John", Email: email, Active: true}
+
+	mockWriter.On("LockCustomer", mock.Anything, 1).Return(version, nil)
+	mockReader.On("EmailExists", mock.Anything, email, 1).Return(false, nil)
+	mockReader.On("FindCityIDs", mock.Anything, "TestCity", (*int)(nil)).Return([]int{42}, nil)
+	mockWriter.On("UpdateCustomerAddress", mock.Anything, 1, *req.Address, &cityID).Return(nil)
+	mockWriter.On("UpdateCustomer", mock.Anything, 1, req).Return(expected, nil)
+
+	got, err := svc.UpdateCustomer(context.Background(), 1, ifMatch(version), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, got)
+	mockReader.AssertExpectations(t)
+	mockWriter.AssertExpectations(t)
+}
+
+func TestService_UpdateCustomer_UnknownCity(t *testing.T) {
+	mockReader := new(mockCustomerReader)
+	mockWriter := new(mockWriter)
+	svc := NewService(mockReader, mockWriter, &mockTxManager{})
+
+	city := "Atlantis"
+	req := UpdateCustomerRequest{Address: &AddressUpdate{CityName: &city}}
+
+	mockWriter.On("LockCustomer", mock.Anything, 1).Return(version, nil)
+	mockReader.On("FindCityIDs", mock.Anything, "Atlantis", (*int)(nil)).Return([]int{}, nil)
+
+	_, err := svc.UpdateCustomer(context.Background(), 1, ifMatch(version), req)
+
+	assert.ErrorIs(t, err, ErrUnknownCity)
+	mockWriter.AssertNotCalled(t, "UpdateCustomer", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_UpdateCustomer_StaleVersion(t *testing.T) {
+	mockWriter := new(mockWriter)
+	svc := NewService(new(mockCustomerReader), mockWriter, &mockTxManager{})
+
+	name := "Updated"
+	mockWriter.On("LockCustomer", mock.Anything, 1).Return(version.Add(time.Second), nil)
+
+	_, err := svc.UpdateCustomer(context.Background(), 1, ifMatch(version), UpdateCustomerRequest{LastName: &name})
+
+	assert.ErrorIs(t, err, etag.ErrPreconditionFailed)
+	mockWriter.AssertNotCalled(t, "UpdateCustomer", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_ReactivateCustomerByID(t *testing.T) {
+	mockWriter := new(mockWriter)
+	svc := NewService(&mockCustomerReader{}, mockWriter, &mockTxManager{})
+
+	expected := Customer{ID: 1, Active: true}
+	mockWriter.On("LockCustomer", mock.Anything, 1).Return(version, nil)
+	mockWriter.On("ReactivateCustomerByID", mock.Anything, 1).Return(expected, nil)
+
+	got, err := svc.ReactivateCustomerByID(context.Background(), 1, ifMatch(version))
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, got)
+	mockWriter.AssertExpectations(t)
+}
+
+func TestService_CreateCustomer_NewCityAndCountry(t *testing.T) {
+	mockReader := new(mockCustomerReader)
+	mockWriter := new(mockWriter)
+	svc := NewService(mockReader, mockWriter, &mockTxManager{})
+
+	req := CreateCustomerRequest{
+		StoreID:   1,
+		FirstName: "John",
+		LastName:  "House",
+		Email:     "JohnHouse@rental.com",
+		Address:   AddressInput{CityName: "Newtown", Country: "Atlantis"},
+	}
+	countryID := 200
+	expected := &Customer{ID: 1, FirstName: "John"}
+
+	mockReader.On("EmailExists", mock.Anything, req.Email, 0).Return(false, nil)
+	mockReader.On("GetCountryIDByName", mock.Anything, "Atlantis").Return(0, pgx.ErrNoRows)
+	mockWriter.On("InsertCountry", mock.Anything, "Atlantis").Return(countryID, nil)
+	mockReader.On("FindCityIDs", mock.Anything, "Newtown", &countryID).Return([]int{}, nil)
+	mockWriter.On("InsertCity", mock.Anything, "Newtown", countryID).Return(700, nil)
+	mockWriter.On("InsertAddress", mock.Anything, req.Address, 700).Return(10, nil)
+	mockWriter.On("InsertCustomer", mock.Anything, req, 10).Return(expected, nil)
+
+	got, err := svc.CreateCustomer(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, got)
+	mockReader.AssertExpectations(t)
+	mockWriter.AssertExpectations(t)
+}
+
+func TestService_CreateCustomer_AmbiguousCity(t *testing.T) {
+	mockReader := new(mockCustomerReader)
+	mockWriter := new(mockWriter)
+	svc := NewService(mockReader, mockWriter, &mockTxManager{})
+
+	req := CreateCustomerRequest{Email: "a@b.com", Address: AddressInput{CityName: "London"}}
+
+	mockReader.On("EmailExists", mock.Anything, "a@b.com", 0).Return(false, nil)
+	mockReader.On("FindCityIDs", mock.Anything, "London", (*int)(nil)).Return([]int{312, 313}, nil)
+
+	_, err := svc.CreateCustomer(context.Background(), req)
+
+	assert.ErrorIs(t, err, ErrAmbiguousCity)
+	mockWriter.AssertNotCalled(t, "InsertAddress", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_CreateCustomer_DuplicateEmail(t *testing.T) {
+	mockReader := new(mockCustomerReader)
+	mockWriter := new(mockWriter)
+	svc := NewService(mockReader, mockWriter, &mockTxManager{})
+
+	req := CreateCustomerRequest{Email: "taken@rental.com", Address: AddressInput{CityName: "TestCity"}}
+
+	mockReader.On("EmailExists", mock.Anything, "taken@rental.com", 0).Return(true, nil)
+
+	_, err := svc.CreateCustomer(context.Background(), req)
+
+	assert.ErrorIs(t, err, ErrDuplicateEmail)
+	mockReader.AssertNotCalled(t, "FindCityIDs", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,52 @@
+package daterange
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Range bounds a timestamp column, From inclusive and To exclusive. A nil end
+// is open.
+type Range struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// Parse reads ?from= and ?to= from the query string as YYYY-MM-DD or RFC3339.
+// A plain to date covers that whole day.
+func Parse(r *http.Request) (Range, error) {
+	q := r.URL.Query()
+	var rng Range
+
+	var err error
+	if rng.From, err = ParseDate(q.Get("from"), false); err != nil {
+		return rng, fmt.Errorf("Invalid from date: %w", err)
+	}
+	if rng.To, err = ParseDate(q.Get("to"), true); err != nil {
+		return rng, fmt.Errorf("Invalid to date: %w", err)
+	}
+	if rng.From != nil && rng.To != nil && !rng.From.Before(*rng.To) {
+		return rng, fmt.Errorf("from must be before to")
+	}
+	return rng, nil
+}
+
+// ParseDate accepts YYYY-MM-DD or RFC3339. A plain end date covers that whole
+// day, so it is moved to midnight of the next day.
+func ParseDate(v string, end bool) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, v)
+	if err != nil {
+		return nil, err
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
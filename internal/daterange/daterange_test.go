@@ -0,0 +1,54 @@
+package daterange
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		from    *time.Time
+		to      *time.Time
+		wantErr bool
+	}{
+		{name: "open", query: ""},
+		{
+			name:  "plain dates cover the whole to day",
+			query: "from=2022-05-01&to=2022-05-31",
+			from:  ptr(time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)),
+			to:    ptr(time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)),
+		},
+		{
+			name:  "rfc3339 is exact",
+			query: "to=2022-05-31T12:00:00Z",
+			to:    ptr(time.Date(2022, 5, 31, 12, 0, 0, 0, time.UTC)),
+		},
+		{name: "bad date", query: "from=05/01/2022", wantErr: true},
+		{name: "from after to", query: "from=2022-06-01&to=2022-05-01", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/v1/reports/revenue?"+tt.query, nil)
+
+			rng, err := Parse(r)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.from, rng.From)
+			assert.Equal(t, tt.to, rng.To)
+		})
+	}
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,19 @@
+package db
+
+import "fmt"
+
+// DaysLate is the SQL for how many whole days past its due date a rental was
+// returned, or is today while it is still out, 0 when it isn't late. rental
+// and film name the rows holding the rental's dates and the film's
+// rental_duration, they can be the same CTE. It is the $1 a day rule of
+// Pagila's get_customer_balance, and what every late filter, flag and count
+// goes by.
+func DaysLate(rental, film string) string {
+	return fmt.Sprintf(`GREATEST(0, DATE_PART('day', COALESCE(%[1]s.return_date, CURRENT_TIMESTAMP) - %[1]s.rental_date) - %[2]s.rental_duration)`, rental, film)
+}
+
+// IsLate is the SQL condition for a rental that was returned late or is
+// still out past its due date, see DaysLate
+func IsLate(rental, film string) string {
+	return DaysLate(rental, film) + " > 0"
+}
@@ -81,6 +81,229 @@ func RunMigrations(pool *pgxpool.Pool) error {
 		return err
 	}
 
+	// 6. Trigram indexes for customer search
+	if err := applyMigration(pool, "2026-10-19-customer-search-trgm", `
+		CREATE EXTENSION IF NOT EXISTS pg_trgm;
+		CREATE INDEX IF NOT EXISTS idx_customer_full_name_trgm
+			ON customer USING gin (lower(first_name || ' ' || last_name) gin_trgm_ops);
+		CREATE INDEX IF NOT EXISTS idx_customer_email_trgm
+			ON customer USING gin (lower(email) gin_trgm_ops);
+	`); err != nil {
+		return err
+	}
+
+	// 7. Film reservations and holds
+	if err := applyMigration(pool, "2026-10-19-reservation", `
+		CREATE TABLE IF NOT EXISTS reservation (
+			reservation_id SERIAL PRIMARY KEY,
+			customer_id INTEGER NOT NULL REFERENCES customer (customer_id),
+			film_id INTEGER NOT NULL REFERENCES film (film_id),
+			store_id INTEGER NOT NULL REFERENCES store (store_id),
+			status TEXT NOT NULL DEFAULT 'waiting'
+				CHECK (status IN ('waiting', 'held', 'fulfilled', 'cancelled', 'expired')),
+			inventory_id INTEGER REFERENCES inventory (inventory_id),
+			hold_expires_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			last_update TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+		-- one open reservation per customer, film and store
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_reservation_open
+			ON reservation (customer_id, film_id, store_id) WHERE status IN ('waiting', 'held');
+		-- a copy is held for one reservation at a time
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_reservation_held_inventory
+			ON reservation (inventory_id) WHERE status = 'held';
+		CREATE INDEX IF NOT EXISTS idx_reservation_queue
+			ON reservation (film_id, store_id, created_at) WHERE status = 'waiting';
+	`); err != nil {
+		return err
+	}
+
+	// 8. Room for bcrypt hashes, Pagila's staff.password is varchar(40)
+	if err := applyMigration(pool, "2026-10-19-staff-password-hash", `
+		ALTER TABLE staff ALTER COLUMN password TYPE varchar(72)
+	`); err != nil {
+		return err
+	}
+
+	// 9. Background job queue
+	if err := applyMigration(pool, "2026-10-19-job-queue", `
+		CREATE TABLE IF NOT EXISTS job (
+			job_id SERIAL PRIMARY KEY,
+			kind TEXT NOT NULL,
+			params JSONB NOT NULL DEFAULT '{}',
+			input BYTEA,
+			status TEXT NOT NULL DEFAULT 'queued'
+				CHECK (status IN ('queued', 'running', 'succeeded', 'failed', 'cancelled')),
+			progress INTEGER NOT NULL DEFAULT 0 CHECK (progress BETWEEN 0 AND 100),
+			attempts INTEGER NOT NULL DEFAULT 0,
+			max_attempts INTEGER NOT NULL DEFAULT 3,
+			cancel_requested BOOLEAN NOT NULL DEFAULT FALSE,
+			error TEXT,
+			run_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			locked_until TIMESTAMPTZ,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			started_at TIMESTAMPTZ,
+			finished_at TIMESTAMPTZ
+		);
+		-- workers claim due jobs in run_at order
+		CREATE INDEX IF NOT EXISTS idx_job_queue ON job (run_at, job_id) WHERE status = 'queued';
+		-- and take over running jobs whose lease ran out
+		CREATE INDEX IF NOT EXISTS idx_job_lease ON job (locked_until) WHERE status = 'running';
+		CREATE TABLE IF NOT EXISTS job_result (
+			job_id INTEGER PRIMARY KEY REFERENCES job (job_id) ON DELETE CASCADE,
+			content_type TEXT NOT NULL,
+			body BYTEA NOT NULL
+		);
+	`); err != nil {
+		return err
+	}
+
+	// 10. Webhook subscriptions, the event outbox and the delivery log
+	if err := applyMigration(pool, "2026-10-19-webhooks", `
+		CREATE TABLE IF NOT EXISTS webhook_subscription (
+			subscription_id SERIAL PRIMARY KEY,
+			url TEXT NOT NULL,
+			events TEXT[] NOT NULL,
+			secret TEXT NOT NULL,
+			active BOOLEAN NOT NULL DEFAULT TRUE,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			last_update TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE TABLE IF NOT EXISTS webhook_event (
+			event_id SERIAL PRIMARY KEY,
+			type TEXT NOT NULL,
+			key TEXT,
+			payload JSONB NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			dispatched_at TIMESTAMPTZ
+		);
+		-- events with a key, like a rental going overdue, are only written once
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_event_key
+			ON webhook_event (type, key) WHERE key IS NOT NULL;
+		CREATE INDEX IF NOT EXISTS idx_webhook_event_outbox
+			ON webhook_event (event_id) WHERE dispatched_at IS NULL;
+		CREATE TABLE IF NOT EXISTS webhook_delivery (
+			delivery_id SERIAL PRIMARY KEY,
+			event_id INTEGER NOT NULL REFERENCES webhook_event (event_id),
+			subscription_id INTEGER NOT NULL REFERENCES webhook_subscription (subscription_id),
+			status TEXT NOT NULL DEFAULT 'pending'
+				CHECK (status IN ('pending', 'delivered', 'failed')),
+			attempts INTEGER NOT NULL DEFAULT 0,
+			next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			locked_until TIMESTAMPTZ,
+			last_status_code INTEGER,
+			last_error TEXT,
+			delivered_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (event_id, subscription_id)
+		);
+		-- the dispatcher claims due deliveries in next_attempt_at order
+		CREATE INDEX IF NOT EXISTS idx_webhook_delivery_due
+			ON webhook_delivery (next_attempt_at, delivery_id) WHERE status = 'pending';
+		CREATE INDEX IF NOT EXISTS idx_webhook_delivery_log
+			ON webhook_delivery (subscription_id, delivery_id DESC);
+	`); err != nil {
+		return err
+	}
+
+	// 11. Idempotency keys and the responses they produced
+	if err := applyMigration(pool, "2026-10-19-idempotency-keys", `
+		CREATE TABLE IF NOT EXISTS idempotency_key (
+			key TEXT PRIMARY KEY,
+			method TEXT NOT NULL,
+			path TEXT NOT NULL,
+			fingerprint TEXT NOT NULL,
+			status INTEGER,
+			header JSONB,
+			body BYTEA,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			expires_at TIMESTAMPTZ NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_idempotency_key_expires ON idempotency_key (expires_at);
+	`); err != nil {
+		return err
+	}
+
+	// 12. Store activity, outbox events tagged with their store and announced
+	// on the store_activity channel when they commit
+	if err := applyMigration(pool, "2026-10-19-store-activity", `
+		ALTER TABLE webhook_event ADD COLUMN IF NOT EXISTS store_id INTEGER;
+		UPDATE webhook_event
+		SET store_id = COALESCE(
+			(payload->>'store_id')::int,
+			(SELECT inventory.store_id FROM inventory WHERE inventory.inventory_id = (payload->>'inventory_id')::int))
+		WHERE store_id IS NULL;
+		CREATE INDEX IF NOT EXISTS idx_webhook_event_store ON webhook_event (store_id, event_id);
+
+		-- payments carry their store, rentals are placed through the copy
+		CREATE OR REPLACE FUNCTION webhook_event_set_store() RETURNS trigger AS $$
+		BEGIN
+			IF NEW.store_id IS NULL THEN
+				NEW.store_id := COALESCE(
+					(NEW.payload->>'store_id')::int,
+					(SELECT inventory.store_id FROM inventory WHERE inventory.inventory_id = (NEW.payload->>'inventory_id')::int));
+			END IF;
+			RETURN NEW;
+		END;
+		$$ LANGUAGE plpgsql;
+		DROP TRIGGER IF EXISTS webhook_event_set_store ON webhook_event;
+		CREATE TRIGGER webhook_event_set_store BEFORE INSERT ON webhook_event
+			FOR EACH ROW EXECUTE FUNCTION webhook_event_set_store();
+
+		-- AFTER so an overdue event skipped by ON CONFLICT isn't announced
+		CREATE OR REPLACE FUNCTION webhook_event_notify() RETURNS trigger AS $$
+		BEGIN
+			IF NEW.store_id IS NOT NULL THEN
+				PERFORM pg_notify('store_activity',
+					json_build_object('store_id', NEW.store_id, 'event_id', NEW.event_id)::text);
+			END IF;
+			RETURN NULL;
+		END;
+		$$ LANGUAGE plpgsql;
+		DROP TRIGGER IF EXISTS webhook_event_notify ON webhook_event;
+		CREATE TRIGGER webhook_event_notify AFTER INSERT ON webhook_event
+			FOR EACH ROW EXECUTE FUNCTION webhook_event_notify();
+	`); err != nil {
+		return err
+	}
+
+	// 13. API keys issued with vrctl, stored as the SHA-256 of the key
+	if err := applyMigration(pool, "2026-10-19-api-keys", `
+		CREATE TABLE IF NOT EXISTS api_key (
+			key_id SERIAL PRIMARY KEY,
+			name TEXT NOT NULL,
+			key_hash BYTEA NOT NULL UNIQUE,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			revoked_at TIMESTAMPTZ
+		);
+	`); err != nil {
+		return err
+	}
+
+	// 14. Staff sessions, opened with a staff password and stored as the
+	// SHA-256 of the token
+	if err := applyMigration(pool, "2026-10-19-staff-sessions", `
+		CREATE TABLE IF NOT EXISTS staff_session (
+			token_hash BYTEA PRIMARY KEY,
+			staff_id INTEGER NOT NULL REFERENCES staff (staff_id) ON DELETE CASCADE,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			expires_at TIMESTAMPTZ NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_staff_session_staff ON staff_session (staff_id);
+	`); err != nil {
+		return err
+	}
+
+	// 15. Idempotency keys scoped to the API key that sent them, 0 is the
+	// configured API_KEY, so one client can't replay another's response
+	if err := applyMigration(pool, "2026-10-19-idempotency-key-scope", `
+		ALTER TABLE idempotency_key ADD COLUMN IF NOT EXISTS api_key_id INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE idempotency_key DROP CONSTRAINT IF EXISTS idempotency_key_pkey;
+		ALTER TABLE idempotency_key ADD PRIMARY KEY (api_key_id, key);
+	`); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -95,3 +318,29 @@ func GetCurrentMigration(pool *pgxpool.Pool) (string, string, error) {
 	}
 	return version, appliedAt.Format(time.RFC3339), nil
 }
+
+// Migration is an applied migration from schema_migrations
+type Migration struct {
+	Version   string    `json:"version"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+// ListMigrations returns the applied migrations, oldest first
+func ListMigrations(pool *pgxpool.Pool) ([]Migration, error) {
+	rows, err := pool.Query(context.Background(),
+		`SELECT version, applied_at FROM schema_migrations ORDER BY applied_at, version`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var migrations []Migration
+	for rows.Next() {
+		var m Migration
+		if err := rows.Scan(&m.Version, &m.AppliedAt); err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, m)
+	}
+	return migrations, rows.Err()
+}
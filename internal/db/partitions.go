@@ -0,0 +1,93 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PaymentPartition is the partition of the payment table holding the
+// payments dated From up to To, one calendar month in UTC.
+type PaymentPartition struct {
+	Name    string    `json:"name"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Created bool      `json:"created"`
+}
+
+// paymentPartition is the partition of the month t falls in, named like the
+// Pagila ones, payment_p2025_07
+func paymentPartition(t time.Time) PaymentPartition {
+	t = t.UTC()
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return PaymentPartition{
+		Name: fmt.Sprintf("payment_p%04d_%02d", from.Year(), from.Month()),
+		From: from,
+		To:   from.AddDate(0, 1, 0),
+	}
+}
+
+// CreatePaymentPartitions creates the partitions of payment for months
+// months starting with the month of from, skipping those that exist. A
+// payment dated in a month without a partition can't be inserted.
+func CreatePaymentPartitions(pool *pgxpool.Pool, from time.Time, months int) ([]PaymentPartition, error) {
+	ctx := context.Background()
+	first := paymentPartition(from).From
+	var partitions []PaymentPartition
+	for i := range months {
+		p := paymentPartition(first.AddDate(0, i, 0))
+
+		var exists bool
+		err := pool.QueryRow(ctx, `SELECT to_regclass('public.' || $1) IS NOT NULL`, p.Name).Scan(&exists)
+		if err != nil {
+			return partitions, fmt.Errorf("failed to check partition %s: %w", p.Name, err)
+		}
+		if !exists {
+			// DDL takes no parameters, the name and bounds are built above
+			_, err := pool.Exec(ctx, fmt.Sprintf(
+				`CREATE TABLE %s PARTITION OF public.payment FOR VALUES FROM ('%s') TO ('%s')`,
+				pgx.Identifier{"public", p.Name}.Sanitize(),
+				p.From.Format(time.RFC3339), p.To.Format(time.RFC3339)))
+			if err != nil {
+				return partitions, fmt.Errorf("failed to create partition %s: %w", p.Name, err)
+			}
+			p.Created = true
+		}
+		partitions = append(partitions, p)
+	}
+	return partitions, nil
+}
+
+// PaymentPartitionBound is an existing partition of payment and its
+// partition bound as Postgres prints it
+type PaymentPartitionBound struct {
+	Name  string `json:"name"`
+	Bound string `json:"bound"`
+}
+
+func ListPaymentPartitions(pool *pgxpool.Pool) ([]PaymentPartitionBound, error) {
+	rows, err := pool.Query(context.Background(), `
+		SELECT c.relname, pg_get_expr(c.relpartbound, c.oid)
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'public.payment'::regclass
+		ORDER BY c.relname
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var partitions []PaymentPartitionBound
+	for rows.Next() {
+		var p PaymentPartitionBound
+		if err := rows.Scan(&p.Name, &p.Bound); err != nil {
+			return nil, err
+		}
+		partitions = append(partitions, p)
+	}
+	return partitions, rows.Err()
+}
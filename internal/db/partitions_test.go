@@ -0,0 +1,17 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaymentPartition(t *testing.T) {
+	p := paymentPartition(time.Date(2026, 12, 31, 23, 0, 0, 0, time.FixedZone("EST", -5*60*60)))
+
+	// 23:00 EST on Dec 31 is January 1 in UTC
+	assert.Equal(t, "payment_p2027_01", p.Name)
+	assert.Equal(t, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), p.From)
+	assert.Equal(t, time.Date(2027, 2, 1, 0, 0, 0, 0, time.UTC), p.To)
+}
@@ -30,6 +30,10 @@ func ConnectPool(url string) (*pgxpool.Pool, error) {
 	config.MaxConnLifetime = 30 * time.Minute
 	config.MaxConnIdleTime = time.Hour
 
+	// customer search finds typos with the <% operator, which only matches
+	// from this word similarity on; the default of 0.6 would miss some
+	config.ConnConfig.RuntimeParams["pg_trgm.word_similarity_threshold"] = "0.5"
+
 	pool, err := pgxpool.NewWithConfig(context.Background(), config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create connection pool: %w", err)
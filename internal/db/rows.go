@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+	"iter"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Seq runs query when ranged over and yields each row as it is scanned,
+// without collecting them. Breaking out of the loop closes the rows, and
+// cancelling ctx stops the query. A query or scan error is yielded once as
+// the last value.
+func Seq[T any](ctx context.Context, conn DBTX, scan pgx.RowToFunc[T], query string, args ...any) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		rows, err := conn.Query(ctx, query, args...)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			v, err := scan(rows)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(v, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// Collect gathers seq into a slice, stopping at the first error.
+func Collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var out []T
+	for v, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
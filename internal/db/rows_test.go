@@ -0,0 +1,46 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollect(t *testing.T) {
+	seq := func(yield func(int, error) bool) {
+		for i := range 3 {
+			if !yield(i, nil) {
+				return
+			}
+		}
+	}
+
+	got, err := Collect(seq)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2}, got)
+}
+
+func TestCollect_StopsAtError(t *testing.T) {
+	failed := errors.New("scan failed")
+	var yielded int
+	seq := func(yield func(int, error) bool) {
+		yielded++
+		if !yield(1, nil) {
+			return
+		}
+		yielded++
+		if !yield(0, failed) {
+			return
+		}
+		yielded++
+		yield(2, nil)
+	}
+
+	got, err := Collect(seq)
+
+	assert.ErrorIs(t, err, failed)
+	assert.Nil(t, got)
+	assert.Equal(t, 2, yielded)
+}
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DBTX is the query surface shared by *pgxpool.Pool and pgx.Tx, so repository
+// methods can run the same SQL inside or outside a transaction.
+type DBTX interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
+type txKey struct{}
+
+// WithTx runs fn in a transaction carried on the context. Repository calls made
+// with that context through Conn join the transaction. Nested calls reuse the
+// outer transaction. The transaction commits if fn returns nil and rolls back
+// otherwise.
+func WithTx(ctx context.Context, pool *pgxpool.Pool, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+	return pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// Conn returns the transaction on ctx if there is one, otherwise the pool.
+func Conn(ctx context.Context, pool *pgxpool.Pool) DBTX {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return pool
+}
+
+// Lock takes a transaction scoped advisory lock on key, serializing
+// check-then-write steps until the surrounding transaction ends.
+func Lock(ctx context.Context, conn DBTX, key string) error {
+	_, err := conn.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtextextended($1, 0))`, key)
+	return err
+}
+
+// InventoryLockKey is the lock every package takes before renting, returning
+// or holding a copy.
+func InventoryLockKey(inventoryID int) string {
+	return "inventory:" + strconv.Itoa(inventoryID)
+}
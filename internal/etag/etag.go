@@ -0,0 +1,111 @@
+package etag
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrPreconditionRequired = errors.New("If-Match is required, GET the resource for its ETag")
+	ErrPreconditionFailed   = errors.New("resource has changed since it was read, GET it again")
+)
+
+// Of is the ETag of a row version, its last_update to the microsecond that
+// Postgres stores.
+func Of(lastUpdate time.Time) string {
+	return `"` + strconv.FormatInt(lastUpdate.UnixMicro(), 36) + `"`
+}
+
+// Set adds ETag and Last-Modified for the row version to the response
+func Set(w http.ResponseWriter, lastUpdate time.Time) {
+	w.Header().Set("ETag", Of(lastUpdate))
+	w.Header().Set("Last-Modified", lastUpdate.UTC().Format(http.TimeFormat))
+}
+
+// NotModified sets ETag and Last-Modified, then answers 304 and returns true
+// when If-None-Match, or If-Modified-Since without it, shows the client
+// already has this version.
+func NotModified(w http.ResponseWriter, r *http.Request, lastUpdate time.Time) bool {
+	Set(w, lastUpdate)
+
+	fresh := false
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		// weak comparison, W/"x" matches "x"
+		current := Of(lastUpdate)
+		for _, tag := range split(inm) {
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == current {
+				fresh = true
+			}
+		}
+	} else if ims, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil {
+		fresh = !lastUpdate.Truncate(time.Second).After(ims)
+	}
+	if !fresh {
+		return false
+	}
+
+	w.Header().Del("Content-Type")
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// Precondition is the If-Match of a write, the versions the client read
+type Precondition struct {
+	any  bool
+	tags []string
+}
+
+// IfMatch reads If-Match, failing with ErrPreconditionRequired when it is
+// missing. Writes to versioned resources must send one.
+func IfMatch(r *http.Request) (Precondition, error) {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return Precondition{}, ErrPreconditionRequired
+	}
+	var p Precondition
+	for _, tag := range split(header) {
+		if tag == "*" {
+			p.any = true
+		}
+		// strong comparison, weak tags never match
+		if !strings.HasPrefix(tag, "W/") {
+			p.tags = append(p.tags, tag)
+		}
+	}
+	return p, nil
+}
+
+// Match is the precondition of a client that read the lastUpdate version,
+// for writes that don't come over HTTP
+func Match(lastUpdate time.Time) Precondition {
+	return Precondition{tags: []string{Of(lastUpdate)}}
+}
+
+// Check fails with ErrPreconditionFailed unless lastUpdate is a version the
+// client sent. Call it with the row locked so the version can't change
+// before the write commits.
+func (p Precondition) Check(lastUpdate time.Time) error {
+	if p.any {
+		return nil
+	}
+	current := Of(lastUpdate)
+	for _, tag := range p.tags {
+		if tag == current {
+			return nil
+		}
+	}
+	return ErrPreconditionFailed
+}
+
+func split(header string) []string {
+	var tags []string
+	for tag := range strings.SplitSeq(header, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,70 @@
+package etag
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var version = time.Date(2026, 10, 19, 9, 30, 0, 123456000, time.UTC)
+
+func TestNotModified_IfNoneMatch(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/customers/1", nil)
+	r.Header.Set("If-None-Match", `"other", `+Of(version))
+	w := httptest.NewRecorder()
+
+	assert.True(t, NotModified(w, r, version))
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, Of(version), w.Header().Get("ETag"))
+}
+
+func TestNotModified_ChangedSince(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/customers/1", nil)
+	r.Header.Set("If-None-Match", Of(version.Add(-time.Microsecond)))
+	w := httptest.NewRecorder()
+
+	assert.False(t, NotModified(w, r, version))
+	assert.Equal(t, Of(version), w.Header().Get("ETag"))
+	assert.Equal(t, "Mon, 19 Oct 2026 09:30:00 GMT", w.Header().Get("Last-Modified"))
+}
+
+func TestNotModified_IfModifiedSince(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/customers/1", nil)
+	r.Header.Set("If-Modified-Since", "Mon, 19 Oct 2026 09:30:00 GMT")
+
+	assert.True(t, NotModified(httptest.NewRecorder(), r, version))
+
+	r.Header.Set("If-Modified-Since", "Mon, 19 Oct 2026 09:29:59 GMT")
+	assert.False(t, NotModified(httptest.NewRecorder(), r, version))
+}
+
+func TestIfMatch(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPatch, "/customers/1", nil)
+	_, err := IfMatch(r)
+	assert.ErrorIs(t, err, ErrPreconditionRequired)
+
+	r.Header.Set("If-Match", Of(version))
+	p, err := IfMatch(r)
+	assert.NoError(t, err)
+	assert.NoError(t, p.Check(version))
+	assert.ErrorIs(t, p.Check(version.Add(time.Microsecond)), ErrPreconditionFailed)
+
+	// weak tags never match a write
+	r.Header.Set("If-Match", "W/"+Of(version))
+	p, _ = IfMatch(r)
+	assert.ErrorIs(t, p.Check(version), ErrPreconditionFailed)
+
+	r.Header.Set("If-Match", "*")
+	p, _ = IfMatch(r)
+	assert.NoError(t, p.Check(version))
+}
+
+func TestMatch(t *testing.T) {
+	p := Match(version)
+
+	assert.NoError(t, p.Check(version))
+	assert.ErrorIs(t, p.Check(version.Add(time.Microsecond)), ErrPreconditionFailed)
+}
@@ -0,0 +1,315 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"log"
+	"mime"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/job"
+	"github.com/rstoltzm-profile/video-rental-api/internal/pagination"
+)
+
+type Format string
+
+const (
+	JSON   Format = "application/json"
+	CSV    Format = "text/csv"
+	NDJSON Format = "application/x-ndjson"
+)
+
+// flushEvery is how many rows are written between flushes to the client
+const flushEvery = 100
+
+// Negotiate picks the response format from the Accept header. The first
+// listed CSV or NDJSON type wins, anything else gets JSON.
+func Negotiate(r *http.Request) Format {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		switch Format(mediaType) {
+		case CSV, NDJSON:
+			return Format(mediaType)
+		case JSON:
+			return JSON
+		}
+	}
+	return JSON
+}
+
+// Stream writes rows in format as they are read from the database, JSON as
+// one array. If rows fails before the first row, fail writes the error
+// response. After that the status is already sent, so the response is cut
+// short and the error logged. A client that goes away cancels the request
+// context, which stops the query.
+func Stream[T any](w http.ResponseWriter, format Format, rows iter.Seq2[T, error], fail func(error)) {
+	out := NewWriter[T](w, format)
+	err := out.WriteAll(rows)
+	if err == nil {
+		err = out.Close()
+	}
+	if err == nil {
+		return
+	}
+	if !out.started {
+		fail(err)
+		return
+	}
+	log.Printf("Response cut short after %d rows: %v", out.rows, err)
+}
+
+// Write writes every row in format and closes the output, returning the
+// first error. It suits outputs that aren't a live response, like a job's
+// result.
+func Write[T any](w http.ResponseWriter, format Format, rows iter.Seq2[T, error]) error {
+	out := NewWriter[T](w, format)
+	if err := out.WriteAll(rows); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// Writer encodes rows of T one at a time. CSV columns are T's exported fields
+// named by their json tags.
+type Writer[T any] struct {
+	w       http.ResponseWriter
+	format  Format
+	csv     *csv.Writer
+	ndjson  *json.Encoder
+	fields  []field
+	started bool
+	rows    int
+}
+
+type field struct {
+	name  string
+	index int
+}
+
+func NewWriter[T any](w http.ResponseWriter, format Format) *Writer[T] {
+	out := &Writer[T]{w: w, format: format}
+	switch format {
+	case CSV:
+		out.csv = csv.NewWriter(w)
+		out.fields = fields(reflect.TypeFor[T]())
+	case NDJSON:
+		out.ndjson = json.NewEncoder(w)
+	default:
+		out.format = JSON
+	}
+	return out
+}
+
+// start sends the headers with the first row, so a query that fails straight
+// away can still get an error status.
+func (o *Writer[T]) start() error {
+	if o.started {
+		return nil
+	}
+	o.started = true
+	o.w.Header().Set("Content-Type", string(o.format))
+	o.w.Header().Del("Content-Length")
+	switch o.format {
+	case CSV:
+		header := make([]string, len(o.fields))
+		for i, f := range o.fields {
+			header[i] = f.name
+		}
+		return o.csv.Write(header)
+	case JSON:
+		_, err := io.WriteString(o.w, "[")
+		return err
+	}
+	return nil
+}
+
+// WriteAll writes every row, stopping at the first error from rows or from
+// writing to the client.
+func (o *Writer[T]) WriteAll(rows iter.Seq2[T, error]) error {
+	for row, err := range rows {
+		if err != nil {
+			return err
+		}
+		if err := o.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *Writer[T]) Write(row T) error {
+	if err := o.start(); err != nil {
+		return err
+	}
+
+	var err error
+	switch o.format {
+	case CSV:
+		err = o.csv.Write(record(reflect.ValueOf(row), o.fields))
+	case NDJSON:
+		err = o.ndjson.Encode(row)
+	default:
+		err = o.writeJSON(row)
+	}
+	if err != nil {
+		return err
+	}
+
+	o.rows++
+	if o.rows%flushEvery == 0 {
+		o.flush()
+	}
+	return nil
+}
+
+// writeJSON writes one array element, the same bytes json.Encoder would
+// write for the element of a slice.
+func (o *Writer[T]) writeJSON(row T) error {
+	b, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+	if o.rows > 0 {
+		if _, err := io.WriteString(o.w, ","); err != nil {
+			return err
+		}
+	}
+	_, err = o.w.Write(b)
+	return err
+}
+
+// Close finishes the response, an empty CSV export still gets its header row
+// and an empty JSON array is [].
+func (o *Writer[T]) Close() error {
+	if err := o.start(); err != nil {
+		return err
+	}
+	if o.format == JSON {
+		if _, err := io.WriteString(o.w, "]\n"); err != nil {
+			return err
+		}
+	}
+	o.flush()
+	if o.csv != nil {
+		return o.csv.Error()
+	}
+	return nil
+}
+
+func (o *Writer[T]) flush() {
+	if o.csv != nil {
+		o.csv.Flush()
+	}
+	if f, ok := o.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// fields lists the exported fields of a struct with their json names,
+// skipping fields tagged "-".
+func fields(t reflect.Type) []field {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var out []field
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		out = append(out, field{name: name, index: i})
+	}
+	return out
+}
+
+func record(v reflect.Value, fields []field) []string {
+	out := make([]string, len(fields))
+	for i, f := range fields {
+		out[i] = cell(v.Field(f.index))
+	}
+	return out
+}
+
+// plainNumber is a signed number or a phone like +1 (555) 010-0100, a
+// spreadsheet reads it as a value and it has nothing to run
+var plainNumber = regexp.MustCompile(`^[+-]?[0-9 ().-]+$`)
+
+// escapeFormula keeps customer supplied text, like a name of
+// =HYPERLINK(...), from being run when the export is opened in a
+// spreadsheet. A leading + or - is only escaped ahead of text, E.164 phones
+// and negative numbers are written as they are.
+func escapeFormula(s string) string {
+	if s == "" {
+		return s
+	}
+	if strings.ContainsRune("=@\t\r", rune(s[0])) || strings.ContainsRune("+-", rune(s[0])) && !plainNumber.MatchString(s) {
+		return "'" + s
+	}
+	return s
+}
+
+// cell formats one CSV value. Nil pointers are empty, times are RFC3339 and
+// anything that isn't a plain value is written as JSON. Strings a spreadsheet
+// would run as a formula are quoted with a leading '.
+func cell(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch x := v.Interface().(type) {
+	case time.Time:
+		return x.Format(time.RFC3339)
+	case string:
+		return escapeFormula(x)
+	case bool:
+		return strconv.FormatBool(x)
+	case float32:
+		return strconv.FormatFloat(float64(x), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	}
+
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		return fmt.Sprint(v.Interface())
+	}
+	return string(b)
+}
+
+// Page reads limit and offset for format. JSON keeps the usual paging,
+// exports and async jobs return every row unless the client asks for a page.
+func Page(r *http.Request, format Format) (pagination.Page, error) {
+	if format == JSON && !job.Async(r) {
+		return pagination.Parse(r)
+	}
+	return pagination.ParseExport(r)
+}
@@ -0,0 +1,179 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type row struct {
+	ID      int        `json:"id"`
+	Name    string     `json:"name"`
+	Amount  float64    `json:"amount"`
+	Due     *time.Time `json:"due"`
+	Secret  string     `json:"-"`
+	private int
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   Format
+	}{
+		{"", JSON},
+		{"*/*", JSON},
+		{"text/csv", CSV},
+		{"text/csv; charset=utf-8", CSV},
+		{"application/x-ndjson", NDJSON},
+		{"text/html, text/csv;q=0.9", CSV},
+		{"application/json, text/csv", JSON},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/v1/rentals", nil)
+		r.Header.Set("Accept", tt.accept)
+		assert.Equal(t, tt.want, Negotiate(r), tt.accept)
+	}
+}
+
+func rows(values ...row) iter.Seq2[row, error] {
+	return func(yield func(row, error) bool) {
+		for _, v := range values {
+			if !yield(v, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestStream_CSV(t *testing.T) {
+	due := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	w := httptest.NewRecorder()
+
+	Stream(w, CSV, rows(
+		row{ID: 1, Name: "Smith, Mary", Amount: 2.99, Due: &due, Secret: "x"},
+		row{ID: 2, Name: "Lee", Amount: 0.5},
+	), func(err error) { t.Fatal(err) })
+
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	assert.Equal(t, "id,name,amount,due\n1,\"Smith, Mary\",2.99,2022-05-01T10:00:00Z\n2,Lee,0.5,\n", w.Body.String())
+}
+
+func TestStream_CSVEscapesFormulas(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	Stream(w, CSV, rows(
+		row{ID: 1, Name: "=HYPERLINK(\"http://evil.example\")"},
+		row{ID: 2, Name: "+SUM(A1)"},
+		row{ID: 3, Name: "-1+cmd|' /C calc'!A0", Amount: -1.5},
+		row{ID: 4, Name: "@SUM(A1)"},
+		row{ID: 5, Name: "\tTab"},
+		row{ID: 6, Name: "\rReturn"},
+		row{ID: 7, Name: "Mary-Jane"},
+	), func(err error) { t.Fatal(err) })
+
+	assert.Equal(t, "id,name,amount,due\n"+
+		"1,\"'=HYPERLINK(\"\"http://evil.example\"\")\",0,\n"+
+		"2,'+SUM(A1),0,\n"+
+		"3,'-1+cmd|' /C calc'!A0,-1.5,\n"+
+		"4,'@SUM(A1),0,\n"+
+		"5,'\tTab,0,\n"+
+		"6,\"'\rReturn\",0,\n"+
+		"7,Mary-Jane,0,\n", w.Body.String())
+}
+
+func TestStream_CSVKeepsNumbersAndPhones(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	Stream(w, CSV, rows(
+		row{ID: 1, Name: "+14155550100"},
+		row{ID: 2, Name: "+1 (555) 010-0100"},
+		row{ID: 3, Name: "-1.5"},
+	), func(err error) { t.Fatal(err) })
+
+	assert.Equal(t, "id,name,amount,due\n"+
+		"1,+14155550100,0,\n"+
+		"2,+1 (555) 010-0100,0,\n"+
+		"3,-1.5,0,\n", w.Body.String())
+}
+
+func TestStream_EmptyCSVHasHeader(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	Stream(w, CSV, rows(), func(err error) { t.Fatal(err) })
+
+	assert.Equal(t, "id,name,amount,due\n", w.Body.String())
+}
+
+func TestStream_NDJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	Stream(w, NDJSON, rows(row{ID: 1, Name: "Lee"}, row{ID: 2, Name: "Kim"}), func(err error) { t.Fatal(err) })
+
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	assert.Equal(t, `{"id":1,"name":"Lee","amount":0,"due":null}`+"\n"+`{"id":2,"name":"Kim","amount":0,"due":null}`+"\n", w.Body.String())
+}
+
+func TestStream_JSONArray(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	Stream(w, JSON, rows(row{ID: 1, Name: "<Lee>"}, row{ID: 2}), func(err error) { t.Fatal(err) })
+
+	var want bytes.Buffer
+	json.NewEncoder(&want).Encode([]row{{ID: 1, Name: "<Lee>"}, {ID: 2}})
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, want.String(), w.Body.String())
+}
+
+func TestStream_StopsWhenClientGoesAway(t *testing.T) {
+	w := &failingWriter{ResponseWriter: httptest.NewRecorder()}
+	var yielded int
+
+	Stream(w, NDJSON, func(yield func(row, error) bool) {
+		for {
+			yielded++
+			if !yield(row{ID: yielded}, nil) {
+				return
+			}
+		}
+	}, func(err error) { t.Fatal("headers were already sent") })
+
+	assert.Equal(t, 1, yielded)
+}
+
+// failingWriter fails every write like a closed connection
+type failingWriter struct {
+	http.ResponseWriter
+}
+
+func (w *failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestStream_EmptyJSONIsArray(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	Stream(w, JSON, rows(), func(err error) { t.Fatal(err) })
+
+	assert.Equal(t, "[]\n", w.Body.String())
+}
+
+func TestStream_ErrorBeforeFirstRow(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	Stream(w, CSV, func(yield func(row, error) bool) {
+		yield(row{}, errors.New("query failed"))
+	}, func(err error) {
+		http.Error(w, "Failed", http.StatusInternalServerError)
+	})
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "Failed\n", w.Body.String())
+}
@@ -0,0 +1,54 @@
+package film
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/cache"
+)
+
+const filmsKey = "films"
+
+func filmKey(id int) string {
+	return fmt.Sprintf("film:%d", id)
+}
+
+func filmDetailsKey(id int) string {
+	return fmt.Sprintf("film:%d:details", id)
+}
+
+// CachedService caches the film catalog reads of the Service it wraps.
+// Title search is passed straight through, its keys are unbounded.
+type CachedService struct {
+	Service
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+func NewCachedService(next Service, c cache.Cache, ttl time.Duration) *CachedService {
+	return &CachedService{Service: next, cache: c, ttl: ttl}
+}
+
+func (s *CachedService) GetFilms(ctx context.Context) ([]Film, error) {
+	return cache.Fetch(ctx, s.cache, filmsKey, s.ttl, s.Service.GetFilms)
+}
+
+func (s *CachedService) GetFilmByID(ctx context.Context, id int) (Film, error) {
+	return cache.Fetch(ctx, s.cache, filmKey(id), s.ttl, func(ctx context.Context) (Film, error) {
+		return s.Service.GetFilmByID(ctx, id)
+	})
+}
+
+func (s *CachedService) GetFilmWithActorsAndCategoriesByID(ctx context.Context, id int) (FilmWithActorsCategories, error) {
+	return cache.Fetch(ctx, s.cache, filmDetailsKey(id), s.ttl, func(ctx context.Context) (FilmWithActorsCategories, error) {
+		return s.Service.GetFilmWithActorsAndCategoriesByID(ctx, id)
+	})
+}
+
+// Invalidate drops everything cached about a film, along with the film list.
+// Writes to a film, its actors or its categories must call it once they
+// commit.
+func (s *CachedService) Invalidate(ctx context.Context, id int) error {
+	return s.cache.Delete(ctx, filmsKey, filmKey(id), filmDetailsKey(id))
+}
@@ -0,0 +1,85 @@
+package film
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockService struct {
+	mock.Mock
+}
+
+func (m *mockService) GetFilms(ctx context.Context) ([]Film, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]Film), args.Error(1)
+}
+
+func (m *mockService) GetFilmByID(ctx context.Context, id int) (Film, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Film), args.Error(1)
+}
+
+func (m *mockService) SearchByTitle(ctx context.Context, title string) ([]Film, error) {
+	args := m.Called(ctx, title)
+	return args.Get(0).([]Film), args.Error(1)
+}
+
+func (m *mockService) GetFilmWithActorsAndCategoriesByID(ctx context.Context, id int) (FilmWithActorsCategories, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(FilmWithActorsCategories), args.Error(1)
+}
+
+func (m *mockService) GetFilmsWithActorsAndCategoriesByIDs(ctx context.Context, ids []int) ([]FilmWithActorsCategories, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]FilmWithActorsCategories), args.Error(1)
+}
+
+func TestCachedService_GetFilmByID(t *testing.T) {
+	next := new(mockService)
+	svc := NewCachedService(next, cache.NewMemory(10), time.Minute)
+
+	next.On("GetFilmByID", mock.Anything, 1).Return(Film{Title: "ACADEMY DINOSAUR"}, nil).Once()
+
+	for range 2 {
+		film, err := svc.GetFilmByID(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "ACADEMY DINOSAUR", film.Title)
+	}
+	next.AssertExpectations(t)
+}
+
+func TestCachedService_Invalidate(t *testing.T) {
+	next := new(mockService)
+	svc := NewCachedService(next, cache.NewMemory(10), time.Minute)
+
+	details := FilmWithActorsCategories{Title: "ACADEMY DINOSAUR", Actors: []string{"PENELOPE GUINESS"}}
+	next.On("GetFilms", mock.Anything).Return([]Film{{Title: "ACADEMY DINOSAUR"}}, nil).Twice()
+	next.On("GetFilmWithActorsAndCategoriesByID", mock.Anything, 1).Return(details, nil).Twice()
+
+	svc.GetFilms(context.Background())
+	svc.GetFilmWithActorsAndCategoriesByID(context.Background(), 1)
+	assert.NoError(t, svc.Invalidate(context.Background(), 1))
+	svc.GetFilms(context.Background())
+	got, err := svc.GetFilmWithActorsAndCategoriesByID(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, details, got)
+	next.AssertExpectations(t)
+}
+
+func TestCachedService_SearchNotCached(t *testing.T) {
+	next := new(mockService)
+	svc := NewCachedService(next, cache.NewMemory(10), time.Minute)
+
+	next.On("SearchByTitle", mock.Anything, "academy").Return([]Film{{Title: "ACADEMY DINOSAUR"}}, nil).Twice()
+
+	svc.SearchByTitle(context.Background(), "academy")
+	svc.SearchByTitle(context.Background(), "academy")
+
+	next.AssertExpectations(t)
+}
@@ -6,7 +6,6 @@ import (
 	"log"
 	"net/http"
 	"strconv"
-	"strings"
 )
 
 type Handler struct {
@@ -24,7 +23,8 @@ func NewHandler(service Service) *Handler {
 // @Produce      json
 // @Success      200  {array}   film.Film
 // @Failure      500  {string}  string "Internal Server Error"
-// @Router       /films [get]
+// @Security     ApiKeyAuth
+// @Router       /v1/films [get]
 func (h *Handler) GetFilms(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -48,7 +48,8 @@ func (h *Handler) GetFilms(w http.ResponseWriter, r *http.Request) {
 // @Failure      400  {string}  string "Invalid film ID"
 // @Failure      404  {string}  string "Film not found"
 // @Failure      500  {string}  string "Internal Server Error"
-// @Router       /films/{id} [get]
+// @Security     ApiKeyAuth
+// @Router       /v1/films/{id} [get]
 func (h *Handler) GetFilmByID(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	idStr := r.PathValue("id")
@@ -75,7 +76,8 @@ func (h *Handler) GetFilmByID(w http.ResponseWriter, r *http.Request) {
 // @Success      200    {array}   film.Film
 // @Failure      400    {string}  string "Missing title query parameter"
 // @Failure      500    {string}  string "Internal Server Error"
-// @Router       /films/search [get]
+// @Security     ApiKeyAuth
+// @Router       /v1/films/search [get]
 func (h *Handler) SearchFilm(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	title := r.URL.Query().Get("title")
@@ -106,16 +108,10 @@ func (h *Handler) SearchFilm(w http.ResponseWriter, r *http.Request) {
 // @Success      200  {object}  film.FilmWithActorsCategories
 // @Failure      400  {string}  string "Invalid film ID"
 // @Failure      404  {string}  string "Film not found"
-// @Router       /films/{id}/with-actors-categories [get]
+// @Security     ApiKeyAuth
+// @Router       /v1/films/{id}/with-actors-categories [get]
 func (h *Handler) GetFilmWithActorsAndCategoriesByID(w http.ResponseWriter, r *http.Request) {
-	parts := strings.Split(r.URL.Path, "/")
-
-	if len(parts) != 4 || parts[3] != "with-actors-categories" {
-		http.NotFound(w, r)
-		return
-	}
-
-	id, err := strconv.Atoi(parts[2])
+	id, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
 		http.Error(w, "Invalid film ID", http.StatusBadRequest)
 		return
@@ -1,6 +1,7 @@
 package film
 
 type Film struct {
+	ID          int    `json:"id"`
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	ReleaseYear int    `json:"release_year"`
@@ -9,6 +10,7 @@ type Film struct {
 }
 
 type FilmWithActorsCategories struct {
+	ID          int      `json:"id"`
 	Title       string   `json:"title"`
 	Description string   `json:"description"`
 	ReleaseYear int      `json:"release_year"`
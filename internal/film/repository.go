@@ -10,7 +10,7 @@ import (
 )
 
 const baseFilmQuery = `
-	SELECT title, description, release_year, language.name, rating
+	SELECT film.film_id, title, description, release_year, language.name, rating
 	FROM film
 	INNER JOIN language on film.language_id = language.language_id
 `
@@ -39,6 +39,7 @@ type FilmReader interface {
 	GetFilmByID(ctx context.Context, id int) (Film, error)
 	FindByTitle(ctx context.Context, title string) ([]Film, error)
 	FindFilmWithActorsAndCategoriesByID(ctx context.Context, id int) (FilmWithActorsCategories, error)
+	FindFilmsWithActorsAndCategoriesByIDs(ctx context.Context, ids []int) ([]FilmWithActorsCategories, error)
 }
 
 type Repository interface {
@@ -73,7 +74,7 @@ func (r *repository) GetFilms(ctx context.Context) ([]Film, error) {
 	var films []Film
 	for rows.Next() {
 		var c Film
-		if err := rows.Scan(&c.Title, &c.Description, &c.ReleaseYear, &c.Language, &c.Rating); err != nil {
+		if err := rows.Scan(&c.ID, &c.Title, &c.Description, &c.ReleaseYear, &c.Language, &c.Rating); err != nil {
 			return nil, err
 		}
 		films = append(films, c)
@@ -85,7 +86,7 @@ func (r *repository) GetFilmByID(ctx context.Context, id int) (Film, error) {
 	var c Film
 	query := baseFilmQuery + ` WHERE film.film_id = $1`
 
-	err := r.pool.QueryRow(ctx, query, id).Scan(&c.Title, &c.Description, &c.ReleaseYear, &c.Language, &c.Rating)
+	err := r.pool.QueryRow(ctx, query, id).Scan(&c.ID, &c.Title, &c.Description, &c.ReleaseYear, &c.Language, &c.Rating)
 
 	return c, err
 }
@@ -101,7 +102,7 @@ func (r *repository) FindByTitle(ctx context.Context, title string) ([]Film, err
 	var films []Film
 	for rows.Next() {
 		var c Film
-		if err := rows.Scan(&c.Title, &c.Description, &c.ReleaseYear, &c.Language, &c.Rating); err != nil {
+		if err := rows.Scan(&c.ID, &c.Title, &c.Description, &c.ReleaseYear, &c.Language, &c.Rating); err != nil {
 			return nil, err
 		}
 		films = append(films, c)
@@ -123,12 +124,11 @@ func (r *repository) FindFilmWithActorsAndCategoriesByID(ctx context.Context, id
 	actorsMap := make(map[string]struct{})
 
 	for rows.Next() {
-		var filmID int
 		var actor string
 		var category string
 
 		err := rows.Scan(
-			&filmID,
+			&film.ID,
 			&film.Title,
 			&film.Description,
 			&film.ReleaseYear,
@@ -174,3 +174,42 @@ func (r *repository) FindFilmWithActorsAndCategoriesByID(ctx context.Context, id
 
 	return film, nil
 }
+
+// FindFilmsWithActorsAndCategoriesByIDs loads many films in one query, ids
+// that don't exist are left out.
+func (r *repository) FindFilmsWithActorsAndCategoriesByIDs(ctx context.Context, ids []int) ([]FilmWithActorsCategories, error) {
+	query := `
+	SELECT
+		film.film_id,
+		film.title,
+		film.description,
+		film.release_year,
+		language.name,
+		film.rating,
+		ARRAY(
+			SELECT category.name
+			FROM film_category INNER JOIN category ON film_category.category_id = category.category_id
+			WHERE film_category.film_id = film.film_id
+			ORDER BY category.name
+		),
+		ARRAY(
+			SELECT actor.first_name || ' ' || actor.last_name
+			FROM film_actor INNER JOIN actor ON film_actor.actor_id = actor.actor_id
+			WHERE film_actor.film_id = film.film_id
+			ORDER BY 1
+		)
+	FROM film
+	INNER JOIN language ON film.language_id = language.language_id
+	WHERE film.film_id = ANY($1)
+	`
+	rows, err := r.pool.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (FilmWithActorsCategories, error) {
+		var f FilmWithActorsCategories
+		err := row.Scan(&f.ID, &f.Title, &f.Description, &f.ReleaseYear, &f.Language, &f.Rating, &f.Categories, &f.Actors)
+		f.Language = strings.TrimSpace(f.Language)
+		return f, err
+	})
+}
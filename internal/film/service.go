@@ -9,6 +9,7 @@ type Service interface {
 	GetFilmByID(ctx context.Context, id int) (Film, error)
 	SearchByTitle(ctx context.Context, title string) ([]Film, error)
 	GetFilmWithActorsAndCategoriesByID(ctx context.Context, id int) (FilmWithActorsCategories, error)
+	GetFilmsWithActorsAndCategoriesByIDs(ctx context.Context, ids []int) ([]FilmWithActorsCategories, error)
 }
 
 type service struct {
@@ -38,3 +39,7 @@ func (s *service) SearchByTitle(ctx context.Context, title string) ([]Film, erro
 func (s *service) GetFilmWithActorsAndCategoriesByID(ctx context.Context, id int) (FilmWithActorsCategories, error) {
 	return s.reader.FindFilmWithActorsAndCategoriesByID(ctx, id)
 }
+
+func (s *service) GetFilmsWithActorsAndCategoriesByIDs(ctx context.Context, ids []int) ([]FilmWithActorsCategories, error) {
+	return s.reader.FindFilmsWithActorsAndCategoriesByIDs(ctx, ids)
+}
@@ -0,0 +1,93 @@
+package graph
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/rstoltzm-profile/video-rental-api/internal/customer"
+	"github.com/rstoltzm-profile/video-rental-api/internal/film"
+	"github.com/rstoltzm-profile/video-rental-api/internal/inventory"
+	"github.com/rstoltzm-profile/video-rental-api/internal/payment"
+	"github.com/rstoltzm-profile/video-rental-api/internal/store"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// Services are the domain services the graph resolves through, the same ones
+// that serve /v1.
+type Services struct {
+	Customers customer.Service
+	Films     film.Service
+	Inventory inventory.Service
+	Payments  payment.Service
+	Stores    store.Service
+}
+
+type Handler struct {
+	schema   *graphql.Schema
+	services Services
+}
+
+// NewHandler parses the schema, it panics if the schema and resolvers
+// disagree. Resolvers of list items run concurrently so their loads land in
+// the same batch, MaxParallelism bounds how many at once.
+func NewHandler(svc Services) *Handler {
+	schema := graphql.MustParseSchema(schemaSDL, &resolver{svc: svc},
+		graphql.MaxParallelism(maxBatch),
+		graphql.MaxDepth(10),
+	)
+	return &Handler{schema: schema, services: svc}
+}
+
+type request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// ServeHTTP godoc
+// @Summary      GraphQL query
+// @Description  Runs a read-only GraphQL query over customers, films, stores, inventory, rentals and payments. Field errors are reported in errors with a 200 status, see docs/api/api-graphql.md for the schema.
+// @Tags         graphql
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      object  true  "query, operationName and variables"
+// @Success      200      {object}  object
+// @Failure      400      {string}  string "Invalid JSON"
+// @Router       /graphql [post]
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Query == "" {
+		http.Error(w, "Missing query", http.StatusBadRequest)
+		return
+	}
+
+	ctx := withLoaders(r.Context(), newLoaders(h.services))
+	response := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// failed logs err and returns the message clients see, database errors are
+// not passed on.
+func failed(what string, err error) error {
+	log.Printf("graphql: %s: %v", what, err)
+	return fmt.Errorf("Failed to fetch %s", what)
+}
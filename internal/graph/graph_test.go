@@ -0,0 +1,178 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/customer"
+	"github.com/rstoltzm-profile/video-rental-api/internal/film"
+	"github.com/rstoltzm-profile/video-rental-api/internal/payment"
+	"github.com/rstoltzm-profile/video-rental-api/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// the mocks embed the service so only the methods a test calls are needed
+
+type mockCustomers struct {
+	customer.Service
+	mock.Mock
+}
+
+func (m *mockCustomers) GetCustomersByIDs(ctx context.Context, ids []int) ([]customer.Customer, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]customer.Customer), args.Error(1)
+}
+
+func (m *mockCustomers) GetCustomerRentalsByID(ctx context.Context, id int, filter customer.RentalHistoryFilter) ([]customer.CustomerRentals, int, error) {
+	args := m.Called(ctx, id, filter)
+	return args.Get(0).([]customer.CustomerRentals), args.Int(1), args.Error(2)
+}
+
+type mockFilms struct {
+	film.Service
+	mock.Mock
+}
+
+func (m *mockFilms) GetFilmsWithActorsAndCategoriesByIDs(ctx context.Context, ids []int) ([]film.FilmWithActorsCategories, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]film.FilmWithActorsCategories), args.Error(1)
+}
+
+type mockPayments struct {
+	payment.Service
+	mock.Mock
+}
+
+func (m *mockPayments) GetPayments(ctx context.Context, filter payment.PaymentFilter) (iter.Seq2[payment.PaymentRecord, error], int, error) {
+	args := m.Called(ctx, filter)
+	records := args.Get(0).([]payment.PaymentRecord)
+	return func(yield func(payment.PaymentRecord, error) bool) {
+		for _, p := range records {
+			if !yield(p, nil) {
+				return
+			}
+		}
+	}, args.Int(1), args.Error(2)
+}
+
+type mockStores struct {
+	store.Service
+	mock.Mock
+}
+
+func (m *mockStores) GetStores(ctx context.Context) ([]store.Store, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]store.Store), args.Error(1)
+}
+
+// sameIDs matches a batch of ids in any order
+func sameIDs(want ...int) any {
+	return mock.MatchedBy(func(ids []int) bool {
+		got := slices.Clone(ids)
+		slices.Sort(got)
+		return slices.Equal(got, want)
+	})
+}
+
+func query(t *testing.T, h http.Handler, q string) map[string]any {
+	body, _ := json.Marshal(map[string]any{"query": q})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]any
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	return resp
+}
+
+func TestHandler_BatchesLookups(t *testing.T) {
+	customers, payments, stores := new(mockCustomers), new(mockPayments), new(mockStores)
+	h := NewHandler(Services{Customers: customers, Payments: payments, Stores: stores})
+
+	paid := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+	payments.On("GetPayments", mock.Anything, mock.Anything).Return([]payment.PaymentRecord{
+		{ID: 1, CustomerID: 1, StoreID: 1, Amount: 2.99, PaymentDate: paid},
+		{ID: 2, CustomerID: 2, StoreID: 2, Amount: 0.99, PaymentDate: paid},
+		{ID: 3, CustomerID: 1, StoreID: 1, Amount: 4.99, PaymentDate: paid},
+	}, 3, nil)
+	customers.On("GetCustomersByIDs", mock.Anything, sameIDs(1, 2)).Return([]customer.Customer{
+		{ID: 1, FirstName: "MARY"}, {ID: 2, FirstName: "PATRICIA"},
+	}, nil).Once()
+	stores.On("GetStores", mock.Anything).Return([]store.Store{{ID: 1}, {ID: 2}}, nil).Once()
+
+	resp := query(t, h, `{ payments { total items { id customer { firstName } store { id } } } }`)
+
+	assert.Nil(t, resp["errors"])
+	page := resp["data"].(map[string]any)["payments"].(map[string]any)
+	assert.Equal(t, float64(3), page["total"])
+	items := page["items"].([]any)
+	assert.Len(t, items, 3)
+	assert.Equal(t, "PATRICIA", items[1].(map[string]any)["customer"].(map[string]any)["firstName"])
+	customers.AssertExpectations(t)
+	stores.AssertExpectations(t)
+}
+
+func TestHandler_CustomerRentals(t *testing.T) {
+	customers, films := new(mockCustomers), new(mockFilms)
+	h := NewHandler(Services{Customers: customers, Films: films})
+
+	customers.On("GetCustomersByIDs", mock.Anything, []int{1}).Return([]customer.Customer{{ID: 1, FirstName: "MARY"}}, nil)
+	customers.On("GetCustomerRentalsByID", mock.Anything, 1, mock.MatchedBy(func(f customer.RentalHistoryFilter) bool {
+		return f.Status == customer.RentalStatusOpen && f.Page.Limit == 2
+	})).Return([]customer.CustomerRentals{
+		{RentalID: 10, FilmID: 1, InventoryID: 1, StoreID: 1},
+		{RentalID: 11, FilmID: 2, InventoryID: 9, StoreID: 1},
+	}, 2, nil)
+	films.On("GetFilmsWithActorsAndCategoriesByIDs", mock.Anything, sameIDs(1, 2)).Return([]film.FilmWithActorsCategories{
+		{ID: 1, Title: "ACADEMY DINOSAUR", Actors: []string{"PENELOPE GUINESS"}},
+		{ID: 2, Title: "ACE GOLDFINGER"},
+	}, nil).Once()
+
+	resp := query(t, h, `{ customer(id: 1) { firstName rentals(status: OPEN, limit: 2) { total items { id film { title actors { name } } } } } }`)
+
+	assert.Nil(t, resp["errors"])
+	rentals := resp["data"].(map[string]any)["customer"].(map[string]any)["rentals"].(map[string]any)
+	items := rentals["items"].([]any)
+	assert.Equal(t, "ACADEMY DINOSAUR", items[0].(map[string]any)["film"].(map[string]any)["title"])
+	films.AssertExpectations(t)
+}
+
+func TestHandler_CustomerNotFound(t *testing.T) {
+	customers := new(mockCustomers)
+	h := NewHandler(Services{Customers: customers})
+
+	customers.On("GetCustomersByIDs", mock.Anything, []int{99999}).Return([]customer.Customer{}, nil)
+
+	resp := query(t, h, `{ customer(id: 99999) { id } }`)
+
+	assert.Nil(t, resp["errors"])
+	assert.Equal(t, map[string]any{"customer": nil}, resp["data"])
+}
+
+func TestHandler_LimitTooLarge(t *testing.T) {
+	h := NewHandler(Services{})
+
+	resp := query(t, h, `{ customers(limit: 500) { total } }`)
+
+	errs := resp["errors"].([]any)
+	assert.Equal(t, "limit must be between 1 and 100", errs[0].(map[string]any)["message"])
+}
+
+func TestHandler_InvalidJSON(t *testing.T) {
+	h := NewHandler(Services{})
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query":`))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,102 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Loader batches the keys resolvers ask for within wait of each other into
+// one call of fetch, so a list of rentals loads its films in one query rather
+// than one per rental. Results, errors included, are kept for the life of the
+// loader, which is one request.
+type Loader[K comparable, V any] struct {
+	fetch    func(ctx context.Context, keys []K) (map[K]V, error)
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	results map[K]*result[V]
+	pending *batch[K, V]
+}
+
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	found bool
+	err   error
+}
+
+type batch[K comparable, V any] struct {
+	keys    []K
+	results []*result[V]
+}
+
+// NewLoader returns a loader that calls fetch with at most maxBatch keys.
+// Keys missing from the map fetch returns were not found.
+func NewLoader[K comparable, V any](wait time.Duration, maxBatch int, fetch func(ctx context.Context, keys []K) (map[K]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		results:  map[K]*result[V]{},
+	}
+}
+
+// Load returns the value for key once its batch has been fetched. found is
+// false when fetch had no value for the key.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (value V, found bool, err error) {
+	l.mu.Lock()
+	r, ok := l.results[key]
+	if !ok {
+		r = &result[V]{done: make(chan struct{})}
+		l.results[key] = r
+		l.enqueue(ctx, key, r)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return r.value, r.found, r.err
+	case <-ctx.Done():
+		return value, false, ctx.Err()
+	}
+}
+
+// enqueue adds key to the pending batch, starting one if there is none.
+// Callers hold l.mu.
+func (l *Loader[K, V]) enqueue(ctx context.Context, key K, r *result[V]) {
+	b := l.pending
+	if b == nil {
+		b = &batch[K, V]{}
+		l.pending = b
+		time.AfterFunc(l.wait, func() {
+			l.mu.Lock()
+			due := l.pending == b
+			if due {
+				l.pending = nil
+			}
+			l.mu.Unlock()
+			if due {
+				l.run(ctx, b)
+			}
+		})
+	}
+
+	b.keys = append(b.keys, key)
+	b.results = append(b.results, r)
+	if len(b.keys) >= l.maxBatch {
+		l.pending = nil
+		go l.run(ctx, b)
+	}
+}
+
+func (l *Loader[K, V]) run(ctx context.Context, b *batch[K, V]) {
+	values, err := l.fetch(ctx, b.keys)
+	for i, key := range b.keys {
+		r := b.results[i]
+		r.value, r.found = values[key]
+		r.err = err
+		close(r.done)
+	}
+}
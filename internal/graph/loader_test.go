@@ -0,0 +1,93 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder is a fetch that remembers the batches it was called with
+type recorder struct {
+	mu      sync.Mutex
+	batches [][]int
+}
+
+func (r *recorder) fetch(ctx context.Context, keys []int) (map[int]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	batch := slices.Clone(keys)
+	slices.Sort(batch)
+	r.batches = append(r.batches, batch)
+
+	values := map[int]string{}
+	for _, k := range keys {
+		if k != 404 {
+			values[k] = "film"
+		}
+	}
+	return values, nil
+}
+
+func loadAll(l *Loader[int, string], keys ...int) {
+	var wg sync.WaitGroup
+	for _, k := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.Load(context.Background(), k)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLoader_Batches(t *testing.T) {
+	r := &recorder{}
+	l := NewLoader(10*time.Millisecond, 100, r.fetch)
+
+	loadAll(l, 3, 1, 2, 1, 3)
+
+	assert.Equal(t, [][]int{{1, 2, 3}}, r.batches)
+}
+
+func TestLoader_CachesResults(t *testing.T) {
+	r := &recorder{}
+	l := NewLoader(time.Millisecond, 100, r.fetch)
+
+	v, found, err := l.Load(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "film", v)
+
+	_, found, err = l.Load(context.Background(), 404)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	l.Load(context.Background(), 1)
+	assert.Equal(t, [][]int{{1}, {404}}, r.batches)
+}
+
+func TestLoader_MaxBatch(t *testing.T) {
+	r := &recorder{}
+	l := NewLoader(10*time.Millisecond, 2, r.fetch)
+
+	loadAll(l, 1, 2, 3)
+
+	assert.Len(t, r.batches, 2)
+}
+
+func TestLoader_Error(t *testing.T) {
+	down := errors.New("connection refused")
+	l := NewLoader(time.Millisecond, 100, func(ctx context.Context, keys []int) (map[int]string, error) {
+		return nil, down
+	})
+
+	_, found, err := l.Load(context.Background(), 1)
+
+	assert.ErrorIs(t, err, down)
+	assert.False(t, found)
+}
@@ -0,0 +1,64 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/customer"
+	"github.com/rstoltzm-profile/video-rental-api/internal/film"
+	"github.com/rstoltzm-profile/video-rental-api/internal/inventory"
+	"github.com/rstoltzm-profile/video-rental-api/internal/store"
+)
+
+const (
+	batchWait = 2 * time.Millisecond
+	maxBatch  = 100
+)
+
+// loaders are made per request, their results only live as long as it does.
+type loaders struct {
+	customers    *Loader[int, customer.Customer]
+	films        *Loader[int, film.FilmWithActorsCategories]
+	availability *Loader[int, inventory.FilmAvailability]
+	stores       *Loader[int, store.Store]
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+func newLoaders(svc Services) *loaders {
+	return &loaders{
+		customers: NewLoader(batchWait, maxBatch, func(ctx context.Context, ids []int) (map[int]customer.Customer, error) {
+			customers, err := svc.Customers.GetCustomersByIDs(ctx, ids)
+			return byID(customers, func(c customer.Customer) int { return c.ID }), err
+		}),
+		films: NewLoader(batchWait, maxBatch, func(ctx context.Context, ids []int) (map[int]film.FilmWithActorsCategories, error) {
+			films, err := svc.Films.GetFilmsWithActorsAndCategoriesByIDs(ctx, ids)
+			return byID(films, func(f film.FilmWithActorsCategories) int { return f.ID }), err
+		}),
+		availability: NewLoader(batchWait, maxBatch, func(ctx context.Context, ids []int) (map[int]inventory.FilmAvailability, error) {
+			films, err := svc.Inventory.GetFilmsAvailability(ctx, ids)
+			return byID(films, func(a inventory.FilmAvailability) int { return a.FilmID }), err
+		}),
+		// there are only a few stores, the first load reads them all
+		stores: NewLoader(batchWait, maxBatch, func(ctx context.Context, ids []int) (map[int]store.Store, error) {
+			stores, err := svc.Stores.GetStores(ctx)
+			return byID(stores, func(s store.Store) int { return s.ID }), err
+		}),
+	}
+}
+
+func byID[V any](values []V, id func(V) int) map[int]V {
+	m := make(map[int]V, len(values))
+	for _, v := range values {
+		m[id(v)] = v
+	}
+	return m
+}
@@ -0,0 +1,197 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/rstoltzm-profile/video-rental-api/internal/customer"
+	"github.com/rstoltzm-profile/video-rental-api/internal/film"
+	"github.com/rstoltzm-profile/video-rental-api/internal/pagination"
+	"github.com/rstoltzm-profile/video-rental-api/internal/payment"
+)
+
+// resolver is the Query type
+type resolver struct {
+	svc Services
+}
+
+// pageOf checks limit and offset the way pagination.Parse does for /v1, the
+// schema defaults them to the first 20.
+func pageOf(limit, offset int32) (pagination.Page, error) {
+	if limit < 1 || limit > pagination.MaxLimit {
+		return pagination.Page{}, fmt.Errorf("limit must be between 1 and %d", pagination.MaxLimit)
+	}
+	if offset < 0 {
+		return pagination.Page{}, fmt.Errorf("offset must be 0 or greater")
+	}
+	return pagination.Page{Limit: int(limit), Offset: int(offset)}, nil
+}
+
+func intPtr(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(*v)
+	return &i
+}
+
+func (r *resolver) Customer(ctx context.Context, args struct{ ID int32 }) (*customerResolver, error) {
+	return loadCustomer(ctx, r.svc, int(args.ID))
+}
+
+type customersArgs struct {
+	Q       *string
+	StoreID *int32
+	Active  *bool
+	Limit   int32
+	Offset  int32
+}
+
+func (r *resolver) Customers(ctx context.Context, args customersArgs) (*customerPageResolver, error) {
+	page, err := pageOf(args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+	filter := customer.CustomerFilter{StoreID: intPtr(args.StoreID), Active: args.Active, Page: page}
+	if args.Q != nil {
+		filter.Query = *args.Q
+	}
+
+	customers, total, err := r.svc.Customers.GetCustomers(ctx, filter)
+	if err != nil {
+		return nil, failed("customers", err)
+	}
+	result := &customerPageResolver{total: total, items: []*customerResolver{}}
+	for c, err := range customers {
+		if err != nil {
+			return nil, failed("customers", err)
+		}
+		result.items = append(result.items, &customerResolver{svc: r.svc, customer: c})
+	}
+	return result, nil
+}
+
+func (r *resolver) Film(ctx context.Context, args struct{ ID int32 }) (*filmResolver, error) {
+	return loadFilm(ctx, r.svc, int(args.ID))
+}
+
+func (r *resolver) Films(ctx context.Context, args struct{ Title *string }) ([]*filmResolver, error) {
+	var films []film.Film
+	var err error
+	if args.Title != nil {
+		films, err = r.svc.Films.SearchByTitle(ctx, *args.Title)
+	} else {
+		films, err = r.svc.Films.GetFilms(ctx)
+	}
+	if err != nil {
+		return nil, failed("films", err)
+	}
+
+	result := make([]*filmResolver, len(films))
+	for i, f := range films {
+		result[i] = &filmResolver{svc: r.svc, film: film.FilmWithActorsCategories{
+			ID:          f.ID,
+			Title:       f.Title,
+			Description: f.Description,
+			ReleaseYear: f.ReleaseYear,
+			Language:    strings.TrimSpace(f.Language),
+			Rating:      f.Rating,
+		}}
+	}
+	return result, nil
+}
+
+func (r *resolver) Store(ctx context.Context, args struct{ ID int32 }) (*storeResolver, error) {
+	return loadStore(ctx, r.svc, int(args.ID))
+}
+
+func (r *resolver) Stores(ctx context.Context) ([]*storeResolver, error) {
+	stores, err := r.svc.Stores.GetStores(ctx)
+	if err != nil {
+		return nil, failed("stores", err)
+	}
+	result := make([]*storeResolver, len(stores))
+	for i, s := range stores {
+		result[i] = &storeResolver{svc: r.svc, store: s}
+	}
+	return result, nil
+}
+
+type paymentsArgs struct {
+	CustomerID *int32
+	StoreID    *int32
+	From       *graphql.Time
+	To         *graphql.Time
+	Limit      int32
+	Offset     int32
+}
+
+func (r *resolver) Payments(ctx context.Context, args paymentsArgs) (*paymentPageResolver, error) {
+	page, err := pageOf(args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+	filter := payment.PaymentFilter{CustomerID: intPtr(args.CustomerID), StoreID: intPtr(args.StoreID), Page: page}
+	if args.From != nil {
+		filter.From = &args.From.Time
+	}
+	if args.To != nil {
+		filter.To = &args.To.Time
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+	return payments(ctx, r.svc, filter)
+}
+
+func payments(ctx context.Context, svc Services, filter payment.PaymentFilter) (*paymentPageResolver, error) {
+	records, total, err := svc.Payments.GetPayments(ctx, filter)
+	if err != nil {
+		return nil, failed("payments", err)
+	}
+	result := &paymentPageResolver{total: total, items: []*paymentResolver{}}
+	for p, err := range records {
+		if err != nil {
+			return nil, failed("payments", err)
+		}
+		result.items = append(result.items, &paymentResolver{svc: svc, payment: p})
+	}
+	return result, nil
+}
+
+// loadCustomer, loadFilm and loadStore return nil for an id that doesn't
+// exist, the field is null rather than an error.
+func loadCustomer(ctx context.Context, svc Services, id int) (*customerResolver, error) {
+	c, found, err := loadersFrom(ctx).customers.Load(ctx, id)
+	if err != nil {
+		return nil, failed("customer", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &customerResolver{svc: svc, customer: c}, nil
+}
+
+func loadFilm(ctx context.Context, svc Services, id int) (*filmResolver, error) {
+	f, found, err := loadersFrom(ctx).films.Load(ctx, id)
+	if err != nil {
+		return nil, failed("film", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &filmResolver{svc: svc, film: f, details: true}, nil
+}
+
+func loadStore(ctx context.Context, svc Services, id int) (*storeResolver, error) {
+	s, found, err := loadersFrom(ctx).stores.Load(ctx, id)
+	if err != nil {
+		return nil, failed("store", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &storeResolver{svc: svc, store: s}, nil
+}
@@ -0,0 +1,353 @@
+package graph
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/rstoltzm-profile/video-rental-api/internal/customer"
+	"github.com/rstoltzm-profile/video-rental-api/internal/film"
+	"github.com/rstoltzm-profile/video-rental-api/internal/inventory"
+	"github.com/rstoltzm-profile/video-rental-api/internal/payment"
+	"github.com/rstoltzm-profile/video-rental-api/internal/store"
+)
+
+func timePtr(t *time.Time) *graphql.Time {
+	if t == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *t}
+}
+
+type customerResolver struct {
+	svc      Services
+	customer customer.Customer
+}
+
+func (r *customerResolver) ID() int32         { return int32(r.customer.ID) }
+func (r *customerResolver) FirstName() string { return r.customer.FirstName }
+func (r *customerResolver) LastName() string  { return r.customer.LastName }
+func (r *customerResolver) Email() string     { return r.customer.Email }
+func (r *customerResolver) Phone() string     { return r.customer.Phone }
+func (r *customerResolver) Active() bool      { return r.customer.Active }
+func (r *customerResolver) LastUpdate() graphql.Time {
+	return graphql.Time{Time: r.customer.LastUpdate}
+}
+
+func (r *customerResolver) Store(ctx context.Context) (*storeResolver, error) {
+	return loadStore(ctx, r.svc, r.customer.StoreID)
+}
+
+type rentalsArgs struct {
+	Status string
+	Limit  int32
+	Offset int32
+}
+
+func (r *customerResolver) Rentals(ctx context.Context, args rentalsArgs) (*rentalPageResolver, error) {
+	page, err := pageOf(args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+	filter := customer.RentalHistoryFilter{Status: strings.ToLower(args.Status), Page: page}
+
+	rentals, total, err := r.svc.Customers.GetCustomerRentalsByID(ctx, r.customer.ID, filter)
+	if err != nil {
+		return nil, failed("rentals", err)
+	}
+	result := &rentalPageResolver{total: total, items: make([]*rentalResolver, len(rentals))}
+	for i, rental := range rentals {
+		result.items[i] = &rentalResolver{svc: r.svc, customer: r, rental: rental}
+	}
+	return result, nil
+}
+
+func (r *customerResolver) Summary(ctx context.Context) (*customerSummaryResolver, error) {
+	summary, err := r.svc.Customers.GetCustomerSummaryByID(ctx, r.customer.ID)
+	if err != nil {
+		return nil, failed("customer summary", err)
+	}
+	return &customerSummaryResolver{summary: summary}, nil
+}
+
+func (r *customerResolver) Payments(ctx context.Context, args struct{ Limit, Offset int32 }) (*paymentPageResolver, error) {
+	page, err := pageOf(args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+	return payments(ctx, r.svc, payment.PaymentFilter{CustomerID: &r.customer.ID, Page: page})
+}
+
+type customerPageResolver struct {
+	total int
+	items []*customerResolver
+}
+
+func (r *customerPageResolver) Total() int32               { return int32(r.total) }
+func (r *customerPageResolver) Items() []*customerResolver { return r.items }
+
+type customerSummaryResolver struct {
+	summary customer.CustomerSummary
+}
+
+func (r *customerSummaryResolver) LifetimeRentals() int32 { return int32(r.summary.LifetimeRentals) }
+func (r *customerSummaryResolver) OpenRentals() int32     { return int32(r.summary.OpenRentals) }
+func (r *customerSummaryResolver) LateRentals() int32     { return int32(r.summary.LateRentals) }
+func (r *customerSummaryResolver) FirstRentalDate() *graphql.Time {
+	return timePtr(r.summary.FirstRentalDate)
+}
+func (r *customerSummaryResolver) LastRentalDate() *graphql.Time {
+	return timePtr(r.summary.LastRentalDate)
+}
+func (r *customerSummaryResolver) TotalSpend() float64 { return r.summary.TotalSpend }
+func (r *customerSummaryResolver) OutstandingBalance() float64 {
+	return r.summary.OutstandingBalance
+}
+
+func (r *customerSummaryResolver) FavoriteCategories() []*categoryCountResolver {
+	result := make([]*categoryCountResolver, len(r.summary.FavoriteCategories))
+	for i, c := range r.summary.FavoriteCategories {
+		result[i] = &categoryCountResolver{count: c}
+	}
+	return result
+}
+
+type categoryCountResolver struct {
+	count customer.CategoryCount
+}
+
+func (r *categoryCountResolver) Category() *nameResolver { return &nameResolver{r.count.Category} }
+func (r *categoryCountResolver) Rentals() int32          { return int32(r.count.Rentals) }
+
+// nameResolver is an Actor or a Category, both are only known by name
+type nameResolver struct {
+	name string
+}
+
+func (r *nameResolver) Name() string { return r.name }
+
+func names(values []string) []*nameResolver {
+	result := make([]*nameResolver, len(values))
+	for i, v := range values {
+		result[i] = &nameResolver{v}
+	}
+	return result
+}
+
+type rentalResolver struct {
+	svc      Services
+	customer *customerResolver
+	rental   customer.CustomerRentals
+}
+
+func (r *rentalResolver) ID() int32                   { return int32(r.rental.RentalID) }
+func (r *rentalResolver) RentalDate() graphql.Time    { return graphql.Time{Time: r.rental.RentalDate} }
+func (r *rentalResolver) DueDate() graphql.Time       { return graphql.Time{Time: r.rental.RentalDueDate} }
+func (r *rentalResolver) ReturnDate() *graphql.Time   { return timePtr(r.rental.ReturnDate) }
+func (r *rentalResolver) Overdue() bool               { return r.rental.Overdue }
+func (r *rentalResolver) Customer() *customerResolver { return r.customer }
+
+func (r *rentalResolver) Film(ctx context.Context) (*filmResolver, error) {
+	return loadFilm(ctx, r.svc, r.rental.FilmID)
+}
+
+func (r *rentalResolver) Inventory() *inventoryResolver {
+	return &inventoryResolver{svc: r.svc, id: r.rental.InventoryID, filmID: r.rental.FilmID, storeID: r.rental.StoreID}
+}
+
+type rentalPageResolver struct {
+	total int
+	items []*rentalResolver
+}
+
+func (r *rentalPageResolver) Total() int32             { return int32(r.total) }
+func (r *rentalPageResolver) Items() []*rentalResolver { return r.items }
+
+// filmResolver has the film's actors and categories when details is set,
+// otherwise they are loaded when asked for.
+type filmResolver struct {
+	svc     Services
+	film    film.FilmWithActorsCategories
+	details bool
+}
+
+func (r *filmResolver) ID() int32           { return int32(r.film.ID) }
+func (r *filmResolver) Title() string       { return r.film.Title }
+func (r *filmResolver) Description() string { return r.film.Description }
+func (r *filmResolver) ReleaseYear() int32  { return int32(r.film.ReleaseYear) }
+func (r *filmResolver) Language() string    { return r.film.Language }
+func (r *filmResolver) Rating() string      { return r.film.Rating }
+
+func (r *filmResolver) load(ctx context.Context) (film.FilmWithActorsCategories, error) {
+	if r.details {
+		return r.film, nil
+	}
+	f, _, err := loadersFrom(ctx).films.Load(ctx, r.film.ID)
+	if err != nil {
+		return f, failed("film", err)
+	}
+	return f, nil
+}
+
+func (r *filmResolver) Actors(ctx context.Context) ([]*nameResolver, error) {
+	f, err := r.load(ctx)
+	return names(f.Actors), err
+}
+
+func (r *filmResolver) Categories(ctx context.Context) ([]*nameResolver, error) {
+	f, err := r.load(ctx)
+	return names(f.Categories), err
+}
+
+func (r *filmResolver) Availability(ctx context.Context) (*filmAvailabilityResolver, error) {
+	a, found, err := loadersFrom(ctx).availability.Load(ctx, r.film.ID)
+	if err != nil {
+		return nil, failed("film availability", err)
+	}
+	if !found {
+		// no store stocks the film
+		a = inventory.FilmAvailability{FilmID: r.film.ID, Title: r.film.Title}
+	}
+	return &filmAvailabilityResolver{svc: r.svc, availability: a}, nil
+}
+
+type filmAvailabilityResolver struct {
+	svc          Services
+	availability inventory.FilmAvailability
+}
+
+func (r *filmAvailabilityResolver) Available() bool { return r.availability.Available }
+func (r *filmAvailabilityResolver) AvailableCopies() int32 {
+	return int32(r.availability.AvailableCopies)
+}
+func (r *filmAvailabilityResolver) TotalCopies() int32 { return int32(r.availability.TotalCopies) }
+func (r *filmAvailabilityResolver) NextReturnDue() *graphql.Time {
+	return timePtr(r.availability.NextReturnDue)
+}
+
+func (r *filmAvailabilityResolver) Stores() []*storeAvailabilityResolver {
+	result := make([]*storeAvailabilityResolver, len(r.availability.Stores))
+	for i, s := range r.availability.Stores {
+		result[i] = &storeAvailabilityResolver{svc: r.svc, availability: s}
+	}
+	return result
+}
+
+type storeAvailabilityResolver struct {
+	svc          Services
+	availability inventory.InventoryAvailability
+}
+
+func (r *storeAvailabilityResolver) Store(ctx context.Context) (*storeResolver, error) {
+	return loadStore(ctx, r.svc, r.availability.StoreID)
+}
+
+func (r *storeAvailabilityResolver) Film(ctx context.Context) (*filmResolver, error) {
+	return loadFilm(ctx, r.svc, r.availability.FilmID)
+}
+
+func (r *storeAvailabilityResolver) Available() bool { return r.availability.Available }
+func (r *storeAvailabilityResolver) AvailableCopies() int32 {
+	return int32(r.availability.AvailableCopies)
+}
+func (r *storeAvailabilityResolver) HeldCopies() int32  { return int32(r.availability.HeldCopies) }
+func (r *storeAvailabilityResolver) TotalCopies() int32 { return int32(r.availability.TotalCopies) }
+func (r *storeAvailabilityResolver) NextReturnDue() *graphql.Time {
+	return timePtr(r.availability.NextReturnDue)
+}
+
+func (r *storeAvailabilityResolver) AvailableInventory() []*inventoryResolver {
+	result := make([]*inventoryResolver, len(r.availability.AvailableInventoryIDs))
+	for i, id := range r.availability.AvailableInventoryIDs {
+		result[i] = &inventoryResolver{svc: r.svc, id: id, filmID: r.availability.FilmID, storeID: r.availability.StoreID}
+	}
+	return result
+}
+
+type inventoryResolver struct {
+	svc     Services
+	id      int
+	filmID  int
+	storeID int
+}
+
+func (r *inventoryResolver) ID() int32 { return int32(r.id) }
+
+func (r *inventoryResolver) Film(ctx context.Context) (*filmResolver, error) {
+	return loadFilm(ctx, r.svc, r.filmID)
+}
+
+func (r *inventoryResolver) Store(ctx context.Context) (*storeResolver, error) {
+	return loadStore(ctx, r.svc, r.storeID)
+}
+
+type storeResolver struct {
+	svc   Services
+	store store.Store
+}
+
+func (r *storeResolver) ID() int32                 { return int32(r.store.ID) }
+func (r *storeResolver) Address() *addressResolver { return &addressResolver{r.store.Address} }
+func (r *storeResolver) Manager() *staffResolver   { return &staffResolver{r.store.Manager} }
+func (r *storeResolver) LastUpdate() graphql.Time  { return graphql.Time{Time: r.store.LastUpdate} }
+
+func (r *storeResolver) Availability(ctx context.Context, args struct{ FilmID int32 }) (*storeAvailabilityResolver, error) {
+	a, err := r.svc.Inventory.GetInventoryAvailable(ctx, r.store.ID, int(args.FilmID))
+	if err != nil {
+		return nil, failed("availability", err)
+	}
+	return &storeAvailabilityResolver{svc: r.svc, availability: a}, nil
+}
+
+type addressResolver struct {
+	address store.StoreAddress
+}
+
+func (r *addressResolver) Address() string     { return r.address.Address }
+func (r *addressResolver) Address2() *string   { return r.address.Address2 }
+func (r *addressResolver) District() string    { return r.address.District }
+func (r *addressResolver) City() string        { return r.address.City }
+func (r *addressResolver) Country() string     { return r.address.Country }
+func (r *addressResolver) PostalCode() *string { return r.address.PostalCode }
+func (r *addressResolver) Phone() string       { return r.address.Phone }
+
+type staffResolver struct {
+	staff store.StaffMember
+}
+
+func (r *staffResolver) ID() int32         { return int32(r.staff.ID) }
+func (r *staffResolver) FirstName() string { return r.staff.FirstName }
+func (r *staffResolver) LastName() string  { return r.staff.LastName }
+func (r *staffResolver) Email() *string    { return r.staff.Email }
+func (r *staffResolver) Username() string  { return r.staff.Username }
+func (r *staffResolver) Active() bool      { return r.staff.Active }
+
+type paymentResolver struct {
+	svc     Services
+	payment payment.PaymentRecord
+}
+
+func (r *paymentResolver) ID() int32       { return int32(r.payment.ID) }
+func (r *paymentResolver) Amount() float64 { return r.payment.Amount }
+func (r *paymentResolver) PaymentDate() graphql.Time {
+	return graphql.Time{Time: r.payment.PaymentDate}
+}
+func (r *paymentResolver) RentalID() int32 { return int32(r.payment.RentalID) }
+func (r *paymentResolver) StaffID() int32  { return int32(r.payment.StaffID) }
+
+func (r *paymentResolver) Customer(ctx context.Context) (*customerResolver, error) {
+	return loadCustomer(ctx, r.svc, r.payment.CustomerID)
+}
+
+func (r *paymentResolver) Store(ctx context.Context) (*storeResolver, error) {
+	return loadStore(ctx, r.svc, r.payment.StoreID)
+}
+
+type paymentPageResolver struct {
+	total int
+	items []*paymentResolver
+}
+
+func (r *paymentPageResolver) Total() int32              { return int32(r.total) }
+func (r *paymentPageResolver) Items() []*paymentResolver { return r.items }
@@ -0,0 +1,161 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/apikey"
+)
+
+// staleAfter is how long a key stays claimed by a request that never
+// finished, like one running when the server crashed. It is well past the
+// server's 15s WriteTimeout.
+const staleAfter = time.Minute
+
+// Middleware makes POST, PUT, PATCH and DELETE requests that carry an
+// Idempotency-Key safe to retry. The first request with a key runs and its
+// status, headers and body are stored for ttl. A repeat gets the stored
+// response with Idempotent-Replayed: true, a repeat that arrives while the
+// first is still running gets 409, and reusing a key for a different request
+// gets 422. Keys are scoped to the API key ApiKeyMiddleware accepted. 5xx
+// responses are not stored, the request can be retried.
+func Middleware(store Store, ttl time.Duration, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(Header)
+		if key == "" || !mutating(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > MaxKeyLength {
+			http.Error(w, fmt.Sprintf("%s must be at most %d characters", Header, MaxKeyLength), http.StatusBadRequest)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		rec := Record{APIKeyID: apikey.IDFrom(r.Context()), Key: key, Method: r.Method, Path: r.URL.Path, Fingerprint: fingerprint(r, body)}
+		claimed, existing, err := store.Claim(r.Context(), rec, ttl, staleAfter)
+		if err != nil {
+			log.Printf("Failed to claim idempotency key: %v", err)
+			http.Error(w, "Failed to check Idempotency-Key", http.StatusInternalServerError)
+			return
+		}
+		if !claimed {
+			switch {
+			case existing.Fingerprint != rec.Fingerprint:
+				http.Error(w, fmt.Sprintf("%s was already used for a different request", Header), http.StatusUnprocessableEntity)
+			case !existing.Done():
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, fmt.Sprintf("A request with this %s is still in progress", Header), http.StatusConflict)
+			default:
+				replay(w, existing)
+			}
+			return
+		}
+
+		recorder := newRecorder(w)
+		next.ServeHTTP(recorder, r)
+		if recorder.status == 0 {
+			recorder.WriteHeader(http.StatusOK)
+		}
+
+		// store the response even when the client has gone away
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), 5*time.Second)
+		defer cancel()
+		if recorder.status >= 500 {
+			err = store.Release(ctx, rec.APIKeyID, key)
+		} else {
+			err = store.Save(ctx, rec.APIKeyID, key, recorder.status, recorder.header, recorder.body.Bytes())
+		}
+		if err != nil {
+			log.Printf("Failed to store the response for idempotency key %q: %v", key, err)
+		}
+	}
+}
+
+func mutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// fingerprint identifies the request a key was first used for
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s?%s\n", r.Method, r.URL.Path, r.URL.RawQuery)
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func replay(w http.ResponseWriter, rec Record) {
+	maps.Copy(w.Header(), rec.Header)
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(rec.Status)
+	w.Write(rec.Body)
+}
+
+// recorder passes the response through while keeping a copy. Only headers
+// the handler set are kept, ones from outer middleware like CORS are set
+// again on a replay.
+type recorder struct {
+	http.ResponseWriter
+	before http.Header
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+func newRecorder(w http.ResponseWriter) *recorder {
+	return &recorder{ResponseWriter: w, before: w.Header().Clone()}
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if r.status != 0 {
+		return
+	}
+	r.status = status
+	r.header = http.Header{}
+	for k, v := range r.Header() {
+		if !slices.Equal(r.before[k], v) {
+			r.header[k] = slices.Clone(v)
+		}
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// Flush keeps streamed responses streaming
+func (r *recorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,188 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/apikey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockStore struct {
+	mock.Mock
+}
+
+func (m *mockStore) Claim(ctx context.Context, rec Record, ttl, stale time.Duration) (bool, Record, error) {
+	args := m.Called(ctx, rec, ttl, stale)
+	return args.Bool(0), args.Get(1).(Record), args.Error(2)
+}
+
+func (m *mockStore) Save(ctx context.Context, apiKeyID int, key string, status int, header http.Header, body []byte) error {
+	return m.Called(ctx, apiKeyID, key, status, header, body).Error(0)
+}
+
+func (m *mockStore) Release(ctx context.Context, apiKeyID int, key string) error {
+	return m.Called(ctx, apiKeyID, key).Error(0)
+}
+
+func (m *mockStore) DeleteExpired(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func created(calls *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/v1/rentals/7")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":7}`))
+	}
+}
+
+func post(key, body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/rentals", strings.NewReader(body))
+	r.Header.Set(Header, key)
+	return r
+}
+
+func TestMiddleware_FirstRequestIsStored(t *testing.T) {
+	store := new(mockStore)
+	calls := 0
+	handler := Middleware(store, time.Hour, created(&calls))
+
+	store.On("Claim", mock.Anything, mock.MatchedBy(func(rec Record) bool {
+		return rec.Key == "k1" && rec.Method == http.MethodPost && rec.Path == "/rentals"
+	}), time.Hour, staleAfter).Return(true, Record{}, nil)
+	store.On("Save", mock.Anything, apikey.ConfiguredKeyID, "k1", http.StatusCreated, http.Header{
+		"Content-Type": {"application/json"},
+		"Location":     {"/v1/rentals/7"},
+	}, []byte(`{"id":7}`)).Return(nil)
+
+	w := httptest.NewRecorder()
+	w.Header().Set("Access-Control-Allow-Origin", "*") // set by CORS, not stored
+	handler(w, post("k1", `{"inventory_id":1}`))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 1, calls)
+	store.AssertExpectations(t)
+}
+
+func TestMiddleware_KeysAreScopedToAPIKey(t *testing.T) {
+	store := new(mockStore)
+	calls := 0
+	handler := Middleware(store, time.Hour, created(&calls))
+
+	store.On("Claim", mock.Anything, mock.MatchedBy(func(rec Record) bool {
+		return rec.APIKeyID == 4 && rec.Key == "k1"
+	}), time.Hour, staleAfter).Return(true, Record{}, nil)
+	store.On("Save", mock.Anything, 4, "k1", http.StatusCreated, mock.Anything, mock.Anything).Return(nil)
+
+	r := post("k1", `{"inventory_id":1}`)
+	handler(httptest.NewRecorder(), r.WithContext(apikey.WithID(r.Context(), 4)))
+
+	assert.Equal(t, 1, calls)
+	store.AssertExpectations(t)
+}
+
+func TestMiddleware_RepeatIsReplayed(t *testing.T) {
+	store := new(mockStore)
+	calls := 0
+	handler := Middleware(store, time.Hour, created(&calls))
+
+	r := post("k1", `{"inventory_id":1}`)
+	store.On("Claim", mock.Anything, mock.Anything, time.Hour, staleAfter).Return(false, Record{
+		Fingerprint: fingerprint(r, []byte(`{"inventory_id":1}`)),
+		Status:      http.StatusCreated,
+		Header:      http.Header{"Location": {"/v1/rentals/7"}},
+		Body:        []byte(`{"id":7}`),
+	}, nil)
+
+	w := httptest.NewRecorder()
+	handler(w, r)
+
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "/v1/rentals/7", w.Header().Get("Location"))
+	assert.Equal(t, "true", w.Header().Get(ReplayedHeader))
+	assert.Equal(t, `{"id":7}`, w.Body.String())
+}
+
+func TestMiddleware_InProgressIsConflict(t *testing.T) {
+	store := new(mockStore)
+	calls := 0
+	handler := Middleware(store, time.Hour, created(&calls))
+
+	r := post("k1", `{}`)
+	store.On("Claim", mock.Anything, mock.Anything, time.Hour, staleAfter).
+		Return(false, Record{Fingerprint: fingerprint(r, []byte(`{}`))}, nil)
+
+	w := httptest.NewRecorder()
+	handler(w, r)
+
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+}
+
+func TestMiddleware_DifferentRequestIsRejected(t *testing.T) {
+	store := new(mockStore)
+	calls := 0
+	handler := Middleware(store, time.Hour, created(&calls))
+
+	store.On("Claim", mock.Anything, mock.Anything, time.Hour, staleAfter).
+		Return(false, Record{Fingerprint: "another request", Status: http.StatusCreated}, nil)
+
+	w := httptest.NewRecorder()
+	handler(w, post("k1", `{"inventory_id":2}`))
+
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestMiddleware_ServerErrorReleasesKey(t *testing.T) {
+	store := new(mockStore)
+	handler := Middleware(store, time.Hour, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Failed to create rental", http.StatusInternalServerError)
+	})
+
+	store.On("Claim", mock.Anything, mock.Anything, time.Hour, staleAfter).Return(true, Record{}, nil)
+	store.On("Release", mock.Anything, apikey.ConfiguredKeyID, "k1").Return(nil)
+
+	w := httptest.NewRecorder()
+	handler(w, post("k1", `{}`))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	store.AssertExpectations(t)
+	store.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMiddleware_IgnoresGetAndRequestsWithoutKey(t *testing.T) {
+	store := new(mockStore)
+	calls := 0
+	handler := Middleware(store, time.Hour, created(&calls))
+
+	get := httptest.NewRequest(http.MethodGet, "/rentals", nil)
+	get.Header.Set(Header, "k1")
+	handler(httptest.NewRecorder(), get)
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/rentals", strings.NewReader(`{}`)))
+
+	assert.Equal(t, 2, calls)
+	store.AssertNotCalled(t, "Claim", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMiddleware_KeyTooLong(t *testing.T) {
+	calls := 0
+	handler := Middleware(new(mockStore), time.Hour, created(&calls))
+
+	w := httptest.NewRecorder()
+	handler(w, post(strings.Repeat("k", MaxKeyLength+1), `{}`))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, 0, calls)
+}
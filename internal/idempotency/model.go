@@ -0,0 +1,29 @@
+package idempotency
+
+import "net/http"
+
+// Header is the request header clients send a key in, any unique string up
+// to MaxKeyLength, a UUID is typical.
+const (
+	Header         = "Idempotency-Key"
+	ReplayedHeader = "Idempotent-Replayed"
+	MaxKeyLength   = 255
+)
+
+// Record is a key's stored request and, once the first request finished,
+// its response. Status is 0 while that request is still running. Keys are
+// per API key, two clients may use the same one.
+type Record struct {
+	APIKeyID    int
+	Key         string
+	Method      string
+	Path        string
+	Fingerprint string
+	Status      int
+	Header      http.Header
+	Body        []byte
+}
+
+func (r Record) Done() bool {
+	return r.Status != 0
+}
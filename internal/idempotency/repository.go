@@ -0,0 +1,107 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Store keeps idempotency keys and the responses they produced
+type Store interface {
+	// Claim takes key for a new request. When the key is already taken it
+	// returns false and the existing record. An expired key, or one whose
+	// request was abandoned for longer than stale, is taken over.
+	Claim(ctx context.Context, rec Record, ttl, stale time.Duration) (bool, Record, error)
+	Save(ctx context.Context, apiKeyID int, key string, status int, header http.Header, body []byte) error
+	Release(ctx context.Context, apiKeyID int, key string) error
+	DeleteExpired(ctx context.Context) (int, error)
+}
+
+type repository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRepository(pool *pgxpool.Pool) Store {
+	return &repository{pool: pool}
+}
+
+func (r *repository) Claim(ctx context.Context, rec Record, ttl, stale time.Duration) (bool, Record, error) {
+	var claimed bool
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO idempotency_key (api_key_id, key, method, path, fingerprint, expires_at)
+		VALUES ($7, $1, $2, $3, $4, CURRENT_TIMESTAMP + make_interval(secs => $5))
+		ON CONFLICT (api_key_id, key) DO UPDATE SET
+			method = EXCLUDED.method,
+			path = EXCLUDED.path,
+			fingerprint = EXCLUDED.fingerprint,
+			status = NULL,
+			header = NULL,
+			body = NULL,
+			created_at = CURRENT_TIMESTAMP,
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_key.expires_at < CURRENT_TIMESTAMP
+			OR (idempotency_key.status IS NULL AND idempotency_key.created_at < CURRENT_TIMESTAMP - make_interval(secs => $6))
+		RETURNING TRUE
+	`, rec.Key, rec.Method, rec.Path, rec.Fingerprint, ttl.Seconds(), stale.Seconds(), rec.APIKeyID).Scan(&claimed)
+	if err == nil {
+		return true, rec, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return false, Record{}, err
+	}
+
+	existing := Record{APIKeyID: rec.APIKeyID, Key: rec.Key}
+	var status *int
+	var header []byte
+	err = r.pool.QueryRow(ctx, `
+		SELECT method, path, fingerprint, status, header, body
+		FROM idempotency_key
+		WHERE api_key_id = $1 AND key = $2
+	`, rec.APIKeyID, rec.Key).Scan(&existing.Method, &existing.Path, &existing.Fingerprint, &status, &header, &existing.Body)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// expired and swept in between, claim it again
+		return r.Claim(ctx, rec, ttl, stale)
+	}
+	if err != nil {
+		return false, Record{}, err
+	}
+	if status != nil {
+		existing.Status = *status
+	}
+	if header != nil {
+		if err := json.Unmarshal(header, &existing.Header); err != nil {
+			return false, Record{}, err
+		}
+	}
+	return false, existing, nil
+}
+
+func (r *repository) Save(ctx context.Context, apiKeyID int, key string, status int, header http.Header, body []byte) error {
+	data, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+	_, err = r.pool.Exec(ctx, `
+		UPDATE idempotency_key SET status = $3, header = $4, body = $5
+		WHERE api_key_id = $1 AND key = $2 AND status IS NULL
+	`, apiKeyID, key, status, data, body)
+	return err
+}
+
+// Release forgets a key whose request failed, so a retry runs again
+func (r *repository) Release(ctx context.Context, apiKeyID int, key string) error {
+	_, err := r.pool.Exec(ctx, `
+		DELETE FROM idempotency_key WHERE api_key_id = $1 AND key = $2 AND status IS NULL
+	`, apiKeyID, key)
+	return err
+}
+
+func (r *repository) DeleteExpired(ctx context.Context) (int, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM idempotency_key WHERE expires_at < CURRENT_TIMESTAMP`)
+	return int(tag.RowsAffected()), err
+}
@@ -0,0 +1,30 @@
+package idempotency
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+const sweepInterval = 10 * time.Minute
+
+// RunSweeper deletes expired keys every 10 minutes until ctx is cancelled
+func RunSweeper(ctx context.Context, store Store) {
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := store.DeleteExpired(ctx)
+			if err != nil {
+				log.Printf("Idempotency key sweeper failed: %v", err)
+			}
+			if deleted > 0 {
+				log.Printf("Idempotency key sweeper deleted %d expired keys", deleted)
+			}
+		}
+	}
+}
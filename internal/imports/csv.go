@@ -0,0 +1,180 @@
+package imports
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/rstoltzm-profile/video-rental-api/internal/customer"
+)
+
+var ErrInvalidCSV = errors.New("invalid CSV")
+
+var (
+	customerColumns  = []string{"first_name", "last_name", "email", "store_id", "address", "address2", "district", "city_name", "country", "postal_code", "phone"}
+	customerOptional = []string{"address2", "country"}
+
+	inventoryColumns  = []string{"film_id", "store_id", "copies"}
+	inventoryOptional = []string{"copies"}
+)
+
+// validate names fields by their json tags so errors point at CSV columns
+var validate = func() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		return name
+	})
+	return v
+}()
+
+// record is one CSV row keyed by column name
+type record struct {
+	line   int
+	values map[string]string
+}
+
+// readCSV reads a header row naming the columns, in any order, then every
+// record. Required columns must be present and unknown columns are rejected.
+// A row with the wrong number of fields is reported, not fatal.
+func readCSV(r io.Reader, columns, optional []string) ([]record, []RowError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, fmt.Errorf("%w: empty file, expected a header row", ErrInvalidCSV)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidCSV, err)
+	}
+
+	known := map[string]bool{}
+	for _, c := range columns {
+		known[c] = true
+	}
+	seen := map[string]bool{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !known[name] {
+			return nil, nil, fmt.Errorf("%w: unknown column %q", ErrInvalidCSV, name)
+		}
+		if seen[name] {
+			return nil, nil, fmt.Errorf("%w: column %q appears twice", ErrInvalidCSV, name)
+		}
+		seen[name] = true
+		header[i] = name
+	}
+	for _, c := range columns {
+		if !seen[c] && !slices.Contains(optional, c) {
+			return nil, nil, fmt.Errorf("%w: missing column %q", ErrInvalidCSV, c)
+		}
+	}
+
+	var records []record
+	var rowErrors []RowError
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, rowErrors, nil
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrInvalidCSV, err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		if len(fields) != len(header) {
+			rowErrors = append(rowErrors, RowError{Row: line, Message: fmt.Sprintf("expected %d fields, got %d", len(header), len(fields))})
+			continue
+		}
+
+		values := make(map[string]string, len(header))
+		for i, name := range header {
+			values[name] = strings.TrimSpace(fields[i])
+		}
+		records = append(records, record{line: line, values: values})
+	}
+}
+
+// parseCustomer builds and validates one customer row
+func parseCustomer(rec record) (CustomerRow, []RowError) {
+	v := rec.values
+	row := CustomerRow{Line: rec.line}
+	row.FirstName = v["first_name"]
+	row.LastName = v["last_name"]
+	row.Email = v["email"]
+	row.Address = customer.AddressInput{
+		Address:    v["address"],
+		Address2:   v["address2"],
+		District:   v["district"],
+		CityName:   v["city_name"],
+		Country:    v["country"],
+		PostalCode: v["postal_code"],
+		Phone:      v["phone"],
+	}
+
+	var errs []RowError
+	if err := atoi(v, "store_id", &row.StoreID); err != nil {
+		errs = append(errs, RowError{Row: rec.line, Field: "store_id", Message: err.Error()})
+	}
+	return row, validationErrors(rec.line, row.CreateCustomerRequest, errs)
+}
+
+// parseInventory builds and validates one inventory row, copies defaults to 1
+func parseInventory(rec record) (InventoryRow, []RowError) {
+	v := rec.values
+	row := InventoryRow{Line: rec.line, Copies: 1}
+
+	var errs []RowError
+	for _, f := range []struct {
+		name string
+		dst  *int
+	}{
+		{"film_id", &row.FilmID},
+		{"store_id", &row.StoreID},
+		{"copies", &row.Copies},
+	} {
+		if err := atoi(v, f.name, f.dst); err != nil {
+			errs = append(errs, RowError{Row: rec.line, Field: f.name, Message: err.Error()})
+		}
+	}
+	return row, validationErrors(rec.line, row, errs)
+}
+
+// atoi parses a whole number column, leaving dst alone when it is blank
+func atoi(values map[string]string, name string, dst *int) error {
+	v := values[name]
+	if v == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("must be a whole number")
+	}
+	*dst = n
+	return nil
+}
+
+// validationErrors adds the validator's errors for s to errs, skipping
+// fields that already failed to parse.
+func validationErrors(line int, s any, errs []RowError) []RowError {
+	var invalid validator.ValidationErrors
+	if !errors.As(validate.Struct(s), &invalid) {
+		return errs
+	}
+
+	for _, fe := range invalid {
+		if slices.ContainsFunc(errs, func(e RowError) bool { return e.Field == fe.Field() }) {
+			continue
+		}
+		errs = append(errs, RowError{Row: line, Field: fe.Field(), Message: fmt.Sprintf("failed %s validation", fe.Tag())})
+	}
+	return errs
+}
@@ -0,0 +1,112 @@
+package imports
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/job"
+)
+
+type Handler struct {
+	service Service
+	jobs    job.Enqueuer
+}
+
+func NewHandler(service Service, jobs job.Enqueuer) *Handler {
+	return &Handler{service: service, jobs: jobs}
+}
+
+// ImportCustomers godoc
+// @Summary      Import customers from CSV
+// @Description  Create customers from a CSV file with a header row. Columns are first_name, last_name, email, store_id, address, address2 (optional), district, city_name, country (optional), postal_code and phone, in any order. Rows are validated like POST /v1/customers and nothing is imported unless every row is valid. Up to 32MB.
+// @Tags         imports
+// @Accept       text/csv
+// @Produce      json
+// @Param        dry_run  query     bool  false  "Check the file without importing it"
+// @Param        async    query     bool  false  "Queue the import as a job, the report is the job's result"
+// @Success      200      {object}  imports.Report
+// @Success      202      {object}  job.Job
+// @Failure      400      {object}  imports.Report  "Rows with errors, or a malformed file as text"
+// @Failure      413      {string}  string  "File too large"
+// @Failure      500      {string}  string  "Failed to import customers"
+// @Security     ApiKeyAuth
+// @Router       /v1/imports/customers [post]
+func (h *Handler) ImportCustomers(w http.ResponseWriter, r *http.Request) {
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	if job.Async(r) {
+		h.enqueue(w, r, KindCustomers, dryRun)
+		return
+	}
+	report, err := h.service.ImportCustomers(r.Context(), r.Body, dryRun)
+	writeReport(w, report, err, "Failed to import customers")
+}
+
+// ImportInventory godoc
+// @Summary      Import inventory from CSV
+// @Description  Add copies of films to stores from a CSV file with a header row. Columns are film_id, store_id and copies (optional, default 1, max 100), in any order. Nothing is imported unless every row is valid. Up to 32MB.
+// @Tags         imports
+// @Accept       text/csv
+// @Produce      json
+// @Param        dry_run  query     bool  false  "Check the file without importing it"
+// @Param        async    query     bool  false  "Queue the import as a job, the report is the job's result"
+// @Success      200      {object}  imports.Report
+// @Success      202      {object}  job.Job
+// @Failure      400      {object}  imports.Report  "Rows with errors, or a malformed file as text"
+// @Failure      413      {string}  string  "File too large"
+// @Failure      500      {string}  string  "Failed to import inventory"
+// @Security     ApiKeyAuth
+// @Router       /v1/imports/inventory [post]
+func (h *Handler) ImportInventory(w http.ResponseWriter, r *http.Request) {
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	if job.Async(r) {
+		h.enqueue(w, r, KindInventory, dryRun)
+		return
+	}
+	report, err := h.service.ImportInventory(r.Context(), r.Body, dryRun)
+	writeReport(w, report, err, "Failed to import inventory")
+}
+
+// enqueue queues the uploaded file as a job, answering 202 with the job
+func (h *Handler) enqueue(w http.ResponseWriter, r *http.Request, kind string, dryRun bool) {
+	input, err := io.ReadAll(r.Body)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, "File too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to read file", http.StatusBadRequest)
+		return
+	}
+
+	j, err := h.jobs.Enqueue(r.Context(), job.NewJob{Kind: kind, Params: taskParams{DryRun: dryRun}, Input: input})
+	if err != nil {
+		http.Error(w, "Failed to queue import", http.StatusInternalServerError)
+		return
+	}
+	job.Accepted(w, j)
+}
+
+// writeReport sends the report, a 400 when any row has an error
+func writeReport(w http.ResponseWriter, report Report, err error, fallback string) {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		http.Error(w, "File too large", http.StatusRequestEntityTooLarge)
+		return
+	case errors.Is(err, ErrInvalidCSV):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case err != nil:
+		http.Error(w, fallback, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if len(report.Errors) > 0 {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,35 @@
+package imports
+
+import "github.com/rstoltzm-profile/video-rental-api/internal/customer"
+
+// RowError is a problem with one CSV row. Row is the line number in the
+// file, the header is line 1.
+type RowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// Report is the result of an import. Nothing is imported when any row has an
+// error, fix the file and send it again.
+type Report struct {
+	DryRun   bool       `json:"dry_run"`
+	Rows     int        `json:"rows"`
+	Imported int        `json:"imported"` // customers or copies inserted
+	Errors   []RowError `json:"errors"`
+}
+
+// CustomerRow is one customer to import, validated with the same rules as
+// POST /customers.
+type CustomerRow struct {
+	Line int
+	customer.CreateCustomerRequest
+}
+
+// InventoryRow adds Copies copies of a film to a store.
+type InventoryRow struct {
+	Line    int
+	FilmID  int `json:"film_id" validate:"required,gt=0"`
+	StoreID int `json:"store_id" validate:"required,gt=0"`
+	Copies  int `json:"copies" validate:"min=1,max=100"`
+}
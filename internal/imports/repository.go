@@ -0,0 +1,276 @@
+package imports
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rstoltzm-profile/video-rental-api/internal/customer"
+	"github.com/rstoltzm-profile/video-rental-api/internal/db"
+)
+
+// Rows are copied into temp staging tables that live until the import's
+// transaction ends, then checked and inserted with set-based SQL.
+
+type ImportReader interface {
+	CheckStagedCustomers(ctx context.Context) ([]RowError, error)
+	CheckStagedInventory(ctx context.Context) ([]RowError, error)
+}
+
+type ImportWriter interface {
+	LockCustomers(ctx context.Context) error
+	StageCustomers(ctx context.Context, rows []CustomerRow) error
+	ResolveStagedCities(ctx context.Context) error
+	InsertStagedCustomers(ctx context.Context) (int, error)
+	StageInventory(ctx context.Context, rows []InventoryRow) error
+	InsertStagedInventory(ctx context.Context) (int, error)
+}
+
+type Repository interface {
+	ImportReader
+	ImportWriter
+	TransactionManager
+}
+
+type TransactionManager interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type repository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRepository(pool *pgxpool.Pool) Repository {
+	return &repository{pool: pool}
+}
+
+func (r *repository) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return db.WithTx(ctx, r.pool, fn)
+}
+
+// conn returns the transaction started by WithTx, or the pool
+func (r *repository) conn(ctx context.Context) db.DBTX {
+	return db.Conn(ctx, r.pool)
+}
+
+// LockCustomers blocks other customer inserts until the import commits, so
+// the email check can't miss a customer added halfway through. Taking the
+// per-email advisory locks POST /customers uses would run out of lock slots
+// on a big file.
+func (r *repository) LockCustomers(ctx context.Context) error {
+	_, err := r.conn(ctx).Exec(ctx, `LOCK TABLE customer IN SHARE ROW EXCLUSIVE MODE`)
+	return err
+}
+
+func (r *repository) StageCustomers(ctx context.Context, rows []CustomerRow) error {
+	_, err := r.conn(ctx).Exec(ctx, `
+	CREATE TEMP TABLE customer_import (
+		line int PRIMARY KEY,
+		first_name text NOT NULL,
+		last_name text NOT NULL,
+		email text NOT NULL,
+		store_id int NOT NULL,
+		address text NOT NULL,
+		address2 text NOT NULL,
+		district text NOT NULL,
+		city_name text NOT NULL,
+		country text NOT NULL,
+		postal_code text NOT NULL,
+		phone text NOT NULL,
+		city_id int,
+		address_id int,
+		customer_id int
+	) ON COMMIT DROP
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.conn(ctx).CopyFrom(ctx,
+		pgx.Identifier{"customer_import"},
+		[]string{"line", "first_name", "last_name", "email", "store_id", "address", "address2", "district", "city_name", "country", "postal_code", "phone"},
+		pgx.CopyFromSlice(len(rows), func(i int) ([]any, error) {
+			c := rows[i]
+			return []any{c.Line, c.FirstName, c.LastName, c.Email, c.StoreID,
+				c.Address.Address, c.Address.Address2, c.Address.District, c.Address.CityName,
+				c.Address.Country, c.Address.PostalCode, c.Address.Phone}, nil
+		}),
+	)
+	return err
+}
+
+// CheckStagedCustomers applies the POST /customers rules that need the
+// database: unique emails, a known store and a city that resolves. Run it
+// after ResolveStagedCities, a row without a country only resolves when the
+// city name matches exactly one city.
+func (r *repository) CheckStagedCustomers(ctx context.Context) ([]RowError, error) {
+	query := `
+	SELECT line, 'email', $1::text FROM customer_import s
+	WHERE EXISTS (SELECT 1 FROM customer WHERE lower(customer.email) = lower(s.email))
+	UNION ALL
+	SELECT line, 'email', 'email appears more than once in the file' FROM (
+		SELECT line, count(*) OVER (PARTITION BY lower(email)) AS n FROM customer_import
+	) d WHERE n > 1
+	UNION ALL
+	SELECT line, 'store_id', $2::text FROM customer_import s
+	WHERE NOT EXISTS (SELECT 1 FROM store WHERE store.store_id = s.store_id)
+	UNION ALL
+	SELECT line, 'city_name', CASE WHEN n = 0 THEN $3::text ELSE $4::text END FROM (
+		SELECT s.line, (SELECT count(*) FROM city WHERE lower(city.city) = lower(s.city_name)) AS n
+		FROM customer_import s
+		WHERE s.city_id IS NULL
+	) c
+	ORDER BY 1
+	`
+	rows, err := r.conn(ctx).Query(ctx, query,
+		customer.ErrDuplicateEmail.Error(),
+		customer.ErrInvalidStore.Error(),
+		customer.ErrUnknownCity.Error(),
+		customer.ErrAmbiguousCity.Error(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanRowError)
+}
+
+// ResolveStagedCities creates the countries and cities rows name that don't
+// exist yet and sets city_id on every row whose city resolves. It takes the
+// same advisory locks as POST /customers, all countries before any city, so
+// the two can't create the same city twice.
+func (r *repository) ResolveStagedCities(ctx context.Context) error {
+	steps := []string{`
+	SELECT pg_advisory_xact_lock(hashtextextended(key, 0)) FROM (
+		SELECT DISTINCT 'country:' || lower(country) AS key FROM customer_import WHERE country <> ''
+	) k ORDER BY key
+	`, `
+	INSERT INTO country (country, last_update)
+	SELECT DISTINCT ON (lower(s.country)) s.country, CURRENT_TIMESTAMP
+	FROM customer_import s
+	WHERE s.country <> ''
+		AND NOT EXISTS (SELECT 1 FROM country WHERE lower(country.country) = lower(s.country))
+	`, `
+	SELECT pg_advisory_xact_lock(hashtextextended(key, 0)) FROM (
+		SELECT DISTINCT 'city:' || country.country_id || ':' || lower(s.city_name) AS key
+		FROM customer_import s
+		INNER JOIN LATERAL (
+			SELECT country_id FROM country WHERE lower(country.country) = lower(s.country) ORDER BY country_id LIMIT 1
+		) country ON TRUE
+		WHERE s.country <> ''
+	) k ORDER BY key
+	`, `
+	INSERT INTO city (city, country_id, last_update)
+	SELECT DISTINCT ON (lower(s.city_name), country.country_id) s.city_name, country.country_id, CURRENT_TIMESTAMP
+	FROM customer_import s
+	INNER JOIN LATERAL (
+		SELECT country_id FROM country WHERE lower(country.country) = lower(s.country) ORDER BY country_id LIMIT 1
+	) country ON TRUE
+	WHERE s.country <> ''
+		AND NOT EXISTS (
+			SELECT 1 FROM city
+			WHERE lower(city.city) = lower(s.city_name) AND city.country_id = country.country_id
+		)
+	`, `
+	UPDATE customer_import s SET city_id = CASE WHEN s.country = '' THEN (
+		SELECT min(city_id) FROM city WHERE lower(city.city) = lower(s.city_name) HAVING count(*) = 1
+	) ELSE (
+		SELECT city.city_id
+		FROM city
+		INNER JOIN country ON city.country_id = country.country_id
+		WHERE lower(city.city) = lower(s.city_name) AND lower(country.country) = lower(s.country)
+		ORDER BY country.country_id, city.city_id
+		LIMIT 1
+	) END
+	`}
+	for _, step := range steps {
+		if _, err := r.conn(ctx).Exec(ctx, step); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InsertStagedCustomers inserts the addresses and customers. IDs are drawn
+// from the sequences up front so each staged row knows its address.
+func (r *repository) InsertStagedCustomers(ctx context.Context) (int, error) {
+	steps := []string{`
+	UPDATE customer_import SET
+		address_id = nextval(pg_get_serial_sequence('address', 'address_id')),
+		customer_id = nextval(pg_get_serial_sequence('customer', 'customer_id'))
+	`, `
+	INSERT INTO address (address_id, address, address2, district, city_id, postal_code, phone)
+	SELECT address_id, address, address2, district, city_id, postal_code, phone
+	FROM customer_import
+	ORDER BY line
+	`}
+	for _, step := range steps {
+		if _, err := r.conn(ctx).Exec(ctx, step); err != nil {
+			return 0, err
+		}
+	}
+
+	tag, err := r.conn(ctx).Exec(ctx, `
+	INSERT INTO customer (customer_id, store_id, first_name, last_name, email, address_id, activebool, create_date, last_update, active)
+	SELECT customer_id, store_id, first_name, last_name, email, address_id, TRUE, CURRENT_DATE, CURRENT_TIMESTAMP, 1
+	FROM customer_import
+	ORDER BY line
+	`)
+	return int(tag.RowsAffected()), err
+}
+
+func (r *repository) StageInventory(ctx context.Context, rows []InventoryRow) error {
+	_, err := r.conn(ctx).Exec(ctx, `
+	CREATE TEMP TABLE inventory_import (
+		line int PRIMARY KEY,
+		film_id int NOT NULL,
+		store_id int NOT NULL,
+		copies int NOT NULL
+	) ON COMMIT DROP
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.conn(ctx).CopyFrom(ctx,
+		pgx.Identifier{"inventory_import"},
+		[]string{"line", "film_id", "store_id", "copies"},
+		pgx.CopyFromSlice(len(rows), func(i int) ([]any, error) {
+			return []any{rows[i].Line, rows[i].FilmID, rows[i].StoreID, rows[i].Copies}, nil
+		}),
+	)
+	return err
+}
+
+func (r *repository) CheckStagedInventory(ctx context.Context) ([]RowError, error) {
+	rows, err := r.conn(ctx).Query(ctx, `
+	SELECT line, 'film_id', 'unknown film' FROM inventory_import s
+	WHERE NOT EXISTS (SELECT 1 FROM film WHERE film.film_id = s.film_id)
+	UNION ALL
+	SELECT line, 'store_id', 'unknown store' FROM inventory_import s
+	WHERE NOT EXISTS (SELECT 1 FROM store WHERE store.store_id = s.store_id)
+	ORDER BY 1
+	`)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanRowError)
+}
+
+// InsertStagedInventory adds copies rows per staged line and returns the
+// number of copies added.
+func (r *repository) InsertStagedInventory(ctx context.Context) (int, error) {
+	tag, err := r.conn(ctx).Exec(ctx, `
+	INSERT INTO inventory (film_id, store_id, last_update)
+	SELECT film_id, store_id, CURRENT_TIMESTAMP
+	FROM inventory_import
+	CROSS JOIN generate_series(1, copies)
+	ORDER BY line
+	`)
+	return int(tag.RowsAffected()), err
+}
+
+func scanRowError(row pgx.CollectableRow) (RowError, error) {
+	var e RowError
+	err := row.Scan(&e.Row, &e.Field, &e.Message)
+	return e, err
+}
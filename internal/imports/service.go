@@ -0,0 +1,129 @@
+package imports
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"io"
+	"slices"
+)
+
+// errRollback ends an import's transaction without committing, for dry runs
+// and files with errors.
+var errRollback = errors.New("rollback import")
+
+type Service interface {
+	ImportCustomers(ctx context.Context, r io.Reader, dryRun bool) (Report, error)
+	ImportInventory(ctx context.Context, r io.Reader, dryRun bool) (Report, error)
+}
+
+type service struct {
+	reader ImportReader
+	writer ImportWriter
+	tx     TransactionManager
+}
+
+func NewService(reader ImportReader, writer ImportWriter, tx TransactionManager) Service {
+	return &service{
+		reader: reader,
+		writer: writer,
+		tx:     tx,
+	}
+}
+
+// ImportCustomers validates every row, stages the valid ones and checks them
+// against the database, so one report lists all the problems in the file.
+// Customers are only inserted when there are none and it isn't a dry run.
+func (s *service) ImportCustomers(ctx context.Context, r io.Reader, dryRun bool) (Report, error) {
+	records, rowErrors, err := readCSV(r, customerColumns, customerOptional)
+	if err != nil {
+		return Report{}, err
+	}
+	report := Report{DryRun: dryRun, Rows: len(records) + len(rowErrors)}
+
+	var rows []CustomerRow
+	for _, rec := range records {
+		row, errs := parseCustomer(rec)
+		if len(errs) > 0 {
+			rowErrors = append(rowErrors, errs...)
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	err = s.run(ctx, &report, rowErrors, len(rows) > 0, func(ctx context.Context) ([]RowError, error) {
+		if err := s.writer.StageCustomers(ctx, rows); err != nil {
+			return nil, err
+		}
+		if err := s.writer.ResolveStagedCities(ctx); err != nil {
+			return nil, err
+		}
+		if err := s.writer.LockCustomers(ctx); err != nil {
+			return nil, err
+		}
+		return s.reader.CheckStagedCustomers(ctx)
+	}, s.writer.InsertStagedCustomers)
+	return report, err
+}
+
+// ImportInventory adds copies of films to stores, checked the same way as
+// ImportCustomers.
+func (s *service) ImportInventory(ctx context.Context, r io.Reader, dryRun bool) (Report, error) {
+	records, rowErrors, err := readCSV(r, inventoryColumns, inventoryOptional)
+	if err != nil {
+		return Report{}, err
+	}
+	report := Report{DryRun: dryRun, Rows: len(records) + len(rowErrors)}
+
+	var rows []InventoryRow
+	for _, rec := range records {
+		row, errs := parseInventory(rec)
+		if len(errs) > 0 {
+			rowErrors = append(rowErrors, errs...)
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	err = s.run(ctx, &report, rowErrors, len(rows) > 0, func(ctx context.Context) ([]RowError, error) {
+		if err := s.writer.StageInventory(ctx, rows); err != nil {
+			return nil, err
+		}
+		return s.reader.CheckStagedInventory(ctx)
+	}, s.writer.InsertStagedInventory)
+	return report, err
+}
+
+// run stages and checks the valid rows in one transaction, then inserts them
+// or rolls everything back.
+func (s *service) run(ctx context.Context, report *Report, rowErrors []RowError, staged bool,
+	check func(ctx context.Context) ([]RowError, error),
+	insert func(ctx context.Context) (int, error),
+) error {
+	report.Errors = rowErrors
+	if staged {
+		err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+			errs, err := check(ctx)
+			if err != nil {
+				return err
+			}
+			report.Errors = append(report.Errors, errs...)
+			if len(report.Errors) > 0 || report.DryRun {
+				return errRollback
+			}
+			report.Imported, err = insert(ctx)
+			return err
+		})
+		if err != nil && !errors.Is(err, errRollback) {
+			return err
+		}
+	}
+
+	if report.Errors == nil {
+		report.Errors = []RowError{}
+	}
+	slices.SortStableFunc(report.Errors, func(a, b RowError) int {
+		return cmp.Compare(a.Row, b.Row)
+	})
+	return nil
+}
@@ -0,0 +1,201 @@
+package imports
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockReader struct {
+	mock.Mock
+}
+
+func (m *mockReader) CheckStagedCustomers(ctx context.Context) ([]RowError, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]RowError), args.Error(1)
+}
+
+func (m *mockReader) CheckStagedInventory(ctx context.Context) ([]RowError, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]RowError), args.Error(1)
+}
+
+type mockWriter struct {
+	mock.Mock
+}
+
+func (m *mockWriter) LockCustomers(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+func (m *mockWriter) StageCustomers(ctx context.Context, rows []CustomerRow) error {
+	return m.Called(ctx, rows).Error(0)
+}
+
+func (m *mockWriter) ResolveStagedCities(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+func (m *mockWriter) InsertStagedCustomers(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockWriter) StageInventory(ctx context.Context, rows []InventoryRow) error {
+	return m.Called(ctx, rows).Error(0)
+}
+
+func (m *mockWriter) InsertStagedInventory(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+type mockTxManager struct{}
+
+// WithTx runs fn straight away, there is no database behind the mocks
+func (m *mockTxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+const customerCSV = `first_name,last_name,email,store_id,address,district,city_name,country,postal_code,phone
+Ada,Lovelace,ada@example.com,1,12 Analytical Way,London,London,United Kingdom,N19GU,+445550100
+Alan,Turing,alan@example.com,2,3 Bletchley Rd,Bucks,Milton Keynes,United Kingdom,MK36EB,+445550101
+`
+
+func TestService_ImportCustomers(t *testing.T) {
+	reader := new(mockReader)
+	writer := new(mockWriter)
+	svc := NewService(reader, writer, &mockTxManager{})
+
+	writer.On("StageCustomers", mock.Anything, mock.MatchedBy(func(rows []CustomerRow) bool {
+		return len(rows) == 2 && rows[0].Line == 2 && rows[1].StoreID == 2 && rows[1].Address.CityName == "Milton Keynes"
+	})).Return(nil)
+	writer.On("ResolveStagedCities", mock.Anything).Return(nil)
+	writer.On("LockCustomers", mock.Anything).Return(nil)
+	reader.On("CheckStagedCustomers", mock.Anything).Return([]RowError(nil), nil)
+	writer.On("InsertStagedCustomers", mock.Anything).Return(2, nil)
+
+	report, err := svc.ImportCustomers(context.Background(), strings.NewReader(customerCSV), false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, Report{Rows: 2, Imported: 2, Errors: []RowError{}}, report)
+	writer.AssertExpectations(t)
+}
+
+func TestService_ImportCustomers_DryRun(t *testing.T) {
+	reader := new(mockReader)
+	writer := new(mockWriter)
+	svc := NewService(reader, writer, &mockTxManager{})
+
+	writer.On("StageCustomers", mock.Anything, mock.Anything).Return(nil)
+	writer.On("ResolveStagedCities", mock.Anything).Return(nil)
+	writer.On("LockCustomers", mock.Anything).Return(nil)
+	reader.On("CheckStagedCustomers", mock.Anything).Return([]RowError(nil), nil)
+
+	report, err := svc.ImportCustomers(context.Background(), strings.NewReader(customerCSV), true)
+
+	assert.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Zero(t, report.Imported)
+	assert.Empty(t, report.Errors)
+	writer.AssertNotCalled(t, "InsertStagedCustomers", mock.Anything)
+}
+
+func TestService_ImportCustomers_RowErrors(t *testing.T) {
+	reader := new(mockReader)
+	writer := new(mockWriter)
+	svc := NewService(reader, writer, &mockTxManager{})
+
+	file := `email,first_name,last_name,store_id,address,district,city_name,postal_code,phone
+ada@example.com,Ada,Lovelace,1,12 Analytical Way,London,London,N19GU,+445550100
+not-an-email,Alan,Turing,two,3 Bletchley Rd,Bucks,Milton Keynes,MK36EB,+445550101
+grace@example.com,Grace
+`
+	// only the valid row is staged, the database finds a problem with it too
+	writer.On("StageCustomers", mock.Anything, mock.MatchedBy(func(rows []CustomerRow) bool {
+		return len(rows) == 1 && rows[0].Email == "ada@example.com"
+	})).Return(nil)
+	writer.On("ResolveStagedCities", mock.Anything).Return(nil)
+	writer.On("LockCustomers", mock.Anything).Return(nil)
+	reader.On("CheckStagedCustomers", mock.Anything).Return([]RowError{{Row: 2, Field: "email", Message: "email already belongs to a customer"}}, nil)
+
+	report, err := svc.ImportCustomers(context.Background(), strings.NewReader(file), false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, report.Rows)
+	assert.Equal(t, []RowError{
+		{Row: 2, Field: "email", Message: "email already belongs to a customer"},
+		{Row: 3, Field: "store_id", Message: "must be a whole number"},
+		{Row: 3, Field: "email", Message: "failed email validation"},
+		{Row: 4, Message: "expected 9 fields, got 2"},
+	}, report.Errors)
+	writer.AssertNotCalled(t, "InsertStagedCustomers", mock.Anything)
+}
+
+func TestService_ImportCustomers_InvalidHeader(t *testing.T) {
+	svc := NewService(new(mockReader), new(mockWriter), &mockTxManager{})
+
+	for name, file := range map[string]string{
+		"empty":     "",
+		"unknown":   "first_name,last_name,email,store_id,address,district,city_name,postal_code,phone,age\n",
+		"missing":   "first_name,last_name,email\n",
+		"duplicate": "first_name,first_name,last_name,email,store_id,address,district,city_name,postal_code,phone\n",
+	} {
+		_, err := svc.ImportCustomers(context.Background(), strings.NewReader(file), false)
+		assert.ErrorIs(t, err, ErrInvalidCSV, name)
+	}
+}
+
+func TestService_ImportInventory(t *testing.T) {
+	reader := new(mockReader)
+	writer := new(mockWriter)
+	svc := NewService(reader, writer, &mockTxManager{})
+
+	file := "store_id,film_id,copies\n1,10,3\n2,11,\n"
+	writer.On("StageInventory", mock.Anything, []InventoryRow{
+		{Line: 2, FilmID: 10, StoreID: 1, Copies: 3},
+		{Line: 3, FilmID: 11, StoreID: 2, Copies: 1},
+	}).Return(nil)
+	reader.On("CheckStagedInventory", mock.Anything).Return([]RowError(nil), nil)
+	writer.On("InsertStagedInventory", mock.Anything).Return(4, nil)
+
+	report, err := svc.ImportInventory(context.Background(), strings.NewReader(file), false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, Report{Rows: 2, Imported: 4, Errors: []RowError{}}, report)
+	writer.AssertExpectations(t)
+}
+
+func TestService_ImportInventory_RowErrors(t *testing.T) {
+	reader := new(mockReader)
+	writer := new(mockWriter)
+	svc := NewService(reader, writer, &mockTxManager{})
+
+	file := "film_id,store_id,copies\n0,1,1\n10,1,500\n"
+	report, err := svc.ImportInventory(context.Background(), strings.NewReader(file), false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []RowError{
+		{Row: 2, Field: "film_id", Message: "failed required validation"},
+		{Row: 3, Field: "copies", Message: "failed max validation"},
+	}, report.Errors)
+	writer.AssertNotCalled(t, "StageInventory", mock.Anything, mock.Anything)
+}
+
+func TestService_ImportInventory_CheckFails(t *testing.T) {
+	reader := new(mockReader)
+	writer := new(mockWriter)
+	svc := NewService(reader, writer, &mockTxManager{})
+
+	writer.On("StageInventory", mock.Anything, mock.Anything).Return(nil)
+	reader.On("CheckStagedInventory", mock.Anything).Return([]RowError(nil), errors.New("connection reset"))
+
+	_, err := svc.ImportInventory(context.Background(), strings.NewReader("film_id,store_id\n1,1\n"), false)
+
+	assert.EqualError(t, err, "connection reset")
+	writer.AssertNotCalled(t, "InsertStagedInventory", mock.Anything)
+}
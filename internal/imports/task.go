@@ -0,0 +1,50 @@
+package imports
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/job"
+)
+
+// Job kinds for imports run with ?async=true
+const (
+	KindCustomers = "import-customers"
+	KindInventory = "import-inventory"
+)
+
+type taskParams struct {
+	DryRun bool `json:"dry_run"`
+}
+
+// Tasks runs queued imports, the uploaded file is the job's input and the
+// report is its result.
+func Tasks(svc Service) map[string]job.Task {
+	return map[string]job.Task{
+		KindCustomers: importTask(svc.ImportCustomers),
+		KindInventory: importTask(svc.ImportInventory),
+	}
+}
+
+func importTask(run func(ctx context.Context, r io.Reader, dryRun bool) (Report, error)) job.Task {
+	return func(ctx context.Context, j job.Job, out *job.Output) error {
+		var params taskParams
+		if err := json.Unmarshal(j.Params, &params); err != nil {
+			return job.Permanent(err)
+		}
+
+		report, err := run(ctx, bytes.NewReader(j.Input), params.DryRun)
+		if errors.Is(err, ErrInvalidCSV) {
+			return job.Permanent(err)
+		}
+		if err != nil {
+			return err
+		}
+
+		out.Header().Set("Content-Type", "application/json")
+		return json.NewEncoder(out).Encode(report)
+	}
+}
@@ -1,12 +1,13 @@
 package inventory
 
 import (
-	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/export"
 )
 
 type Handler struct {
@@ -19,93 +20,102 @@ func NewHandler(service Service) *Handler {
 
 // GetInventory godoc
 // @Summary      Get inventory
-// @Description  Retrieve inventory items. Optionally filter by store_id query param.
+// @Description  Retrieve inventory items, streamed as they are read. Optionally filter by store_id query param.
 // @Tags         inventory
-// @Produce      json
+// @Produce      json,text/csv,application/x-ndjson
 // @Param        store_id  query     int     false  "Store ID to filter inventory"
 // @Success      200       {array}   inventory.Inventory
+// @Failure      400       {string}  string  "Invalid store_id"
 // @Failure      500       {string}  string  "Internal Server Error"
-// @Router       /inventory [get]
+// @Security     ApiKeyAuth
+// @Router       /v1/inventory [get]
 func (h *Handler) GetInventory(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	storeIDStr := r.URL.Query().Get("store_id")
 
-	var inventory []Inventory
-	var err error
-	storeID := -1
-
+	var storeID *int
 	if storeIDStr != "" {
-		storeID, _ = strconv.Atoi(storeIDStr)
-		inventory, err = h.service.GetInventoryByStore(r.Context(), storeID)
-	} else {
-		inventory, err = h.service.GetInventory(r.Context())
+		id, err := strconv.Atoi(storeIDStr)
+		if err != nil {
+			http.Error(w, "Invalid store_id", http.StatusBadRequest)
+			return
+		}
+		storeID = &id
 	}
 
-	if err != nil {
+	export.Stream(w, export.Negotiate(r), h.service.EachInventory(r.Context(), storeID), func(err error) {
 		http.Error(w, fmt.Sprint(err), http.StatusInternalServerError)
-		return
-	}
-
-	json.NewEncoder(w).Encode(inventory)
+	})
 }
 
 // GetInventoryAvailable godoc
 // @Summary      Check inventory availability
-// @Description  Check if a specific film is available in a given store
+// @Description  Every available copy of a film at a store. Copies rented out or held for a reservation are not available, next_return_due is when the soonest rented copy is due back.
 // @Tags         inventory
 // @Produce      json
 // @Param        store_id  query     int     true   "Store ID"
 // @Param        film_id   query     int     true   "Film ID"
 // @Success      200       {object}  inventory.InventoryAvailability
-// @Failure      404       {object}  map[string]interface{}  "Not Found, available=false"
+// @Failure      400       {string}  string  "Invalid store_id or film_id"
+// @Failure      404       {object}  inventory.InventoryAvailability  "No copy available, available=false"
 // @Failure      500       {string}  string  "Internal Server Error"
-// @Router       /inventory/available [get]
+// @Security     ApiKeyAuth
+// @Router       /v1/inventory/available [get]
 func (h *Handler) GetInventoryAvailable(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	var err error
-	var inventoryAvailability InventoryAvailability
-	storeIDStr := r.URL.Query().Get("store_id")
-	filmIDStr := r.URL.Query().Get("film_id")
-	storeID := -1
-	filmID := -1
-
-	if storeIDStr != "" {
-		storeID, err = strconv.Atoi(storeIDStr)
+	storeID, err := strconv.Atoi(r.URL.Query().Get("store_id"))
+	if err != nil {
+		http.Error(w, "Invalid store_id", http.StatusBadRequest)
+		return
+	}
+	filmID, err := strconv.Atoi(r.URL.Query().Get("film_id"))
+	if err != nil {
+		http.Error(w, "Invalid film_id", http.StatusBadRequest)
+		return
 	}
 
+	inventoryAvailability, err := h.service.GetInventoryAvailable(r.Context(), storeID, filmID)
 	if err != nil {
-		http.Error(w, fmt.Sprint(err), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	if filmIDStr != "" {
-		filmID, err = strconv.Atoi(filmIDStr)
+	if !inventoryAvailability.Available {
+		w.WriteHeader(http.StatusNotFound)
 	}
+	json.NewEncoder(w).Encode(inventoryAvailability)
+}
 
+// GetFilmAvailability godoc
+// @Summary      Film availability across stores
+// @Description  Available copies, copy counts and the next return due for a film at every store that stocks it
+// @Tags         inventory
+// @Produce      json
+// @Param        id   path      int  true  "Film ID"
+// @Success      200  {object}  inventory.FilmAvailability
+// @Failure      400  {string}  string  "Invalid film ID"
+// @Failure      404  {string}  string  "Film not found"
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Security     ApiKeyAuth
+// @Router       /v1/films/{id}/availability [get]
+func (h *Handler) GetFilmAvailability(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
-		http.Error(w, fmt.Sprint(err), http.StatusInternalServerError)
+		http.Error(w, "Invalid film ID", http.StatusBadRequest)
 		return
 	}
 
-	if storeID != -1 && filmID != -1 {
-		inventoryAvailability, err = h.service.GetInventoryAvailable(r.Context(), storeID, filmID)
+	availability, err := h.service.GetFilmAvailability(r.Context(), id)
+	if errors.Is(err, ErrFilmNotFound) {
+		http.Error(w, "Film not found", http.StatusNotFound)
+		return
 	}
-
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			w.WriteHeader(http.StatusNotFound)
-			json.NewEncoder(w).Encode(map[string]any{
-				"store_id":  storeID,
-				"film_id":   filmID,
-				"available": false,
-			})
-			return
-		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	json.NewEncoder(w).Encode(inventoryAvailability)
-
+	json.NewEncoder(w).Encode(availability)
 }
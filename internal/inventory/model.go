@@ -12,10 +12,40 @@ type Inventory struct {
 	Phone       string    `json:"phone"`
 }
 
+// InventoryAvailability is the availability of one film at one store. A copy
+// is available when it is not rented out and not held for a reservation.
 type InventoryAvailability struct {
-	InventoryID int    `json:"inventory_id"`
-	StoreID     int    `json:"store_id"`
-	FilmID      int    `json:"film_id"`
-	Title       string `json:"title"`
-	Available   bool   `json:"available"`
+	InventoryID           int        `json:"inventory_id"` // first available copy, 0 if none
+	StoreID               int        `json:"store_id"`
+	FilmID                int        `json:"film_id"`
+	Title                 string     `json:"title"`
+	Available             bool       `json:"available"`
+	AvailableInventoryIDs []int      `json:"available_inventory_ids"`
+	AvailableCopies       int        `json:"available_copies"`
+	HeldCopies            int        `json:"held_copies"`
+	TotalCopies           int        `json:"total_copies"`
+	NextReturnDue         *time.Time `json:"next_return_due"` // soonest due date of a rented out copy
+}
+
+// FilmAvailability is the availability of a film across every store that
+// stocks it.
+type FilmAvailability struct {
+	FilmID          int                     `json:"film_id"`
+	Title           string                  `json:"title"`
+	Available       bool                    `json:"available"`
+	AvailableCopies int                     `json:"available_copies"`
+	TotalCopies     int                     `json:"total_copies"`
+	NextReturnDue   *time.Time              `json:"next_return_due"`
+	Stores          []InventoryAvailability `json:"stores"`
+}
+
+// add totals one store's copies into the film's availability
+func (a *FilmAvailability) add(store InventoryAvailability) {
+	a.AvailableCopies += store.AvailableCopies
+	a.TotalCopies += store.TotalCopies
+	if store.NextReturnDue != nil && (a.NextReturnDue == nil || store.NextReturnDue.Before(*a.NextReturnDue)) {
+		a.NextReturnDue = store.NextReturnDue
+	}
+	a.Stores = append(a.Stores, store)
+	a.Available = a.AvailableCopies > 0
 }
@@ -2,15 +2,19 @@ package inventory
 
 import (
 	"context"
+	"errors"
+	"iter"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rstoltzm-profile/video-rental-api/internal/db"
 )
 
 type InventoryReader interface {
-	GetInventory(ctx context.Context) ([]Inventory, error)
-	GetInventoryByStore(ctx context.Context, storeID int) ([]Inventory, error)
-	FindInventoryAvailable(ctx context.Context, storeID int, filmID int) (InventoryAvailability, error)
+	EachInventory(ctx context.Context, storeID *int) iter.Seq2[Inventory, error]
+	FindAvailability(ctx context.Context, filmID int, storeID *int) ([]InventoryAvailability, error)
+	FindFilmsAvailability(ctx context.Context, filmIDs []int) ([]InventoryAvailability, error)
+	GetFilmTitle(ctx context.Context, filmID int) (string, error)
 }
 
 type Repository interface {
@@ -34,7 +38,9 @@ func (r *repository) BeginTx(ctx context.Context) (pgx.Tx, error) {
 	return r.pool.Begin(ctx)
 }
 
-func (r *repository) GetInventory(ctx context.Context) ([]Inventory, error) {
+// EachInventory iterates every copy with its film and store, or only one
+// store's copies.
+func (r *repository) EachInventory(ctx context.Context, storeID *int) iter.Seq2[Inventory, error] {
 	query := `
 	SELECT 	inventory.inventory_id,
 			inventory.last_update,
@@ -48,92 +54,109 @@ func (r *repository) GetInventory(ctx context.Context) ([]Inventory, error) {
 		INNER JOIN store ON inventory.store_id = store.store_id
 		INNER JOIN film ON inventory.film_id = film.film_id
 		INNER JOIN address ON store.address_id = address.address_id
+	WHERE
+		($1::int IS NULL OR store.store_id = $1)
+	ORDER BY
+		inventory.inventory_id
 	`
-	rows, err := r.pool.Query(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var rentals []Inventory
-	for rows.Next() {
+	return db.Seq(ctx, r.pool, func(row pgx.CollectableRow) (Inventory, error) {
 		var c Inventory
-		if err := rows.Scan(&c.InventoryID, &c.LastUpdate, &c.FilmID, &c.Title, &c.StoreID, &c.AddressId, &c.Phone); err != nil {
-			return nil, err
-		}
-		rentals = append(rentals, c)
-	}
-	return rentals, nil
+		err := row.Scan(&c.InventoryID, &c.LastUpdate, &c.FilmID, &c.Title, &c.StoreID, &c.AddressId, &c.Phone)
+		return c, err
+	}, query, storeID)
 }
 
-func (r *repository) GetInventoryByStore(ctx context.Context, storeID int) ([]Inventory, error) {
-	query := `
-	SELECT 	inventory.inventory_id,
-			inventory.last_update,
-			inventory.film_id,
-			film.title,
+// availabilityQuery returns one row per film and store for the films in $1,
+// or only store $2. A copy is rented out while it has a rental with no
+// return_date, copies that were never rented are on the shelf. Copies held for
+// a reservation that hasn't expired are not available.
+const availabilityQuery = `
+	WITH copies AS (
+		SELECT
+			inventory.inventory_id,
 			inventory.store_id,
-			store.address_id,
-			address.phone
+			inventory.film_id,
+			open_rental.due_date,
+			open_rental.due_date IS NOT NULL AS rented,
+			EXISTS (
+				SELECT 1 FROM reservation
+				WHERE reservation.inventory_id = inventory.inventory_id
+					AND reservation.status = 'held'
+					AND reservation.hold_expires_at > CURRENT_TIMESTAMP
+			) AS held
+		FROM
+			inventory
+			INNER JOIN film ON inventory.film_id = film.film_id
+			LEFT JOIN LATERAL (
+				SELECT rental.rental_date + film.rental_duration * INTERVAL '1 day' AS due_date
+				FROM rental
+				WHERE rental.inventory_id = inventory.inventory_id
+					AND rental.return_date IS NULL
+				ORDER BY rental.rental_date DESC
+				LIMIT 1
+			) open_rental ON TRUE
+		WHERE
+			inventory.film_id = ANY($1)
+			AND ($2::int IS NULL OR inventory.store_id = $2)
+	)
+	SELECT
+		copies.store_id,
+		copies.film_id,
+		film.title,
+		COALESCE(array_agg(copies.inventory_id ORDER BY copies.inventory_id)
+			FILTER (WHERE NOT copies.rented AND NOT copies.held), '{}') AS available_ids,
+		COUNT(*) FILTER (WHERE copies.held AND NOT copies.rented) AS held_copies,
+		COUNT(*) AS total_copies,
+		MIN(copies.due_date) AS next_return_due
 	FROM
-		inventory
-		INNER JOIN store ON inventory.store_id = store.store_id
-		INNER JOIN film ON inventory.film_id = film.film_id
-		INNER JOIN address ON store.address_id = address.address_id
-	WHERE
-		store.store_id = $1
-	`
-	rows, err := r.pool.Query(ctx, query, storeID)
+		copies
+		INNER JOIN film ON copies.film_id = film.film_id
+	GROUP BY
+		copies.store_id, copies.film_id, film.title
+	ORDER BY
+		copies.film_id, copies.store_id
+`
+
+// FindAvailability returns one row per store stocking the film, or only the
+// given store.
+func (r *repository) FindAvailability(ctx context.Context, filmID int, storeID *int) ([]InventoryAvailability, error) {
+	return r.findAvailability(ctx, []int{filmID}, storeID)
+}
+
+// FindFilmsAvailability returns one row per film and store for many films at
+// once, films that aren't stocked have no rows.
+func (r *repository) FindFilmsAvailability(ctx context.Context, filmIDs []int) ([]InventoryAvailability, error) {
+	return r.findAvailability(ctx, filmIDs, nil)
+}
+
+func (r *repository) findAvailability(ctx context.Context, filmIDs []int, storeID *int) ([]InventoryAvailability, error) {
+	rows, err := r.pool.Query(ctx, availabilityQuery, filmIDs, storeID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var rentals []Inventory
+	var stores []InventoryAvailability
 	for rows.Next() {
-		var c Inventory
-		if err := rows.Scan(&c.InventoryID, &c.LastUpdate, &c.FilmID, &c.Title, &c.StoreID, &c.AddressId, &c.Phone); err != nil {
+		var a InventoryAvailability
+		if err := rows.Scan(&a.StoreID, &a.FilmID, &a.Title, &a.AvailableInventoryIDs, &a.HeldCopies, &a.TotalCopies, &a.NextReturnDue); err != nil {
 			return nil, err
 		}
-		rentals = append(rentals, c)
+		a.AvailableCopies = len(a.AvailableInventoryIDs)
+		a.Available = a.AvailableCopies > 0
+		if a.Available {
+			a.InventoryID = a.AvailableInventoryIDs[0]
+		}
+		stores = append(stores, a)
 	}
-	return rentals, nil
+	return stores, rows.Err()
 }
 
-func (r *repository) FindInventoryAvailable(ctx context.Context, storeID int, filmID int) (InventoryAvailability, error) {
-	var i InventoryAvailability
-	query := `
-	WITH latest_rentals AS (
-		SELECT DISTINCT ON (inv.inventory_id)
-			inv.inventory_id,
-			inv.store_id,
-			inv.film_id,
-			f.title,
-			r.rental_date,
-			r.return_date
-		FROM
-			inventory inv
-		JOIN film f ON inv.film_id = f.film_id
-		LEFT JOIN rental r ON inv.inventory_id = r.inventory_id
-		WHERE
-			inv.store_id = $1
-			AND inv.film_id = $2
-		ORDER BY
-			inv.inventory_id,
-			r.rental_date DESC
-	)
-	SELECT 
-		inventory_id,
-		store_id,
-		film_id,
-		title,
-		TRUE AS available
-	FROM latest_rentals
-	WHERE return_date IS NOT NULL
-	LIMIT 1;
-	`
-
-	err := r.pool.QueryRow(ctx, query, storeID, filmID).Scan(&i.InventoryID, &i.StoreID, &i.FilmID, &i.Title, &i.Available)
-
-	return i, err
+func (r *repository) GetFilmTitle(ctx context.Context, filmID int) (string, error) {
+	var title string
+	err := r.pool.QueryRow(ctx, `SELECT title FROM film WHERE film_id = $1`, filmID).Scan(&title)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrFilmNotFound
+	}
+	return title, err
 }
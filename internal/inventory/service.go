@@ -2,12 +2,17 @@ package inventory
 
 import (
 	"context"
+	"errors"
+	"iter"
 )
 
+var ErrFilmNotFound = errors.New("film not found")
+
 type Service interface {
-	GetInventory(ctx context.Context) ([]Inventory, error)
-	GetInventoryByStore(ctx context.Context, storeID int) ([]Inventory, error)
+	EachInventory(ctx context.Context, storeID *int) iter.Seq2[Inventory, error]
 	GetInventoryAvailable(ctx context.Context, storeID int, filmID int) (InventoryAvailability, error)
+	GetFilmAvailability(ctx context.Context, filmID int) (FilmAvailability, error)
+	GetFilmsAvailability(ctx context.Context, filmIDs []int) ([]FilmAvailability, error)
 }
 
 type service struct {
@@ -22,14 +27,56 @@ func NewService(reader InventoryReader, tx TransactionManager) Service {
 	}
 }
 
-func (s *service) GetInventory(ctx context.Context) ([]Inventory, error) {
-	return s.reader.GetInventory(ctx)
+func (s *service) EachInventory(ctx context.Context, storeID *int) iter.Seq2[Inventory, error] {
+	return s.reader.EachInventory(ctx, storeID)
 }
 
-func (s *service) GetInventoryByStore(ctx context.Context, storeID int) ([]Inventory, error) {
-	return s.reader.GetInventoryByStore(ctx, storeID)
+// GetInventoryAvailable reports Available false, with no copies, when the
+// store doesn't stock the film.
+func (s *service) GetInventoryAvailable(ctx context.Context, storeID int, filmID int) (InventoryAvailability, error) {
+	stores, err := s.reader.FindAvailability(ctx, filmID, &storeID)
+	if err != nil {
+		return InventoryAvailability{}, err
+	}
+	if len(stores) == 0 {
+		return InventoryAvailability{StoreID: storeID, FilmID: filmID, AvailableInventoryIDs: []int{}}, nil
+	}
+	return stores[0], nil
 }
 
-func (s *service) GetInventoryAvailable(ctx context.Context, storeID int, filmID int) (InventoryAvailability, error) {
-	return s.reader.FindInventoryAvailable(ctx, storeID, filmID)
+func (s *service) GetFilmAvailability(ctx context.Context, filmID int) (FilmAvailability, error) {
+	title, err := s.reader.GetFilmTitle(ctx, filmID)
+	if err != nil {
+		return FilmAvailability{}, err
+	}
+
+	stores, err := s.reader.FindAvailability(ctx, filmID, nil)
+	if err != nil {
+		return FilmAvailability{}, err
+	}
+
+	availability := FilmAvailability{FilmID: filmID, Title: title, Stores: []InventoryAvailability{}}
+	for _, store := range stores {
+		availability.add(store)
+	}
+	return availability, nil
+}
+
+// GetFilmsAvailability is GetFilmAvailability for many films in one query.
+// Films that no store stocks, or that don't exist, are left out.
+func (s *service) GetFilmsAvailability(ctx context.Context, filmIDs []int) ([]FilmAvailability, error) {
+	stores, err := s.reader.FindFilmsAvailability(ctx, filmIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// rows come ordered by film
+	var films []FilmAvailability
+	for _, store := range stores {
+		if len(films) == 0 || films[len(films)-1].FilmID != store.FilmID {
+			films = append(films, FilmAvailability{FilmID: store.FilmID, Title: store.Title, Stores: []InventoryAvailability{}})
+		}
+		films[len(films)-1].add(store)
+	}
+	return films, nil
 }
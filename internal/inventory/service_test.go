@@ -0,0 +1,106 @@
+package inventory
+
+import (
+	"context"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockInventoryReader struct {
+	mock.Mock
+}
+
+func (m *mockInventoryReader) EachInventory(ctx context.Context, storeID *int) iter.Seq2[Inventory, error] {
+	args := m.Called(ctx, storeID)
+	return args.Get(0).(iter.Seq2[Inventory, error])
+}
+
+func (m *mockInventoryReader) FindAvailability(ctx context.Context, filmID int, storeID *int) ([]InventoryAvailability, error) {
+	args := m.Called(ctx, filmID, storeID)
+	return args.Get(0).([]InventoryAvailability), args.Error(1)
+}
+
+func (m *mockInventoryReader) FindFilmsAvailability(ctx context.Context, filmIDs []int) ([]InventoryAvailability, error) {
+	args := m.Called(ctx, filmIDs)
+	return args.Get(0).([]InventoryAvailability), args.Error(1)
+}
+
+func (m *mockInventoryReader) GetFilmTitle(ctx context.Context, filmID int) (string, error) {
+	args := m.Called(ctx, filmID)
+	return args.String(0), args.Error(1)
+}
+
+func TestService_GetFilmAvailability(t *testing.T) {
+	reader := new(mockInventoryReader)
+	svc := NewService(reader, nil)
+
+	soon := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	later := soon.AddDate(0, 0, 2)
+	reader.On("GetFilmTitle", mock.Anything, 1).Return("ACADEMY DINOSAUR", nil)
+	reader.On("FindAvailability", mock.Anything, 1, (*int)(nil)).Return([]InventoryAvailability{
+		{StoreID: 1, FilmID: 1, AvailableInventoryIDs: []int{}, TotalCopies: 2, NextReturnDue: &later},
+		{StoreID: 2, FilmID: 1, Available: true, InventoryID: 5, AvailableInventoryIDs: []int{5, 6}, AvailableCopies: 2, TotalCopies: 3, NextReturnDue: &soon},
+	}, nil)
+
+	availability, err := svc.GetFilmAvailability(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.True(t, availability.Available)
+	assert.Equal(t, 2, availability.AvailableCopies)
+	assert.Equal(t, 5, availability.TotalCopies)
+	assert.Equal(t, &soon, availability.NextReturnDue)
+	assert.Len(t, availability.Stores, 2)
+}
+
+func TestService_GetFilmAvailability_NotFound(t *testing.T) {
+	reader := new(mockInventoryReader)
+	svc := NewService(reader, nil)
+
+	reader.On("GetFilmTitle", mock.Anything, 99999).Return("", ErrFilmNotFound)
+
+	_, err := svc.GetFilmAvailability(context.Background(), 99999)
+
+	assert.ErrorIs(t, err, ErrFilmNotFound)
+	reader.AssertNotCalled(t, "FindAvailability", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_GetFilmsAvailability(t *testing.T) {
+	reader := new(mockInventoryReader)
+	svc := NewService(reader, nil)
+
+	reader.On("FindFilmsAvailability", mock.Anything, []int{1, 2, 3}).Return([]InventoryAvailability{
+		{StoreID: 1, FilmID: 1, Title: "ACADEMY DINOSAUR", AvailableInventoryIDs: []int{}, TotalCopies: 2},
+		{StoreID: 2, FilmID: 1, Title: "ACADEMY DINOSAUR", Available: true, InventoryID: 5, AvailableInventoryIDs: []int{5}, AvailableCopies: 1, TotalCopies: 1},
+		{StoreID: 2, FilmID: 3, Title: "ADAPTATION HOLES", AvailableInventoryIDs: []int{}, TotalCopies: 4},
+	}, nil)
+
+	films, err := svc.GetFilmsAvailability(context.Background(), []int{1, 2, 3})
+
+	assert.NoError(t, err)
+	assert.Len(t, films, 2) // film 2 isn't stocked
+	assert.Equal(t, 1, films[0].FilmID)
+	assert.True(t, films[0].Available)
+	assert.Equal(t, 3, films[0].TotalCopies)
+	assert.Len(t, films[0].Stores, 2)
+	assert.Equal(t, "ADAPTATION HOLES", films[1].Title)
+	assert.False(t, films[1].Available)
+}
+
+func TestService_GetInventoryAvailable_NotStocked(t *testing.T) {
+	reader := new(mockInventoryReader)
+	svc := NewService(reader, nil)
+
+	storeID := 2
+	reader.On("FindAvailability", mock.Anything, 1, &storeID).Return([]InventoryAvailability{}, nil)
+
+	availability, err := svc.GetInventoryAvailable(context.Background(), storeID, 1)
+
+	assert.NoError(t, err)
+	assert.False(t, availability.Available)
+	assert.Equal(t, 2, availability.StoreID)
+	assert.Equal(t, []int{}, availability.AvailableInventoryIDs)
+}
@@ -0,0 +1,133 @@
+package job
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// writeError maps service errors to a status code, anything unknown is a 500
+// with the fallback message.
+func writeError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, ErrJobNotFound):
+		http.Error(w, "Job not found", http.StatusNotFound)
+	case errors.Is(err, ErrJobFinished), errors.Is(err, ErrNoResult):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, fallback, http.StatusInternalServerError)
+	}
+}
+
+// Async reports whether the client asked for the work to run as a job with
+// ?async=true.
+func Async(r *http.Request) bool {
+	return r.URL.Query().Get("async") == "true"
+}
+
+// Accepted answers a request that was queued as a job, 202 with the job and
+// a Location to poll.
+func Accepted(w http.ResponseWriter, j Job) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/v1/jobs/%d", j.ID))
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(j)
+}
+
+// GetJob godoc
+// @Summary      Get job status
+// @Description  Status, progress and attempts of a background job. result_url is set once the job has succeeded.
+// @Tags         jobs
+// @Produce      json
+// @Param        id   path      int  true  "Job ID"
+// @Success      200  {object}  job.Job
+// @Failure      400  {string}  string  "Invalid job ID"
+// @Failure      404  {string}  string  "Job not found"
+// @Failure      500  {string}  string  "Failed to fetch job"
+// @Security     ApiKeyAuth
+// @Router       /v1/jobs/{id} [get]
+func (h *Handler) GetJob(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	j, err := h.service.GetJob(r.Context(), id)
+	if err != nil {
+		writeError(w, err, "Failed to fetch job")
+		return
+	}
+	json.NewEncoder(w).Encode(j)
+}
+
+// GetResult godoc
+// @Summary      Get job result
+// @Description  The output of a succeeded job, in the format the job was asked for
+// @Tags         jobs
+// @Produce      json,text/csv,application/x-ndjson
+// @Param        id   path      int  true  "Job ID"
+// @Success      200  {file}    file
+// @Failure      400  {string}  string  "Invalid job ID"
+// @Failure      404  {string}  string  "Job not found"
+// @Failure      409  {string}  string  "Job has not succeeded"
+// @Failure      500  {string}  string  "Failed to fetch job result"
+// @Security     ApiKeyAuth
+// @Router       /v1/jobs/{id}/result [get]
+func (h *Handler) GetResult(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.GetResult(r.Context(), id)
+	if err != nil {
+		writeError(w, err, "Failed to fetch job result")
+		return
+	}
+
+	w.Header().Set("Content-Type", result.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(result.Body)))
+	w.Write(result.Body)
+}
+
+// CancelJob godoc
+// @Summary      Cancel a job
+// @Description  A queued job is cancelled straight away. A running job gets cancel_requested and stops within a few seconds.
+// @Tags         jobs
+// @Produce      json
+// @Param        id   path      int  true  "Job ID"
+// @Success      200  {object}  job.Job
+// @Failure      400  {string}  string  "Invalid job ID"
+// @Failure      404  {string}  string  "Job not found"
+// @Failure      409  {string}  string  "Job has already finished"
+// @Failure      500  {string}  string  "Failed to cancel job"
+// @Security     ApiKeyAuth
+// @Router       /v1/jobs/{id} [delete]
+func (h *Handler) CancelJob(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	j, err := h.service.CancelJob(r.Context(), id)
+	if err != nil {
+		writeError(w, err, "Failed to cancel job")
+		return
+	}
+	json.NewEncoder(w).Encode(j)
+}
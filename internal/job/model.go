@@ -0,0 +1,98 @@
+package job
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// DefaultMaxAttempts is how many times a job runs before it fails for good
+const DefaultMaxAttempts = 3
+
+type Job struct {
+	ID              int        `json:"id"`
+	Kind            string     `json:"kind"`
+	Status          Status     `json:"status"`
+	Progress        int        `json:"progress"` // percent
+	Attempts        int        `json:"attempts"`
+	MaxAttempts     int        `json:"max_attempts"`
+	CancelRequested bool       `json:"cancel_requested"`
+	Error           *string    `json:"error,omitempty"`
+	ResultURL       *string    `json:"result_url,omitempty"`
+	RunAt           time.Time  `json:"run_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	StartedAt       *time.Time `json:"started_at,omitempty"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
+
+	// Params and Input are only loaded for the worker running the job
+	Params json.RawMessage `json:"-"`
+	Input  []byte          `json:"-"`
+}
+
+// NewJob is a job to enqueue. Params is stored as JSON, Input is an optional
+// upload such as a CSV file.
+type NewJob struct {
+	Kind        string
+	Params      any
+	Input       []byte
+	MaxAttempts int // DefaultMaxAttempts when 0
+}
+
+// Result is what a succeeded job produced, served at ResultURL
+type Result struct {
+	ContentType string
+	Body        []byte
+}
+
+// Output is where a task writes its result. It is an http.ResponseWriter so
+// the export writers can stream rows into it the same way they stream a
+// response.
+type Output struct {
+	header   http.Header
+	body     bytes.Buffer
+	progress atomic.Int32
+}
+
+func newOutput() *Output {
+	return &Output{header: http.Header{}}
+}
+
+func (o *Output) Header() http.Header {
+	return o.header
+}
+
+func (o *Output) Write(p []byte) (int, error) {
+	return o.body.Write(p)
+}
+
+// WriteHeader is a no-op, a job's result has no status code
+func (o *Output) WriteHeader(int) {}
+
+// Progress records that done of total units of work are finished. It is
+// saved with the job's next heartbeat.
+func (o *Output) Progress(done, total int) {
+	if total <= 0 {
+		return
+	}
+	o.progress.Store(int32(min(100, done*100/total)))
+}
+
+func (o *Output) result() Result {
+	contentType := o.header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(o.body.Bytes())
+	}
+	return Result{ContentType: contentType, Body: o.body.Bytes()}
+}
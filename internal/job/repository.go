@@ -0,0 +1,249 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rstoltzm-profile/video-rental-api/internal/db"
+)
+
+const jobColumns = `
+	job_id, kind, status, progress, attempts, max_attempts, cancel_requested,
+	error, run_at, created_at, started_at, finished_at
+`
+
+type JobReader interface {
+	GetJob(ctx context.Context, id int) (Job, error)
+	GetResult(ctx context.Context, id int) (Result, error)
+}
+
+type JobWriter interface {
+	InsertJob(ctx context.Context, kind string, params []byte, input []byte, maxAttempts int) (Job, error)
+	CancelJob(ctx context.Context, id int) (Job, error)
+}
+
+// Queue is the worker side of the job table. Every update after a claim is
+// fenced on the attempt number, so a worker that lost its lease can't
+// overwrite the worker that took the job over.
+type Queue interface {
+	ClaimJob(ctx context.Context, lease time.Duration) (*Job, error)
+	Heartbeat(ctx context.Context, id, attempt, progress int, lease time.Duration) (cancelRequested bool, err error)
+	CompleteJob(ctx context.Context, id, attempt int, result Result) error
+	RetryJob(ctx context.Context, id, attempt int, message string, runAt time.Time) error
+	FinishJob(ctx context.Context, id, attempt int, status Status, message *string) error
+	ReleaseJob(ctx context.Context, id, attempt int) error
+	SweepJobs(ctx context.Context, retention time.Duration) (int, error)
+}
+
+type Repository interface {
+	JobReader
+	JobWriter
+	Queue
+}
+
+type repository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRepository(pool *pgxpool.Pool) Repository {
+	return &repository{pool: pool}
+}
+
+// conn returns the transaction started by db.WithTx, or the pool
+func (r *repository) conn(ctx context.Context) db.DBTX {
+	return db.Conn(ctx, r.pool)
+}
+
+// scanJob scans jobColumns, then any extra columns into extra
+func scanJob(row pgx.Row, extra ...any) (Job, error) {
+	var j Job
+	dest := append([]any{
+		&j.ID, &j.Kind, &j.Status, &j.Progress, &j.Attempts, &j.MaxAttempts, &j.CancelRequested,
+		&j.Error, &j.RunAt, &j.CreatedAt, &j.StartedAt, &j.FinishedAt,
+	}, extra...)
+	err := row.Scan(dest...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return j, ErrJobNotFound
+	}
+	if j.Status == StatusSucceeded {
+		url := fmt.Sprintf("/v1/jobs/%d/result", j.ID)
+		j.ResultURL = &url
+	}
+	return j, err
+}
+
+func (r *repository) GetJob(ctx context.Context, id int) (Job, error) {
+	return scanJob(r.conn(ctx).QueryRow(ctx, `SELECT `+jobColumns+` FROM job WHERE job_id = $1`, id))
+}
+
+func (r *repository) GetResult(ctx context.Context, id int) (Result, error) {
+	var result Result
+	err := r.conn(ctx).QueryRow(ctx,
+		`SELECT content_type, body FROM job_result WHERE job_id = $1`, id,
+	).Scan(&result.ContentType, &result.Body)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return result, ErrNoResult
+	}
+	return result, err
+}
+
+func (r *repository) InsertJob(ctx context.Context, kind string, params []byte, input []byte, maxAttempts int) (Job, error) {
+	return scanJob(r.conn(ctx).QueryRow(ctx, `
+		INSERT INTO job (kind, params, input, max_attempts)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+jobColumns,
+		kind, params, input, maxAttempts,
+	))
+}
+
+// CancelJob cancels a queued job straight away and asks the worker running a
+// running job to stop. Finished jobs are not matched.
+func (r *repository) CancelJob(ctx context.Context, id int) (Job, error) {
+	return scanJob(r.conn(ctx).QueryRow(ctx, `
+		UPDATE job SET
+			status = CASE WHEN status = 'queued' THEN 'cancelled' ELSE status END,
+			finished_at = CASE WHEN status = 'queued' THEN CURRENT_TIMESTAMP END,
+			input = CASE WHEN status = 'queued' THEN NULL ELSE input END,
+			cancel_requested = TRUE
+		WHERE job_id = $1 AND status IN ('queued', 'running')
+		RETURNING `+jobColumns,
+		id,
+	))
+}
+
+// ClaimJob takes the next due job, or a running job whose worker stopped
+// renewing its lease. SKIP LOCKED lets every worker claim at once without
+// waiting on each other. It returns nil when there is nothing to run.
+func (r *repository) ClaimJob(ctx context.Context, lease time.Duration) (*Job, error) {
+	var params, input []byte
+	j, err := scanJob(r.conn(ctx).QueryRow(ctx, `
+		UPDATE job SET
+			status = 'running',
+			attempts = attempts + 1,
+			progress = 0,
+			started_at = CURRENT_TIMESTAMP,
+			locked_until = CURRENT_TIMESTAMP + make_interval(secs => $1)
+		WHERE job_id = (
+			SELECT job_id FROM job
+			WHERE (status = 'queued' AND run_at <= CURRENT_TIMESTAMP)
+				OR (status = 'running' AND locked_until < CURRENT_TIMESTAMP
+					AND attempts < max_attempts AND NOT cancel_requested)
+			ORDER BY run_at, job_id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+jobColumns+`, params, input`,
+		lease.Seconds(),
+	), &params, &input)
+	if errors.Is(err, ErrJobNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	j.Params, j.Input = params, input
+	return &j, nil
+}
+
+func (r *repository) Heartbeat(ctx context.Context, id, attempt, progress int, lease time.Duration) (bool, error) {
+	var cancelRequested bool
+	err := r.conn(ctx).QueryRow(ctx, `
+		UPDATE job SET
+			progress = GREATEST(progress, $3),
+			locked_until = CURRENT_TIMESTAMP + make_interval(secs => $4)
+		WHERE job_id = $1 AND attempts = $2 AND status = 'running'
+		RETURNING cancel_requested
+	`, id, attempt, progress, lease.Seconds()).Scan(&cancelRequested)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, ErrLeaseLost
+	}
+	return cancelRequested, err
+}
+
+func (r *repository) CompleteJob(ctx context.Context, id, attempt int, result Result) error {
+	return db.WithTx(ctx, r.pool, func(ctx context.Context) error {
+		tag, err := r.conn(ctx).Exec(ctx, `
+			UPDATE job SET
+				status = 'succeeded', progress = 100, error = NULL, input = NULL,
+				locked_until = NULL, finished_at = CURRENT_TIMESTAMP
+			WHERE job_id = $1 AND attempts = $2 AND status = 'running'
+		`, id, attempt)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrLeaseLost
+		}
+		_, err = r.conn(ctx).Exec(ctx, `
+			INSERT INTO job_result (job_id, content_type, body) VALUES ($1, $2, $3)
+			ON CONFLICT (job_id) DO UPDATE SET content_type = EXCLUDED.content_type, body = EXCLUDED.body
+		`, id, result.ContentType, result.Body)
+		return err
+	})
+}
+
+func (r *repository) RetryJob(ctx context.Context, id, attempt int, message string, runAt time.Time) error {
+	return r.fenced(ctx, `
+		UPDATE job SET status = 'queued', error = $3, run_at = $4, locked_until = NULL
+		WHERE job_id = $1 AND attempts = $2 AND status = 'running'
+	`, id, attempt, message, runAt)
+}
+
+// FinishJob ends a job as failed or cancelled
+func (r *repository) FinishJob(ctx context.Context, id, attempt int, status Status, message *string) error {
+	return r.fenced(ctx, `
+		UPDATE job SET
+			status = $3, error = $4, input = NULL,
+			locked_until = NULL, finished_at = CURRENT_TIMESTAMP
+		WHERE job_id = $1 AND attempts = $2 AND status = 'running'
+	`, id, attempt, status, message)
+}
+
+// ReleaseJob puts a job back in the queue without counting the attempt, for
+// workers stopping with the server.
+func (r *repository) ReleaseJob(ctx context.Context, id, attempt int) error {
+	return r.fenced(ctx, `
+		UPDATE job SET status = 'queued', attempts = attempts - 1, run_at = CURRENT_TIMESTAMP, locked_until = NULL
+		WHERE job_id = $1 AND attempts = $2 AND status = 'running'
+	`, id, attempt)
+}
+
+func (r *repository) fenced(ctx context.Context, query string, args ...any) error {
+	tag, err := r.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// SweepJobs ends jobs whose worker died on their last attempt or after a
+// cancel request, and deletes finished jobs older than retention with their
+// results.
+func (r *repository) SweepJobs(ctx context.Context, retention time.Duration) (int, error) {
+	tag, err := r.conn(ctx).Exec(ctx, `
+		UPDATE job SET
+			status = CASE WHEN cancel_requested THEN 'cancelled' ELSE 'failed' END,
+			error = CASE WHEN cancel_requested THEN error ELSE 'worker stopped responding' END,
+			input = NULL, locked_until = NULL, finished_at = CURRENT_TIMESTAMP
+		WHERE status = 'running' AND locked_until < CURRENT_TIMESTAMP
+			AND (attempts >= max_attempts OR cancel_requested)
+	`)
+	if err != nil {
+		return 0, err
+	}
+	swept := int(tag.RowsAffected())
+
+	tag, err = r.conn(ctx).Exec(ctx, `
+		DELETE FROM job
+		WHERE status IN ('succeeded', 'failed', 'cancelled')
+			AND finished_at < CURRENT_TIMESTAMP - make_interval(secs => $1)
+	`, retention.Seconds())
+	return swept + int(tag.RowsAffected()), err
+}
@@ -0,0 +1,82 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+	ErrJobFinished = errors.New("job has already finished")
+	ErrNoResult    = errors.New("job has no result, it has not succeeded")
+	ErrLeaseLost   = errors.New("job was taken over by another worker")
+)
+
+// Enqueuer is what handlers need to hand work to the job queue
+type Enqueuer interface {
+	Enqueue(ctx context.Context, req NewJob) (Job, error)
+}
+
+type Service interface {
+	Enqueuer
+	GetJob(ctx context.Context, id int) (Job, error)
+	GetResult(ctx context.Context, id int) (Result, error)
+	CancelJob(ctx context.Context, id int) (Job, error)
+}
+
+type service struct {
+	reader JobReader
+	writer JobWriter
+}
+
+func NewService(reader JobReader, writer JobWriter) Service {
+	return &service{
+		reader: reader,
+		writer: writer,
+	}
+}
+
+func (s *service) Enqueue(ctx context.Context, req NewJob) (Job, error) {
+	params := []byte("{}")
+	if req.Params != nil {
+		var err error
+		if params, err = json.Marshal(req.Params); err != nil {
+			return Job{}, err
+		}
+	}
+	if req.MaxAttempts < 1 {
+		req.MaxAttempts = DefaultMaxAttempts
+	}
+	return s.writer.InsertJob(ctx, req.Kind, params, req.Input, req.MaxAttempts)
+}
+
+func (s *service) GetJob(ctx context.Context, id int) (Job, error) {
+	return s.reader.GetJob(ctx, id)
+}
+
+func (s *service) GetResult(ctx context.Context, id int) (Result, error) {
+	j, err := s.reader.GetJob(ctx, id)
+	if err != nil {
+		return Result{}, err
+	}
+	if j.Status != StatusSucceeded {
+		return Result{}, ErrNoResult
+	}
+	return s.reader.GetResult(ctx, id)
+}
+
+// CancelJob cancels a queued job, or asks the worker to stop a running one.
+// The job's status changes once the worker notices, on its next heartbeat.
+func (s *service) CancelJob(ctx context.Context, id int) (Job, error) {
+	j, err := s.writer.CancelJob(ctx, id)
+	if !errors.Is(err, ErrJobNotFound) {
+		return j, err
+	}
+
+	// Not queued or running, tell a finished job from a missing one
+	if j, err = s.reader.GetJob(ctx, id); err != nil {
+		return j, err
+	}
+	return j, ErrJobFinished
+}
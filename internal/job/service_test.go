@@ -0,0 +1,119 @@
+package job
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockReader struct {
+	mock.Mock
+}
+
+func (m *mockReader) GetJob(ctx context.Context, id int) (Job, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Job), args.Error(1)
+}
+
+func (m *mockReader) GetResult(ctx context.Context, id int) (Result, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Result), args.Error(1)
+}
+
+type mockWriter struct {
+	mock.Mock
+}
+
+func (m *mockWriter) InsertJob(ctx context.Context, kind string, params []byte, input []byte, maxAttempts int) (Job, error) {
+	args := m.Called(ctx, kind, params, input, maxAttempts)
+	return args.Get(0).(Job), args.Error(1)
+}
+
+func (m *mockWriter) CancelJob(ctx context.Context, id int) (Job, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Job), args.Error(1)
+}
+
+func TestService_Enqueue(t *testing.T) {
+	writer := new(mockWriter)
+	svc := NewService(new(mockReader), writer)
+
+	type params struct {
+		DryRun bool `json:"dry_run"`
+	}
+	writer.On("InsertJob", mock.Anything, "import-customers", []byte(`{"dry_run":true}`), []byte("a,b\n"), DefaultMaxAttempts).
+		Return(Job{ID: 7, Status: StatusQueued}, nil)
+
+	j, err := svc.Enqueue(context.Background(), NewJob{Kind: "import-customers", Params: params{DryRun: true}, Input: []byte("a,b\n")})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, j.ID)
+	writer.AssertExpectations(t)
+}
+
+func TestService_Enqueue_NoParams(t *testing.T) {
+	writer := new(mockWriter)
+	svc := NewService(new(mockReader), writer)
+
+	writer.On("InsertJob", mock.Anything, "report", []byte("{}"), []byte(nil), 5).Return(Job{ID: 8}, nil)
+
+	_, err := svc.Enqueue(context.Background(), NewJob{Kind: "report", MaxAttempts: 5})
+
+	assert.NoError(t, err)
+	writer.AssertExpectations(t)
+}
+
+func TestService_GetResult_NotSucceeded(t *testing.T) {
+	reader := new(mockReader)
+	svc := NewService(reader, new(mockWriter))
+
+	reader.On("GetJob", mock.Anything, 3).Return(Job{ID: 3, Status: StatusRunning}, nil)
+
+	_, err := svc.GetResult(context.Background(), 3)
+
+	assert.ErrorIs(t, err, ErrNoResult)
+	reader.AssertNotCalled(t, "GetResult", mock.Anything, mock.Anything)
+}
+
+func TestService_CancelJob(t *testing.T) {
+	writer := new(mockWriter)
+	svc := NewService(new(mockReader), writer)
+
+	writer.On("CancelJob", mock.Anything, 4).Return(Job{ID: 4, Status: StatusRunning, CancelRequested: true}, nil)
+
+	j, err := svc.CancelJob(context.Background(), 4)
+
+	assert.NoError(t, err)
+	assert.True(t, j.CancelRequested)
+}
+
+func TestService_CancelJob_Finished(t *testing.T) {
+	reader := new(mockReader)
+	writer := new(mockWriter)
+	svc := NewService(reader, writer)
+
+	finished := time.Now()
+	writer.On("CancelJob", mock.Anything, 5).Return(Job{}, ErrJobNotFound)
+	reader.On("GetJob", mock.Anything, 5).Return(Job{ID: 5, Status: StatusSucceeded, FinishedAt: &finished}, nil)
+
+	j, err := svc.CancelJob(context.Background(), 5)
+
+	assert.ErrorIs(t, err, ErrJobFinished)
+	assert.Equal(t, StatusSucceeded, j.Status)
+}
+
+func TestService_CancelJob_NotFound(t *testing.T) {
+	reader := new(mockReader)
+	writer := new(mockWriter)
+	svc := NewService(reader, writer)
+
+	writer.On("CancelJob", mock.Anything, 6).Return(Job{}, ErrJobNotFound)
+	reader.On("GetJob", mock.Anything, 6).Return(Job{}, ErrJobNotFound)
+
+	_, err := svc.CancelJob(context.Background(), 6)
+
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
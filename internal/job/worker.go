@@ -0,0 +1,255 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	lease         = 30 * time.Second // how long a claim lasts without a heartbeat
+	heartbeat     = 5 * time.Second  // how often a running job renews its lease
+	retryBase     = 10 * time.Second // first retry delay, doubled every attempt
+	retryMax      = 10 * time.Minute
+	sweepInterval = time.Minute
+	finishTimeout = 10 * time.Second
+)
+
+var errCancelled = errors.New("job cancelled")
+
+// Task runs one kind of job. It writes its result to out and reports
+// progress on it. Returning an error retries the job with backoff unless the
+// error is Permanent or the job is out of attempts.
+type Task func(ctx context.Context, job Job, out *Output) error
+
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error retrying won't fix, like a malformed upload
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// Track reports progress on out as rows are read, for tasks that know how
+// many rows to expect.
+func Track[T any](rows iter.Seq2[T, error], out *Output, total int) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		done := 0
+		for row, err := range rows {
+			if !yield(row, err) {
+				return
+			}
+			done++
+			out.Progress(done, total)
+		}
+	}
+}
+
+// Runner is the worker pool that runs queued jobs inside the server process.
+// Several servers can share one queue, claims skip jobs another worker holds.
+type Runner struct {
+	queue        Queue
+	tasks        map[string]Task
+	workers      int
+	pollInterval time.Duration
+	retention    time.Duration
+
+	// heartbeat is a field so tests can shorten it
+	heartbeat time.Duration
+}
+
+func NewRunner(queue Queue, tasks map[string]Task, workers int, pollInterval, retention time.Duration) *Runner {
+	return &Runner{
+		queue:        queue,
+		tasks:        tasks,
+		workers:      workers,
+		pollInterval: pollInterval,
+		retention:    retention,
+		heartbeat:    heartbeat,
+	}
+}
+
+// Run starts the workers and the sweeper, and returns once ctx is cancelled
+// and every worker has put back the job it was running.
+func (r *Runner) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for range r.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.work(ctx)
+		}()
+	}
+	r.sweep(ctx)
+	wg.Wait()
+}
+
+// work runs jobs back to back, and polls when the queue is empty
+func (r *Runner) work(ctx context.Context) {
+	for {
+		if r.runNext(ctx) {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.pollInterval):
+		}
+	}
+}
+
+func (r *Runner) sweep(ctx context.Context) {
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			swept, err := r.queue.SweepJobs(ctx, r.retention)
+			if err != nil {
+				log.Printf("Job sweeper failed: %v", err)
+			}
+			if swept > 0 {
+				log.Printf("Job sweeper ended or deleted %d jobs", swept)
+			}
+		}
+	}
+}
+
+// runNext claims and runs one job, reporting whether there was one
+func (r *Runner) runNext(ctx context.Context) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	j, err := r.queue.ClaimJob(ctx, lease)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Failed to claim job: %v", err)
+		}
+		return false
+	}
+	if j == nil {
+		return false
+	}
+	r.run(ctx, *j)
+	return true
+}
+
+func (r *Runner) run(ctx context.Context, j Job) {
+	out := newOutput()
+	err := r.execute(ctx, j, out)
+
+	// record the outcome even when the server is stopping
+	finishCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), finishTimeout)
+	defer cancel()
+
+	var finishErr error
+	var permanent permanentError
+	switch {
+	case err == nil:
+		finishErr = r.queue.CompleteJob(finishCtx, j.ID, j.Attempts, out.result())
+	case errors.Is(err, errCancelled):
+		finishErr = r.queue.FinishJob(finishCtx, j.ID, j.Attempts, StatusCancelled, nil)
+	case errors.Is(err, ErrLeaseLost):
+		log.Printf("Job %d lost its lease, another worker has it", j.ID)
+		return
+	case ctx.Err() != nil:
+		finishErr = r.queue.ReleaseJob(finishCtx, j.ID, j.Attempts)
+	case errors.As(err, &permanent) || j.Attempts >= j.MaxAttempts:
+		message := err.Error()
+		finishErr = r.queue.FinishJob(finishCtx, j.ID, j.Attempts, StatusFailed, &message)
+	default:
+		delay := backoff(j.Attempts)
+		log.Printf("Job %d (%s) attempt %d failed, retrying in %s: %v", j.ID, j.Kind, j.Attempts, delay, err)
+		finishErr = r.queue.RetryJob(finishCtx, j.ID, j.Attempts, err.Error(), time.Now().Add(delay))
+	}
+	if finishErr != nil {
+		log.Printf("Failed to record the outcome of job %d: %v", j.ID, finishErr)
+	}
+}
+
+// execute runs the job's task while a heartbeat renews its lease, saves
+// progress and watches for a cancel request.
+func (r *Runner) execute(ctx context.Context, j Job, out *Output) error {
+	task, ok := r.tasks[j.Kind]
+	if !ok {
+		return Permanent(fmt.Errorf("no task for job kind %q", j.Kind))
+	}
+
+	taskCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		r.beat(taskCtx, j, out, cancel)
+	}()
+
+	err := runTask(taskCtx, task, j, out)
+	cancel(nil)
+	wg.Wait()
+
+	// the task's error is usually just "context canceled", report why
+	if cause := context.Cause(taskCtx); err != nil && cause != nil && ctx.Err() == nil && !errors.Is(cause, context.Canceled) {
+		return cause
+	}
+	return err
+}
+
+// beat renews the job's lease until ctx is done, cancelling the task when
+// the job is cancelled or another worker has taken it.
+func (r *Runner) beat(ctx context.Context, j Job, out *Output, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(r.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cancelRequested, err := r.queue.Heartbeat(ctx, j.ID, j.Attempts, int(out.progress.Load()), lease)
+			switch {
+			case errors.Is(err, ErrLeaseLost):
+				cancel(ErrLeaseLost)
+				return
+			case err != nil:
+				if ctx.Err() == nil {
+					log.Printf("Job %d heartbeat failed: %v", j.ID, err)
+				}
+			case cancelRequested:
+				cancel(errCancelled)
+				return
+			}
+		}
+	}
+}
+
+// runTask turns a panic in a task into an error so the worker survives it
+func runTask(ctx context.Context, task Task, j Job, out *Output) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("task panicked: %v", p)
+		}
+	}()
+	return task(ctx, j, out)
+}
+
+// backoff is the delay before retrying after the given attempt
+func backoff(attempt int) time.Duration {
+	d := retryBase
+	for i := 1; i < attempt && d < retryMax; i++ {
+		d *= 2
+	}
+	return min(d, retryMax)
+}
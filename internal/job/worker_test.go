@@ -0,0 +1,221 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockQueue struct {
+	mock.Mock
+}
+
+func (m *mockQueue) ClaimJob(ctx context.Context, lease time.Duration) (*Job, error) {
+	args := m.Called(ctx, lease)
+	j, _ := args.Get(0).(*Job)
+	return j, args.Error(1)
+}
+
+func (m *mockQueue) Heartbeat(ctx context.Context, id, attempt, progress int, lease time.Duration) (bool, error) {
+	args := m.Called(ctx, id, attempt, progress, lease)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockQueue) CompleteJob(ctx context.Context, id, attempt int, result Result) error {
+	return m.Called(ctx, id, attempt, result).Error(0)
+}
+
+func (m *mockQueue) RetryJob(ctx context.Context, id, attempt int, message string, runAt time.Time) error {
+	return m.Called(ctx, id, attempt, message, runAt).Error(0)
+}
+
+func (m *mockQueue) FinishJob(ctx context.Context, id, attempt int, status Status, message *string) error {
+	return m.Called(ctx, id, attempt, status, message).Error(0)
+}
+
+func (m *mockQueue) ReleaseJob(ctx context.Context, id, attempt int) error {
+	return m.Called(ctx, id, attempt).Error(0)
+}
+
+func (m *mockQueue) SweepJobs(ctx context.Context, retention time.Duration) (int, error) {
+	args := m.Called(ctx, retention)
+	return args.Int(0), args.Error(1)
+}
+
+func newTestRunner(queue Queue, tasks map[string]Task) *Runner {
+	r := NewRunner(queue, tasks, 1, time.Millisecond, time.Hour)
+	r.heartbeat = 5 * time.Millisecond
+	return r
+}
+
+func message(s string) any {
+	return mock.MatchedBy(func(m *string) bool { return m != nil && *m == s })
+}
+
+func TestRunner_Complete(t *testing.T) {
+	queue := new(mockQueue)
+	runner := newTestRunner(queue, map[string]Task{
+		"export": func(ctx context.Context, j Job, out *Output) error {
+			out.Header().Set("Content-Type", "text/csv")
+			_, err := io.WriteString(out, "id\n1\n")
+			return err
+		},
+	})
+
+	queue.On("CompleteJob", mock.Anything, 1, 1, Result{ContentType: "text/csv", Body: []byte("id\n1\n")}).Return(nil)
+
+	runner.run(context.Background(), Job{ID: 1, Kind: "export", Attempts: 1, MaxAttempts: 3})
+
+	queue.AssertExpectations(t)
+}
+
+func TestRunner_RetryWithBackoff(t *testing.T) {
+	queue := new(mockQueue)
+	runner := newTestRunner(queue, map[string]Task{
+		"export": func(ctx context.Context, j Job, out *Output) error {
+			return errors.New("connection reset")
+		},
+	})
+
+	start := time.Now()
+	queue.On("RetryJob", mock.Anything, 2, 2, "connection reset", mock.MatchedBy(func(runAt time.Time) bool {
+		return !runAt.Before(start.Add(2*retryBase)) && runAt.Before(time.Now().Add(2*retryBase+time.Second))
+	})).Return(nil)
+
+	runner.run(context.Background(), Job{ID: 2, Kind: "export", Attempts: 2, MaxAttempts: 3})
+
+	queue.AssertExpectations(t)
+}
+
+func TestRunner_FailAfterLastAttempt(t *testing.T) {
+	queue := new(mockQueue)
+	runner := newTestRunner(queue, map[string]Task{
+		"export": func(ctx context.Context, j Job, out *Output) error {
+			return errors.New("connection reset")
+		},
+	})
+
+	queue.On("FinishJob", mock.Anything, 3, 3, StatusFailed, message("connection reset")).Return(nil)
+
+	runner.run(context.Background(), Job{ID: 3, Kind: "export", Attempts: 3, MaxAttempts: 3})
+
+	queue.AssertExpectations(t)
+	queue.AssertNotCalled(t, "RetryJob", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRunner_PermanentError(t *testing.T) {
+	queue := new(mockQueue)
+	runner := newTestRunner(queue, map[string]Task{
+		"import": func(ctx context.Context, j Job, out *Output) error {
+			return Permanent(errors.New("invalid CSV: missing column"))
+		},
+	})
+
+	queue.On("FinishJob", mock.Anything, 4, 1, StatusFailed, message("invalid CSV: missing column")).Return(nil)
+
+	runner.run(context.Background(), Job{ID: 4, Kind: "import", Attempts: 1, MaxAttempts: 3})
+
+	queue.AssertExpectations(t)
+}
+
+func TestRunner_UnknownKind(t *testing.T) {
+	queue := new(mockQueue)
+	runner := newTestRunner(queue, map[string]Task{})
+
+	queue.On("FinishJob", mock.Anything, 5, 1, StatusFailed, message(`no task for job kind "mystery"`)).Return(nil)
+
+	runner.run(context.Background(), Job{ID: 5, Kind: "mystery", Attempts: 1, MaxAttempts: 3})
+
+	queue.AssertExpectations(t)
+}
+
+func TestRunner_PanicIsRetried(t *testing.T) {
+	queue := new(mockQueue)
+	runner := newTestRunner(queue, map[string]Task{
+		"export": func(ctx context.Context, j Job, out *Output) error {
+			panic("nil map")
+		},
+	})
+
+	queue.On("RetryJob", mock.Anything, 6, 1, "task panicked: nil map", mock.Anything).Return(nil)
+
+	runner.run(context.Background(), Job{ID: 6, Kind: "export", Attempts: 1, MaxAttempts: 3})
+
+	queue.AssertExpectations(t)
+}
+
+func TestRunner_Cancel(t *testing.T) {
+	queue := new(mockQueue)
+	runner := newTestRunner(queue, map[string]Task{
+		"export": func(ctx context.Context, j Job, out *Output) error {
+			out.Progress(1, 4)
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+
+	queue.On("Heartbeat", mock.Anything, 7, 1, 25, lease).Return(true, nil)
+	queue.On("FinishJob", mock.Anything, 7, 1, StatusCancelled, (*string)(nil)).Return(nil)
+
+	runner.run(context.Background(), Job{ID: 7, Kind: "export", Attempts: 1, MaxAttempts: 3})
+
+	queue.AssertExpectations(t)
+}
+
+func TestRunner_ReleaseOnShutdown(t *testing.T) {
+	queue := new(mockQueue)
+	ctx, cancel := context.WithCancel(context.Background())
+	runner := newTestRunner(queue, map[string]Task{
+		"export": func(ctx context.Context, j Job, out *Output) error {
+			cancel()
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+
+	queue.On("ReleaseJob", mock.Anything, 8, 1).Return(nil)
+
+	runner.run(ctx, Job{ID: 8, Kind: "export", Attempts: 1, MaxAttempts: 3})
+
+	queue.AssertExpectations(t)
+}
+
+func TestRunner_RunNext_EmptyQueue(t *testing.T) {
+	queue := new(mockQueue)
+	runner := newTestRunner(queue, nil)
+
+	queue.On("ClaimJob", mock.Anything, lease).Return(nil, nil)
+
+	assert.False(t, runner.runNext(context.Background()))
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, retryBase, backoff(1))
+	assert.Equal(t, 2*retryBase, backoff(2))
+	assert.Equal(t, 4*retryBase, backoff(3))
+	assert.Equal(t, retryMax, backoff(20))
+}
+
+func TestTrack(t *testing.T) {
+	out := newOutput()
+	rows := func(yield func(int, error) bool) {
+		for i := range 3 {
+			if !yield(i, nil) {
+				return
+			}
+		}
+	}
+
+	var seen []int
+	for row := range Track(rows, out, 4) {
+		seen = append(seen, row)
+	}
+
+	assert.Equal(t, []int{0, 1, 2}, seen)
+	assert.EqualValues(t, 75, out.progress.Load())
+}
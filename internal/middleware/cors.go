@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/config"
+)
+
+// CORSMiddleware applies the configured CORS policy. routeMethods returns the
+// methods registered for a path so preflight responses only advertise what the
+// router actually serves.
+func CORSMiddleware(cfg config.CORSConfig, routeMethods func(path string) []string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+
+		// Not a browser cross-origin request
+		if origin == "" {
+			if r.Method == http.MethodOptions {
+				w.Header().Set("Allow", strings.Join(append(routeMethods(r.URL.Path), http.MethodOptions), ", "))
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+		allowed, wildcard := matchOrigin(origin, cfg.AllowedOrigins)
+		if !allowed {
+			if r.Method == http.MethodOptions {
+				http.Error(w, "Origin not allowed", http.StatusForbidden)
+				return
+			}
+			// No CORS headers, the browser will block the response
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// "*" never comes with credentials, echoing the origin would let any
+		// site make credentialed requests; only listed origins get them
+		if wildcard {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			if cfg.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+		}
+
+		if !preflight {
+			if len(cfg.ExposedHeaders) > 0 {
+				w.Header().Set("Access-Control-Expose-Headers", strings.Join(cfg.ExposedHeaders, ", "))
+			}
+			if r.Method == http.MethodOptions {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Handle preflight request
+		w.Header().Add("Vary", "Access-Control-Request-Method")
+		w.Header().Add("Vary", "Access-Control-Request-Headers")
+
+		methods := routeMethods(r.URL.Path)
+		if len(methods) == 0 {
+			http.NotFound(w, r)
+			return
+		}
+		if !slices.Contains(methods, r.Header.Get("Access-Control-Request-Method")) {
+			http.Error(w, "Method not allowed", http.StatusForbidden)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Methods", strings.Join(append(methods, http.MethodOptions), ", "))
+		if len(cfg.AllowedHeaders) > 0 {
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(cfg.AllowedHeaders, ", "))
+		}
+		if cfg.MaxAge > 0 {
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(cfg.MaxAge))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// matchOrigin reports whether origin is in the allowlist, and whether it was
+// matched by the "*" entry rather than a specific origin.
+func matchOrigin(origin string, allowed []string) (bool, bool) {
+	origin = strings.ToLower(origin)
+	wildcard := false
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(pattern)
+		switch {
+		case pattern == "*":
+			wildcard = true
+		case pattern == origin:
+			return true, false
+		case strings.Contains(pattern, "://*."):
+			// https://*.example.com matches https://app.example.com
+			prefix, suffix, _ := strings.Cut(pattern, "*")
+			rest, ok := strings.CutPrefix(origin, prefix)
+			if !ok || !strings.HasSuffix(rest, suffix) {
+				continue
+			}
+			sub := strings.TrimSuffix(rest, suffix)
+			if sub != "" && !strings.ContainsAny(sub, "/:@") {
+				return true, false
+			}
+		}
+	}
+	return wildcard, wildcard
+}
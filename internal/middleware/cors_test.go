@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchOrigin(t *testing.T) {
+	allowed := []string{"http://localhost:8000", "https://*.example.com"}
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"http://localhost:8000", true},
+		{"http://localhost:3000", false},
+		{"https://app.example.com", true},
+		{"https://a.b.example.com", true},
+		{"https://example.com", false},
+		{"http://app.example.com", false},
+		{"https://evil.com/.example.com", false},
+		{"https://app.example.com.evil.com", false},
+	}
+
+	for _, tt := range tests {
+		got, _ := matchOrigin(tt.origin, allowed)
+		assert.Equal(t, tt.want, got, tt.origin)
+	}
+}
+
+func TestMatchOrigin_Wildcard(t *testing.T) {
+	allowed, wildcard := matchOrigin("https://anything.dev", []string{"*"})
+
+	assert.True(t, allowed)
+	assert.True(t, wildcard)
+}
+
+func TestCORSMiddleware_WildcardHasNoCredentials(t *testing.T) {
+	cfg := config.CORSConfig{AllowedOrigins: []string{"https://app.example.com", "*"}, AllowCredentials: true}
+	handler := CORSMiddleware(cfg, func(string) []string { return []string{http.MethodGet} }, func(w http.ResponseWriter, r *http.Request) {})
+
+	for origin, want := range map[string]struct{ allowOrigin, credentials string }{
+		"https://app.example.com": {"https://app.example.com", "true"},
+		"https://evil.example":    {"*", ""},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/customers", nil)
+		r.Header.Set("Origin", origin)
+		w := httptest.NewRecorder()
+
+		handler(w, r)
+
+		assert.Equal(t, want.allowOrigin, w.Header().Get("Access-Control-Allow-Origin"), origin)
+		assert.Equal(t, want.credentials, w.Header().Get("Access-Control-Allow-Credentials"), origin)
+	}
+}
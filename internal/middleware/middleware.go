@@ -1,26 +1,13 @@
 package middleware
 
 import (
+	"context"
 	"log"
 	"net/http"
-)
-
-func CORSMiddleware(next http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		// Set CORS headers
-		w.Header().Set("Access-Control-Allow-Origin", "*") // You can restrict this to specific origins
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key")
-
-		// Handle preflight request
-		if r.Method == http.MethodOptions {
-			w.WriteHeader(http.StatusNoContent)
-			return
-		}
+	"strings"
 
-		next.ServeHTTP(w, r)
-	}
-}
+	"github.com/rstoltzm-profile/video-rental-api/internal/apikey"
+)
 
 func ErrorMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -38,7 +25,12 @@ func ErrorMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
-func ApiKeyMiddleware(validAPIKey string, next http.HandlerFunc) http.HandlerFunc {
+// KeyChecker reports whether an API key may call the API and its ID
+type KeyChecker interface {
+	Check(ctx context.Context, key string) (int, bool, error)
+}
+
+func ApiKeyMiddleware(keys KeyChecker, next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Check for API key in header
 		apiKey := r.Header.Get("X-API-Key")
@@ -47,21 +39,32 @@ func ApiKeyMiddleware(validAPIKey string, next http.HandlerFunc) http.HandlerFun
 			return
 		}
 
-		if apiKey != validAPIKey {
+		id, valid, err := keys.Check(r.Context(), apiKey)
+		if err != nil {
+			log.Printf("API key check failed: %v", err)
+			http.Error(w, "Failed to check API key", http.StatusInternalServerError)
+			return
+		}
+		if !valid {
 			http.Error(w, "Invalid API key", http.StatusUnauthorized)
 			return
 		}
 
-		// API key is valid, continue
-		next.ServeHTTP(w, r)
+		// API key is valid, continue with its ID for idempotency keys
+		next.ServeHTTP(w, r.WithContext(apikey.WithID(r.Context(), id)))
 	}
 }
 
 func RequestSizeMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	const maxRequestSize = 1 << 20 // 1MB limit
+	const maxImportSize = 32 << 20 // CSV imports, 32MB limit
 
 	return func(w http.ResponseWriter, r *http.Request) {
-		r.Body = http.MaxBytesReader(w, r.Body, maxRequestSize)
+		limit := int64(maxRequestSize)
+		if strings.HasPrefix(r.URL.Path, "/imports/") {
+			limit = maxImportSize
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
 
 		next.ServeHTTP(w, r)
 	}
@@ -0,0 +1,121 @@
+package openapi
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	paramLine    = regexp.MustCompile(`^(\S+)\s+(path|query|header|body)\s+(\S+)\s+(true|false)\s+"(.*)"$`)
+	responseLine = regexp.MustCompile(`^(\d{3})\s+\{(\w+)\}\s+(\S+)(?:\s+"(.*)")?$`)
+	routerLine   = regexp.MustCompile(`^(\S+)\s+\[(\w+)\]$`)
+)
+
+// annotation is a handler's swag comment block
+type annotation struct {
+	pos         string // file:line, for errors
+	handler     string
+	summary     string
+	description string
+	tags        []string
+	accept      []string
+	produce     []string
+	params      []param
+	responses   []response
+	security    []string
+	path        string
+	method      string
+}
+
+type param struct {
+	name, in, typ, description string
+	required                   bool
+}
+
+type response struct {
+	status      int
+	kind        string // array, object, string, integer, file...
+	typ         string
+	description string
+}
+
+// parseFile returns the annotation of every function in the file that names
+// its route
+func parseFile(filename string) ([]annotation, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var annotations []annotation
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Doc == nil {
+			continue
+		}
+		a, err := parseComment(fn.Doc)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", fset.Position(fn.Pos()), fn.Name.Name, err)
+		}
+		if a.path == "" {
+			continue
+		}
+		a.pos = fset.Position(fn.Pos()).String()
+		a.handler = fn.Name.Name
+		annotations = append(annotations, a)
+	}
+	return annotations, nil
+}
+
+func parseComment(doc *ast.CommentGroup) (annotation, error) {
+	var a annotation
+	for _, c := range doc.List {
+		line := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+		if !strings.HasPrefix(line, "@") {
+			continue
+		}
+		key, rest, _ := strings.Cut(line, " ")
+		rest = strings.TrimSpace(rest)
+
+		switch key {
+		case "@Summary":
+			a.summary = rest
+		case "@Description":
+			a.description = rest
+		case "@Tags":
+			a.tags = strings.Split(rest, ",")
+		case "@Accept":
+			a.accept = strings.Split(rest, ",")
+		case "@Produce":
+			a.produce = strings.Split(rest, ",")
+		case "@Security":
+			a.security = append(a.security, rest)
+		case "@Param":
+			m := paramLine.FindStringSubmatch(rest)
+			if m == nil {
+				return a, fmt.Errorf("invalid @Param %q", rest)
+			}
+			a.params = append(a.params, param{name: m[1], in: m[2], typ: m[3], required: m[4] == "true", description: m[5]})
+		case "@Success", "@Failure":
+			m := responseLine.FindStringSubmatch(rest)
+			if m == nil {
+				return a, fmt.Errorf("invalid %s %q", key, rest)
+			}
+			status, _ := strconv.Atoi(m[1])
+			a.responses = append(a.responses, response{status: status, kind: m[2], typ: m[3], description: m[4]})
+		case "@Router":
+			m := routerLine.FindStringSubmatch(rest)
+			if m == nil {
+				return a, fmt.Errorf("invalid @Router %q", rest)
+			}
+			a.path, a.method = m[1], strings.ToLower(m[2])
+		}
+	}
+	return a, nil
+}
@@ -0,0 +1,236 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var pathParam = regexp.MustCompile(`\{(\w+)\}`)
+
+// Generate builds the document from the swag annotations of the handlers in
+// files. models are the types the annotations name, a model's name is
+// "<package>.<Type>", like customer.Customer.
+func Generate(info Info, files []string, models ...any) (*Document, error) {
+	g := generator{
+		models:  map[string]reflect.Type{},
+		schemas: schemas{components: map[string]*Schema{}},
+	}
+	for _, m := range models {
+		t := reflect.TypeOf(m)
+		g.models[name(t)] = t
+	}
+
+	doc := &Document{
+		OpenAPI: "3.1.0",
+		Info:    info,
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			Schemas: g.schemas.components,
+			SecuritySchemes: map[string]SecurityScheme{
+				"ApiKeyAuth": {Type: "apiKey", In: "header", Name: "X-API-Key"},
+			},
+		},
+	}
+	operationIDs := map[string]string{}
+	for _, file := range files {
+		annotations, err := parseFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range annotations {
+			op, err := g.operation(a)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", a.pos, a.handler, err)
+			}
+			if other, ok := operationIDs[op.OperationID]; ok {
+				return nil, fmt.Errorf("%s: %s: handler name already used at %s", a.pos, a.handler, other)
+			}
+			operationIDs[op.OperationID] = a.pos
+
+			item := doc.Paths[a.path]
+			if item == nil {
+				item = PathItem{}
+				doc.Paths[a.path] = item
+			}
+			if _, ok := item[a.method]; ok {
+				return nil, fmt.Errorf("%s: %s: %s %s is documented twice", a.pos, a.handler, a.method, a.path)
+			}
+			item[a.method] = op
+		}
+	}
+	return doc, nil
+}
+
+type generator struct {
+	models  map[string]reflect.Type
+	schemas schemas
+}
+
+func (g *generator) operation(a annotation) (*Operation, error) {
+	op := &Operation{
+		OperationID: a.handler,
+		Summary:     a.summary,
+		Description: a.description,
+		Tags:        a.tags,
+		Responses:   map[string]Response{},
+		Security:    []map[string][]string{},
+	}
+	for _, scheme := range a.security {
+		op.Security = append(op.Security, map[string][]string{scheme: {}})
+	}
+
+	documented := map[string]bool{}
+	for _, p := range a.params {
+		if p.in == "body" {
+			schema, err := g.schema("object", p.typ)
+			if err != nil {
+				return nil, err
+			}
+			op.RequestBody = &RequestBody{Description: p.description, Required: p.required, Content: content(a.accept, schema)}
+			continue
+		}
+		schema, ok := primitive(p.typ)
+		if !ok {
+			return nil, fmt.Errorf("unknown %s parameter type %s", p.in, p.typ)
+		}
+		if p.in == "path" {
+			documented[p.name] = true
+		}
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:        p.name,
+			In:          p.in,
+			Description: p.description,
+			Required:    p.required || p.in == "path",
+			Schema:      schema,
+		})
+	}
+	for _, m := range pathParam.FindAllStringSubmatch(a.path, -1) {
+		if !documented[m[1]] {
+			return nil, fmt.Errorf("path parameter %s has no @Param", m[1])
+		}
+	}
+	// a raw upload, like a CSV file or a picture, has @Accept but no body @Param
+	if op.RequestBody == nil && len(a.accept) > 0 && !slicesContain(a.accept, "json") {
+		op.RequestBody = &RequestBody{Required: true, Content: content(a.accept, &Schema{Type: "string"})}
+	}
+
+	for _, r := range a.responses {
+		code := strconv.Itoa(r.status)
+		resp, ok := op.Responses[code]
+		if !ok {
+			resp = Response{Description: r.description}
+			if resp.Description == "" {
+				resp.Description = http.StatusText(r.status)
+			}
+		}
+		if r.status != http.StatusNoContent && r.status != http.StatusNotModified {
+			schema, err := g.schema(r.kind, r.typ)
+			if err != nil {
+				return nil, err
+			}
+			produce := a.produce
+			if r.status >= 400 && r.kind == "string" {
+				produce = []string{"text/plain"} // http.Error
+			}
+			if resp.Content == nil {
+				resp.Content = content(produce, schema)
+			}
+		}
+		op.Responses[code] = resp
+	}
+	if len(op.Responses) == 0 {
+		return nil, fmt.Errorf("no @Success or @Failure")
+	}
+	return op, nil
+}
+
+// schema is the schema of an annotation's {kind} type pair
+func (g *generator) schema(kind, typ string) (*Schema, error) {
+	var schema *Schema
+	switch {
+	case typ == "object":
+		schema = &Schema{Type: "object"}
+	case typ == "file":
+		schema = &Schema{Type: "string", Format: "binary"}
+	case strings.HasPrefix(typ, "map[string]"):
+		value, ok := primitive(strings.TrimPrefix(typ, "map[string]"))
+		if !ok {
+			return nil, fmt.Errorf("unknown map value type in %s", typ)
+		}
+		schema = &Schema{Type: "object", AdditionalProperties: value}
+	default:
+		if p, ok := primitive(typ); ok {
+			schema = p
+			break
+		}
+		t, ok := g.models[typ]
+		if !ok {
+			return nil, fmt.Errorf("unknown type %s, add it to the models", typ)
+		}
+		schema = g.schemas.of(t)
+	}
+	if kind == "array" {
+		return &Schema{Type: "array", Items: schema}, nil
+	}
+	return schema, nil
+}
+
+func primitive(typ string) (*Schema, bool) {
+	switch typ {
+	case "string":
+		return &Schema{Type: "string"}, true
+	case "int", "integer":
+		return &Schema{Type: "integer"}, true
+	case "bool", "boolean":
+		return &Schema{Type: "boolean"}, true
+	case "number", "float":
+		return &Schema{Type: "number"}, true
+	}
+	return nil, false
+}
+
+// content describes a body in each media type of an @Accept or @Produce
+// list, JSON is the default. Other formats are text, images are binary.
+func content(types []string, schema *Schema) map[string]MediaType {
+	if len(types) == 0 {
+		types = []string{"json"}
+	}
+	c := map[string]MediaType{}
+	for _, t := range types {
+		mt := mediaType(t)
+		switch {
+		case mt == "application/json":
+			c[mt] = MediaType{Schema: schema}
+		case strings.HasPrefix(mt, "image/"):
+			c[mt] = MediaType{Schema: &Schema{Type: "string", Format: "binary"}}
+		default:
+			c[mt] = MediaType{Schema: &Schema{Type: "string"}}
+		}
+	}
+	return c
+}
+
+// mediaType expands swag's short names
+func mediaType(t string) string {
+	t = strings.TrimSpace(t)
+	switch t {
+	case "json":
+		return "application/json"
+	case "png", "jpeg", "gif", "webp":
+		return "image/" + t
+	}
+	return t
+}
+
+func slicesContain(list []string, v string) bool {
+	for _, s := range list {
+		if strings.TrimSpace(s) == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Widget struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name" validate:"required,min=1,max=20"`
+	Colour    string     `json:"colour" validate:"omitempty,oneof=red blue"`
+	Tags      []string   `json:"tags" validate:"omitempty,max=3,dive,min=2"`
+	Parent    *Widget    `json:"parent"`
+	Note      *string    `json:"note"`
+	CreatedAt time.Time  `json:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at"`
+	secret    string
+}
+
+const handlers = `package widget
+
+// GetWidget godoc
+// @Summary      Get widget
+// @Tags         widgets
+// @Produce      json
+// @Param        id   path      int  true  "Widget ID"
+// @Success      200  {object}  openapi.Widget
+// @Failure      404  {string}  string "Widget not found"
+// @Security     ApiKeyAuth
+// @Router       /v1/widgets/{id} [get]
+func GetWidget() {}
+
+// CreateWidget godoc
+// @Summary      Create widget
+// @Accept       json
+// @Produce      json
+// @Param        widget  body  openapi.Widget  true  "Widget"
+// @Success      201  {object}  openapi.Widget
+// @Router       /v1/widgets [post]
+func CreateWidget() {}
+
+// helper has no route
+func helper() {}
+`
+
+func writeFile(t *testing.T, src string) string {
+	t.Helper()
+	name := filepath.Join(t.TempDir(), "handler.go")
+	require.NoError(t, os.WriteFile(name, []byte(src), 0o644))
+	return name
+}
+
+func TestGenerate(t *testing.T) {
+	doc, err := Generate(Info{Title: "Widgets", Version: "1"}, []string{writeFile(t, handlers)}, Widget{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "3.1.0", doc.OpenAPI)
+	get := doc.Paths["/v1/widgets/{id}"]["get"]
+	require.NotNil(t, get)
+	assert.Equal(t, "GetWidget", get.OperationID)
+	assert.Equal(t, []map[string][]string{{"ApiKeyAuth": {}}}, get.Security)
+	assert.Equal(t, "#/components/schemas/openapi.Widget", get.Responses["200"].Content["application/json"].Schema.Ref)
+	assert.Contains(t, get.Responses["404"].Content, "text/plain")
+	assert.True(t, get.Parameters[0].Required)
+
+	post := doc.Paths["/v1/widgets"]["post"]
+	require.NotNil(t, post)
+	assert.Empty(t, post.Security)
+	assert.True(t, post.RequestBody.Required)
+}
+
+func TestGenerate_Schema(t *testing.T) {
+	doc, err := Generate(Info{}, []string{writeFile(t, handlers)}, Widget{})
+	require.NoError(t, err)
+
+	widget := doc.Components.Schemas["openapi.Widget"]
+	require.NotNil(t, widget)
+	assert.Equal(t, []string{"name"}, widget.Required)
+	assert.Equal(t, false, widget.AdditionalProperties)
+	assert.NotContains(t, widget.Properties, "secret")
+
+	name := widget.Properties["name"]
+	assert.Equal(t, 1, *name.MinLength)
+	assert.Equal(t, 20, *name.MaxLength)
+	colour := widget.Properties["colour"]
+	assert.Equal(t, []any{"red", "blue"}, colour.AnyOf[0].Enum)
+	assert.Equal(t, []any{""}, colour.AnyOf[1].Enum, "omitempty allows the empty string")
+
+	tags := widget.Properties["tags"]
+	assert.Equal(t, 3, *tags.MaxItems)
+	assert.Equal(t, 2, *tags.Items.MinLength)
+
+	assert.Equal(t, []string{"string", "null"}, widget.Properties["note"].Type)
+	assert.Equal(t, "date-time", widget.Properties["created_at"].Format)
+	assert.Equal(t, "#/components/schemas/openapi.Widget", widget.Properties["parent"].AnyOf[0].Ref)
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "unknown type",
+			src: `package widget
+// @Success 200 {object} widget.Gadget
+// @Router /gadgets [get]
+func GetGadget() {}
+`,
+			want: "unknown type widget.Gadget",
+		},
+		{
+			name: "undocumented path parameter",
+			src: `package widget
+// @Success 200 {string} string
+// @Router /widgets/{id} [get]
+func GetWidget() {}
+`,
+			want: "path parameter id has no @Param",
+		},
+		{
+			name: "malformed param",
+			src: `package widget
+// @Param id path int
+// @Router /widgets/{id} [get]
+func GetWidget() {}
+`,
+			want: "invalid @Param",
+		},
+		{
+			name: "route documented twice",
+			src: `package widget
+// @Success 200 {string} string
+// @Router /widgets [get]
+func GetWidgets() {}
+
+// @Success 200 {string} string
+// @Router /widgets [get]
+func ListWidgets() {}
+`,
+			want: "get /widgets is documented twice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Generate(Info{}, []string{writeFile(t, tt.src)}, Widget{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType = reflect.TypeFor[time.Time]()
+	rawType  = reflect.TypeFor[json.RawMessage]()
+)
+
+// e164 is the pattern behind validate:"e164"
+const e164 = `^\+[1-9][0-9]{1,14}$`
+
+// schemas turns Go types into schemas, named structs become components
+// referenced by "<package>.<Type>", the name the annotations use.
+type schemas struct {
+	components map[string]*Schema
+}
+
+func name(t reflect.Type) string {
+	return path.Base(t.PkgPath()) + "." + t.Name()
+}
+
+func (s *schemas) of(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(s.of(t.Elem()))
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: s.of(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.of(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		ref := name(t)
+		if _, ok := s.components[ref]; !ok {
+			s.components[ref] = &Schema{} // placeholder, a type may refer to itself
+			s.components[ref] = s.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + ref}
+	default:
+		return &Schema{}
+	}
+}
+
+// object is a struct's schema. Embedded structs without a json name are
+// flattened into it, like encoding/json does.
+func (s *schemas) object(t reflect.Type) *Schema {
+	obj := &Schema{Type: "object", Properties: map[string]*Schema{}, AdditionalProperties: false}
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		jsonName, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && jsonName == "" && field.Type.Kind() == reflect.Struct {
+			embedded := s.object(field.Type)
+			for k, v := range embedded.Properties {
+				obj.Properties[k] = v
+			}
+			obj.Required = append(obj.Required, embedded.Required...)
+			continue
+		}
+		if jsonName == "" {
+			jsonName = field.Name
+		}
+
+		prop := s.of(field.Type)
+		if validate := field.Tag.Get("validate"); validate != "" {
+			rules := strings.Split(validate, ",")
+			if constrain(prop, field.Type, rules) {
+				obj.Required = append(obj.Required, jsonName)
+			}
+			if zero, ok := zeroValue(field.Type); ok && slices.Contains(rules, "omitempty") {
+				// the rules skip the zero value, like an empty string
+				prop = &Schema{AnyOf: []*Schema{prop, {Enum: []any{zero}}}}
+			}
+		}
+		obj.Properties[jsonName] = prop
+	}
+	return obj
+}
+
+func nullable(s *Schema) *Schema {
+	switch t := s.Type.(type) {
+	case string:
+		s.Type = []string{t, "null"}
+		return s
+	case nil:
+		if s.Ref != "" {
+			return &Schema{AnyOf: []*Schema{s, {Type: "null"}}}
+		}
+	}
+	return s
+}
+
+// constrain adds the validate rules to a field's schema and reports whether
+// the field is required. Rules after dive apply to the items.
+func constrain(s *Schema, t reflect.Type, rules []string) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	required := false
+	for i, rule := range rules {
+		key, value, _ := strings.Cut(rule, "=")
+		n, _ := strconv.ParseFloat(value, 64)
+		switch key {
+		case "required":
+			required = true
+		case "dive":
+			if s.Items != nil {
+				constrain(s.Items, t.Elem(), rules[i+1:])
+			}
+			return required
+		case "min", "gte":
+			setMin(s, t, n)
+		case "max", "lte":
+			setMax(s, t, n)
+		case "gt":
+			if isNumber(t) {
+				s.ExclusiveMinimum = &n
+			} else {
+				setMin(s, t, n+1)
+			}
+		case "lt":
+			if isNumber(t) {
+				s.ExclusiveMaximum = &n
+			} else {
+				setMax(s, t, n-1)
+			}
+		case "len":
+			setMin(s, t, n)
+			setMax(s, t, n)
+		case "oneof":
+			for _, v := range strings.Fields(value) {
+				if isNumber(t) {
+					n, _ := strconv.ParseFloat(v, 64)
+					s.Enum = append(s.Enum, n)
+				} else {
+					s.Enum = append(s.Enum, v)
+				}
+			}
+			if types, ok := s.Type.([]string); ok && slices.Contains(types, "null") {
+				s.Enum = append(s.Enum, nil) // a pointer may still be null
+			}
+		case "unique":
+			s.UniqueItems = true
+		case "email":
+			s.Format = "email"
+		case "url", "http_url":
+			s.Format = "uri"
+		case "e164":
+			s.Pattern = e164
+		}
+	}
+	return required
+}
+
+// zeroValue is the JSON of a string or number type's zero value, pointers
+// and lists are left alone as their zero value is null.
+func zeroValue(t reflect.Type) (any, bool) {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map, reflect.Struct, reflect.Bool:
+		return nil, false
+	case reflect.String:
+		return "", true
+	}
+	return 0, true
+}
+
+func isNumber(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return false
+	}
+	return true
+}
+
+func setMin(s *Schema, t reflect.Type, n float64) {
+	size := int(n)
+	switch t.Kind() {
+	case reflect.String:
+		s.MinLength = &size
+	case reflect.Slice, reflect.Array:
+		s.MinItems = &size
+	default:
+		s.Minimum = &n
+	}
+}
+
+func setMax(s *Schema, t reflect.Type, n float64) {
+	size := int(n)
+	switch t.Kind() {
+	case reflect.String:
+		s.MaxLength = &size
+	case reflect.Slice, reflect.Array:
+		s.MaxItems = &size
+	default:
+		s.Maximum = &n
+	}
+}
@@ -0,0 +1,28 @@
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// spec is written by `make docs-openapi`
+//
+//go:embed openapi.json
+var spec []byte
+
+// Spec is the committed OpenAPI document
+func Spec() []byte {
+	return spec
+}
+
+// ServeSpec godoc
+// @Summary      OpenAPI document
+// @Description  The OpenAPI 3.1 description of every route of the API
+// @Tags         docs
+// @Produce      json
+// @Success      200  {object}  object
+// @Router       /openapi.json [get]
+func ServeSpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(spec)
+}
@@ -0,0 +1,479 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// FieldError is one problem with a request. Field is the parameter's name,
+// or the path into the body like address.city or tags[1], empty for the
+// body itself.
+type FieldError struct {
+	In      string `json:"in"` // path, query or body
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError is the body of the 400 the validator answers with
+type ValidationError struct {
+	Error  string       `json:"error"`
+	Fields []FieldError `json:"fields"`
+}
+
+// Validator checks requests against the path and query parameters and the
+// JSON request bodies of a document. Headers are left to the handlers, they
+// answer a missing X-Staff-Token or If-Match with 401 and 428.
+type Validator struct {
+	doc    *Document
+	prefix string
+	// patterns are the schemas' patterns, compiled once
+	patterns map[string]*regexp.Regexp
+}
+
+// NewValidator reads an OpenAPI document written by Generate. prefix is cut
+// from the document's paths, "/v1" for the routes mounted under it. A pattern
+// that doesn't compile is an error.
+func NewValidator(spec []byte, prefix string) (*Validator, error) {
+	var doc Document
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("read OpenAPI document: %w", err)
+	}
+	patterns := map[string]*regexp.Regexp{}
+	for _, s := range doc.Components.Schemas {
+		if err := normalize(s, patterns); err != nil {
+			return nil, err
+		}
+	}
+	for _, item := range doc.Paths {
+		for _, op := range item {
+			for _, p := range op.Parameters {
+				if err := normalize(p.Schema, patterns); err != nil {
+					return nil, err
+				}
+			}
+			if op.RequestBody != nil {
+				for _, mt := range op.RequestBody.Content {
+					if err := normalize(mt.Schema, patterns); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+	return &Validator{doc: &doc, prefix: prefix, patterns: patterns}, nil
+}
+
+// Middleware checks each request to a documented operation before next runs.
+// Requests to other paths, or with a method the path doesn't have, are passed
+// on for next to answer.
+func (v *Validator) Middleware(next http.HandlerFunc) http.HandlerFunc {
+	mux := http.NewServeMux()
+	mux.Handle("/", next)
+	for path, item := range v.doc.Paths {
+		if !strings.HasPrefix(path, v.prefix+"/") {
+			continue
+		}
+		for method, op := range item {
+			pattern := strings.ToUpper(method) + " " + strings.TrimPrefix(path, v.prefix)
+			mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+				fields, err := v.check(op, r)
+				var tooLarge *http.MaxBytesError
+				switch {
+				case errors.As(err, &tooLarge):
+					http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+					return
+				case err != nil:
+					http.Error(w, "Failed to read request body", http.StatusBadRequest)
+					return
+				case len(fields) > 0:
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusBadRequest)
+					json.NewEncoder(w).Encode(ValidationError{Error: "Validation failed", Fields: fields})
+					return
+				}
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	return mux.ServeHTTP
+}
+
+// check returns what is wrong with the request, the error is from reading
+// the body. The body is put back for the handler.
+func (v *Validator) check(op *Operation, r *http.Request) ([]FieldError, error) {
+	var fields []FieldError
+	query := r.URL.Query()
+	for _, p := range op.Parameters {
+		var raw string
+		var present bool
+		switch p.In {
+		case "path":
+			raw = r.PathValue(p.Name)
+			present = raw != ""
+		case "query":
+			present = query.Has(p.Name) && query.Get(p.Name) != ""
+			raw = query.Get(p.Name)
+		default:
+			continue
+		}
+		if !present {
+			if p.Required {
+				fields = append(fields, FieldError{In: p.In, Field: p.Name, Message: "is required"})
+			}
+			continue
+		}
+		value, ok := parseParam(p.Schema, raw)
+		if !ok {
+			fields = append(fields, FieldError{In: p.In, Field: p.Name, Message: "must be " + article(typeNames(p.Schema)[0])})
+			continue
+		}
+		fields = append(fields, v.value(p.Schema, value, p.In, p.Name)...)
+	}
+
+	if op.RequestBody == nil {
+		return fields, nil
+	}
+	// a body that may also be CSV or an image is only read when sent as JSON
+	mt, ok := op.RequestBody.Content["application/json"]
+	if !ok || len(op.RequestBody.Content) > 1 && !isJSON(r.Header.Get("Content-Type")) {
+		return fields, nil
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if op.RequestBody.Required {
+			fields = append(fields, FieldError{In: "body", Message: "is required"})
+		}
+		return fields, nil
+	}
+	var value any
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&value); err != nil {
+		return append(fields, FieldError{In: "body", Message: "invalid JSON"}), nil
+	}
+	return append(fields, v.value(mt.Schema, value, "body", "")...), nil
+}
+
+// value checks a decoded JSON value against a schema
+func (v *Validator) value(s *Schema, value any, in, field string) []FieldError {
+	fail := func(format string, args ...any) []FieldError {
+		return []FieldError{{In: in, Field: field, Message: fmt.Sprintf(format, args...)}}
+	}
+
+	if s.Ref != "" {
+		target, ok := v.doc.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+		if !ok {
+			return nil
+		}
+		s = target
+	}
+	if len(s.AnyOf) > 0 {
+		var first []FieldError
+		for i, option := range s.AnyOf {
+			errs := v.value(option, value, in, field)
+			if len(errs) == 0 {
+				return nil
+			}
+			if i == 0 {
+				first = errs
+			}
+		}
+		return first
+	}
+
+	types := typeNames(s)
+	if len(types) > 0 && !hasType(types, value) {
+		return fail("must be %s", article(types[0]))
+	}
+	if len(s.Enum) > 0 && !inEnum(s.Enum, value) {
+		var options []string
+		for _, e := range s.Enum {
+			if e != nil {
+				options = append(options, fmt.Sprint(e))
+			}
+		}
+		return fail("must be one of %s", strings.Join(options, ", "))
+	}
+
+	switch val := value.(type) {
+	case string:
+		n := utf8.RuneCountInString(val)
+		if s.MinLength != nil && n < *s.MinLength {
+			return fail("must be at least %d characters", *s.MinLength)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			return fail("must be at most %d characters", *s.MaxLength)
+		}
+		if s.Pattern != "" && !v.patterns[s.Pattern].MatchString(val) {
+			return fail("must match %s", s.Pattern)
+		}
+		if !validFormat(s.Format, val) {
+			return fail("must be a valid %s", s.Format)
+		}
+	case json.Number:
+		n, _ := val.Float64()
+		if s.Minimum != nil && n < *s.Minimum {
+			return fail("must be at least %v", *s.Minimum)
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			return fail("must be at most %v", *s.Maximum)
+		}
+		if s.ExclusiveMinimum != nil && n <= *s.ExclusiveMinimum {
+			return fail("must be greater than %v", *s.ExclusiveMinimum)
+		}
+		if s.ExclusiveMaximum != nil && n >= *s.ExclusiveMaximum {
+			return fail("must be less than %v", *s.ExclusiveMaximum)
+		}
+	case []any:
+		if s.MinItems != nil && len(val) < *s.MinItems {
+			return fail("must have at least %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(val) > *s.MaxItems {
+			return fail("must have at most %d items", *s.MaxItems)
+		}
+		var errs []FieldError
+		seen := map[string]bool{}
+		for i, item := range val {
+			if s.Items != nil {
+				errs = append(errs, v.value(s.Items, item, in, fmt.Sprintf("%s[%d]", field, i))...)
+			}
+			if s.UniqueItems {
+				key := fmt.Sprint(item)
+				if seen[key] {
+					errs = append(errs, FieldError{In: in, Field: fmt.Sprintf("%s[%d]", field, i), Message: "is a duplicate"})
+				}
+				seen[key] = true
+			}
+		}
+		return errs
+	case map[string]any:
+		var errs []FieldError
+		for _, name := range s.Required {
+			if _, ok := val[name]; !ok {
+				errs = append(errs, FieldError{In: in, Field: join(field, name), Message: "is required"})
+			}
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if prop, ok := s.Properties[k]; ok {
+				errs = append(errs, v.value(prop, val[k], in, join(field, k))...)
+				continue
+			}
+			switch extra := s.AdditionalProperties.(type) {
+			case bool:
+				if !extra {
+					errs = append(errs, FieldError{In: in, Field: join(field, k), Message: "is not a known field"})
+				}
+			case *Schema:
+				errs = append(errs, v.value(extra, val[k], in, join(field, k))...)
+			}
+		}
+		return errs
+	}
+	return nil
+}
+
+// normalize gives a schema read back from JSON the Go types Generate uses:
+// Type a string or []string and AdditionalProperties a bool or *Schema. It
+// compiles the patterns it finds into patterns.
+func normalize(s *Schema, patterns map[string]*regexp.Regexp) error {
+	if s == nil {
+		return nil
+	}
+	if _, ok := patterns[s.Pattern]; s.Pattern != "" && !ok {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("compile pattern %q: %w", s.Pattern, err)
+		}
+		patterns[s.Pattern] = re
+	}
+	if list, ok := s.Type.([]any); ok {
+		types := make([]string, len(list))
+		for i, t := range list {
+			types[i], _ = t.(string)
+		}
+		s.Type = types
+	}
+	if m, ok := s.AdditionalProperties.(map[string]any); ok {
+		raw, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+		var extra Schema
+		if err := json.Unmarshal(raw, &extra); err != nil {
+			return err
+		}
+		s.AdditionalProperties = &extra
+	}
+	if extra, ok := s.AdditionalProperties.(*Schema); ok {
+		if err := normalize(extra, patterns); err != nil {
+			return err
+		}
+	}
+	for _, p := range s.Properties {
+		if err := normalize(p, patterns); err != nil {
+			return err
+		}
+	}
+	for _, a := range s.AnyOf {
+		if err := normalize(a, patterns); err != nil {
+			return err
+		}
+	}
+	return normalize(s.Items, patterns)
+}
+
+func typeNames(s *Schema) []string {
+	switch t := s.Type.(type) {
+	case string:
+		return []string{t}
+	case []string:
+		return t
+	}
+	return nil
+}
+
+func hasType(types []string, value any) bool {
+	for _, t := range types {
+		switch val := value.(type) {
+		case nil:
+			if t == "null" {
+				return true
+			}
+		case bool:
+			if t == "boolean" {
+				return true
+			}
+		case string:
+			if t == "string" {
+				return true
+			}
+		case json.Number:
+			if t == "number" {
+				return true
+			}
+			if _, err := val.Int64(); err == nil && t == "integer" {
+				return true
+			}
+		case []any:
+			if t == "array" {
+				return true
+			}
+		case map[string]any:
+			if t == "object" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func inEnum(enum []any, value any) bool {
+	for _, e := range enum {
+		switch e := e.(type) {
+		case float64:
+			if n, ok := value.(json.Number); ok {
+				if f, _ := n.Float64(); f == e {
+					return true
+				}
+			}
+		default:
+			if e == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func validFormat(format, value string) bool {
+	switch format {
+	case "email":
+		addr, err := mail.ParseAddress(value)
+		return err == nil && addr.Address == value
+	case "uri":
+		u, err := url.ParseRequestURI(value)
+		return err == nil && u.Scheme != "" && u.Host != ""
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	}
+	return true
+}
+
+// parseParam turns a path or query string into the JSON value its schema
+// describes
+func parseParam(s *Schema, raw string) (any, bool) {
+	types := typeNames(s)
+	if len(types) == 0 {
+		return raw, true
+	}
+	switch types[0] {
+	case "integer":
+		if _, err := strconv.ParseInt(raw, 10, 64); err != nil {
+			return nil, false
+		}
+		return json.Number(raw), true
+	case "number":
+		if _, err := strconv.ParseFloat(raw, 64); err != nil {
+			return nil, false
+		}
+		return json.Number(raw), true
+	case "boolean":
+		// only what the handlers compare with, ?dry_run=1 would pass as true
+		// here and then be read as false
+		switch raw {
+		case "true":
+			return true, true
+		case "false":
+			return false, true
+		}
+		return nil, false
+	}
+	return raw, true
+}
+
+func isJSON(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mt, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mt == "application/json"
+}
+
+func join(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
+
+func article(t string) string {
+	switch t {
+	case "integer", "object", "array":
+		return "an " + t
+	}
+	return "a " + t
+}
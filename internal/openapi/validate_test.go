@@ -0,0 +1,222 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Order struct {
+	Widgets  []int   `json:"widgets" validate:"required,min=1,dive,gt=0"`
+	Email    string  `json:"email" validate:"omitempty,email"`
+	Phone    string  `json:"phone" validate:"omitempty,e164"`
+	Priority *string `json:"priority" validate:"omitempty,oneof=low high"`
+	Address  Address `json:"address" validate:"required"`
+}
+
+type Address struct {
+	City string `json:"city" validate:"required,min=1"`
+}
+
+const orderHandlers = `package widget
+
+// CreateOrder godoc
+// @Accept       json
+// @Produce      json
+// @Param        order  body  openapi.Order  true  "Order"
+// @Success      201  {object}  openapi.Order
+// @Router       /v1/orders [post]
+func CreateOrder() {}
+
+// GetOrders godoc
+// @Produce      json
+// @Param        limit  query  int     false  "Page size"
+// @Param        state  query  string  true   "Order state"
+// @Param        paid   query  bool    false  "Only paid orders"
+// @Success      200  {array}  openapi.Order
+// @Router       /v1/orders [get]
+func GetOrders() {}
+
+// GetOrder godoc
+// @Produce      json
+// @Param        id  path  int  true  "Order ID"
+// @Success      200  {object}  openapi.Order
+// @Router       /v1/orders/{id} [get]
+func GetOrder() {}
+`
+
+// validated serves the generated document's routes under /v1 behind the
+// validator, the handler echoes the body it got
+func validated(t *testing.T) http.Handler {
+	t.Helper()
+	doc, err := Generate(Info{}, []string{writeFile(t, orderHandlers)}, Order{})
+	require.NoError(t, err)
+	spec, err := json.Marshal(doc)
+	require.NoError(t, err)
+	v, err := NewValidator(spec, "/v1")
+	require.NoError(t, err)
+
+	return http.StripPrefix("/v1", v.Middleware(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(w, r.Body)
+	}))
+}
+
+func fieldErrors(t *testing.T, rr *httptest.ResponseRecorder) []FieldError {
+	t.Helper()
+	require.Equal(t, http.StatusBadRequest, rr.Code, rr.Body.String())
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	var resp ValidationError
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	assert.Equal(t, "Validation failed", resp.Error)
+	return resp.Fields
+}
+
+func TestValidator_Body(t *testing.T) {
+	handler := validated(t)
+
+	tests := []struct {
+		name string
+		body string
+		want []FieldError
+	}{
+		{
+			name: "missing fields",
+			body: `{}`,
+			want: []FieldError{
+				{In: "body", Field: "widgets", Message: "is required"},
+				{In: "body", Field: "address", Message: "is required"},
+			},
+		},
+		{
+			name: "unknown field",
+			body: `{"widgets": [1], "address": {"city": "Leeds", "zip": "LS1"}, "colour": "red"}`,
+			want: []FieldError{
+				{In: "body", Field: "address.zip", Message: "is not a known field"},
+				{In: "body", Field: "colour", Message: "is not a known field"},
+			},
+		},
+		{
+			name: "constraints",
+			body: `{"widgets": [1, 0, "2"], "email": "nope", "phone": "555-0100", "priority": "urgent", "address": {"city": ""}}`,
+			want: []FieldError{
+				{In: "body", Field: "address.city", Message: "must be at least 1 characters"},
+				{In: "body", Field: "email", Message: "must be a valid email"},
+				{In: "body", Field: "phone", Message: "must match " + e164},
+				{In: "body", Field: "priority", Message: "must be one of low, high"},
+				{In: "body", Field: "widgets[1]", Message: "must be greater than 0"},
+				{In: "body", Field: "widgets[2]", Message: "must be an integer"},
+			},
+		},
+		{
+			name: "wrong type",
+			body: `[]`,
+			want: []FieldError{{In: "body", Message: "must be an object"}},
+		},
+		{
+			name: "invalid JSON",
+			body: `{"widgets":`,
+			want: []FieldError{{In: "body", Message: "invalid JSON"}},
+		},
+		{
+			name: "empty body",
+			body: ``,
+			want: []FieldError{{In: "body", Message: "is required"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/orders", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.want, fieldErrors(t, rr))
+		})
+	}
+}
+
+func TestValidator_ValidBodyReachesHandler(t *testing.T) {
+	handler := validated(t)
+
+	// the empty email is allowed by omitempty, a null priority by the pointer
+	body := `{"widgets": [1, 2], "email": "", "priority": null, "address": {"city": "Leeds"}}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/orders", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, body, rr.Body.String(), "the handler reads the body again")
+}
+
+func TestValidator_Parameters(t *testing.T) {
+	handler := validated(t)
+
+	tests := []struct {
+		name string
+		url  string
+		want []FieldError
+	}{
+		{
+			name: "bad path parameter",
+			url:  "/v1/orders/abc",
+			want: []FieldError{{In: "path", Field: "id", Message: "must be an integer"}},
+		},
+		{
+			name: "missing required query parameter",
+			url:  "/v1/orders?limit=10",
+			want: []FieldError{{In: "query", Field: "state", Message: "is required"}},
+		},
+		{
+			name: "bad query parameter",
+			url:  "/v1/orders?state=open&limit=ten",
+			want: []FieldError{{In: "query", Field: "limit", Message: "must be an integer"}},
+		},
+		{
+			// the handlers only take "true", 1 must not reach them
+			name: "boolean other than true or false",
+			url:  "/v1/orders?state=open&paid=1",
+			want: []FieldError{{In: "query", Field: "paid", Message: "must be a boolean"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.want, fieldErrors(t, rr))
+		})
+	}
+}
+
+func TestNewValidator_BadPattern(t *testing.T) {
+	spec := `{"components": {"schemas": {"widget.Order": {"type": "object", "properties": {"code": {"type": "string", "pattern": "^[a-z"}}}}}}`
+
+	_, err := NewValidator([]byte(spec), "/v1")
+
+	assert.ErrorContains(t, err, `compile pattern "^[a-z"`)
+}
+
+func TestValidator_PassesOtherRequests(t *testing.T) {
+	handler := validated(t)
+
+	for _, r := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/v1/orders/7", nil),
+		httptest.NewRequest(http.MethodGet, "/v1/orders?state=open&limit=10", nil),
+		httptest.NewRequest(http.MethodGet, "/v1/orders?state=open&paid=false", nil),
+		httptest.NewRequest(http.MethodGet, "/v1/unknown", nil),
+		httptest.NewRequest(http.MethodDelete, "/v1/orders/7", nil), // next answers 405
+	} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, r)
+		assert.Equal(t, http.StatusOK, rr.Code, r.URL.String())
+	}
+}
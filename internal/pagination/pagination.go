@@ -0,0 +1,74 @@
+package pagination
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// Parse reads ?limit= and ?offset= from the query string, defaulting to the
+// first DefaultLimit rows.
+func Parse(r *http.Request) (Page, error) {
+	page := Page{Limit: DefaultLimit}
+	q := r.URL.Query()
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			return page, fmt.Errorf("limit must be between 1 and %d", MaxLimit)
+		}
+		page.Limit = limit
+	}
+
+	if v := q.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return page, fmt.Errorf("offset must be 0 or greater")
+		}
+		page.Offset = offset
+	}
+
+	return page, nil
+}
+
+// WriteTotal sets the total number of matching rows so clients can page
+// through a plain JSON array.
+func WriteTotal(w http.ResponseWriter, total int) {
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+}
+
+// ParseExport is Parse without the default or maximum limit, exports stream
+// every matching row unless the client asks for a page. A zero Limit means
+// no limit.
+func ParseExport(r *http.Request) (Page, error) {
+	var page Page
+	q := r.URL.Query()
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return page, fmt.Errorf("limit must be 1 or greater")
+		}
+		page.Limit = limit
+	}
+
+	if v := q.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return page, fmt.Errorf("offset must be 0 or greater")
+		}
+		page.Offset = offset
+	}
+
+	return page, nil
+}
@@ -2,20 +2,106 @@ package payment
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/rstoltzm-profile/video-rental-api/internal/daterange"
+	"github.com/rstoltzm-profile/video-rental-api/internal/export"
+	"github.com/rstoltzm-profile/video-rental-api/internal/job"
+	"github.com/rstoltzm-profile/video-rental-api/internal/pagination"
+	"github.com/rstoltzm-profile/video-rental-api/internal/staff"
 )
 
 var validate = validator.New()
 
 type Handler struct {
 	service Service
+	jobs    job.Enqueuer
 }
 
-func NewHandler(service Service) *Handler {
-	return &Handler{service: service}
+func NewHandler(service Service, jobs job.Enqueuer) *Handler {
+	return &Handler{service: service, jobs: jobs}
+}
+
+// GetPayments godoc
+// @Summary      List payments
+// @Description  Page through payments, newest first. Total matches are in X-Total-Count. CSV and NDJSON exports stream every match unless limit is given. With async=true the export runs as a background job.
+// @Tags         payments
+// @Produce      json,text/csv,application/x-ndjson
+// @Param        from         query  string  false  "Paid on or after, YYYY-MM-DD or RFC3339"
+// @Param        to           query  string  false  "Paid before, a plain date includes that day"
+// @Param        customer_id  query  int     false  "Only this customer's payments"
+// @Param        staff_id     query  int     false  "Only payments taken by this staff member"
+// @Param        store_id     query  int     false  "Only payments for this store's copies"
+// @Param        limit        query  int     false  "Page size, default 20, max 100, exports default to every match"
+// @Param        offset       query  int     false  "Rows to skip"
+// @Param        async        query  bool    false  "Export every match as a background job"
+// @Success      200  {array}   payment.PaymentRecord
+// @Success      202  {object}  job.Job
+// @Failure      400  {string}  string  "Invalid query parameter"
+// @Security     ApiKeyAuth
+// @Router       /v1/payments [get]
+func (h *Handler) GetPayments(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	format := export.Negotiate(r)
+	filter, err := parsePaymentFilter(r, format)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if job.Async(r) {
+		j, err := h.jobs.Enqueue(r.Context(), job.NewJob{Kind: KindExport, Params: exportParams{Filter: filter, Format: format}})
+		if err != nil {
+			http.Error(w, "Failed to queue export", http.StatusInternalServerError)
+			return
+		}
+		job.Accepted(w, j)
+		return
+	}
+
+	payments, total, err := h.service.GetPayments(r.Context(), filter)
+	if err != nil {
+		http.Error(w, "Failed to fetch payments", http.StatusInternalServerError)
+		return
+	}
+
+	pagination.WriteTotal(w, total)
+	export.Stream(w, format, payments, func(err error) {
+		http.Error(w, "Failed to fetch payments", http.StatusInternalServerError)
+	})
+}
+
+func parsePaymentFilter(r *http.Request, format export.Format) (PaymentFilter, error) {
+	q := r.URL.Query()
+	var filter PaymentFilter
+
+	rng, err := daterange.Parse(r)
+	if err != nil {
+		return filter, err
+	}
+	filter.Range = rng
+
+	for name, dst := range map[string]**int{
+		"customer_id": &filter.CustomerID,
+		"staff_id":    &filter.StaffID,
+		"store_id":    &filter.StoreID,
+	} {
+		if v := q.Get(name); v != "" {
+			id, err := strconv.Atoi(v)
+			if err != nil {
+				return filter, fmt.Errorf("Invalid %s", name)
+			}
+			*dst = &id
+		}
+	}
+
+	filter.Page, err = export.Page(r, format)
+	return filter, err
 }
 
 // MakePayment godoc
@@ -27,6 +113,7 @@ func NewHandler(service Service) *Handler {
 // @Param        payment  body      payment.Payment  true  "Payment data"
 // @Success      201  {integer}  int  "Payment ID"
 // @Failure      400  {string}  string  "Invalid input"
+// @Failure      403  {string}  string  "Staff member inactive or not at the rental's store"
 // @Failure      500  {string}  string  "Failed to make payment"
 // @Security     ApiKeyAuth
 // @Router       /v1/payments [post]
@@ -48,6 +135,14 @@ func (h *Handler) MakePayment(w http.ResponseWriter, r *http.Request) {
 	}
 
 	payment_id, err := h.service.MakePayment(r.Context(), req)
+	if errors.Is(err, staff.ErrStaffNotAllowed) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if errors.Is(err, staff.ErrInvalidReference) {
+		http.Error(w, "Invalid rental ID", http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Failed to make payment", http.StatusInternalServerError)
 		return
@@ -1,8 +1,36 @@
 package payment
 
+import (
+	"time"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/daterange"
+	"github.com/rstoltzm-profile/video-rental-api/internal/pagination"
+)
+
 type Payment struct {
 	CustomerID int     `json:"customer_id" validate:"required,gt=0"`
 	StaffID    int     `json:"staff_id" validate:"required,gt=0"`
 	RentalID   int     `json:"rental_id" validate:"required,gt=0"`
 	Amount     float32 `json:"amount" validate:"required"`
 }
+
+// PaymentRecord is a payment as listed by GET /payments, with the store that
+// owns the rented copy.
+type PaymentRecord struct {
+	ID          int       `json:"id"`
+	CustomerID  int       `json:"customer_id"`
+	StaffID     int       `json:"staff_id"`
+	RentalID    int       `json:"rental_id"`
+	StoreID     int       `json:"store_id"`
+	Amount      float64   `json:"amount"`
+	PaymentDate time.Time `json:"payment_date"`
+}
+
+// PaymentFilter narrows the payment list, From and To match payment_date.
+type PaymentFilter struct {
+	daterange.Range
+	CustomerID *int
+	StaffID    *int
+	StoreID    *int
+	Page       pagination.Page
+}
@@ -3,21 +3,45 @@ package payment
 import (
 	"context"
 	"fmt"
+	"iter"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rstoltzm-profile/video-rental-api/internal/db"
 )
 
+// paymentWhere filters payments, $1 from, $2 to, $3 customer, $4 staff and
+// $5 store, each may be NULL.
+const paymentWhere = `
+	FROM
+		payment
+		INNER JOIN rental ON payment.rental_id = rental.rental_id
+		INNER JOIN inventory ON rental.inventory_id = inventory.inventory_id
+	WHERE
+		($1::timestamptz IS NULL OR payment.payment_date >= $1)
+		AND ($2::timestamptz IS NULL OR payment.payment_date < $2)
+		AND ($3::int IS NULL OR payment.customer_id = $3)
+		AND ($4::int IS NULL OR payment.staff_id = $4)
+		AND ($5::int IS NULL OR inventory.store_id = $5)
+`
+
+type PaymentReader interface {
+	CountPayments(ctx context.Context, filter PaymentFilter) (int, error)
+	EachPayment(ctx context.Context, filter PaymentFilter) iter.Seq2[PaymentRecord, error]
+}
+
 type PaymentWriter interface {
-	InsertPayment(ctx context.Context, req Payment) (int, error)
+	InsertPayment(ctx context.Context, req Payment) (PaymentRecord, error)
 }
 
 type TransactionManager interface {
 	BeginTx(ctx context.Context) (pgx.Tx, error)
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 type Repository interface {
+	PaymentReader
 	PaymentWriter
 	TransactionManager
 }
@@ -34,26 +58,86 @@ func (r *repository) BeginTx(ctx context.Context) (pgx.Tx, error) {
 	return r.pool.Begin(ctx)
 }
 
-func (r *repository) InsertPayment(ctx context.Context, req Payment) (int, error) {
-	var payment_id int
+func (r *repository) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return db.WithTx(ctx, r.pool, fn)
+}
+
+// conn returns the transaction started by WithTx, or the pool
+func (r *repository) conn(ctx context.Context) db.DBTX {
+	return db.Conn(ctx, r.pool)
+}
+
+func (r *repository) CountPayments(ctx context.Context, filter PaymentFilter) (int, error) {
+	var total int
+	err := r.pool.QueryRow(ctx, `SELECT count(*)`+paymentWhere, filterArgs(filter)...).Scan(&total)
+	return total, err
+}
+
+// EachPayment iterates the payments matching filter, newest first. A zero
+// page limit returns every match.
+func (r *repository) EachPayment(ctx context.Context, filter PaymentFilter) iter.Seq2[PaymentRecord, error] {
+	query := `
+	SELECT
+		payment.payment_id,
+		payment.customer_id,
+		payment.staff_id,
+		payment.rental_id,
+		inventory.store_id,
+		payment.amount::float8,
+		payment.payment_date
+	` + paymentWhere + `
+	ORDER BY payment.payment_date DESC, payment.payment_id DESC
+	LIMIT NULLIF($6, 0) OFFSET $7
+	`
+	return db.Seq(ctx, r.pool, func(row pgx.CollectableRow) (PaymentRecord, error) {
+		var p PaymentRecord
+		err := row.Scan(&p.ID, &p.CustomerID, &p.StaffID, &p.RentalID, &p.StoreID, &p.Amount, &p.PaymentDate)
+		return p, err
+	}, query, append(filterArgs(filter), filter.Page.Limit, filter.Page.Offset)...)
+}
+
+// filterArgs are $1 to $5 of paymentWhere
+func filterArgs(filter PaymentFilter) []any {
+	return []any{filter.From, filter.To, filter.CustomerID, filter.StaffID, filter.StoreID}
+}
+
+// InsertPayment records a payment and returns it with the store that owns
+// the rented copy.
+func (r *repository) InsertPayment(ctx context.Context, req Payment) (PaymentRecord, error) {
+	var p PaymentRecord
 	fmt.Println(req)
 	query := `
-		INSERT INTO payment (customer_id, staff_id, rental_id, amount, payment_date)
-		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
-		RETURNING payment_id
+		WITH inserted AS (
+			INSERT INTO payment (customer_id, staff_id, rental_id, amount, payment_date)
+			VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+			RETURNING payment_id, customer_id, staff_id, rental_id, amount, payment_date
+		)
+		SELECT
+			inserted.payment_id,
+			inserted.customer_id,
+			inserted.staff_id,
+			inserted.rental_id,
+			inventory.store_id,
+			inserted.amount::float8,
+			inserted.payment_date
+		FROM
+			inserted
+			INNER JOIN rental ON inserted.rental_id = rental.rental_id
+			INNER JOIN inventory ON rental.inventory_id = inventory.inventory_id
 	`
-	err := r.pool.QueryRow(ctx, query, req.CustomerID, req.StaffID, req.RentalID, req.Amount).Scan(&payment_id)
+	err := r.conn(ctx).QueryRow(ctx, query, req.CustomerID, req.StaffID, req.RentalID, req.Amount).
+		Scan(&p.ID, &p.CustomerID, &p.StaffID, &p.RentalID, &p.StoreID, &p.Amount, &p.PaymentDate)
 
 	if err != nil {
 		if pgErr, ok := err.(*pgconn.PgError); ok {
 			if pgErr.Code == "23514" { // check SQLSTATE code
 				fmt.Printf("InsertPayment partition error: %v\n", pgErr.Message)
-				return -1, fmt.Errorf("payment insert failed: partition missing for current date")
+				return p, fmt.Errorf("payment insert failed: partition missing for current date")
 			}
 		}
 		fmt.Printf("InsertPayment error : %v\n", err)
-		return -1, err
+		return p, err
 	}
 
-	return payment_id, nil
+	return p, nil
 }
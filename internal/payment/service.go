@@ -1,21 +1,71 @@
 package payment
 
-import "context"
+import (
+	"context"
+	"iter"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/webhook"
+)
+
+// StaffChecker verifies the staff member taking a payment works at the store
+// that owns the rented copy.
+type StaffChecker interface {
+	CheckStaffForRental(ctx context.Context, staffID, rentalID int) error
+}
+
+// Events publishes webhook events on the payment's transaction
+type Events interface {
+	Publish(ctx context.Context, eventType string, payload any) error
+}
 
 type Service interface {
+	GetPayments(ctx context.Context, filter PaymentFilter) (iter.Seq2[PaymentRecord, error], int, error)
 	MakePayment(ctx context.Context, req Payment) (int, error)
 }
 
 type service struct {
+	reader PaymentReader
 	writer PaymentWriter
+	tx     TransactionManager
+	staff  StaffChecker
+	events Events
 }
 
-func NewService(writer PaymentWriter) Service {
+func NewService(reader PaymentReader, writer PaymentWriter, tx TransactionManager, staff StaffChecker, events Events) Service {
 	return &service{
+		reader: reader,
 		writer: writer,
+		tx:     tx,
+		staff:  staff,
+		events: events,
 	}
 }
 
+// GetPayments counts the matches straight away and returns an iterator over
+// the page that runs its query when ranged over.
+func (s *service) GetPayments(ctx context.Context, filter PaymentFilter) (iter.Seq2[PaymentRecord, error], int, error) {
+	total, err := s.reader.CountPayments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.reader.EachPayment(ctx, filter), total, nil
+}
+
 func (s *service) MakePayment(ctx context.Context, req Payment) (int, error) {
-	return s.writer.InsertPayment(ctx, req)
+	paymentID := -1
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.staff.CheckStaffForRental(ctx, req.StaffID, req.RentalID); err != nil {
+			return err
+		}
+		payment, err := s.writer.InsertPayment(ctx, req)
+		if err != nil {
+			return err
+		}
+		paymentID = payment.ID
+		return s.events.Publish(ctx, webhook.PaymentCreated, payment)
+	})
+	if err != nil {
+		return -1, err
+	}
+	return paymentID, nil
 }
@@ -0,0 +1,35 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/export"
+	"github.com/rstoltzm-profile/video-rental-api/internal/job"
+)
+
+// KindExport is the job kind for GET /payments?async=true
+const KindExport = "export-payments"
+
+type exportParams struct {
+	Filter PaymentFilter `json:"filter"`
+	Format export.Format `json:"format"`
+}
+
+// Tasks runs queued payment exports, the export is the job's result
+func Tasks(svc Service) map[string]job.Task {
+	return map[string]job.Task{
+		KindExport: func(ctx context.Context, j job.Job, out *job.Output) error {
+			var params exportParams
+			if err := json.Unmarshal(j.Params, &params); err != nil {
+				return job.Permanent(err)
+			}
+
+			payments, total, err := svc.GetPayments(ctx, params.Filter)
+			if err != nil {
+				return err
+			}
+			return export.Write(out, params.Format, job.Track(payments, out, total))
+		},
+	}
+}
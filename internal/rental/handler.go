@@ -2,11 +2,14 @@ package rental
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/rstoltzm-profile/video-rental-api/internal/export"
+	"github.com/rstoltzm-profile/video-rental-api/internal/staff"
 )
 
 var validate = validator.New()
@@ -24,8 +27,8 @@ func NewHandler(service Service) *Handler {
 // @Description  Get all rentals or only late rentals based on query param
 // @Tags         rentals
 // @Accept       json
-// @Produce      json
-// @Param        late  query     bool  false  "Filter late rentals (true)"
+// @Produce      json,text/csv,application/x-ndjson
+// @Param        late  query     bool  false  "Only rentals past their due date (true)"
 // @Success      200   {array}   rental.Rental
 // @Failure      500   {string}  string  "Failed to fetch rentals"
 // @Security     ApiKeyAuth
@@ -34,23 +37,11 @@ func (h *Handler) GetRentals(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	// Parameters
-	late := r.URL.Query().Get("late")
-
-	var rentals []Rental
-	var err error
-
-	if late == "true" {
-		rentals, err = h.service.GetLateRentals(r.Context())
-	} else {
-		rentals, err = h.service.GetRentals(r.Context())
-	}
+	late := r.URL.Query().Get("late") == "true"
 
-	if err != nil {
+	export.Stream(w, export.Negotiate(r), h.service.EachRental(r.Context(), late), func(err error) {
 		http.Error(w, fmt.Sprint(err), http.StatusInternalServerError)
-		return
-	}
-
-	json.NewEncoder(w).Encode(rentals)
+	})
 }
 
 // CreateRental godoc
@@ -62,25 +53,37 @@ func (h *Handler) GetRentals(w http.ResponseWriter, r *http.Request) {
 // @Param        rental  body      rental.CreateRentalRequest  true  "Rental request"
 // @Success      201     {object}  map[string]int  "Created rental ID"
 // @Failure      400     {string}  string  "Invalid input"
+// @Failure      403     {string}  string  "Staff member inactive or not at the inventory's store"
+// @Failure      409     {string}  string  "Inventory rented out or held for another customer"
 // @Failure      500     {string}  string  "Failed to create rental"
 // @Security     ApiKeyAuth
 // @Router       /v1/rentals [post]
 func (h *Handler) CreateRental(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	var req CreateRentalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
 
-	// Validate the request
 	if err := validate.Struct(req); err != nil {
 		http.Error(w, fmt.Sprintf("Validation error: %v", err), http.StatusBadRequest)
 		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	rental, err := h.service.CreateRental(r.Context(), req)
+	if errors.Is(err, ErrInventoryRented) || errors.Is(err, ErrInventoryOnHold) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if errors.Is(err, staff.ErrStaffNotAllowed) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if errors.Is(err, staff.ErrInvalidReference) {
+		http.Error(w, "Invalid inventory ID", http.StatusBadRequest)
 		return
 	}
-
-	rental, err := h.service.CreateRental(r.Context(), req)
 	if err != nil {
 		http.Error(w, "Failed to create rental", http.StatusInternalServerError)
 		return
@@ -93,16 +96,18 @@ func (h *Handler) CreateRental(w http.ResponseWriter, r *http.Request) {
 
 // ReturnRental godoc
 // @Summary      Return rental
-// @Description  Mark a rental as returned by ID
+// @Description  Mark a rental as returned by ID, the copy is held for the next reservation in line
 // @Tags         rentals
 // @Accept       json
 // @Produce      json
 // @Param        id   path      int  true  "Rental ID"
 // @Success      204  {string}  string  "Rental returned successfully"
 // @Failure      400  {string}  string  "Invalid rental ID"
+// @Failure      404  {string}  string  "Rental not found"
+// @Failure      409  {string}  string  "Rental already returned"
 // @Failure      500  {string}  string  "Failed to return rental"
 // @Security     ApiKeyAuth
-// @Router       /v1/rentals/{id}/return [put]
+// @Router       /v1/rentals/{id}/return [post]
 func (h *Handler) ReturnRental(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	idStr := r.PathValue("id")
@@ -112,7 +117,14 @@ func (h *Handler) ReturnRental(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	err = h.service.ReturnRentalByID(r.Context(), id)
-
+	if errors.Is(err, ErrRentalNotFound) {
+		http.Error(w, "Rental not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, ErrRentalReturned) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Failed to return rental", http.StatusInternalServerError)
 		return
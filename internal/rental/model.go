@@ -11,7 +11,19 @@ type Rental struct {
 }
 
 type CreateRentalRequest struct {
-	InventoryID int `json:"inventory_id" validate:"min=0"`
-	CustomerID  int `json:"customer_id" validate:"min=0"`
-	StaffID     int `json:"staff_id" validate:"min=0"`
+	InventoryID int `json:"inventory_id" validate:"required,gt=0"`
+	CustomerID  int `json:"customer_id" validate:"required,gt=0"`
+	StaffID     int `json:"staff_id" validate:"required,gt=0"`
+}
+
+// RentalEvent is the payload of the rental.created, rental.returned and
+// rental.overdue webhooks
+type RentalEvent struct {
+	RentalID    int        `json:"rental_id"`
+	InventoryID int        `json:"inventory_id"`
+	CustomerID  int        `json:"customer_id"`
+	StaffID     int        `json:"staff_id"`
+	RentalDate  time.Time  `json:"rental_date"`
+	DueDate     time.Time  `json:"due_date"`
+	ReturnDate  *time.Time `json:"return_date,omitempty"`
 }
@@ -2,21 +2,23 @@ package rental
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"iter"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rstoltzm-profile/video-rental-api/internal/db"
 )
 
 type RentalReader interface {
-	GetRentals(ctx context.Context) ([]Rental, error)
-	GetLateRentals(ctx context.Context) ([]Rental, error)
+	EachRental(ctx context.Context, late bool) iter.Seq2[Rental, error]
 	GetActiveRentalByInventoryID(ctx context.Context, inventoryID int) (*Rental, error)
 }
 
 type RentalWriter interface {
-	InsertRental(ctx context.Context, req CreateRentalRequest) (int, error)
-	UpdateRentalByID(ctx context.Context, id int) error
+	InsertRental(ctx context.Context, req CreateRentalRequest) (RentalEvent, error)
+	UpdateRentalByID(ctx context.Context, id int) (RentalEvent, error)
 }
 
 type Repository interface {
@@ -27,6 +29,8 @@ type Repository interface {
 
 type TransactionManager interface {
 	BeginTx(ctx context.Context) (pgx.Tx, error)
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+	Lock(ctx context.Context, key string) error
 }
 
 type repository struct {
@@ -41,7 +45,22 @@ func (r *repository) BeginTx(ctx context.Context) (pgx.Tx, error) {
 	return r.pool.Begin(ctx)
 }
 
-func (r *repository) GetRentals(ctx context.Context) ([]Rental, error) {
+func (r *repository) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return db.WithTx(ctx, r.pool, fn)
+}
+
+func (r *repository) Lock(ctx context.Context, key string) error {
+	return db.Lock(ctx, r.conn(ctx), key)
+}
+
+// conn returns the transaction started by WithTx, or the pool
+func (r *repository) conn(ctx context.Context) db.DBTX {
+	return db.Conn(ctx, r.pool)
+}
+
+// EachRental iterates open rentals, oldest first, or only the ones past their
+// due date by db.IsLate.
+func (r *repository) EachRental(ctx context.Context, late bool) iter.Seq2[Rental, error] {
 	query := `
 	SELECT
 		customer.first_name,
@@ -57,97 +76,82 @@ func (r *repository) GetRentals(ctx context.Context) ([]Rental, error) {
 		INNER JOIN film ON inventory.film_id = film.film_id
 	WHERE
 		rental.return_date IS NULL
+		AND (NOT $1 OR ` + db.IsLate("rental", "film") + `)
 	ORDER BY
 		rental.rental_date
 	`
-	rows, err := r.pool.Query(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var rentals []Rental
-	for rows.Next() {
+	return db.Seq(ctx, r.conn(ctx), func(row pgx.CollectableRow) (Rental, error) {
 		var c Rental
-		if err := rows.Scan(&c.FirstName, &c.LastName, &c.Phone, &c.RentalDate, &c.Title); err != nil {
-			return nil, err
-		}
-		rentals = append(rentals, c)
-	}
-	return rentals, nil
+		err := row.Scan(&c.FirstName, &c.LastName, &c.Phone, &c.RentalDate, &c.Title)
+		return c, err
+	}, query, late)
 }
 
-func (r *repository) GetLateRentals(ctx context.Context) ([]Rental, error) {
-	query := `
+// rentalEventColumns reads a RentalEvent from a CTE named changed holding
+// the inserted or updated rental row.
+const rentalEventColumns = `
 	SELECT
-		customer.first_name,
-		customer.last_name, 
-		address.phone,
-		rental.rental_date,
-		film.title
+		changed.rental_id,
+		changed.inventory_id,
+		changed.customer_id,
+		changed.staff_id,
+		changed.rental_date,
+		changed.rental_date + film.rental_duration * INTERVAL '1 day',
+		changed.return_date
 	FROM
-		rental
-		INNER JOIN customer ON rental.customer_id = customer.customer_id
-		INNER JOIN address ON customer.address_id = address.address_id
-		INNER JOIN inventory ON rental.inventory_id = inventory.inventory_id
+		changed
+		INNER JOIN inventory ON changed.inventory_id = inventory.inventory_id
 		INNER JOIN film ON inventory.film_id = film.film_id
-	WHERE
-		rental.return_date IS NULL
-		AND rental_date < CURRENT_DATE
-	ORDER BY
-		rental.rental_date
-	`
-	rows, err := r.pool.Query(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+`
 
-	var rentals []Rental
-	for rows.Next() {
-		var c Rental
-		if err := rows.Scan(&c.FirstName, &c.LastName, &c.Phone, &c.RentalDate, &c.Title); err != nil {
-			return nil, err
-		}
-		rentals = append(rentals, c)
-	}
-	return rentals, nil
+func scanRentalEvent(row pgx.Row) (RentalEvent, error) {
+	var e RentalEvent
+	err := row.Scan(&e.RentalID, &e.InventoryID, &e.CustomerID, &e.StaffID, &e.RentalDate, &e.DueDate, &e.ReturnDate)
+	return e, err
 }
 
-func (r *repository) InsertRental(ctx context.Context, req CreateRentalRequest) (int, error) {
-	var rental_id int
+func (r *repository) InsertRental(ctx context.Context, req CreateRentalRequest) (RentalEvent, error) {
 	query := `
-		INSERT INTO rental (rental_date, inventory_id, customer_id, staff_id, last_update)
-		VALUES (
-		TO_TIMESTAMP(TO_CHAR(CURRENT_TIMESTAMP, 'YYYY-MM-DD HH24:MI:SS'), 'YYYY-MM-DD HH24:MI:SS'),
-		$1, $2, $3,
-		TO_TIMESTAMP(TO_CHAR(CURRENT_TIMESTAMP, 'YYYY-MM-DD HH24:MI:SS'), 'YYYY-MM-DD HH24:MI:SS')
+		WITH changed AS (
+			INSERT INTO rental (rental_date, inventory_id, customer_id, staff_id, last_update)
+			VALUES (
+			TO_TIMESTAMP(TO_CHAR(CURRENT_TIMESTAMP, 'YYYY-MM-DD HH24:MI:SS'), 'YYYY-MM-DD HH24:MI:SS'),
+			$1, $2, $3,
+			TO_TIMESTAMP(TO_CHAR(CURRENT_TIMESTAMP, 'YYYY-MM-DD HH24:MI:SS'), 'YYYY-MM-DD HH24:MI:SS')
+			)
+			RETURNING *
 		)
-		RETURNING rental_id
-	`
-	err := r.pool.QueryRow(ctx, query, req.InventoryID, req.CustomerID, req.StaffID).Scan(&rental_id)
-
-	if err != nil {
-		return -1, err
-	}
-
-	return rental_id, nil
+	` + rentalEventColumns
+	return scanRentalEvent(r.conn(ctx).QueryRow(ctx, query, req.InventoryID, req.CustomerID, req.StaffID))
 }
 
-func (r *repository) UpdateRentalByID(ctx context.Context, id int) error {
+// UpdateRentalByID marks an open rental returned and returns it, the copy's
+// inventory ID goes to the next reservation in line. A rental that is
+// already returned is left alone and fails with ErrRentalReturned.
+func (r *repository) UpdateRentalByID(ctx context.Context, id int) (RentalEvent, error) {
 	query := `
-	UPDATE rental
-	SET return_date = CURRENT_TIMESTAMP
-	WHERE rental_id = $1
-	`
-	cmdTag, err := r.pool.Exec(ctx, query, id)
-	if err != nil {
-		return fmt.Errorf("update rental failed: %w", err)
+	WITH changed AS (
+		UPDATE rental
+		SET return_date = CURRENT_TIMESTAMP
+		WHERE rental_id = $1 AND return_date IS NULL
+		RETURNING *
+	)
+	` + rentalEventColumns
+	rental, err := scanRentalEvent(r.conn(ctx).QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		var exists bool
+		if err := r.conn(ctx).QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM rental WHERE rental_id = $1)`, id).Scan(&exists); err != nil {
+			return rental, fmt.Errorf("update rental failed: %w", err)
+		}
+		if exists {
+			return rental, fmt.Errorf("%w, %d", ErrRentalReturned, id)
+		}
+		return rental, fmt.Errorf("%w, %d", ErrRentalNotFound, id)
 	}
-	if cmdTag.RowsAffected() == 0 {
-		return fmt.Errorf("no rental found with ID %d", id)
+	if err != nil {
+		return rental, fmt.Errorf("update rental failed: %w", err)
 	}
-	return nil
+	return rental, nil
 }
 
 func (r *repository) GetActiveRentalByInventoryID(ctx context.Context, inventoryID int) (*Rental, error) {
@@ -171,8 +175,8 @@ func (r *repository) GetActiveRentalByInventoryID(ctx context.Context, inventory
 	ORDER BY
 		rental.rental_date
 	`
-	err := r.pool.QueryRow(ctx, query, inventoryID).Scan(&rental.FirstName, &rental.LastName, &rental.Phone, &rental.RentalDate, &rental.Title)
-	if err == pgx.ErrNoRows {
+	err := r.conn(ctx).QueryRow(ctx, query, inventoryID).Scan(&rental.FirstName, &rental.LastName, &rental.Phone, &rental.RentalDate, &rental.Title)
+	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil // No Active rental
 	}
 	if err != nil {
@@ -2,12 +2,40 @@ package rental
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"iter"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/db"
+	"github.com/rstoltzm-profile/video-rental-api/internal/webhook"
+)
+
+var (
+	ErrInventoryRented = errors.New("inventory is already rented out")
+	ErrInventoryOnHold = errors.New("inventory is held for another customer's reservation")
+	ErrRentalNotFound  = errors.New("rental not found")
+	ErrRentalReturned  = errors.New("rental is already returned")
 )
 
+// Holds is the reservation side of renting and returning a copy
+type Holds interface {
+	ClaimHold(ctx context.Context, inventoryID, customerID int) (bool, error)
+	CopyReturned(ctx context.Context, inventoryID int) error
+}
+
+// StaffChecker verifies the staff member recording a rental works at the
+// store that owns the copy.
+type StaffChecker interface {
+	CheckStaffForInventory(ctx context.Context, staffID, inventoryID int) error
+}
+
+// Events publishes webhook events on the rental's transaction
+type Events interface {
+	Publish(ctx context.Context, eventType string, payload any) error
+}
+
 type Service interface {
-	GetRentals(ctx context.Context) ([]Rental, error)
-	GetLateRentals(ctx context.Context) ([]Rental, error)
+	EachRental(ctx context.Context, late bool) iter.Seq2[Rental, error]
 	CreateRental(ctx context.Context, req CreateRentalRequest) (int, error)
 	ReturnRentalByID(ctx context.Context, id int) error
 }
@@ -16,37 +44,74 @@ type service struct {
 	reader RentalReader
 	writer RentalWriter
 	tx     TransactionManager
+	holds  Holds
+	staff  StaffChecker
+	events Events
 }
 
-func NewService(reader RentalReader, writer RentalWriter, tx TransactionManager) Service {
+func NewService(reader RentalReader, writer RentalWriter, tx TransactionManager, holds Holds, staff StaffChecker, events Events) Service {
 	return &service{
 		reader: reader,
 		writer: writer,
 		tx:     tx,
+		holds:  holds,
+		staff:  staff,
+		events: events,
 	}
 }
 
-func (s *service) GetRentals(ctx context.Context) ([]Rental, error) {
-	return s.reader.GetRentals(ctx)
-}
-
-func (s *service) GetLateRentals(ctx context.Context) ([]Rental, error) {
-	return s.reader.GetLateRentals(ctx)
+func (s *service) EachRental(ctx context.Context, late bool) iter.Seq2[Rental, error] {
+	return s.reader.EachRental(ctx, late)
 }
 
 func (s *service) CreateRental(ctx context.Context, req CreateRentalRequest) (int, error) {
-	// Check if the inventory is already rented and not yet returned
-	activeRental, err := s.reader.GetActiveRentalByInventoryID(ctx, req.InventoryID)
-	if err != nil {
-		return 0, fmt.Errorf("failed to check inventory availability, %w", err)
-	}
-	if activeRental != nil {
-		return 0, fmt.Errorf("inventory is already rented out, %d", req.InventoryID)
-	}
+	var rentalID int
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.staff.CheckStaffForInventory(ctx, req.StaffID, req.InventoryID); err != nil {
+			return err
+		}
+
+		if err := s.tx.Lock(ctx, db.InventoryLockKey(req.InventoryID)); err != nil {
+			return err
+		}
+
+		// Check if the inventory is already rented and not yet returned
+		activeRental, err := s.reader.GetActiveRentalByInventoryID(ctx, req.InventoryID)
+		if err != nil {
+			return fmt.Errorf("failed to check inventory availability, %w", err)
+		}
+		if activeRental != nil {
+			return fmt.Errorf("%w, %d", ErrInventoryRented, req.InventoryID)
+		}
+
+		// Only the customer a copy is held for may rent it
+		claimed, err := s.holds.ClaimHold(ctx, req.InventoryID, req.CustomerID)
+		if err != nil {
+			return fmt.Errorf("failed to check reservation holds, %w", err)
+		}
+		if !claimed {
+			return fmt.Errorf("%w, %d", ErrInventoryOnHold, req.InventoryID)
+		}
 
-	return s.writer.InsertRental(ctx, req)
+		rental, err := s.writer.InsertRental(ctx, req)
+		if err != nil {
+			return err
+		}
+		rentalID = rental.RentalID
+		return s.events.Publish(ctx, webhook.RentalCreated, rental)
+	})
+	return rentalID, err
 }
 
 func (s *service) ReturnRentalByID(ctx context.Context, id int) error {
-	return s.writer.UpdateRentalByID(ctx, id)
+	return s.tx.WithTx(ctx, func(ctx context.Context) error {
+		rental, err := s.writer.UpdateRentalByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.holds.CopyReturned(ctx, rental.InventoryID); err != nil {
+			return err
+		}
+		return s.events.Publish(ctx, webhook.RentalReturned, rental)
+	})
 }
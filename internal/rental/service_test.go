@@ -0,0 +1,224 @@
+package rental
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rstoltzm-profile/video-rental-api/internal/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockReader struct {
+	mock.Mock
+}
+
+func (m *mockReader) EachRental(ctx context.Context, late bool) iter.Seq2[Rental, error] {
+	args := m.Called(ctx, late)
+	return func(yield func(Rental, error) bool) {
+		for _, r := range args.Get(0).([]Rental) {
+			if !yield(r, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (m *mockReader) GetActiveRentalByInventoryID(ctx context.Context, inventoryID int) (*Rental, error) {
+	args := m.Called(ctx, inventoryID)
+
+	var rental *Rental
+	if r := args.Get(0); r != nil {
+		rental = r.(*Rental)
+	}
+	return rental, args.Error(1)
+}
+
+type mockWriter struct {
+	mock.Mock
+}
+
+func (m *mockWriter) InsertRental(ctx context.Context, req CreateRentalRequest) (RentalEvent, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(RentalEvent), args.Error(1)
+}
+
+func (m *mockWriter) UpdateRentalByID(ctx context.Context, id int) (RentalEvent, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(RentalEvent), args.Error(1)
+}
+
+type mockTxManager struct{}
+
+func (m *mockTxManager) BeginTx(ctx context.Context) (pgx.Tx, error) {
+	return nil, nil
+}
+
+// WithTx runs fn straight away, there is no database behind the mocks
+func (m *mockTxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (m *mockTxManager) Lock(ctx context.Context, key string) error {
+	return nil
+}
+
+type mockHolds struct {
+	mock.Mock
+}
+
+func (m *mockHolds) ClaimHold(ctx context.Context, inventoryID, customerID int) (bool, error) {
+	args := m.Called(ctx, inventoryID, customerID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockHolds) CopyReturned(ctx context.Context, inventoryID int) error {
+	args := m.Called(ctx, inventoryID)
+	return args.Error(0)
+}
+
+type mockStaff struct {
+	mock.Mock
+}
+
+func (m *mockStaff) CheckStaffForInventory(ctx context.Context, staffID, inventoryID int) error {
+	args := m.Called(ctx, staffID, inventoryID)
+	return args.Error(0)
+}
+
+type mockEvents struct {
+	mock.Mock
+}
+
+func (m *mockEvents) Publish(ctx context.Context, eventType string, payload any) error {
+	args := m.Called(ctx, eventType, payload)
+	return args.Error(0)
+}
+
+type mocks struct {
+	reader *mockReader
+	writer *mockWriter
+	holds  *mockHolds
+	staff  *mockStaff
+	events *mockEvents
+}
+
+func newTestService() (Service, mocks) {
+	m := mocks{
+		reader: new(mockReader),
+		writer: new(mockWriter),
+		holds:  new(mockHolds),
+		staff:  new(mockStaff),
+		events: new(mockEvents),
+	}
+	return NewService(m.reader, m.writer, new(mockTxManager), m.holds, m.staff, m.events), m
+}
+
+func TestService_ReturnRentalByID(t *testing.T) {
+	svc, m := newTestService()
+
+	returned := time.Now()
+	rental := RentalEvent{RentalID: 5, InventoryID: 42, CustomerID: 1, ReturnDate: &returned}
+	m.writer.On("UpdateRentalByID", mock.Anything, 5).Return(rental, nil)
+	m.holds.On("CopyReturned", mock.Anything, 42).Return(nil)
+	m.events.On("Publish", mock.Anything, webhook.RentalReturned, rental).Return(nil)
+
+	err := svc.ReturnRentalByID(context.Background(), 5)
+
+	assert.NoError(t, err)
+	m.writer.AssertExpectations(t)
+	m.holds.AssertExpectations(t)
+	m.events.AssertExpectations(t)
+}
+
+func TestService_ReturnRentalByID_AlreadyReturned(t *testing.T) {
+	svc, m := newTestService()
+
+	m.writer.On("UpdateRentalByID", mock.Anything, 5).Return(RentalEvent{}, fmt.Errorf("%w, %d", ErrRentalReturned, 5))
+
+	err := svc.ReturnRentalByID(context.Background(), 5)
+
+	assert.ErrorIs(t, err, ErrRentalReturned)
+	m.holds.AssertNotCalled(t, "CopyReturned", mock.Anything, mock.Anything)
+	m.events.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_ReturnRentalByID_HoldsCopyForReservation(t *testing.T) {
+	svc, m := newTestService()
+
+	rental := RentalEvent{RentalID: 5, InventoryID: 42}
+	var calls []string
+	m.writer.On("UpdateRentalByID", mock.Anything, 5).Return(rental, nil)
+	m.holds.On("CopyReturned", mock.Anything, 42).Return(nil).Run(func(mock.Arguments) {
+		calls = append(calls, "hold")
+	})
+	m.events.On("Publish", mock.Anything, webhook.RentalReturned, rental).Return(nil).Run(func(mock.Arguments) {
+		calls = append(calls, "publish")
+	})
+
+	err := svc.ReturnRentalByID(context.Background(), 5)
+
+	assert.NoError(t, err)
+	// the copy goes to the next reservation before the return is announced
+	assert.Equal(t, []string{"hold", "publish"}, calls)
+}
+
+func TestService_ReturnRentalByID_HoldFails(t *testing.T) {
+	svc, m := newTestService()
+
+	m.writer.On("UpdateRentalByID", mock.Anything, 5).Return(RentalEvent{RentalID: 5, InventoryID: 42}, nil)
+	m.holds.On("CopyReturned", mock.Anything, 42).Return(assert.AnError)
+
+	err := svc.ReturnRentalByID(context.Background(), 5)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	m.events.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_ReturnRentalByID_NotFound(t *testing.T) {
+	svc, m := newTestService()
+
+	m.writer.On("UpdateRentalByID", mock.Anything, 999).Return(RentalEvent{}, fmt.Errorf("%w, %d", ErrRentalNotFound, 999))
+
+	err := svc.ReturnRentalByID(context.Background(), 999)
+
+	assert.ErrorIs(t, err, ErrRentalNotFound)
+	m.holds.AssertNotCalled(t, "CopyReturned", mock.Anything, mock.Anything)
+	m.events.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_CreateRental_HeldForAnotherCustomer(t *testing.T) {
+	svc, m := newTestService()
+
+	req := CreateRentalRequest{InventoryID: 42, CustomerID: 2, StaffID: 1}
+	m.staff.On("CheckStaffForInventory", mock.Anything, 1, 42).Return(nil)
+	m.reader.On("GetActiveRentalByInventoryID", mock.Anything, 42).Return(nil, nil)
+	m.holds.On("ClaimHold", mock.Anything, 42, 2).Return(false, nil)
+
+	_, err := svc.CreateRental(context.Background(), req)
+
+	assert.ErrorIs(t, err, ErrInventoryOnHold)
+	m.writer.AssertNotCalled(t, "InsertRental", mock.Anything, mock.Anything)
+}
+
+func TestService_CreateRental(t *testing.T) {
+	svc, m := newTestService()
+
+	req := CreateRentalRequest{InventoryID: 42, CustomerID: 2, StaffID: 1}
+	rental := RentalEvent{RentalID: 9, InventoryID: 42, CustomerID: 2, StaffID: 1}
+	m.staff.On("CheckStaffForInventory", mock.Anything, 1, 42).Return(nil)
+	m.reader.On("GetActiveRentalByInventoryID", mock.Anything, 42).Return(nil, nil)
+	m.holds.On("ClaimHold", mock.Anything, 42, 2).Return(true, nil)
+	m.writer.On("InsertRental", mock.Anything, req).Return(rental, nil)
+	m.events.On("Publish", mock.Anything, webhook.RentalCreated, rental).Return(nil)
+
+	id, err := svc.CreateRental(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 9, id)
+	m.events.AssertExpectations(t)
+}
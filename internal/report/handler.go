@@ -0,0 +1,297 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/daterange"
+	"github.com/rstoltzm-profile/video-rental-api/internal/export"
+	"github.com/rstoltzm-profile/video-rental-api/internal/job"
+	"github.com/rstoltzm-profile/video-rental-api/internal/pagination"
+)
+
+type Handler struct {
+	service Service
+	jobs    job.Enqueuer
+}
+
+func NewHandler(service Service, jobs job.Enqueuer) *Handler {
+	return &Handler{service: service, jobs: jobs}
+}
+
+// writeError maps service errors to a status code, anything unknown is a 500
+// with the fallback message.
+func writeError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, ErrStoreNotFound):
+		http.Error(w, "Store not found", http.StatusNotFound)
+	default:
+		http.Error(w, fallback, http.StatusInternalServerError)
+	}
+}
+
+// parseReportFilter reads from, to and store_id, shared by every report.
+func parseReportFilter(r *http.Request) (ReportFilter, error) {
+	var filter ReportFilter
+
+	rng, err := daterange.Parse(r)
+	if err != nil {
+		return filter, err
+	}
+	filter.Range = rng
+
+	if v := r.URL.Query().Get("store_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, fmt.Errorf("Invalid store_id")
+		}
+		filter.StoreID = &id
+	}
+	return filter, nil
+}
+
+// parseTopFilter also reads ?limit= as the number of ranked rows to return.
+func parseTopFilter(r *http.Request) (ReportFilter, error) {
+	filter, err := parseReportFilter(r)
+	if err != nil {
+		return filter, err
+	}
+
+	filter.Top = DefaultTop
+	if v := r.URL.Query().Get("limit"); v != "" {
+		top, err := strconv.Atoi(v)
+		if err != nil || top < 1 || top > MaxTop {
+			return filter, fmt.Errorf("limit must be between 1 and %d", MaxTop)
+		}
+		filter.Top = top
+	}
+	return filter, nil
+}
+
+// enqueue queues the report as a job when the client asked for ?async=true,
+// reporting whether it did.
+func (h *Handler) enqueue(w http.ResponseWriter, r *http.Request, report string, filter ReportFilter) bool {
+	if !job.Async(r) {
+		return false
+	}
+
+	params := taskParams{Report: report, Filter: filter, Format: export.Negotiate(r)}
+	j, err := h.jobs.Enqueue(r.Context(), job.NewJob{Kind: KindReport, Params: params})
+	if err != nil {
+		http.Error(w, "Failed to queue report", http.StatusInternalServerError)
+		return true
+	}
+	job.Accepted(w, j)
+	return true
+}
+
+// GetRevenue godoc
+// @Summary      Revenue by store and month
+// @Description  Payments taken per store per calendar month, matched on payment date
+// @Tags         reports
+// @Produce      json,text/csv,application/x-ndjson
+// @Param        from      query  string  false  "Start date, YYYY-MM-DD or RFC3339, inclusive"
+// @Param        to        query  string  false  "End date, YYYY-MM-DD or RFC3339, a plain date includes that day"
+// @Param        store_id  query  int     false  "Only this store"
+// @Param        async     query  bool    false  "Run the report as a background job"
+// @Success      200  {array}   report.StoreRevenue
+// @Success      202  {object}  job.Job
+// @Failure      400  {string}  string  "Invalid query parameter"
+// @Failure      404  {string}  string  "Store not found"
+// @Security     ApiKeyAuth
+// @Router       /v1/reports/revenue [get]
+func (h *Handler) GetRevenue(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	filter, err := parseReportFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if h.enqueue(w, r, ReportRevenue, filter) {
+		return
+	}
+
+	export.Stream(w, export.Negotiate(r), h.service.EachStoreRevenue(r.Context(), filter), func(err error) {
+		writeError(w, err, "Failed to fetch revenue report")
+	})
+}
+
+// GetTopFilms godoc
+// @Summary      Top rented films
+// @Description  Films with the most rentals in the range, with the revenue paid for them
+// @Tags         reports
+// @Produce      json,text/csv,application/x-ndjson
+// @Param        from      query  string  false  "Start date, YYYY-MM-DD or RFC3339, inclusive"
+// @Param        to        query  string  false  "End date, YYYY-MM-DD or RFC3339, a plain date includes that day"
+// @Param        store_id  query  int     false  "Only this store"
+// @Param        limit     query  int     false  "Number of films, default 10, max 100"
+// @Param        async     query  bool    false  "Run the report as a background job"
+// @Success      200  {array}   report.FilmRank
+// @Success      202  {object}  job.Job
+// @Failure      400  {string}  string  "Invalid query parameter"
+// @Failure      404  {string}  string  "Store not found"
+// @Security     ApiKeyAuth
+// @Router       /v1/reports/top-films [get]
+func (h *Handler) GetTopFilms(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	filter, err := parseTopFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if h.enqueue(w, r, ReportTopFilms, filter) {
+		return
+	}
+
+	export.Stream(w, export.Negotiate(r), h.service.EachTopFilm(r.Context(), filter), func(err error) {
+		writeError(w, err, "Failed to fetch top films")
+	})
+}
+
+// GetTopCustomers godoc
+// @Summary      Top customers
+// @Description  Customers who paid the most for rentals in the range
+// @Tags         reports
+// @Produce      json,text/csv,application/x-ndjson
+// @Param        from      query  string  false  "Start date, YYYY-MM-DD or RFC3339, inclusive"
+// @Param        to        query  string  false  "End date, YYYY-MM-DD or RFC3339, a plain date includes that day"
+// @Param        store_id  query  int     false  "Only this store"
+// @Param        limit     query  int     false  "Number of customers, default 10, max 100"
+// @Param        async     query  bool    false  "Run the report as a background job"
+// @Success      200  {array}   report.CustomerRank
+// @Success      202  {object}  job.Job
+// @Failure      400  {string}  string  "Invalid query parameter"
+// @Failure      404  {string}  string  "Store not found"
+// @Security     ApiKeyAuth
+// @Router       /v1/reports/top-customers [get]
+func (h *Handler) GetTopCustomers(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	filter, err := parseTopFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if h.enqueue(w, r, ReportTopCustomers, filter) {
+		return
+	}
+
+	export.Stream(w, export.Negotiate(r), h.service.EachTopCustomer(r.Context(), filter), func(err error) {
+		writeError(w, err, "Failed to fetch top customers")
+	})
+}
+
+// GetCategoryPopularity godoc
+// @Summary      Category popularity
+// @Description  Rentals per film category and each category's share of all rentals in the range
+// @Tags         reports
+// @Produce      json,text/csv,application/x-ndjson
+// @Param        from      query  string  false  "Start date, YYYY-MM-DD or RFC3339, inclusive"
+// @Param        to        query  string  false  "End date, YYYY-MM-DD or RFC3339, a plain date includes that day"
+// @Param        store_id  query  int     false  "Only this store"
+// @Param        async     query  bool    false  "Run the report as a background job"
+// @Success      200  {array}   report.CategoryPopularity
+// @Success      202  {object}  job.Job
+// @Failure      400  {string}  string  "Invalid query parameter"
+// @Failure      404  {string}  string  "Store not found"
+// @Security     ApiKeyAuth
+// @Router       /v1/reports/categories [get]
+func (h *Handler) GetCategoryPopularity(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	filter, err := parseReportFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if h.enqueue(w, r, ReportCategories, filter) {
+		return
+	}
+
+	export.Stream(w, export.Negotiate(r), h.service.EachCategory(r.Context(), filter), func(err error) {
+		writeError(w, err, "Failed to fetch category report")
+	})
+}
+
+// GetLateReturnRates godoc
+// @Summary      Late return rates
+// @Description  Per store, how many rentals in the range were kept past the film's rental duration
+// @Tags         reports
+// @Produce      json,text/csv,application/x-ndjson
+// @Param        from      query  string  false  "Start date, YYYY-MM-DD or RFC3339, inclusive"
+// @Param        to        query  string  false  "End date, YYYY-MM-DD or RFC3339, a plain date includes that day"
+// @Param        store_id  query  int     false  "Only this store"
+// @Param        async     query  bool    false  "Run the report as a background job"
+// @Success      200  {array}   report.LateReturnRate
+// @Success      202  {object}  job.Job
+// @Failure      400  {string}  string  "Invalid query parameter"
+// @Failure      404  {string}  string  "Store not found"
+// @Security     ApiKeyAuth
+// @Router       /v1/reports/late-returns [get]
+func (h *Handler) GetLateReturnRates(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	filter, err := parseReportFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if h.enqueue(w, r, ReportLateReturns, filter) {
+		return
+	}
+
+	export.Stream(w, export.Negotiate(r), h.service.EachLateReturnRate(r.Context(), filter), func(err error) {
+		writeError(w, err, "Failed to fetch late return report")
+	})
+}
+
+// GetCopyUtilization godoc
+// @Summary      Utilization per copy
+// @Description  Share of the period each copy spent rented out, busiest first. Without from or to the period runs from the first to the last rental on record. Total copies are in X-Total-Count.
+// @Tags         reports
+// @Produce      json,text/csv,application/x-ndjson
+// @Param        from      query  string  false  "Start date, YYYY-MM-DD or RFC3339, inclusive"
+// @Param        to        query  string  false  "End date, YYYY-MM-DD or RFC3339, a plain date includes that day"
+// @Param        store_id  query  int     false  "Only this store"
+// @Param        limit     query  int     false  "Page size, default 20, max 100, exports default to every copy"
+// @Param        offset    query  int     false  "Rows to skip"
+// @Param        async     query  bool    false  "Run the report as a background job"
+// @Success      200  {array}   report.CopyUtilization
+// @Success      202  {object}  job.Job
+// @Failure      400  {string}  string  "Invalid query parameter"
+// @Failure      404  {string}  string  "Store not found"
+// @Security     ApiKeyAuth
+// @Router       /v1/reports/utilization [get]
+func (h *Handler) GetCopyUtilization(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	filter, err := parseReportFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	format := export.Negotiate(r)
+	if filter.Page, err = export.Page(r, format); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if h.enqueue(w, r, ReportUtilization, filter) {
+		return
+	}
+
+	total, err := h.service.CountCopies(r.Context(), filter)
+	if err != nil {
+		writeError(w, err, "Failed to fetch utilization report")
+		return
+	}
+	pagination.WriteTotal(w, total)
+
+	export.Stream(w, format, h.service.EachCopyUtilization(r.Context(), filter), func(err error) {
+		writeError(w, err, "Failed to fetch utilization report")
+	})
+}
@@ -0,0 +1,74 @@
+package report
+
+import (
+	"time"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/daterange"
+	"github.com/rstoltzm-profile/video-rental-api/internal/pagination"
+)
+
+const (
+	DefaultTop = 10
+	MaxTop     = 100
+)
+
+// ReportFilter narrows a report to a date range and optionally one store.
+// Rentals are matched on rental_date, revenue on payment_date.
+type ReportFilter struct {
+	daterange.Range
+	StoreID *int
+	Top     int             // rows in a top-N report
+	Page    pagination.Page // utilization only
+}
+
+// StoreRevenue is the payments taken at a store in one calendar month.
+type StoreRevenue struct {
+	StoreID  int       `json:"store_id"`
+	Month    time.Time `json:"month"`
+	Payments int       `json:"payments"`
+	Revenue  float64   `json:"revenue"`
+}
+
+type FilmRank struct {
+	FilmID  int     `json:"film_id"`
+	Title   string  `json:"title"`
+	Rentals int     `json:"rentals"`
+	Revenue float64 `json:"revenue"`
+}
+
+type CustomerRank struct {
+	CustomerID int     `json:"customer_id"`
+	FirstName  string  `json:"first_name"`
+	LastName   string  `json:"last_name"`
+	Rentals    int     `json:"rentals"`
+	TotalSpend float64 `json:"total_spend"`
+}
+
+type CategoryPopularity struct {
+	CategoryID int     `json:"category_id"`
+	Name       string  `json:"name"`
+	Rentals    int     `json:"rentals"`
+	Share      float64 `json:"share"` // fraction of all rentals in the range
+}
+
+// LateReturnRate counts rentals kept past the film's rental duration, returned
+// or still out.
+type LateReturnRate struct {
+	StoreID  int     `json:"store_id"`
+	Rentals  int     `json:"rentals"`
+	Late     int     `json:"late"`
+	Overdue  int     `json:"overdue"` // late and not yet returned
+	LateRate float64 `json:"late_rate"`
+}
+
+// CopyUtilization is the share of the period a copy spent rented out. Without
+// from or to the period runs from the first to the last rental on record.
+type CopyUtilization struct {
+	InventoryID int     `json:"inventory_id"`
+	FilmID      int     `json:"film_id"`
+	Title       string  `json:"title"`
+	StoreID     int     `json:"store_id"`
+	Rentals     int     `json:"rentals"`
+	RentedDays  float64 `json:"rented_days"`
+	Utilization float64 `json:"utilization"`
+}
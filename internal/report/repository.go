@@ -0,0 +1,242 @@
+package report
+
+import (
+	"context"
+	"iter"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rstoltzm-profile/video-rental-api/internal/db"
+)
+
+// rentalsInRange is the rentals matching a filter, with the copy's film and
+// store. $1 from, $2 to and $3 store_id, each may be NULL.
+const rentalsInRange = `
+	WITH rentals AS (
+		SELECT
+			rental.rental_id,
+			rental.rental_date,
+			rental.return_date,
+			rental.customer_id,
+			inventory.film_id,
+			inventory.store_id,
+			film.rental_duration
+		FROM
+			rental
+			INNER JOIN inventory ON rental.inventory_id = inventory.inventory_id
+			INNER JOIN film ON inventory.film_id = film.film_id
+		WHERE
+			($1::timestamptz IS NULL OR rental.rental_date >= $1)
+			AND ($2::timestamptz IS NULL OR rental.rental_date < $2)
+			AND ($3::int IS NULL OR inventory.store_id = $3)
+	)
+`
+
+// ReportReader returns report rows as iterators that run the query when
+// ranged over, so a report is never held in memory.
+type ReportReader interface {
+	StoreExists(ctx context.Context, storeID int) (bool, error)
+	EachStoreRevenue(ctx context.Context, filter ReportFilter) iter.Seq2[StoreRevenue, error]
+	EachTopFilm(ctx context.Context, filter ReportFilter) iter.Seq2[FilmRank, error]
+	EachTopCustomer(ctx context.Context, filter ReportFilter) iter.Seq2[CustomerRank, error]
+	EachCategory(ctx context.Context, filter ReportFilter) iter.Seq2[CategoryPopularity, error]
+	EachLateReturnRate(ctx context.Context, filter ReportFilter) iter.Seq2[LateReturnRate, error]
+	CountCopies(ctx context.Context, filter ReportFilter) (int, error)
+	EachCopyUtilization(ctx context.Context, filter ReportFilter) iter.Seq2[CopyUtilization, error]
+}
+
+type Repository interface {
+	ReportReader
+}
+
+type repository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRepository(pool *pgxpool.Pool) Repository {
+	return &repository{pool: pool}
+}
+
+func (r *repository) StoreExists(ctx context.Context, storeID int) (bool, error) {
+	var exists bool
+	err := r.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM store WHERE store_id = $1)`, storeID).Scan(&exists)
+	return exists, err
+}
+
+// EachStoreRevenue attributes each payment to the store that owns the
+// rented copy.
+func (r *repository) EachStoreRevenue(ctx context.Context, filter ReportFilter) iter.Seq2[StoreRevenue, error] {
+	query := `
+	SELECT
+		inventory.store_id,
+		DATE_TRUNC('month', payment.payment_date) AS month,
+		COUNT(*),
+		SUM(payment.amount)::float8
+	FROM
+		payment
+		INNER JOIN rental ON payment.rental_id = rental.rental_id
+		INNER JOIN inventory ON rental.inventory_id = inventory.inventory_id
+	WHERE
+		($1::timestamptz IS NULL OR payment.payment_date >= $1)
+		AND ($2::timestamptz IS NULL OR payment.payment_date < $2)
+		AND ($3::int IS NULL OR inventory.store_id = $3)
+	GROUP BY inventory.store_id, month
+	ORDER BY month, inventory.store_id
+	`
+	return db.Seq(ctx, r.pool, func(row pgx.CollectableRow) (StoreRevenue, error) {
+		var s StoreRevenue
+		err := row.Scan(&s.StoreID, &s.Month, &s.Payments, &s.Revenue)
+		return s, err
+	}, query, filter.From, filter.To, filter.StoreID)
+}
+
+func (r *repository) EachTopFilm(ctx context.Context, filter ReportFilter) iter.Seq2[FilmRank, error] {
+	query := rentalsInRange + `
+	SELECT
+		film.film_id,
+		film.title,
+		COUNT(DISTINCT rentals.rental_id) AS rentals,
+		COALESCE(SUM(payment.amount), 0)::float8
+	FROM
+		rentals
+		INNER JOIN film ON rentals.film_id = film.film_id
+		LEFT JOIN payment ON rentals.rental_id = payment.rental_id
+	GROUP BY film.film_id
+	ORDER BY rentals DESC, film.film_id
+	LIMIT $4
+	`
+	return db.Seq(ctx, r.pool, func(row pgx.CollectableRow) (FilmRank, error) {
+		var f FilmRank
+		err := row.Scan(&f.FilmID, &f.Title, &f.Rentals, &f.Revenue)
+		return f, err
+	}, query, filter.From, filter.To, filter.StoreID, filter.Top)
+}
+
+// EachTopCustomer ranks by what was paid for the rentals in range.
+func (r *repository) EachTopCustomer(ctx context.Context, filter ReportFilter) iter.Seq2[CustomerRank, error] {
+	query := rentalsInRange + `
+	SELECT
+		customer.customer_id,
+		customer.first_name,
+		customer.last_name,
+		COUNT(DISTINCT rentals.rental_id) AS rentals,
+		COALESCE(SUM(payment.amount), 0)::float8 AS total_spend
+	FROM
+		rentals
+		INNER JOIN customer ON rentals.customer_id = customer.customer_id
+		LEFT JOIN payment ON rentals.rental_id = payment.rental_id
+	GROUP BY customer.customer_id
+	ORDER BY total_spend DESC, rentals DESC, customer.customer_id
+	LIMIT $4
+	`
+	return db.Seq(ctx, r.pool, func(row pgx.CollectableRow) (CustomerRank, error) {
+		var c CustomerRank
+		err := row.Scan(&c.CustomerID, &c.FirstName, &c.LastName, &c.Rentals, &c.TotalSpend)
+		return c, err
+	}, query, filter.From, filter.To, filter.StoreID, filter.Top)
+}
+
+func (r *repository) EachCategory(ctx context.Context, filter ReportFilter) iter.Seq2[CategoryPopularity, error] {
+	query := rentalsInRange + `
+	SELECT
+		category.category_id,
+		category.name,
+		COUNT(*) AS rentals,
+		(COUNT(*)::float8 / NULLIF((SELECT COUNT(*) FROM rentals), 0))::float8
+	FROM
+		rentals
+		INNER JOIN film_category ON rentals.film_id = film_category.film_id
+		INNER JOIN category ON film_category.category_id = category.category_id
+	GROUP BY category.category_id
+	ORDER BY rentals DESC, category.name
+	`
+	return db.Seq(ctx, r.pool, func(row pgx.CollectableRow) (CategoryPopularity, error) {
+		var c CategoryPopularity
+		err := row.Scan(&c.CategoryID, &c.Name, &c.Rentals, &c.Share)
+		return c, err
+	}, query, filter.From, filter.To, filter.StoreID)
+}
+
+// EachLateReturnRate uses db.IsLate, the rule of the customer summary and
+// rental history: a rental is late once it has been out longer than the
+// film's rental_duration in days.
+func (r *repository) EachLateReturnRate(ctx context.Context, filter ReportFilter) iter.Seq2[LateReturnRate, error] {
+	query := rentalsInRange + `, late AS (
+		SELECT
+			rentals.store_id,
+			rentals.return_date,
+			` + db.IsLate("rentals", "rentals") + ` AS is_late
+		FROM rentals
+	)
+	SELECT
+		late.store_id,
+		COUNT(*),
+		COUNT(*) FILTER (WHERE late.is_late),
+		COUNT(*) FILTER (WHERE late.is_late AND late.return_date IS NULL),
+		(COUNT(*) FILTER (WHERE late.is_late))::float8 / COUNT(*)
+	FROM late
+	GROUP BY late.store_id
+	ORDER BY late.store_id
+	`
+	return db.Seq(ctx, r.pool, func(row pgx.CollectableRow) (LateReturnRate, error) {
+		var l LateReturnRate
+		err := row.Scan(&l.StoreID, &l.Rentals, &l.Late, &l.Overdue, &l.LateRate)
+		return l, err
+	}, query, filter.From, filter.To, filter.StoreID)
+}
+
+func (r *repository) CountCopies(ctx context.Context, filter ReportFilter) (int, error) {
+	var total int
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM inventory WHERE ($1::int IS NULL OR store_id = $1)`, filter.StoreID).Scan(&total)
+	return total, err
+}
+
+// EachCopyUtilization clips every rental to the period and sums the time each
+// copy was out, busiest copies first. Copies never rented are included.
+func (r *repository) EachCopyUtilization(ctx context.Context, filter ReportFilter) iter.Seq2[CopyUtilization, error] {
+	query := `
+	WITH period AS (
+		SELECT
+			COALESCE($1::timestamptz, MIN(rental.rental_date)) AS starts,
+			COALESCE($2::timestamptz, GREATEST(MAX(rental.rental_date), MAX(rental.return_date))) AS ends
+		FROM rental
+	), copies AS (
+		SELECT
+			inventory.inventory_id,
+			inventory.film_id,
+			film.title,
+			inventory.store_id,
+			COUNT(rental.rental_id) AS rentals,
+			COALESCE(SUM(EXTRACT(EPOCH FROM
+				LEAST(COALESCE(rental.return_date, CURRENT_TIMESTAMP), period.ends) - GREATEST(rental.rental_date, period.starts)
+			)), 0) AS rented_seconds,
+			EXTRACT(EPOCH FROM period.ends - period.starts) AS period_seconds
+		FROM
+			inventory
+			INNER JOIN film ON inventory.film_id = film.film_id
+			CROSS JOIN period
+			LEFT JOIN rental ON rental.inventory_id = inventory.inventory_id
+				AND rental.rental_date < period.ends
+				AND COALESCE(rental.return_date, CURRENT_TIMESTAMP) > period.starts
+		WHERE
+			($3::int IS NULL OR inventory.store_id = $3)
+		GROUP BY inventory.inventory_id, film.title, period.starts, period.ends
+	)
+	SELECT
+		inventory_id,
+		film_id,
+		title,
+		store_id,
+		rentals,
+		ROUND(rented_seconds / 86400, 2)::float8,
+		COALESCE(ROUND(rented_seconds / NULLIF(period_seconds, 0), 4), 0)::float8 AS utilization
+	FROM copies
+	ORDER BY utilization DESC, inventory_id
+	LIMIT NULLIF($4, 0) OFFSET $5
+	`
+	return db.Seq(ctx, r.pool, func(row pgx.CollectableRow) (CopyUtilization, error) {
+		var c CopyUtilization
+		err := row.Scan(&c.InventoryID, &c.FilmID, &c.Title, &c.StoreID, &c.Rentals, &c.RentedDays, &c.Utilization)
+		return c, err
+	}, query, filter.From, filter.To, filter.StoreID, filter.Page.Limit, filter.Page.Offset)
+}
@@ -0,0 +1,92 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"iter"
+)
+
+var ErrStoreNotFound = errors.New("store not found")
+
+// Service returns each report as an iterator, an unknown store is yielded as
+// ErrStoreNotFound before any row.
+type Service interface {
+	EachStoreRevenue(ctx context.Context, filter ReportFilter) iter.Seq2[StoreRevenue, error]
+	EachTopFilm(ctx context.Context, filter ReportFilter) iter.Seq2[FilmRank, error]
+	EachTopCustomer(ctx context.Context, filter ReportFilter) iter.Seq2[CustomerRank, error]
+	EachCategory(ctx context.Context, filter ReportFilter) iter.Seq2[CategoryPopularity, error]
+	EachLateReturnRate(ctx context.Context, filter ReportFilter) iter.Seq2[LateReturnRate, error]
+	CountCopies(ctx context.Context, filter ReportFilter) (int, error)
+	EachCopyUtilization(ctx context.Context, filter ReportFilter) iter.Seq2[CopyUtilization, error]
+}
+
+type service struct {
+	reader ReportReader
+}
+
+func NewService(reader ReportReader) Service {
+	return &service{reader: reader}
+}
+
+func (s *service) EachStoreRevenue(ctx context.Context, filter ReportFilter) iter.Seq2[StoreRevenue, error] {
+	return checkStore(ctx, s.reader, filter, s.reader.EachStoreRevenue(ctx, filter))
+}
+
+func (s *service) EachTopFilm(ctx context.Context, filter ReportFilter) iter.Seq2[FilmRank, error] {
+	return checkStore(ctx, s.reader, filter, s.reader.EachTopFilm(ctx, filter))
+}
+
+func (s *service) EachTopCustomer(ctx context.Context, filter ReportFilter) iter.Seq2[CustomerRank, error] {
+	return checkStore(ctx, s.reader, filter, s.reader.EachTopCustomer(ctx, filter))
+}
+
+func (s *service) EachCategory(ctx context.Context, filter ReportFilter) iter.Seq2[CategoryPopularity, error] {
+	return checkStore(ctx, s.reader, filter, s.reader.EachCategory(ctx, filter))
+}
+
+func (s *service) EachLateReturnRate(ctx context.Context, filter ReportFilter) iter.Seq2[LateReturnRate, error] {
+	return checkStore(ctx, s.reader, filter, s.reader.EachLateReturnRate(ctx, filter))
+}
+
+func (s *service) CountCopies(ctx context.Context, filter ReportFilter) (int, error) {
+	if err := storeExists(ctx, s.reader, filter); err != nil {
+		return 0, err
+	}
+	return s.reader.CountCopies(ctx, filter)
+}
+
+// EachCopyUtilization expects the store to have been checked by CountCopies.
+func (s *service) EachCopyUtilization(ctx context.Context, filter ReportFilter) iter.Seq2[CopyUtilization, error] {
+	return s.reader.EachCopyUtilization(ctx, filter)
+}
+
+// checkStore checks the filter's store before running the report query, so an
+// unknown store is told apart from a store with nothing to report.
+func checkStore[T any](ctx context.Context, reader ReportReader, filter ReportFilter, rows iter.Seq2[T, error]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		if err := storeExists(ctx, reader, filter); err != nil {
+			var zero T
+			yield(zero, err)
+			return
+		}
+		for row, err := range rows {
+			if !yield(row, err) {
+				return
+			}
+		}
+	}
+}
+
+func storeExists(ctx context.Context, reader ReportReader, filter ReportFilter) error {
+	if filter.StoreID == nil {
+		return nil
+	}
+	exists, err := reader.StoreExists(ctx, *filter.StoreID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrStoreNotFound
+	}
+	return nil
+}
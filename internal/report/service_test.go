@@ -0,0 +1,111 @@
+package report
+
+import (
+	"context"
+	"iter"
+	"testing"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockReader struct {
+	mock.Mock
+}
+
+func (m *mockReader) StoreExists(ctx context.Context, storeID int) (bool, error) {
+	args := m.Called(ctx, storeID)
+	return args.Bool(0), args.Error(1)
+}
+
+// seq yields the mocked rows, or only the error, like the repository
+func seq[T any](args mock.Arguments) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		if err := args.Error(1); err != nil {
+			var zero T
+			yield(zero, err)
+			return
+		}
+		for _, row := range args.Get(0).([]T) {
+			if !yield(row, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (m *mockReader) EachStoreRevenue(ctx context.Context, filter ReportFilter) iter.Seq2[StoreRevenue, error] {
+	return seq[StoreRevenue](m.Called(ctx, filter))
+}
+
+func (m *mockReader) EachTopFilm(ctx context.Context, filter ReportFilter) iter.Seq2[FilmRank, error] {
+	return seq[FilmRank](m.Called(ctx, filter))
+}
+
+func (m *mockReader) EachTopCustomer(ctx context.Context, filter ReportFilter) iter.Seq2[CustomerRank, error] {
+	return seq[CustomerRank](m.Called(ctx, filter))
+}
+
+func (m *mockReader) EachCategory(ctx context.Context, filter ReportFilter) iter.Seq2[CategoryPopularity, error] {
+	return seq[CategoryPopularity](m.Called(ctx, filter))
+}
+
+func (m *mockReader) EachLateReturnRate(ctx context.Context, filter ReportFilter) iter.Seq2[LateReturnRate, error] {
+	return seq[LateReturnRate](m.Called(ctx, filter))
+}
+
+func (m *mockReader) CountCopies(ctx context.Context, filter ReportFilter) (int, error) {
+	args := m.Called(ctx, filter)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockReader) EachCopyUtilization(ctx context.Context, filter ReportFilter) iter.Seq2[CopyUtilization, error] {
+	return seq[CopyUtilization](m.Called(ctx, filter))
+}
+
+func TestService_EachStoreRevenue_AllStores(t *testing.T) {
+	reader := new(mockReader)
+	svc := NewService(reader)
+
+	filter := ReportFilter{}
+	reader.On("EachStoreRevenue", mock.Anything, filter).Return([]StoreRevenue{{StoreID: 1, Payments: 3, Revenue: 8.97}}, nil)
+
+	revenue, err := db.Collect(svc.EachStoreRevenue(context.Background(), filter))
+
+	assert.NoError(t, err)
+	assert.Len(t, revenue, 1)
+	reader.AssertNotCalled(t, "StoreExists", mock.Anything, mock.Anything)
+}
+
+func TestService_EachTopFilm_UnknownStore(t *testing.T) {
+	reader := new(mockReader)
+	svc := NewService(reader)
+
+	storeID := 99
+	reader.On("StoreExists", mock.Anything, 99).Return(false, nil)
+
+	filter := ReportFilter{StoreID: &storeID, Top: DefaultTop}
+	reader.On("EachTopFilm", mock.Anything, filter).Return([]FilmRank{{FilmID: 1}}, nil)
+
+	films, err := db.Collect(svc.EachTopFilm(context.Background(), filter))
+
+	assert.ErrorIs(t, err, ErrStoreNotFound)
+	assert.Empty(t, films)
+}
+
+func TestService_CountCopies(t *testing.T) {
+	reader := new(mockReader)
+	svc := NewService(reader)
+
+	storeID := 1
+	filter := ReportFilter{StoreID: &storeID}
+	reader.On("StoreExists", mock.Anything, 1).Return(true, nil)
+	reader.On("CountCopies", mock.Anything, filter).Return(2270, nil)
+
+	total, err := svc.CountCopies(context.Background(), filter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2270, total)
+	reader.AssertExpectations(t)
+}
@@ -0,0 +1,73 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/export"
+	"github.com/rstoltzm-profile/video-rental-api/internal/job"
+)
+
+// KindReport is the job kind for any report run with ?async=true
+const KindReport = "report"
+
+// Report names, the last part of each report's path
+const (
+	ReportRevenue      = "revenue"
+	ReportTopFilms     = "top-films"
+	ReportTopCustomers = "top-customers"
+	ReportCategories   = "categories"
+	ReportLateReturns  = "late-returns"
+	ReportUtilization  = "utilization"
+)
+
+type taskParams struct {
+	Report string        `json:"report"`
+	Filter ReportFilter  `json:"filter"`
+	Format export.Format `json:"format"`
+}
+
+// Tasks runs queued reports, the report in the requested format is the
+// job's result.
+func Tasks(svc Service) map[string]job.Task {
+	return map[string]job.Task{
+		KindReport: func(ctx context.Context, j job.Job, out *job.Output) error {
+			var params taskParams
+			if err := json.Unmarshal(j.Params, &params); err != nil {
+				return job.Permanent(err)
+			}
+
+			err := runReport(ctx, svc, params, out)
+			if errors.Is(err, ErrStoreNotFound) {
+				return job.Permanent(err)
+			}
+			return err
+		},
+	}
+}
+
+func runReport(ctx context.Context, svc Service, params taskParams, out *job.Output) error {
+	filter, format := params.Filter, params.Format
+	switch params.Report {
+	case ReportRevenue:
+		return export.Write(out, format, svc.EachStoreRevenue(ctx, filter))
+	case ReportTopFilms:
+		return export.Write(out, format, svc.EachTopFilm(ctx, filter))
+	case ReportTopCustomers:
+		return export.Write(out, format, svc.EachTopCustomer(ctx, filter))
+	case ReportCategories:
+		return export.Write(out, format, svc.EachCategory(ctx, filter))
+	case ReportLateReturns:
+		return export.Write(out, format, svc.EachLateReturnRate(ctx, filter))
+	case ReportUtilization:
+		total, err := svc.CountCopies(ctx, filter)
+		if err != nil {
+			return err
+		}
+		return export.Write(out, format, job.Track(svc.EachCopyUtilization(ctx, filter), out, total))
+	default:
+		return job.Permanent(fmt.Errorf("unknown report %q", params.Report))
+	}
+}
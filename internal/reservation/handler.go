@@ -0,0 +1,196 @@
+package reservation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/rstoltzm-profile/video-rental-api/internal/pagination"
+)
+
+var validate = validator.New()
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// writeError maps service errors to a status code, anything unknown is a 500
+// with the fallback message.
+func writeError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, ErrReservationNotFound):
+		http.Error(w, "Reservation not found", http.StatusNotFound)
+	case errors.Is(err, ErrCustomerInactive), errors.Is(err, ErrNoCopies), errors.Is(err, ErrInvalidReservation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrAlreadyReserved), errors.Is(err, ErrReservationClosed):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, fallback, http.StatusInternalServerError)
+	}
+}
+
+// GetReservations godoc
+// @Summary      List reservations
+// @Description  Page through reservations, oldest first. Total matches are in X-Total-Count.
+// @Tags         reservations
+// @Produce      json
+// @Param        customer_id  query  int     false  "Only this customer's reservations"
+// @Param        film_id      query  int     false  "Only reservations for this film"
+// @Param        store_id     query  int     false  "Only reservations at this store"
+// @Param        status       query  string  false  "waiting, held, fulfilled, cancelled or expired"
+// @Param        limit        query  int     false  "Page size, default 20, max 100"
+// @Param        offset       query  int     false  "Rows to skip"
+// @Success      200  {array}   reservation.Reservation
+// @Failure      400  {string}  string  "Invalid query parameter"
+// @Security     ApiKeyAuth
+// @Router       /v1/reservations [get]
+func (h *Handler) GetReservations(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	filter, err := parseReservationFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	reservations, total, err := h.service.GetReservations(r.Context(), filter)
+	if err != nil {
+		http.Error(w, "Failed to fetch reservations", http.StatusInternalServerError)
+		return
+	}
+
+	pagination.WriteTotal(w, total)
+	if reservations == nil {
+		reservations = []Reservation{}
+	}
+	json.NewEncoder(w).Encode(reservations)
+}
+
+func parseReservationFilter(r *http.Request) (ReservationFilter, error) {
+	q := r.URL.Query()
+	filter := ReservationFilter{Status: q.Get("status")}
+
+	for name, dst := range map[string]**int{
+		"customer_id": &filter.CustomerID,
+		"film_id":     &filter.FilmID,
+		"store_id":    &filter.StoreID,
+	} {
+		if v := q.Get(name); v != "" {
+			id, err := strconv.Atoi(v)
+			if err != nil {
+				return filter, fmt.Errorf("Invalid %s", name)
+			}
+			*dst = &id
+		}
+	}
+
+	switch filter.Status {
+	case "", StatusWaiting, StatusHeld, StatusFulfilled, StatusCancelled, StatusExpired:
+	default:
+		return filter, fmt.Errorf("status must be waiting, held, fulfilled, cancelled or expired")
+	}
+
+	var err error
+	filter.Page, err = pagination.Parse(r)
+	return filter, err
+}
+
+// GetReservationByID godoc
+// @Summary      Get reservation by ID
+// @Description  Get a reservation with its place in the queue or its held copy
+// @Tags         reservations
+// @Produce      json
+// @Param        id   path      int  true  "Reservation ID"
+// @Success      200  {object}  reservation.Reservation
+// @Failure      400  {string}  string  "Invalid reservation ID"
+// @Failure      404  {string}  string  "Reservation not found"
+// @Security     ApiKeyAuth
+// @Router       /v1/reservations/{id} [get]
+func (h *Handler) GetReservationByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid reservation ID", http.StatusBadRequest)
+		return
+	}
+
+	reservation, err := h.service.GetReservationByID(r.Context(), id)
+	if err != nil {
+		writeError(w, err, "Failed to fetch reservation")
+		return
+	}
+
+	json.NewEncoder(w).Encode(reservation)
+}
+
+// CreateReservation godoc
+// @Summary      Reserve a film
+// @Description  Queue a customer for a film at a store. When a copy comes back the next customer in line gets a time-limited hold on it, a copy already on the shelf is held straight away.
+// @Tags         reservations
+// @Accept       json
+// @Produce      json
+// @Param        reservation  body      reservation.CreateReservationRequest  true  "Reservation request"
+// @Success      201  {object}  reservation.Reservation
+// @Failure      400  {string}  string  "Invalid input"
+// @Failure      409  {string}  string  "Customer already has an open reservation"
+// @Failure      500  {string}  string  "Failed to create reservation"
+// @Security     ApiKeyAuth
+// @Router       /v1/reservations [post]
+func (h *Handler) CreateReservation(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var req CreateReservationRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		http.Error(w, fmt.Sprintf("Validation error: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	reservation, err := h.service.CreateReservation(r.Context(), req)
+	if err != nil {
+		writeError(w, err, "Failed to create reservation")
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/v1/reservations/%d", reservation.ID))
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(reservation)
+}
+
+// CancelReservation godoc
+// @Summary      Cancel reservation
+// @Description  Leave the queue or give up a hold, a held copy passes to the next in line
+// @Tags         reservations
+// @Param        id   path      int  true  "Reservation ID"
+// @Success      204  {string}  string  "No Content"
+// @Failure      400  {string}  string  "Invalid reservation ID"
+// @Failure      404  {string}  string  "Reservation not found"
+// @Failure      409  {string}  string  "Reservation is no longer waiting or held"
+// @Security     ApiKeyAuth
+// @Router       /v1/reservations/{id} [delete]
+func (h *Handler) CancelReservation(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid reservation ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.CancelReservation(r.Context(), id); err != nil {
+		writeError(w, err, "Failed to cancel reservation")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent) // 204
+}
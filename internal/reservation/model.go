@@ -0,0 +1,47 @@
+package reservation
+
+import (
+	"time"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/pagination"
+)
+
+const (
+	StatusWaiting   = "waiting"   // in the queue for a copy
+	StatusHeld      = "held"      // a copy is set aside until HoldExpiresAt
+	StatusFulfilled = "fulfilled" // the customer rented the held copy
+	StatusCancelled = "cancelled"
+	StatusExpired   = "expired" // the hold ran out before the customer rented it
+)
+
+type Reservation struct {
+	ID            int        `json:"id"`
+	CustomerID    int        `json:"customer_id"`
+	FilmID        int        `json:"film_id"`
+	StoreID       int        `json:"store_id"`
+	Status        string     `json:"status"`
+	QueuePosition int        `json:"queue_position,omitempty"` // 1 is next in line, only while waiting
+	InventoryID   *int       `json:"inventory_id"`             // the held copy
+	HoldExpiresAt *time.Time `json:"hold_expires_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type CreateReservationRequest struct {
+	CustomerID int `json:"customer_id" validate:"required,gt=0"`
+	FilmID     int `json:"film_id" validate:"required,gt=0"`
+	StoreID    int `json:"store_id" validate:"required,gt=0"`
+}
+
+type ReservationFilter struct {
+	CustomerID *int
+	FilmID     *int
+	StoreID    *int
+	Status     string
+	Page       pagination.Page
+}
+
+// FilmStore identifies one reservation queue
+type FilmStore struct {
+	FilmID  int
+	StoreID int
+}
@@ -0,0 +1,313 @@
+package reservation
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rstoltzm-profile/video-rental-api/internal/db"
+)
+
+const reservationSelect = `
+	SELECT
+		r.reservation_id,
+		r.customer_id,
+		r.film_id,
+		r.store_id,
+		r.status,
+		CASE WHEN r.status = 'waiting' THEN (
+			SELECT COUNT(*) FROM reservation q
+			WHERE q.film_id = r.film_id AND q.store_id = r.store_id AND q.status = 'waiting'
+				AND (q.created_at, q.reservation_id) <= (r.created_at, r.reservation_id)
+		) ELSE 0 END AS queue_position,
+		r.inventory_id,
+		r.hold_expires_at,
+		r.created_at
+	FROM reservation r
+`
+
+// reservationWhere is shared by the list page and count queries
+const reservationWhere = `
+	WHERE ($1::int IS NULL OR r.customer_id = $1)
+		AND ($2::int IS NULL OR r.film_id = $2)
+		AND ($3::int IS NULL OR r.store_id = $3)
+		AND ($4 = '' OR r.status = $4)
+`
+
+// activeHold is a hold that has not expired yet. A hold past its expiry no
+// longer blocks its copy, fillQueue expires it before passing the copy on.
+const activeHold = `reservation.status = 'held' AND reservation.hold_expires_at > CURRENT_TIMESTAMP`
+
+type ReservationReader interface {
+	GetByID(ctx context.Context, id int) (Reservation, error)
+	GetReservations(ctx context.Context, filter ReservationFilter) ([]Reservation, int, error)
+	FindActiveHold(ctx context.Context, inventoryID int) (*Reservation, error)
+	FindFreeCopies(ctx context.Context, filmID, storeID int) ([]int, error)
+	FindCopy(ctx context.Context, inventoryID int) (FilmStore, error)
+	CountCopies(ctx context.Context, filmID, storeID int) (int, error)
+	IsCustomerActive(ctx context.Context, customerID int) (bool, error)
+	FindExpiredHolds(ctx context.Context) ([]FilmStore, error)
+}
+
+type ReservationWriter interface {
+	InsertReservation(ctx context.Context, req CreateReservationRequest) (int, error)
+	HoldNextInLine(ctx context.Context, inventoryID int, expiresAt time.Time) (bool, error)
+	UpdateStatus(ctx context.Context, id int, status string) error
+	ExpireHolds(ctx context.Context, queue FilmStore) (int, error)
+}
+
+type Repository interface {
+	ReservationReader
+	ReservationWriter
+	TransactionManager
+}
+
+type TransactionManager interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+	Lock(ctx context.Context, key string) error
+}
+
+type repository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRepository(pool *pgxpool.Pool) Repository {
+	return &repository{pool: pool}
+}
+
+func (r *repository) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return db.WithTx(ctx, r.pool, fn)
+}
+
+func (r *repository) Lock(ctx context.Context, key string) error {
+	return db.Lock(ctx, r.conn(ctx), key)
+}
+
+// conn returns the transaction started by WithTx, or the pool
+func (r *repository) conn(ctx context.Context) db.DBTX {
+	return db.Conn(ctx, r.pool)
+}
+
+func scanReservation(row pgx.Row) (Reservation, error) {
+	var res Reservation
+	err := row.Scan(
+		&res.ID,
+		&res.CustomerID,
+		&res.FilmID,
+		&res.StoreID,
+		&res.Status,
+		&res.QueuePosition,
+		&res.InventoryID,
+		&res.HoldExpiresAt,
+		&res.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return res, ErrReservationNotFound
+	}
+	return res, err
+}
+
+func (r *repository) GetByID(ctx context.Context, id int) (Reservation, error) {
+	return scanReservation(r.conn(ctx).QueryRow(ctx, reservationSelect+` WHERE r.reservation_id = $1`, id))
+}
+
+func (r *repository) GetReservations(ctx context.Context, filter ReservationFilter) ([]Reservation, int, error) {
+	args := []any{filter.CustomerID, filter.FilmID, filter.StoreID, filter.Status}
+
+	var total int
+	err := r.conn(ctx).QueryRow(ctx, `SELECT COUNT(*) FROM reservation r`+reservationWhere, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := reservationSelect + reservationWhere + `
+		ORDER BY r.created_at, r.reservation_id
+		LIMIT $5 OFFSET $6
+	`
+	rows, err := r.conn(ctx).Query(ctx, query, append(args, filter.Page.Limit, filter.Page.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var reservations []Reservation
+	for rows.Next() {
+		res, err := scanReservation(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		reservations = append(reservations, res)
+	}
+	return reservations, total, rows.Err()
+}
+
+// FindActiveHold returns the unexpired hold on a copy, or nil
+func (r *repository) FindActiveHold(ctx context.Context, inventoryID int) (*Reservation, error) {
+	query := reservationSelect + `
+		WHERE r.reservation_id = (
+			SELECT reservation.reservation_id FROM reservation
+			WHERE reservation.inventory_id = $1 AND ` + activeHold + `
+		)
+	`
+	res, err := scanReservation(r.conn(ctx).QueryRow(ctx, query, inventoryID))
+	if errors.Is(err, ErrReservationNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// FindFreeCopies returns copies of a film at a store that are neither rented
+// out nor held by an unexpired hold.
+func (r *repository) FindFreeCopies(ctx context.Context, filmID, storeID int) ([]int, error) {
+	rows, err := r.conn(ctx).Query(ctx, `
+		SELECT inventory.inventory_id
+		FROM inventory
+		WHERE inventory.film_id = $1
+			AND inventory.store_id = $2
+			AND NOT EXISTS (
+				SELECT 1 FROM rental
+				WHERE rental.inventory_id = inventory.inventory_id AND rental.return_date IS NULL
+			)
+			AND NOT EXISTS (
+				SELECT 1 FROM reservation
+				WHERE reservation.inventory_id = inventory.inventory_id AND ` + activeHold + `
+			)
+		ORDER BY inventory.inventory_id
+	`, filmID, storeID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[int])
+}
+
+func (r *repository) FindCopy(ctx context.Context, inventoryID int) (FilmStore, error) {
+	var fs FilmStore
+	err := r.conn(ctx).QueryRow(ctx,
+		`SELECT film_id, store_id FROM inventory WHERE inventory_id = $1`,
+		inventoryID,
+	).Scan(&fs.FilmID, &fs.StoreID)
+	return fs, err
+}
+
+func (r *repository) CountCopies(ctx context.Context, filmID, storeID int) (int, error) {
+	var count int
+	err := r.conn(ctx).QueryRow(ctx,
+		`SELECT COUNT(*) FROM inventory WHERE film_id = $1 AND store_id = $2`,
+		filmID, storeID,
+	).Scan(&count)
+	return count, err
+}
+
+func (r *repository) IsCustomerActive(ctx context.Context, customerID int) (bool, error) {
+	var active bool
+	err := r.conn(ctx).QueryRow(ctx,
+		`SELECT activebool FROM customer WHERE customer_id = $1`,
+		customerID,
+	).Scan(&active)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return active, err
+}
+
+func (r *repository) InsertReservation(ctx context.Context, req CreateReservationRequest) (int, error) {
+	var id int
+	err := r.conn(ctx).QueryRow(ctx, `
+		INSERT INTO reservation (customer_id, film_id, store_id)
+		VALUES ($1, $2, $3)
+		RETURNING reservation_id
+	`, req.CustomerID, req.FilmID, req.StoreID).Scan(&id)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505": // unique_violation on the one open reservation per customer index
+			return 0, ErrAlreadyReserved
+		case "23503": // foreign_key_violation
+			return 0, ErrInvalidReservation
+		}
+	}
+	return id, err
+}
+
+// HoldNextInLine gives a copy to the longest waiting reservation for its film
+// and store. It reports false when nobody is waiting or the copy is no longer
+// free. An expired hold on the copy must have been expired first, the copy
+// can only be held once.
+func (r *repository) HoldNextInLine(ctx context.Context, inventoryID int, expiresAt time.Time) (bool, error) {
+	cmdTag, err := r.conn(ctx).Exec(ctx, `
+		UPDATE reservation
+		SET status = 'held', inventory_id = $1, hold_expires_at = $2, last_update = CURRENT_TIMESTAMP
+		WHERE reservation_id = (
+			SELECT reservation.reservation_id
+			FROM reservation
+			INNER JOIN inventory ON reservation.film_id = inventory.film_id
+				AND reservation.store_id = inventory.store_id
+			WHERE inventory.inventory_id = $1 AND reservation.status = 'waiting'
+			ORDER BY reservation.created_at, reservation.reservation_id
+			LIMIT 1
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM rental WHERE rental.inventory_id = $1 AND rental.return_date IS NULL
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM reservation WHERE reservation.inventory_id = $1 AND ` + activeHold + `
+		)
+	`, inventoryID, expiresAt)
+	if err != nil {
+		return false, err
+	}
+	return cmdTag.RowsAffected() == 1, nil
+}
+
+func (r *repository) UpdateStatus(ctx context.Context, id int, status string) error {
+	cmdTag, err := r.conn(ctx).Exec(ctx,
+		`UPDATE reservation SET status = $2, last_update = CURRENT_TIMESTAMP WHERE reservation_id = $1`,
+		id, status,
+	)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ErrReservationNotFound
+	}
+	return nil
+}
+
+// FindExpiredHolds returns the queues that have a hold past its expiry
+func (r *repository) FindExpiredHolds(ctx context.Context) ([]FilmStore, error) {
+	rows, err := r.conn(ctx).Query(ctx, `
+		SELECT DISTINCT film_id, store_id
+		FROM reservation
+		WHERE status = 'held' AND hold_expires_at <= CURRENT_TIMESTAMP
+	`)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (FilmStore, error) {
+		var fs FilmStore
+		err := row.Scan(&fs.FilmID, &fs.StoreID)
+		return fs, err
+	})
+}
+
+// ExpireHolds marks the holds in one queue that are past their expiry as
+// expired.
+func (r *repository) ExpireHolds(ctx context.Context, queue FilmStore) (int, error) {
+	cmdTag, err := r.conn(ctx).Exec(ctx, `
+		UPDATE reservation
+		SET status = 'expired', last_update = CURRENT_TIMESTAMP
+		WHERE film_id = $1 AND store_id = $2
+			AND status = 'held' AND hold_expires_at <= CURRENT_TIMESTAMP
+	`, queue.FilmID, queue.StoreID)
+	if err != nil {
+		return 0, err
+	}
+	return int(cmdTag.RowsAffected()), nil
+}
@@ -0,0 +1,216 @@
+package reservation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/db"
+)
+
+var (
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrAlreadyReserved     = errors.New("customer already has an open reservation for this film at this store")
+	ErrReservationClosed   = errors.New("reservation is no longer waiting or held")
+	ErrCustomerInactive    = errors.New("customer is not active")
+	ErrNoCopies            = errors.New("store does not stock this film")
+	ErrInvalidReservation  = errors.New("invalid customer, film or store id")
+)
+
+type Service interface {
+	GetReservations(ctx context.Context, filter ReservationFilter) ([]Reservation, int, error)
+	GetReservationByID(ctx context.Context, id int) (Reservation, error)
+	CreateReservation(ctx context.Context, req CreateReservationRequest) (Reservation, error)
+	CancelReservation(ctx context.Context, id int) error
+	ExpireHolds(ctx context.Context) (int, error)
+
+	// ClaimHold is called before renting a copy. It reports false if the copy
+	// is held for another customer, and fulfills the customer's own hold.
+	ClaimHold(ctx context.Context, inventoryID, customerID int) (bool, error)
+	// CopyReturned offers a returned copy to the next customer in line.
+	CopyReturned(ctx context.Context, inventoryID int) error
+}
+
+type service struct {
+	reader       ReservationReader
+	writer       ReservationWriter
+	tx           TransactionManager
+	holdDuration time.Duration
+}
+
+func NewService(reader ReservationReader, writer ReservationWriter, tx TransactionManager, holdDuration time.Duration) Service {
+	return &service{
+		reader:       reader,
+		writer:       writer,
+		tx:           tx,
+		holdDuration: holdDuration,
+	}
+}
+
+func (s *service) GetReservations(ctx context.Context, filter ReservationFilter) ([]Reservation, int, error) {
+	return s.reader.GetReservations(ctx, filter)
+}
+
+func (s *service) GetReservationByID(ctx context.Context, id int) (Reservation, error) {
+	return s.reader.GetByID(ctx, id)
+}
+
+func (s *service) CreateReservation(ctx context.Context, req CreateReservationRequest) (Reservation, error) {
+	var reservation Reservation
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		active, err := s.reader.IsCustomerActive(ctx, req.CustomerID)
+		if err != nil {
+			return err
+		}
+		if !active {
+			return ErrCustomerInactive
+		}
+
+		copies, err := s.reader.CountCopies(ctx, req.FilmID, req.StoreID)
+		if err != nil {
+			return err
+		}
+		if copies == 0 {
+			return ErrNoCopies
+		}
+
+		queue := FilmStore{FilmID: req.FilmID, StoreID: req.StoreID}
+		if err := s.lockQueue(ctx, queue); err != nil {
+			return err
+		}
+
+		id, err := s.writer.InsertReservation(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		// A copy may already be on the shelf, hold it straight away
+		if _, err := s.fillQueue(ctx, queue); err != nil {
+			return err
+		}
+
+		reservation, err = s.reader.GetByID(ctx, id)
+		return err
+	})
+	return reservation, err
+}
+
+func (s *service) CancelReservation(ctx context.Context, id int) error {
+	return s.tx.WithTx(ctx, func(ctx context.Context) error {
+		reservation, err := s.reader.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		queue := FilmStore{FilmID: reservation.FilmID, StoreID: reservation.StoreID}
+		if err := s.lockQueue(ctx, queue); err != nil {
+			return err
+		}
+
+		if reservation.Status != StatusWaiting && reservation.Status != StatusHeld {
+			return ErrReservationClosed
+		}
+		if err := s.writer.UpdateStatus(ctx, id, StatusCancelled); err != nil {
+			return err
+		}
+
+		// A cancelled hold frees its copy for the next in line
+		if reservation.Status == StatusHeld {
+			_, err := s.fillQueue(ctx, queue)
+			return err
+		}
+		return nil
+	})
+}
+
+// ExpireHolds releases holds past their expiry and passes the copies on. It
+// returns the number of holds released.
+func (s *service) ExpireHolds(ctx context.Context) (int, error) {
+	queues, err := s.reader.FindExpiredHolds(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var expired int
+	for _, queue := range queues {
+		err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+			if err := s.lockQueue(ctx, queue); err != nil {
+				return err
+			}
+			n, err := s.fillQueue(ctx, queue)
+			expired += n
+			return err
+		})
+		if err != nil {
+			return expired, err
+		}
+	}
+	return expired, nil
+}
+
+func (s *service) ClaimHold(ctx context.Context, inventoryID, customerID int) (bool, error) {
+	var claimed bool
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		hold, err := s.reader.FindActiveHold(ctx, inventoryID)
+		if err != nil {
+			return err
+		}
+		if hold == nil {
+			claimed = true
+			return nil
+		}
+		if hold.CustomerID != customerID {
+			return nil
+		}
+
+		claimed = true
+		return s.writer.UpdateStatus(ctx, hold.ID, StatusFulfilled)
+	})
+	return claimed, err
+}
+
+func (s *service) CopyReturned(ctx context.Context, inventoryID int) error {
+	return s.tx.WithTx(ctx, func(ctx context.Context) error {
+		queue, err := s.reader.FindCopy(ctx, inventoryID)
+		if err != nil {
+			return err
+		}
+		if err := s.lockQueue(ctx, queue); err != nil {
+			return err
+		}
+		_, err = s.fillQueue(ctx, queue)
+		return err
+	})
+}
+
+// fillQueue expires the queue's holds that are past their expiry, then holds
+// every free copy for the next customers in line. It returns the number of
+// holds expired. Must run inside WithTx with the queue locked. The queue lock
+// is always taken before a copy's inventory lock.
+func (s *service) fillQueue(ctx context.Context, queue FilmStore) (int, error) {
+	expired, err := s.writer.ExpireHolds(ctx, queue)
+	if err != nil {
+		return 0, err
+	}
+
+	copies, err := s.reader.FindFreeCopies(ctx, queue.FilmID, queue.StoreID)
+	if err != nil {
+		return expired, err
+	}
+
+	for _, inventoryID := range copies {
+		if err := s.tx.Lock(ctx, db.InventoryLockKey(inventoryID)); err != nil {
+			return expired, err
+		}
+		// false means nobody is left waiting or the copy was rented since we looked
+		if _, err := s.writer.HoldNextInLine(ctx, inventoryID, time.Now().Add(s.holdDuration)); err != nil {
+			return expired, err
+		}
+	}
+	return expired, nil
+}
+
+func (s *service) lockQueue(ctx context.Context, queue FilmStore) error {
+	return s.tx.Lock(ctx, fmt.Sprintf("reservation-queue:%d:%d", queue.FilmID, queue.StoreID))
+}
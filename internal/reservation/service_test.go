@@ -0,0 +1,280 @@
+package reservation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockReader struct {
+	mock.Mock
+}
+
+func (m *mockReader) GetByID(ctx context.Context, id int) (Reservation, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Reservation), args.Error(1)
+}
+
+func (m *mockReader) GetReservations(ctx context.Context, filter ReservationFilter) ([]Reservation, int, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]Reservation), args.Int(1), args.Error(2)
+}
+
+func (m *mockReader) FindActiveHold(ctx context.Context, inventoryID int) (*Reservation, error) {
+	args := m.Called(ctx, inventoryID)
+
+	var hold *Reservation
+	if h := args.Get(0); h != nil {
+		hold = h.(*Reservation)
+	}
+	return hold, args.Error(1)
+}
+
+func (m *mockReader) FindFreeCopies(ctx context.Context, filmID, storeID int) ([]int, error) {
+	args := m.Called(ctx, filmID, storeID)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *mockReader) FindCopy(ctx context.Context, inventoryID int) (FilmStore, error) {
+	args := m.Called(ctx, inventoryID)
+	return args.Get(0).(FilmStore), args.Error(1)
+}
+
+func (m *mockReader) CountCopies(ctx context.Context, filmID, storeID int) (int, error) {
+	args := m.Called(ctx, filmID, storeID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockReader) IsCustomerActive(ctx context.Context, customerID int) (bool, error) {
+	args := m.Called(ctx, customerID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockReader) FindExpiredHolds(ctx context.Context) ([]FilmStore, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]FilmStore), args.Error(1)
+}
+
+type mockWriter struct {
+	mock.Mock
+}
+
+func (m *mockWriter) InsertReservation(ctx context.Context, req CreateReservationRequest) (int, error) {
+	args := m.Called(ctx, req)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockWriter) HoldNextInLine(ctx context.Context, inventoryID int, expiresAt time.Time) (bool, error) {
+	args := m.Called(ctx, inventoryID, expiresAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockWriter) UpdateStatus(ctx context.Context, id int, status string) error {
+	args := m.Called(ctx, id, status)
+	return args.Error(0)
+}
+
+func (m *mockWriter) ExpireHolds(ctx context.Context, queue FilmStore) (int, error) {
+	args := m.Called(ctx, queue)
+	return args.Int(0), args.Error(1)
+}
+
+type mockTxManager struct {
+	locks []string
+}
+
+// WithTx runs fn straight away, there is no database behind the mocks
+func (m *mockTxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (m *mockTxManager) Lock(ctx context.Context, key string) error {
+	m.locks = append(m.locks, key)
+	return nil
+}
+
+func TestService_CreateReservation_HoldsFreeCopy(t *testing.T) {
+	reader := new(mockReader)
+	writer := new(mockWriter)
+	tx := new(mockTxManager)
+	svc := NewService(reader, writer, tx, time.Hour)
+
+	req := CreateReservationRequest{CustomerID: 1, FilmID: 10, StoreID: 2}
+	reader.On("IsCustomerActive", mock.Anything, 1).Return(true, nil)
+	reader.On("CountCopies", mock.Anything, 10, 2).Return(3, nil)
+	writer.On("InsertReservation", mock.Anything, req).Return(7, nil)
+	writer.On("ExpireHolds", mock.Anything, FilmStore{FilmID: 10, StoreID: 2}).Return(0, nil)
+	reader.On("FindFreeCopies", mock.Anything, 10, 2).Return([]int{42}, nil)
+	writer.On("HoldNextInLine", mock.Anything, 42, mock.MatchedBy(func(expiresAt time.Time) bool {
+		return time.Until(expiresAt) > 59*time.Minute
+	})).Return(true, nil)
+	inventoryID := 42
+	reader.On("GetByID", mock.Anything, 7).Return(Reservation{ID: 7, Status: StatusHeld, InventoryID: &inventoryID}, nil)
+
+	reservation, err := svc.CreateReservation(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, StatusHeld, reservation.Status)
+	// queue lock before the copy's inventory lock
+	assert.Equal(t, []string{"reservation-queue:10:2", "inventory:42"}, tx.locks)
+	reader.AssertExpectations(t)
+	writer.AssertExpectations(t)
+}
+
+func TestService_CreateReservation_InactiveCustomer(t *testing.T) {
+	reader := new(mockReader)
+	writer := new(mockWriter)
+	svc := NewService(reader, writer, new(mockTxManager), time.Hour)
+
+	reader.On("IsCustomerActive", mock.Anything, 1).Return(false, nil)
+
+	_, err := svc.CreateReservation(context.Background(), CreateReservationRequest{CustomerID: 1, FilmID: 10, StoreID: 2})
+
+	assert.ErrorIs(t, err, ErrCustomerInactive)
+	writer.AssertNotCalled(t, "InsertReservation", mock.Anything, mock.Anything)
+}
+
+func TestService_CreateReservation_NoCopiesAtStore(t *testing.T) {
+	reader := new(mockReader)
+	writer := new(mockWriter)
+	svc := NewService(reader, writer, new(mockTxManager), time.Hour)
+
+	reader.On("IsCustomerActive", mock.Anything, 1).Return(true, nil)
+	reader.On("CountCopies", mock.Anything, 10, 2).Return(0, nil)
+
+	_, err := svc.CreateReservation(context.Background(), CreateReservationRequest{CustomerID: 1, FilmID: 10, StoreID: 2})
+
+	assert.ErrorIs(t, err, ErrNoCopies)
+	writer.AssertNotCalled(t, "InsertReservation", mock.Anything, mock.Anything)
+}
+
+func TestService_ClaimHold(t *testing.T) {
+	hold := &Reservation{ID: 7, CustomerID: 1}
+
+	tests := []struct {
+		name       string
+		hold       *Reservation
+		customerID int
+		claimed    bool
+		fulfilled  bool
+	}{
+		{name: "no hold", hold: nil, customerID: 2, claimed: true},
+		{name: "held for another customer", hold: hold, customerID: 2, claimed: false},
+		{name: "held for this customer", hold: hold, customerID: 1, claimed: true, fulfilled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := new(mockReader)
+			writer := new(mockWriter)
+			svc := NewService(reader, writer, new(mockTxManager), time.Hour)
+
+			reader.On("FindActiveHold", mock.Anything, 42).Return(tt.hold, nil)
+			writer.On("UpdateStatus", mock.Anything, 7, StatusFulfilled).Return(nil)
+
+			claimed, err := svc.ClaimHold(context.Background(), 42, tt.customerID)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.claimed, claimed)
+			if tt.fulfilled {
+				writer.AssertCalled(t, "UpdateStatus", mock.Anything, 7, StatusFulfilled)
+			} else {
+				writer.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestService_CopyReturned(t *testing.T) {
+	reader := new(mockReader)
+	writer := new(mockWriter)
+	svc := NewService(reader, writer, new(mockTxManager), time.Hour)
+
+	reader.On("FindCopy", mock.Anything, 42).Return(FilmStore{FilmID: 10, StoreID: 2}, nil)
+	writer.On("ExpireHolds", mock.Anything, FilmStore{FilmID: 10, StoreID: 2}).Return(0, nil)
+	reader.On("FindFreeCopies", mock.Anything, 10, 2).Return([]int{42}, nil)
+	writer.On("HoldNextInLine", mock.Anything, 42, mock.Anything).Return(true, nil)
+
+	err := svc.CopyReturned(context.Background(), 42)
+
+	assert.NoError(t, err)
+	writer.AssertExpectations(t)
+}
+
+func TestService_CopyReturned_ExpiresStaleHoldFirst(t *testing.T) {
+	reader := new(mockReader)
+	writer := new(mockWriter)
+	svc := NewService(reader, writer, new(mockTxManager), time.Hour)
+
+	queue := FilmStore{FilmID: 10, StoreID: 2}
+	var calls []string
+	reader.On("FindCopy", mock.Anything, 42).Return(queue, nil)
+	writer.On("ExpireHolds", mock.Anything, queue).Return(1, nil).Run(func(mock.Arguments) {
+		calls = append(calls, "expire")
+	})
+	reader.On("FindFreeCopies", mock.Anything, 10, 2).Return([]int{42}, nil).Run(func(mock.Arguments) {
+		calls = append(calls, "find")
+	})
+	writer.On("HoldNextInLine", mock.Anything, 42, mock.Anything).Return(true, nil)
+
+	err := svc.CopyReturned(context.Background(), 42)
+
+	assert.NoError(t, err)
+	// a lapsed hold must be gone before the copy can be held again
+	assert.Equal(t, []string{"expire", "find"}, calls)
+	writer.AssertExpectations(t)
+}
+
+func TestService_CancelReservation(t *testing.T) {
+	t.Run("held copy passes to the next in line", func(t *testing.T) {
+		reader := new(mockReader)
+		writer := new(mockWriter)
+		svc := NewService(reader, writer, new(mockTxManager), time.Hour)
+
+		reader.On("GetByID", mock.Anything, 7).Return(Reservation{ID: 7, FilmID: 10, StoreID: 2, Status: StatusHeld}, nil)
+		writer.On("UpdateStatus", mock.Anything, 7, StatusCancelled).Return(nil)
+		writer.On("ExpireHolds", mock.Anything, FilmStore{FilmID: 10, StoreID: 2}).Return(0, nil)
+		reader.On("FindFreeCopies", mock.Anything, 10, 2).Return([]int{42}, nil)
+		writer.On("HoldNextInLine", mock.Anything, 42, mock.Anything).Return(true, nil)
+
+		err := svc.CancelReservation(context.Background(), 7)
+
+		assert.NoError(t, err)
+		writer.AssertExpectations(t)
+	})
+
+	t.Run("closed reservation", func(t *testing.T) {
+		reader := new(mockReader)
+		writer := new(mockWriter)
+		svc := NewService(reader, writer, new(mockTxManager), time.Hour)
+
+		reader.On("GetByID", mock.Anything, 7).Return(Reservation{ID: 7, Status: StatusFulfilled}, nil)
+
+		err := svc.CancelReservation(context.Background(), 7)
+
+		assert.ErrorIs(t, err, ErrReservationClosed)
+		writer.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_ExpireHolds(t *testing.T) {
+	reader := new(mockReader)
+	writer := new(mockWriter)
+	svc := NewService(reader, writer, new(mockTxManager), time.Hour)
+
+	queue := FilmStore{FilmID: 10, StoreID: 2}
+	reader.On("FindExpiredHolds", mock.Anything).Return([]FilmStore{queue}, nil)
+	writer.On("ExpireHolds", mock.Anything, queue).Return(2, nil)
+	reader.On("FindFreeCopies", mock.Anything, 10, 2).Return([]int{42, 43}, nil)
+	writer.On("HoldNextInLine", mock.Anything, 42, mock.Anything).Return(true, nil)
+	writer.On("HoldNextInLine", mock.Anything, 43, mock.Anything).Return(false, nil)
+
+	expired, err := svc.ExpireHolds(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, expired)
+	writer.AssertExpectations(t)
+}
@@ -0,0 +1,29 @@
+package reservation
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// RunSweeper releases expired holds every interval until ctx is cancelled.
+// Copies from released holds go to the next customer in line.
+func RunSweeper(ctx context.Context, service Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			expired, err := service.ExpireHolds(ctx)
+			if err != nil {
+				log.Printf("Reservation sweeper failed: %v", err)
+			}
+			if expired > 0 {
+				log.Printf("Reservation sweeper released %d expired holds", expired)
+			}
+		}
+	}
+}
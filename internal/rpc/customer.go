@@ -0,0 +1,151 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/customer"
+	"github.com/rstoltzm-profile/video-rental-api/internal/etag"
+	pb "github.com/rstoltzm-profile/video-rental-api/pkg/pb/videorental/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type customerServer struct {
+	pb.UnimplementedCustomerServiceServer
+	service customer.Service
+}
+
+var rentalStatuses = map[pb.RentalStatus]string{
+	pb.RentalStatus_RENTAL_STATUS_UNSPECIFIED: customer.RentalStatusAll,
+	pb.RentalStatus_RENTAL_STATUS_OPEN:        customer.RentalStatusOpen,
+	pb.RentalStatus_RENTAL_STATUS_RETURNED:    customer.RentalStatusReturned,
+	pb.RentalStatus_RENTAL_STATUS_LATE:        customer.RentalStatusLate,
+}
+
+func toCustomer(c customer.Customer) *pb.Customer {
+	return &pb.Customer{
+		Id:         int32(c.ID),
+		FirstName:  c.FirstName,
+		LastName:   c.LastName,
+		Email:      c.Email,
+		Phone:      c.Phone,
+		StoreId:    int32(c.StoreID),
+		Active:     c.Active,
+		LastUpdate: timestamppb.New(c.LastUpdate),
+		Etag:       etag.Of(c.LastUpdate),
+	}
+}
+
+func (s *customerServer) ListCustomers(ctx context.Context, req *pb.ListCustomersRequest) (*pb.ListCustomersResponse, error) {
+	p, err := page(req.Page)
+	if err != nil {
+		return nil, err
+	}
+	filter := customer.CustomerFilter{Query: req.Query, StoreID: intPtr(req.StoreId), Active: req.Active, Page: p}
+
+	customers, total, err := s.service.GetCustomers(ctx, filter)
+	if err != nil {
+		return nil, statusError(err, "Failed to fetch customers")
+	}
+	resp := &pb.ListCustomersResponse{Total: int32(total)}
+	for c, err := range customers {
+		if err != nil {
+			return nil, statusError(err, "Failed to fetch customers")
+		}
+		resp.Customers = append(resp.Customers, toCustomer(c))
+	}
+	return resp, nil
+}
+
+func (s *customerServer) GetCustomer(ctx context.Context, req *pb.GetCustomerRequest) (*pb.Customer, error) {
+	c, err := s.service.GetCustomerByID(ctx, int(req.Id))
+	if err != nil {
+		return nil, statusError(err, "Failed to fetch customer")
+	}
+	return toCustomer(c), nil
+}
+
+func (s *customerServer) CreateCustomer(ctx context.Context, req *pb.CreateCustomerRequest) (*pb.Customer, error) {
+	create := customer.CreateCustomerRequest{
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Email:     req.Email,
+		StoreID:   int(req.StoreId),
+	}
+	if a := req.Address; a != nil {
+		create.Address = customer.AddressInput{
+			Address:    a.Address,
+			Address2:   a.Address2,
+			District:   a.District,
+			CityName:   a.CityName,
+			Country:    a.Country,
+			PostalCode: a.PostalCode,
+			Phone:      a.Phone,
+		}
+	}
+	if err := validate.Struct(create); err != nil {
+		return nil, validationError(err)
+	}
+
+	c, err := s.service.CreateCustomer(ctx, create)
+	if err != nil {
+		return nil, statusError(err, "Failed to create customer")
+	}
+	return toCustomer(*c), nil
+}
+
+func (s *customerServer) ListCustomerRentals(ctx context.Context, req *pb.ListCustomerRentalsRequest) (*pb.ListCustomerRentalsResponse, error) {
+	p, err := page(req.Page)
+	if err != nil {
+		return nil, err
+	}
+	filter := customer.RentalHistoryFilter{Status: rentalStatuses[req.Status], Page: p}
+	if req.From != nil {
+		from := req.From.AsTime()
+		filter.From = &from
+	}
+	if req.To != nil {
+		to := req.To.AsTime()
+		filter.To = &to
+	}
+
+	rentals, total, err := s.service.GetCustomerRentalsByID(ctx, int(req.CustomerId), filter)
+	if err != nil {
+		return nil, statusError(err, "Failed to fetch customers")
+	}
+	resp := &pb.ListCustomerRentalsResponse{Total: int32(total)}
+	for _, r := range rentals {
+		resp.Rentals = append(resp.Rentals, &pb.CustomerRental{
+			RentalId:    int32(r.RentalID),
+			InventoryId: int32(r.InventoryID),
+			FilmId:      int32(r.FilmID),
+			StoreId:     int32(r.StoreID),
+			Title:       r.Title,
+			RentalDate:  timestamppb.New(r.RentalDate),
+			DueDate:     timestamppb.New(r.RentalDueDate),
+			ReturnDate:  timestamp(r.ReturnDate),
+			Overdue:     r.Overdue,
+		})
+	}
+	return resp, nil
+}
+
+func (s *customerServer) GetCustomerSummary(ctx context.Context, req *pb.GetCustomerSummaryRequest) (*pb.CustomerSummary, error) {
+	summary, err := s.service.GetCustomerSummaryByID(ctx, int(req.CustomerId))
+	if err != nil {
+		return nil, statusError(err, "Failed to fetch customer summary")
+	}
+	resp := &pb.CustomerSummary{
+		CustomerId:         int32(summary.CustomerID),
+		LifetimeRentals:    int32(summary.LifetimeRentals),
+		OpenRentals:        int32(summary.OpenRentals),
+		LateRentals:        int32(summary.LateRentals),
+		FirstRentalDate:    timestamp(summary.FirstRentalDate),
+		LastRentalDate:     timestamp(summary.LastRentalDate),
+		TotalSpend:         summary.TotalSpend,
+		OutstandingBalance: summary.OutstandingBalance,
+	}
+	for _, c := range summary.FavoriteCategories {
+		resp.FavoriteCategories = append(resp.FavoriteCategories, &pb.CategoryCount{Category: c.Category, Rentals: int32(c.Rentals)})
+	}
+	return resp, nil
+}
@@ -0,0 +1,38 @@
+package rpc
+
+import (
+	"errors"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/customer"
+	"github.com/rstoltzm-profile/video-rental-api/internal/inventory"
+	"github.com/rstoltzm-profile/video-rental-api/internal/rental"
+	"github.com/rstoltzm-profile/video-rental-api/internal/staff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusError is writeError for gRPC, the status codes match the /v1 answers
+// for the same errors. Anything unexpected is Internal with the fallback
+// message.
+func statusError(err error, fallback string) error {
+	switch {
+	case errors.Is(err, customer.ErrCustomerNotFound):
+		return status.Error(codes.NotFound, "Customer not found")
+	case errors.Is(err, inventory.ErrFilmNotFound):
+		return status.Error(codes.NotFound, "Film not found")
+	case errors.Is(err, rental.ErrRentalNotFound):
+		return status.Error(codes.NotFound, "Rental not found")
+	case errors.Is(err, customer.ErrUnknownCity), errors.Is(err, customer.ErrAmbiguousCity), errors.Is(err, customer.ErrInvalidStore):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, customer.ErrDuplicateEmail):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, rental.ErrInventoryRented), errors.Is(err, rental.ErrInventoryOnHold), errors.Is(err, rental.ErrRentalReturned):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, staff.ErrStaffNotAllowed):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, staff.ErrInvalidReference):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, fallback)
+	}
+}
@@ -0,0 +1,59 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/film"
+	pb "github.com/rstoltzm-profile/video-rental-api/pkg/pb/videorental/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type filmServer struct {
+	pb.UnimplementedFilmServiceServer
+	service film.Service
+}
+
+func (s *filmServer) ListFilms(ctx context.Context, req *pb.ListFilmsRequest) (*pb.ListFilmsResponse, error) {
+	var films []film.Film
+	var err error
+	if req.Title == "" {
+		films, err = s.service.GetFilms(ctx)
+	} else {
+		films, err = s.service.SearchByTitle(ctx, req.Title)
+	}
+	if err != nil {
+		return nil, statusError(err, "Failed to fetch films")
+	}
+
+	resp := &pb.ListFilmsResponse{}
+	for _, f := range films {
+		resp.Films = append(resp.Films, &pb.Film{
+			Id:          int32(f.ID),
+			Title:       f.Title,
+			Description: f.Description,
+			ReleaseYear: int32(f.ReleaseYear),
+			Language:    f.Language,
+			Rating:      f.Rating,
+		})
+	}
+	return resp, nil
+}
+
+// GetFilm answers NotFound for any error, like GET /films/{id}/with-actors-categories
+func (s *filmServer) GetFilm(ctx context.Context, req *pb.GetFilmRequest) (*pb.Film, error) {
+	f, err := s.service.GetFilmWithActorsAndCategoriesByID(ctx, int(req.Id))
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "Film not found")
+	}
+	return &pb.Film{
+		Id:          int32(f.ID),
+		Title:       f.Title,
+		Description: f.Description,
+		ReleaseYear: int32(f.ReleaseYear),
+		Language:    f.Language,
+		Rating:      f.Rating,
+		Actors:      f.Actors,
+		Categories:  f.Categories,
+	}, nil
+}
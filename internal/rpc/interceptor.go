@@ -0,0 +1,72 @@
+package rpc
+
+import (
+	"context"
+	"log"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// checkAPIKey is ApiKeyMiddleware for gRPC, the key is the x-api-key metadata
+func checkAPIKey(ctx context.Context, keys middleware.KeyChecker) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("x-api-key")
+	if len(values) == 0 || values[0] == "" {
+		return status.Error(codes.Unauthenticated, "Missing API key")
+	}
+	_, valid, err := keys.Check(ctx, values[0])
+	if err != nil {
+		log.Printf("API key check failed: %v", err)
+		return status.Error(codes.Internal, "Failed to check API key")
+	}
+	if !valid {
+		return status.Error(codes.Unauthenticated, "Invalid API key")
+	}
+	return nil
+}
+
+func apiKeyUnary(keys middleware.KeyChecker) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := checkAPIKey(ctx, keys); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func apiKeyStream(keys middleware.KeyChecker) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := checkAPIKey(ss.Context(), keys); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// recoverUnary and recoverStream are ErrorMiddleware for gRPC, they log each
+// call and turn a panic into an Internal error.
+func recoverUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	log.Printf("gRPC request: %s", info.FullMethod)
+	defer func() {
+		if p := recover(); p != nil {
+			log.Printf("PANIC recovered: %v", p)
+			err = status.Error(codes.Internal, "Internal server error")
+		}
+	}()
+	return handler(ctx, req)
+}
+
+func recoverStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	log.Printf("gRPC request: %s", info.FullMethod)
+	defer func() {
+		if p := recover(); p != nil {
+			log.Printf("PANIC recovered: %v", p)
+			err = status.Error(codes.Internal, "Internal server error")
+		}
+	}()
+	return handler(srv, ss)
+}
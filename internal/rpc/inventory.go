@@ -0,0 +1,82 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/inventory"
+	pb "github.com/rstoltzm-profile/video-rental-api/pkg/pb/videorental/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type inventoryServer struct {
+	pb.UnimplementedInventoryServiceServer
+	service inventory.Service
+}
+
+// ListInventory streams the inventory a row at a time, like GET /v1/inventory
+// does with its JSON array
+func (s *inventoryServer) ListInventory(req *pb.ListInventoryRequest, stream grpc.ServerStreamingServer[pb.InventoryItem]) error {
+	for item, err := range s.service.EachInventory(stream.Context(), intPtr(req.StoreId)) {
+		if err != nil {
+			return statusError(err, "Failed to fetch inventory")
+		}
+		if err := stream.Send(&pb.InventoryItem{
+			InventoryId: int32(item.InventoryID),
+			FilmId:      int32(item.FilmID),
+			Title:       item.Title,
+			StoreId:     int32(item.StoreID),
+			LastUpdate:  timestamppb.New(item.LastUpdate),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *inventoryServer) GetFilmAvailability(ctx context.Context, req *pb.GetFilmAvailabilityRequest) (*pb.FilmAvailability, error) {
+	a, err := s.service.GetFilmAvailability(ctx, int(req.FilmId))
+	if err != nil {
+		return nil, statusError(err, "Failed to fetch film availability")
+	}
+	resp := &pb.FilmAvailability{
+		FilmId:          int32(a.FilmID),
+		Title:           a.Title,
+		Available:       a.Available,
+		AvailableCopies: int32(a.AvailableCopies),
+		TotalCopies:     int32(a.TotalCopies),
+		NextReturnDue:   timestamp(a.NextReturnDue),
+	}
+	for _, store := range a.Stores {
+		resp.Stores = append(resp.Stores, toStoreAvailability(store))
+	}
+	return resp, nil
+}
+
+// GetStoreAvailability answers with available false rather than NotFound when
+// no copy is free, the REST route's 404 still carries the body
+func (s *inventoryServer) GetStoreAvailability(ctx context.Context, req *pb.GetStoreAvailabilityRequest) (*pb.StoreAvailability, error) {
+	a, err := s.service.GetInventoryAvailable(ctx, int(req.StoreId), int(req.FilmId))
+	if err != nil {
+		return nil, statusError(err, "Failed to fetch inventory availability")
+	}
+	return toStoreAvailability(a), nil
+}
+
+func toStoreAvailability(a inventory.InventoryAvailability) *pb.StoreAvailability {
+	ids := make([]int32, len(a.AvailableInventoryIDs))
+	for i, id := range a.AvailableInventoryIDs {
+		ids[i] = int32(id)
+	}
+	return &pb.StoreAvailability{
+		StoreId:               int32(a.StoreID),
+		FilmId:                int32(a.FilmID),
+		Title:                 a.Title,
+		Available:             a.Available,
+		AvailableInventoryIds: ids,
+		AvailableCopies:       int32(a.AvailableCopies),
+		HeldCopies:            int32(a.HeldCopies),
+		TotalCopies:           int32(a.TotalCopies),
+		NextReturnDue:         timestamp(a.NextReturnDue),
+	}
+}
@@ -0,0 +1,79 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/payment"
+	pb "github.com/rstoltzm-profile/video-rental-api/pkg/pb/videorental/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type paymentServer struct {
+	pb.UnimplementedPaymentServiceServer
+	service payment.Service
+}
+
+func (s *paymentServer) ListPayments(ctx context.Context, req *pb.ListPaymentsRequest) (*pb.ListPaymentsResponse, error) {
+	p, err := page(req.Page)
+	if err != nil {
+		return nil, err
+	}
+	filter := payment.PaymentFilter{
+		CustomerID: intPtr(req.CustomerId),
+		StaffID:    intPtr(req.StaffId),
+		StoreID:    intPtr(req.StoreId),
+		Page:       p,
+	}
+	if req.From != nil {
+		from := req.From.AsTime()
+		filter.From = &from
+	}
+	if req.To != nil {
+		to := req.To.AsTime()
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, status.Error(codes.InvalidArgument, "from must be before to")
+	}
+
+	payments, total, err := s.service.GetPayments(ctx, filter)
+	if err != nil {
+		return nil, statusError(err, "Failed to fetch payments")
+	}
+	resp := &pb.ListPaymentsResponse{Total: int32(total)}
+	for p, err := range payments {
+		if err != nil {
+			return nil, statusError(err, "Failed to fetch payments")
+		}
+		resp.Payments = append(resp.Payments, &pb.Payment{
+			Id:          int32(p.ID),
+			CustomerId:  int32(p.CustomerID),
+			StaffId:     int32(p.StaffID),
+			RentalId:    int32(p.RentalID),
+			StoreId:     int32(p.StoreID),
+			Amount:      p.Amount,
+			PaymentDate: timestamppb.New(p.PaymentDate),
+		})
+	}
+	return resp, nil
+}
+
+func (s *paymentServer) CreatePayment(ctx context.Context, req *pb.CreatePaymentRequest) (*pb.CreatePaymentResponse, error) {
+	create := payment.Payment{
+		CustomerID: int(req.CustomerId),
+		StaffID:    int(req.StaffId),
+		RentalID:   int(req.RentalId),
+		Amount:     req.Amount,
+	}
+	if err := validate.Struct(create); err != nil {
+		return nil, validationError(err)
+	}
+
+	id, err := s.service.MakePayment(ctx, create)
+	if err != nil {
+		return nil, statusError(err, "Failed to make payment")
+	}
+	return &pb.CreatePaymentResponse{Id: int32(id)}, nil
+}
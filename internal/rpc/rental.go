@@ -0,0 +1,57 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/rental"
+	pb "github.com/rstoltzm-profile/video-rental-api/pkg/pb/videorental/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type rentalServer struct {
+	pb.UnimplementedRentalServiceServer
+	service rental.Service
+}
+
+func (s *rentalServer) ListRentals(req *pb.ListRentalsRequest, stream grpc.ServerStreamingServer[pb.Rental]) error {
+	for r, err := range s.service.EachRental(stream.Context(), req.Late) {
+		if err != nil {
+			return statusError(err, "Failed to fetch rentals")
+		}
+		if err := stream.Send(&pb.Rental{
+			FirstName:  r.FirstName,
+			LastName:   r.LastName,
+			Phone:      r.Phone,
+			RentalDate: timestamppb.New(r.RentalDate),
+			Title:      r.Title,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *rentalServer) CreateRental(ctx context.Context, req *pb.CreateRentalRequest) (*pb.CreateRentalResponse, error) {
+	create := rental.CreateRentalRequest{
+		InventoryID: int(req.InventoryId),
+		CustomerID:  int(req.CustomerId),
+		StaffID:     int(req.StaffId),
+	}
+	if err := validate.Struct(create); err != nil {
+		return nil, validationError(err)
+	}
+
+	id, err := s.service.CreateRental(ctx, create)
+	if err != nil {
+		return nil, statusError(err, "Failed to create rental")
+	}
+	return &pb.CreateRentalResponse{Id: int32(id)}, nil
+}
+
+func (s *rentalServer) ReturnRental(ctx context.Context, req *pb.ReturnRentalRequest) (*pb.ReturnRentalResponse, error) {
+	if err := s.service.ReturnRentalByID(ctx, int(req.Id)); err != nil {
+		return nil, statusError(err, "Failed to return rental")
+	}
+	return &pb.ReturnRentalResponse{}, nil
+}
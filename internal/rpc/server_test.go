@@ -0,0 +1,171 @@
+package rpc
+
+import (
+	"context"
+	"iter"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/apikey"
+	"github.com/rstoltzm-profile/video-rental-api/internal/customer"
+	"github.com/rstoltzm-profile/video-rental-api/internal/inventory"
+	"github.com/rstoltzm-profile/video-rental-api/internal/rental"
+	pb "github.com/rstoltzm-profile/video-rental-api/pkg/pb/videorental/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testKey = "secure-dev-key-123"
+
+// the mocks embed the service so only the methods a test calls are needed
+
+type mockCustomers struct {
+	customer.Service
+	mock.Mock
+}
+
+func (m *mockCustomers) GetCustomerByID(ctx context.Context, id int) (customer.Customer, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(customer.Customer), args.Error(1)
+}
+
+type mockInventory struct {
+	inventory.Service
+	mock.Mock
+}
+
+func (m *mockInventory) EachInventory(ctx context.Context, storeID *int) iter.Seq2[inventory.Inventory, error] {
+	args := m.Called(ctx, storeID)
+	items := args.Get(0).([]inventory.Inventory)
+	return func(yield func(inventory.Inventory, error) bool) {
+		for _, item := range items {
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
+
+type mockRentals struct {
+	rental.Service
+	mock.Mock
+}
+
+func (m *mockRentals) CreateRental(ctx context.Context, req rental.CreateRentalRequest) (int, error) {
+	args := m.Called(ctx, req)
+	return args.Int(0), args.Error(1)
+}
+
+// dial serves svc over an in-memory listener and returns a connection to it
+func dial(t *testing.T, svc Services) *grpc.ClientConn {
+	lis := bufconn.Listen(1 << 20)
+	server := NewServer(svc, apikey.NewChecker(testKey, nil))
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func withKey(key string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "x-api-key", key)
+}
+
+func TestServer_MissingAPIKey(t *testing.T) {
+	client := pb.NewCustomerServiceClient(dial(t, Services{}))
+
+	_, err := client.GetCustomer(context.Background(), &pb.GetCustomerRequest{Id: 1})
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, "Missing API key", status.Convert(err).Message())
+}
+
+func TestServer_InvalidAPIKeyOnStream(t *testing.T) {
+	client := pb.NewInventoryServiceClient(dial(t, Services{}))
+
+	stream, err := client.ListInventory(withKey("wrong"), &pb.ListInventoryRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, "Invalid API key", status.Convert(err).Message())
+}
+
+func TestServer_GetCustomer(t *testing.T) {
+	customers := new(mockCustomers)
+	client := pb.NewCustomerServiceClient(dial(t, Services{Customers: customers}))
+
+	updated := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+	customers.On("GetCustomerByID", mock.Anything, 1).Return(customer.Customer{ID: 1, FirstName: "MARY", LastUpdate: updated}, nil)
+	customers.On("GetCustomerByID", mock.Anything, 99999).Return(customer.Customer{}, customer.ErrCustomerNotFound)
+
+	c, err := client.GetCustomer(withKey(testKey), &pb.GetCustomerRequest{Id: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "MARY", c.FirstName)
+	assert.Equal(t, updated, c.LastUpdate.AsTime())
+	assert.NotEmpty(t, c.Etag)
+
+	_, err = client.GetCustomer(withKey(testKey), &pb.GetCustomerRequest{Id: 99999})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, "Customer not found", status.Convert(err).Message())
+}
+
+func TestServer_ListCustomersLimitTooLarge(t *testing.T) {
+	client := pb.NewCustomerServiceClient(dial(t, Services{}))
+
+	_, err := client.ListCustomers(withKey(testKey), &pb.ListCustomersRequest{Page: &pb.Page{Limit: 500}})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "limit must be between 1 and 100", status.Convert(err).Message())
+}
+
+func TestServer_ListInventoryStreams(t *testing.T) {
+	stock := new(mockInventory)
+	client := pb.NewInventoryServiceClient(dial(t, Services{Inventory: stock}))
+
+	storeID := 2
+	stock.On("EachInventory", mock.Anything, &storeID).Return([]inventory.Inventory{
+		{InventoryID: 1, FilmID: 1, StoreID: 2, Title: "ACADEMY DINOSAUR"},
+		{InventoryID: 2, FilmID: 1, StoreID: 2, Title: "ACADEMY DINOSAUR"},
+	})
+
+	store := int32(2)
+	stream, err := client.ListInventory(withKey(testKey), &pb.ListInventoryRequest{StoreId: &store})
+	require.NoError(t, err)
+	var ids []int32
+	for {
+		item, err := stream.Recv()
+		if err != nil {
+			break
+		}
+		ids = append(ids, item.InventoryId)
+	}
+
+	assert.Equal(t, []int32{1, 2}, ids)
+}
+
+func TestServer_CreateRentalErrors(t *testing.T) {
+	rentals := new(mockRentals)
+	client := pb.NewRentalServiceClient(dial(t, Services{Rentals: rentals}))
+
+	rentals.On("CreateRental", mock.Anything, rental.CreateRentalRequest{InventoryID: 1, CustomerID: 1, StaffID: 1}).Return(0, rental.ErrInventoryRented)
+
+	_, err := client.CreateRental(withKey(testKey), &pb.CreateRentalRequest{InventoryId: 1, CustomerId: 1, StaffId: 1})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = client.CreateRental(withKey(testKey), &pb.CreateRentalRequest{InventoryId: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	rentals.AssertNumberOfCalls(t, "CreateRental", 1)
+}
@@ -0,0 +1,566 @@
+package staff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/rstoltzm-profile/video-rental-api/internal/auth"
+	"github.com/rstoltzm-profile/video-rental-api/internal/etag"
+	"github.com/rstoltzm-profile/video-rental-api/internal/pagination"
+)
+
+var validate = validator.New()
+
+// pictureTypes are the image formats accepted for staff pictures, as sniffed
+// by http.DetectContentType.
+var pictureTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// writeError maps service errors to a status code, anything unknown is a 500
+// with the fallback message.
+func writeError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, ErrStaffNotFound):
+		http.Error(w, "Staff member not found", http.StatusNotFound)
+	case errors.Is(err, ErrNoPicture):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrInvalidReference):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrInvalidLogin), errors.Is(err, auth.ErrMissingStaff):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, ErrNotSelfOrManager):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ErrDuplicateUsername), errors.Is(err, ErrStaffIsManager):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, etag.ErrPreconditionFailed):
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+	default:
+		http.Error(w, fallback, http.StatusInternalServerError)
+	}
+}
+
+// GetStaff godoc
+// @Summary      List staff
+// @Description  Page through staff members. Total matches are in X-Total-Count.
+// @Tags         staff
+// @Produce      json
+// @Param        store_id  query  int     false  "Only staff of this store"
+// @Param        active    query  string  false  "true (default), false or all"
+// @Param        limit     query  int     false  "Page size, default 20, max 100"
+// @Param        offset    query  int     false  "Rows to skip"
+// @Success      200  {array}   staff.Staff
+// @Failure      400  {string}  string  "Invalid query parameter"
+// @Security     ApiKeyAuth
+// @Router       /v1/staff [get]
+func (h *Handler) GetStaff(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	filter, err := parseStaffFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	staff, total, err := h.service.GetStaff(r.Context(), filter)
+	if err != nil {
+		http.Error(w, "Failed to fetch staff", http.StatusInternalServerError)
+		return
+	}
+
+	pagination.WriteTotal(w, total)
+	if staff == nil {
+		staff = []Staff{}
+	}
+	json.NewEncoder(w).Encode(staff)
+}
+
+func parseStaffFilter(r *http.Request) (StaffFilter, error) {
+	q := r.URL.Query()
+	var filter StaffFilter
+
+	if v := q.Get("store_id"); v != "" {
+		storeID, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, fmt.Errorf("Invalid store_id")
+		}
+		filter.StoreID = &storeID
+	}
+
+	switch status := q.Get("active"); status {
+	case "", "true", "false":
+		active := status != "false"
+		filter.Active = &active
+	case "all":
+	default:
+		return filter, fmt.Errorf("active must be true, false or all")
+	}
+
+	var err error
+	filter.Page, err = pagination.Parse(r)
+	return filter, err
+}
+
+// GetStaffByID godoc
+// @Summary      Get staff member by ID
+// @Description  Get a staff member, without password or picture. The ETag is the version to send in If-Match when changing the staff member, If-None-Match with it answers 304 while they are unchanged.
+// @Tags         staff
+// @Produce      json
+// @Param        id             path      int     true   "Staff ID"
+// @Param        If-None-Match  header    string  false  "ETag of the copy the client has"
+// @Success      200  {object}  staff.Staff
+// @Success      304  {string}  string  "Not Modified"
+// @Failure      400  {string}  string  "Invalid staff ID"
+// @Failure      404  {string}  string  "Staff member not found"
+// @Security     ApiKeyAuth
+// @Router       /v1/staff/{id} [get]
+func (h *Handler) GetStaffByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid staff ID", http.StatusBadRequest)
+		return
+	}
+
+	staff, err := h.service.GetStaffByID(r.Context(), id)
+	if err != nil {
+		writeError(w, err, "Failed to fetch staff member")
+		return
+	}
+	if etag.NotModified(w, r, staff.LastUpdate) {
+		return
+	}
+
+	json.NewEncoder(w).Encode(staff)
+}
+
+// CreateStaff godoc
+// @Summary      Create staff member
+// @Description  Create a staff member, the password is stored as a bcrypt hash. Without address_id the store's address is used.
+// @Tags         staff
+// @Accept       json
+// @Produce      json
+// @Param        staff  body      staff.CreateStaffRequest  true  "Staff data"
+// @Success      201  {object}  staff.Staff
+// @Failure      400  {string}  string  "Invalid input"
+// @Failure      409  {string}  string  "Username already belongs to a staff member"
+// @Failure      500  {string}  string  "Failed to create staff member"
+// @Security     ApiKeyAuth
+// @Router       /v1/staff [post]
+func (h *Handler) CreateStaff(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var req CreateStaffRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		http.Error(w, fmt.Sprintf("Validation error: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	staff, err := h.service.CreateStaff(r.Context(), req)
+	if err != nil {
+		writeError(w, err, "Failed to create staff member")
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/v1/staff/%d", staff.ID))
+	etag.Set(w, staff.LastUpdate)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(staff)
+}
+
+// UpdateStaff godoc
+// @Summary      Update staff member
+// @Description  Partially update a staff member's names, email, address and username
+// @Tags         staff
+// @Accept       json
+// @Produce      json
+// @Param        id             path      int                       true  "Staff ID"
+// @Param        X-Staff-Token  header    string                    true  "Session token of the staff member or their store's manager"
+// @Param        If-Match       header    string                    true  "ETag from GET /v1/staff/{id}"
+// @Param        staff          body      staff.UpdateStaffRequest  true  "Fields to change"
+// @Success      200  {object}  staff.Staff
+// @Failure      400  {string}  string  "Invalid input"
+// @Failure      401  {string}  string  "Missing, invalid or expired X-Staff-Token"
+// @Failure      403  {string}  string  "Not the staff member or their store's manager"
+// @Failure      404  {string}  string  "Staff member not found"
+// @Failure      409  {string}  string  "Username already belongs to a staff member"
+// @Failure      412  {string}  string  "Staff member changed since it was read"
+// @Failure      428  {string}  string  "If-Match is required"
+// @Security     ApiKeyAuth
+// @Router       /v1/staff/{id} [patch]
+func (h *Handler) UpdateStaff(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid staff ID", http.StatusBadRequest)
+		return
+	}
+
+	actingStaffID, err := auth.StaffID(r, h.service)
+	if err != nil {
+		writeError(w, err, "Failed to update staff member")
+		return
+	}
+	match, err := etag.IfMatch(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusPreconditionRequired)
+		return
+	}
+
+	var req UpdateStaffRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		http.Error(w, fmt.Sprintf("Validation error: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.IsEmpty() {
+		http.Error(w, "Validation error: no fields to update", http.StatusBadRequest)
+		return
+	}
+
+	staff, err := h.service.UpdateStaff(r.Context(), id, actingStaffID, match, req)
+	if err != nil {
+		writeError(w, err, "Failed to update staff member")
+		return
+	}
+	etag.Set(w, staff.LastUpdate)
+
+	json.NewEncoder(w).Encode(staff)
+}
+
+// DeactivateStaff godoc
+// @Summary      Deactivate staff member
+// @Description  Soft delete a staff member, their rentals and payments are kept. Store managers must be replaced first.
+// @Tags         staff
+// @Param        id             path      int     true  "Staff ID"
+// @Param        X-Staff-Token  header    string  true  "Session token of the staff member or their store's manager"
+// @Param        If-Match       header    string  true  "ETag from GET /v1/staff/{id}"
+// @Success      204  {string}  string  "No Content"
+// @Failure      400  {string}  string  "Invalid staff ID"
+// @Failure      401  {string}  string  "Missing, invalid or expired X-Staff-Token"
+// @Failure      403  {string}  string  "Not the staff member or their store's manager"
+// @Failure      404  {string}  string  "Staff member not found"
+// @Failure      409  {string}  string  "Staff member manages a store"
+// @Failure      412  {string}  string  "Staff member changed since it was read"
+// @Failure      428  {string}  string  "If-Match is required"
+// @Security     ApiKeyAuth
+// @Router       /v1/staff/{id} [delete]
+func (h *Handler) DeactivateStaff(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid staff ID", http.StatusBadRequest)
+		return
+	}
+
+	actingStaffID, err := auth.StaffID(r, h.service)
+	if err != nil {
+		writeError(w, err, "Failed to deactivate staff member")
+		return
+	}
+	match, err := etag.IfMatch(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusPreconditionRequired)
+		return
+	}
+
+	if err := h.service.DeactivateStaff(r.Context(), id, actingStaffID, match); err != nil {
+		writeError(w, err, "Failed to deactivate staff member")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent) // 204
+}
+
+// ReactivateStaff godoc
+// @Summary      Reactivate staff member
+// @Description  Reactivate a deactivated staff member
+// @Tags         staff
+// @Produce      json
+// @Param        id             path      int     true  "Staff ID"
+// @Param        X-Staff-Token  header    string  true  "Session token of the staff member or their store's manager"
+// @Param        If-Match       header    string  true  "ETag from GET /v1/staff/{id}"
+// @Success      200  {object}  staff.Staff
+// @Failure      400  {string}  string  "Invalid staff ID"
+// @Failure      401  {string}  string  "Missing, invalid or expired X-Staff-Token"
+// @Failure      403  {string}  string  "Not the staff member or their store's manager"
+// @Failure      404  {string}  string  "Staff member not found"
+// @Failure      412  {string}  string  "Staff member changed since it was read"
+// @Failure      428  {string}  string  "If-Match is required"
+// @Security     ApiKeyAuth
+// @Router       /v1/staff/{id}/reactivate [post]
+func (h *Handler) ReactivateStaff(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid staff ID", http.StatusBadRequest)
+		return
+	}
+
+	actingStaffID, err := auth.StaffID(r, h.service)
+	if err != nil {
+		writeError(w, err, "Failed to reactivate staff member")
+		return
+	}
+	match, err := etag.IfMatch(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusPreconditionRequired)
+		return
+	}
+
+	staff, err := h.service.ReactivateStaff(r.Context(), id, actingStaffID, match)
+	if err != nil {
+		writeError(w, err, "Failed to reactivate staff member")
+		return
+	}
+	etag.Set(w, staff.LastUpdate)
+
+	json.NewEncoder(w).Encode(staff)
+}
+
+// MoveStaff godoc
+// @Summary      Move staff member
+// @Description  Move a staff member to another store. Store managers must be replaced first.
+// @Tags         staff
+// @Accept       json
+// @Produce      json
+// @Param        id             path      int                     true  "Staff ID"
+// @Param        X-Staff-Token  header    string                  true  "Session token of the staff member or their store's manager"
+// @Param        If-Match       header    string                  true  "ETag from GET /v1/staff/{id}"
+// @Param        store          body      staff.MoveStaffRequest  true  "New store"
+// @Success      200  {object}  staff.Staff
+// @Failure      400  {string}  string  "Invalid input"
+// @Failure      401  {string}  string  "Missing, invalid or expired X-Staff-Token"
+// @Failure      403  {string}  string  "Not the staff member or their store's manager"
+// @Failure      404  {string}  string  "Staff member not found"
+// @Failure      409  {string}  string  "Staff member manages a store"
+// @Failure      412  {string}  string  "Staff member changed since it was read"
+// @Failure      428  {string}  string  "If-Match is required"
+// @Security     ApiKeyAuth
+// @Router       /v1/staff/{id}/store [put]
+func (h *Handler) MoveStaff(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid staff ID", http.StatusBadRequest)
+		return
+	}
+
+	actingStaffID, err := auth.StaffID(r, h.service)
+	if err != nil {
+		writeError(w, err, "Failed to move staff member")
+		return
+	}
+	match, err := etag.IfMatch(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusPreconditionRequired)
+		return
+	}
+
+	var req MoveStaffRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		http.Error(w, fmt.Sprintf("Validation error: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	staff, err := h.service.MoveStaff(r.Context(), id, actingStaffID, match, req)
+	if err != nil {
+		writeError(w, err, "Failed to move staff member")
+		return
+	}
+	etag.Set(w, staff.LastUpdate)
+
+	json.NewEncoder(w).Encode(staff)
+}
+
+// SetPassword godoc
+// @Summary      Set staff password
+// @Description  Replace a staff member's password, stored as a bcrypt hash. Only the staff member or their store's manager can, the first password of a Pagila staff member is set with vrctl staff set-password.
+// @Tags         staff
+// @Accept       json
+// @Param        id             path      int                       true  "Staff ID"
+// @Param        X-Staff-Token  header    string                    true  "Session token of the staff member or their store's manager"
+// @Param        password       body      staff.SetPasswordRequest  true  "New password"
+// @Success      204  {string}  string  "No Content"
+// @Failure      400  {string}  string  "Invalid input"
+// @Failure      401  {string}  string  "Missing, invalid or expired X-Staff-Token"
+// @Failure      403  {string}  string  "Not the staff member or their store's manager"
+// @Failure      404  {string}  string  "Staff member not found"
+// @Security     ApiKeyAuth
+// @Router       /v1/staff/{id}/password [put]
+func (h *Handler) SetPassword(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid staff ID", http.StatusBadRequest)
+		return
+	}
+
+	actingStaffID, err := auth.StaffID(r, h.service)
+	if err != nil {
+		writeError(w, err, "Failed to set password")
+		return
+	}
+
+	var req SetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		http.Error(w, fmt.Sprintf("Validation error: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.SetPassword(r.Context(), id, actingStaffID, req.Password); err != nil {
+		writeError(w, err, "Failed to set password")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent) // 204
+}
+
+// OpenSession godoc
+// @Summary      Open staff session
+// @Description  Check a staff member's username and password and open a session. Send its token in X-Staff-Token on staff writes and on the store writes only a manager may make. Sessions last 12 hours and end when the password changes or the staff member is deactivated.
+// @Tags         staff
+// @Accept       json
+// @Produce      json
+// @Param        credentials  body      staff.LoginRequest  true  "Staff credentials"
+// @Success      201  {object}  staff.Session
+// @Failure      400  {string}  string  "Invalid input"
+// @Failure      401  {string}  string  "Invalid username or password"
+// @Failure      500  {string}  string  "Failed to open session"
+// @Security     ApiKeyAuth
+// @Router       /v1/staff/sessions [post]
+func (h *Handler) OpenSession(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		http.Error(w, fmt.Sprintf("Validation error: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	session, err := h.service.Login(r.Context(), req)
+	if err != nil {
+		writeError(w, err, "Failed to open session")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(session)
+}
+
+// GetPicture godoc
+// @Summary      Get staff picture
+// @Description  The staff member's profile picture as uploaded
+// @Tags         staff
+// @Produce      png,jpeg,gif,webp
+// @Param        id   path      int  true  "Staff ID"
+// @Success      200  {file}    file
+// @Failure      400  {string}  string  "Invalid staff ID"
+// @Failure      404  {string}  string  "Staff member or picture not found"
+// @Security     ApiKeyAuth
+// @Router       /v1/staff/{id}/picture [get]
+func (h *Handler) GetPicture(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid staff ID", http.StatusBadRequest)
+		return
+	}
+
+	picture, err := h.service.GetPicture(r.Context(), id)
+	if err != nil {
+		writeError(w, err, "Failed to fetch picture")
+		return
+	}
+
+	w.Header().Set("Content-Type", http.DetectContentType(picture))
+	w.Header().Set("Content-Length", strconv.Itoa(len(picture)))
+	w.Write(picture)
+}
+
+// SetPicture godoc
+// @Summary      Upload staff picture
+// @Description  Replace a staff member's profile picture with the raw image in the request body, PNG, JPEG, GIF or WebP up to 1MB
+// @Tags         staff
+// @Accept       png,jpeg,gif,webp
+// @Param        id   path      int  true  "Staff ID"
+// @Success      204  {string}  string  "No Content"
+// @Failure      400  {string}  string  "Invalid staff ID or empty body"
+// @Failure      404  {string}  string  "Staff member not found"
+// @Failure      413  {string}  string  "Picture too large"
+// @Failure      415  {string}  string  "Not a supported image"
+// @Security     ApiKeyAuth
+// @Router       /v1/staff/{id}/picture [put]
+func (h *Handler) SetPicture(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid staff ID", http.StatusBadRequest)
+		return
+	}
+
+	picture, err := io.ReadAll(r.Body)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, "Picture too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to read picture", http.StatusBadRequest)
+		return
+	}
+	if len(picture) == 0 {
+		http.Error(w, "Empty picture", http.StatusBadRequest)
+		return
+	}
+	if !pictureTypes[http.DetectContentType(picture)] {
+		http.Error(w, "Picture must be PNG, JPEG, GIF or WebP", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	if err := h.service.SetPicture(r.Context(), id, picture); err != nil {
+		writeError(w, err, "Failed to upload picture")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent) // 204
+}
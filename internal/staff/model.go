@@ -0,0 +1,74 @@
+package staff
+
+import (
+	"time"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/pagination"
+)
+
+// Staff never carries the password hash or the picture bytes, HasPicture says
+// whether GET /staff/{id}/picture has anything to return.
+type Staff struct {
+	ID         int       `json:"id"`
+	FirstName  string    `json:"first_name"`
+	LastName   string    `json:"last_name"`
+	Email      *string   `json:"email"`
+	StoreID    int       `json:"store_id"`
+	AddressID  int       `json:"address_id"`
+	Username   string    `json:"username"`
+	Active     bool      `json:"active"`
+	HasPicture bool      `json:"has_picture"`
+	LastUpdate time.Time `json:"last_update"`
+}
+
+type StaffFilter struct {
+	StoreID *int
+	Active  *bool // nil returns active and inactive staff
+	Page    pagination.Page
+}
+
+type CreateStaffRequest struct {
+	FirstName string `json:"first_name" validate:"required,min=1,max=45"`
+	LastName  string `json:"last_name" validate:"required,min=1,max=45"`
+	Email     string `json:"email" validate:"omitempty,email,max=50"`
+	StoreID   int    `json:"store_id" validate:"required,gt=0"`
+	AddressID *int   `json:"address_id" validate:"omitempty,gt=0"` // defaults to the store's address
+	Username  string `json:"username" validate:"required,min=1,max=16"`
+	Password  string `json:"password" validate:"required,min=8,max=72"`
+}
+
+// UpdateStaffRequest is a partial update, nil fields are left unchanged.
+type UpdateStaffRequest struct {
+	FirstName *string `json:"first_name" validate:"omitempty,min=1,max=45"`
+	LastName  *string `json:"last_name" validate:"omitempty,min=1,max=45"`
+	Email     *string `json:"email" validate:"omitempty,email,max=50"`
+	AddressID *int    `json:"address_id" validate:"omitempty,gt=0"`
+	Username  *string `json:"username" validate:"omitempty,min=1,max=16"`
+}
+
+func (r UpdateStaffRequest) IsEmpty() bool {
+	return r.FirstName == nil && r.LastName == nil && r.Email == nil && r.AddressID == nil && r.Username == nil
+}
+
+type MoveStaffRequest struct {
+	StoreID int `json:"store_id" validate:"required,gt=0"`
+}
+
+// LoginRequest is a staff member's credentials, exchanged for a session
+type LoginRequest struct {
+	Username string `json:"username" validate:"required,max=16"`
+	Password string `json:"password" validate:"required,max=72"`
+}
+
+// Session says which staff member is at the counter. Its token goes in
+// X-Staff-Token on the store writes only a manager may make.
+type Session struct {
+	Token     string    `json:"token"`
+	StaffID   int       `json:"staff_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// bcrypt only uses the first 72 bytes of a password
+type SetPasswordRequest struct {
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
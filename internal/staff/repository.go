@@ -0,0 +1,380 @@
+package staff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rstoltzm-profile/video-rental-api/internal/db"
+)
+
+// staffSelect reads staff without the password hash or picture bytes. Queries
+// that return written rows alias their CTE as staff and reuse it.
+const staffSelect = `
+	SELECT
+		staff.staff_id,
+		staff.first_name,
+		staff.last_name,
+		staff.email,
+		staff.store_id,
+		staff.address_id,
+		staff.username,
+		staff.active,
+		staff.picture IS NOT NULL AS has_picture,
+		staff.last_update
+	FROM %s staff
+`
+
+// staffWhere is shared by the list page and count queries
+const staffWhere = `
+	WHERE ($1::int IS NULL OR staff.store_id = $1)
+		AND ($2::bool IS NULL OR staff.active = $2)
+`
+
+type StaffReader interface {
+	GetStaff(ctx context.Context, filter StaffFilter) ([]Staff, int, error)
+	GetByID(ctx context.Context, id int) (Staff, error)
+	GetPicture(ctx context.Context, id int) ([]byte, error)
+	UsernameExists(ctx context.Context, username string, excludeID int) (bool, error)
+	IsStoreManager(ctx context.Context, id int) (bool, error)
+	ManagesStore(ctx context.Context, id int, storeID int) (bool, error)
+	GetStoreAddressID(ctx context.Context, storeID int) (int, error)
+	GetInventoryStoreID(ctx context.Context, inventoryID int) (int, error)
+	GetRentalStoreID(ctx context.Context, rentalID int) (int, error)
+	GetLogin(ctx context.Context, username string) (int, string, error)
+	GetSessionStaffID(ctx context.Context, tokenHash []byte) (int, error)
+}
+
+type StaffWriter interface {
+	InsertStaff(ctx context.Context, req CreateStaffRequest, addressID int, passwordHash string) (Staff, error)
+	UpdateStaff(ctx context.Context, id int, req UpdateStaffRequest) (Staff, error)
+	SetActive(ctx context.Context, id int, active bool) (Staff, error)
+	UpdateStore(ctx context.Context, id int, storeID int) (Staff, error)
+	UpdatePassword(ctx context.Context, id int, passwordHash string) error
+	UpdatePicture(ctx context.Context, id int, picture []byte) error
+	LockStaff(ctx context.Context, id int) (time.Time, error)
+	InsertSession(ctx context.Context, staffID int, tokenHash []byte, expiresAt time.Time) error
+}
+
+type Repository interface {
+	StaffReader
+	StaffWriter
+	TransactionManager
+}
+
+type TransactionManager interface {
+	BeginTx(ctx context.Context) (pgx.Tx, error)
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+	Lock(ctx context.Context, key string) error
+}
+
+type repository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRepository(pool *pgxpool.Pool) Repository {
+	return &repository{pool: pool}
+}
+
+func (r *repository) BeginTx(ctx context.Context) (pgx.Tx, error) {
+	return r.pool.Begin(ctx)
+}
+
+func (r *repository) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return db.WithTx(ctx, r.pool, fn)
+}
+
+func (r *repository) Lock(ctx context.Context, key string) error {
+	return db.Lock(ctx, r.conn(ctx), key)
+}
+
+// conn returns the transaction started by WithTx, or the pool
+func (r *repository) conn(ctx context.Context) db.DBTX {
+	return db.Conn(ctx, r.pool)
+}
+
+func scanStaff(row pgx.Row) (Staff, error) {
+	var s Staff
+	err := row.Scan(
+		&s.ID,
+		&s.FirstName,
+		&s.LastName,
+		&s.Email,
+		&s.StoreID,
+		&s.AddressID,
+		&s.Username,
+		&s.Active,
+		&s.HasPicture,
+		&s.LastUpdate,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return s, ErrStaffNotFound
+	}
+	return s, err
+}
+
+// mapWriteError turns foreign key violations on store_id / address_id into
+// ErrInvalidReference.
+func mapWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+		return ErrInvalidReference
+	}
+	return err
+}
+
+func (r *repository) GetStaff(ctx context.Context, filter StaffFilter) ([]Staff, int, error) {
+	args := []any{filter.StoreID, filter.Active}
+
+	var total int
+	err := r.conn(ctx).QueryRow(ctx, `SELECT COUNT(*) FROM staff`+staffWhere, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(staffSelect, `staff`) + staffWhere + `
+		ORDER BY staff.staff_id
+		LIMIT $3 OFFSET $4
+	`
+	rows, err := r.conn(ctx).Query(ctx, query, append(args, filter.Page.Limit, filter.Page.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var staff []Staff
+	for rows.Next() {
+		s, err := scanStaff(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		staff = append(staff, s)
+	}
+	return staff, total, rows.Err()
+}
+
+func (r *repository) GetByID(ctx context.Context, id int) (Staff, error) {
+	return scanStaff(r.conn(ctx).QueryRow(ctx, fmt.Sprintf(staffSelect, `staff`)+` WHERE staff.staff_id = $1`, id))
+}
+
+// GetPicture returns nil when the staff member has no picture
+func (r *repository) GetPicture(ctx context.Context, id int) ([]byte, error) {
+	var picture []byte
+	err := r.conn(ctx).QueryRow(ctx, `SELECT picture FROM staff WHERE staff_id = $1`, id).Scan(&picture)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrStaffNotFound
+	}
+	return picture, err
+}
+
+// UsernameExists ignores case and the staff member being updated
+func (r *repository) UsernameExists(ctx context.Context, username string, excludeID int) (bool, error) {
+	var exists bool
+	err := r.conn(ctx).QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM staff WHERE lower(username) = lower($1) AND staff_id <> $2)`,
+		username, excludeID,
+	).Scan(&exists)
+	return exists, err
+}
+
+func (r *repository) IsStoreManager(ctx context.Context, id int) (bool, error) {
+	var manager bool
+	err := r.conn(ctx).QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM store WHERE manager_staff_id = $1)`,
+		id,
+	).Scan(&manager)
+	return manager, err
+}
+
+func (r *repository) ManagesStore(ctx context.Context, id int, storeID int) (bool, error) {
+	var manager bool
+	err := r.conn(ctx).QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM store WHERE store_id = $1 AND manager_staff_id = $2)`,
+		storeID, id,
+	).Scan(&manager)
+	return manager, err
+}
+
+func (r *repository) GetStoreAddressID(ctx context.Context, storeID int) (int, error) {
+	var addressID int
+	err := r.conn(ctx).QueryRow(ctx, `SELECT address_id FROM store WHERE store_id = $1`, storeID).Scan(&addressID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrInvalidReference
+	}
+	return addressID, err
+}
+
+func (r *repository) GetInventoryStoreID(ctx context.Context, inventoryID int) (int, error) {
+	var storeID int
+	err := r.conn(ctx).QueryRow(ctx, `SELECT store_id FROM inventory WHERE inventory_id = $1`, inventoryID).Scan(&storeID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrInvalidReference
+	}
+	return storeID, err
+}
+
+// GetRentalStoreID returns the store owning the rented copy
+func (r *repository) GetRentalStoreID(ctx context.Context, rentalID int) (int, error) {
+	var storeID int
+	err := r.conn(ctx).QueryRow(ctx, `
+		SELECT inventory.store_id
+		FROM rental
+		INNER JOIN inventory ON rental.inventory_id = inventory.inventory_id
+		WHERE rental.rental_id = $1
+	`, rentalID).Scan(&storeID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrInvalidReference
+	}
+	return storeID, err
+}
+
+// GetLogin returns the ID and password hash of the active staff member with
+// username, ignoring case
+func (r *repository) GetLogin(ctx context.Context, username string) (int, string, error) {
+	var id int
+	var hash string
+	err := r.conn(ctx).QueryRow(ctx,
+		`SELECT staff_id, password FROM staff WHERE lower(username) = lower($1) AND active`,
+		username,
+	).Scan(&id, &hash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, "", ErrStaffNotFound
+	}
+	return id, hash, err
+}
+
+// GetSessionStaffID returns the staff member an unexpired session belongs to,
+// as long as they are still active
+func (r *repository) GetSessionStaffID(ctx context.Context, tokenHash []byte) (int, error) {
+	var id int
+	err := r.conn(ctx).QueryRow(ctx, `
+		SELECT staff_session.staff_id
+		FROM staff_session
+		INNER JOIN staff ON staff_session.staff_id = staff.staff_id
+		WHERE staff_session.token_hash = $1
+			AND staff_session.expires_at > CURRENT_TIMESTAMP
+			AND staff.active
+	`, tokenHash).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrSessionNotFound
+	}
+	return id, err
+}
+
+func (r *repository) InsertSession(ctx context.Context, staffID int, tokenHash []byte, expiresAt time.Time) error {
+	_, err := r.conn(ctx).Exec(ctx,
+		`INSERT INTO staff_session (token_hash, staff_id, expires_at) VALUES ($1, $2, $3)`,
+		tokenHash, staffID, expiresAt,
+	)
+	return err
+}
+
+func (r *repository) InsertStaff(ctx context.Context, req CreateStaffRequest, addressID int, passwordHash string) (Staff, error) {
+	query := `
+		WITH inserted AS (
+			INSERT INTO staff (first_name, last_name, email, store_id, address_id, username, password, active, last_update)
+			VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, TRUE, CURRENT_TIMESTAMP)
+			RETURNING *
+		)
+	` + fmt.Sprintf(staffSelect, `inserted`)
+
+	s, err := scanStaff(r.conn(ctx).QueryRow(ctx, query,
+		req.FirstName, req.LastName, req.Email, req.StoreID, addressID, req.Username, passwordHash,
+	))
+	return s, mapWriteError(err)
+}
+
+func (r *repository) UpdateStaff(ctx context.Context, id int, req UpdateStaffRequest) (Staff, error) {
+	query := `
+		WITH updated AS (
+			UPDATE staff SET
+				first_name = COALESCE($2, first_name),
+				last_name = COALESCE($3, last_name),
+				email = COALESCE($4, email),
+				address_id = COALESCE($5, address_id),
+				username = COALESCE($6, username),
+				last_update = CURRENT_TIMESTAMP
+			WHERE staff_id = $1
+			RETURNING *
+		)
+	` + fmt.Sprintf(staffSelect, `updated`)
+
+	s, err := scanStaff(r.conn(ctx).QueryRow(ctx, query,
+		id, req.FirstName, req.LastName, req.Email, req.AddressID, req.Username,
+	))
+	return s, mapWriteError(err)
+}
+
+func (r *repository) SetActive(ctx context.Context, id int, active bool) (Staff, error) {
+	query := `
+		WITH updated AS (
+			UPDATE staff SET active = $2, last_update = CURRENT_TIMESTAMP
+			WHERE staff_id = $1
+			RETURNING *
+		)
+	` + fmt.Sprintf(staffSelect, `updated`)
+
+	return scanStaff(r.conn(ctx).QueryRow(ctx, query, id, active))
+}
+
+func (r *repository) UpdateStore(ctx context.Context, id int, storeID int) (Staff, error) {
+	query := `
+		WITH updated AS (
+			UPDATE staff SET store_id = $2, last_update = CURRENT_TIMESTAMP
+			WHERE staff_id = $1
+			RETURNING *
+		)
+	` + fmt.Sprintf(staffSelect, `updated`)
+
+	s, err := scanStaff(r.conn(ctx).QueryRow(ctx, query, id, storeID))
+	return s, mapWriteError(err)
+}
+
+// UpdatePassword also ends the staff member's sessions, they were opened with
+// the old password
+func (r *repository) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
+	cmdTag, err := r.conn(ctx).Exec(ctx, `
+		WITH ended AS (
+			DELETE FROM staff_session WHERE staff_id = $1
+		)
+		UPDATE staff SET password = $2, last_update = CURRENT_TIMESTAMP WHERE staff_id = $1
+	`, id, passwordHash)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ErrStaffNotFound
+	}
+	return nil
+}
+
+func (r *repository) UpdatePicture(ctx context.Context, id int, picture []byte) error {
+	cmdTag, err := r.conn(ctx).Exec(ctx,
+		`UPDATE staff SET picture = $2, last_update = CURRENT_TIMESTAMP WHERE staff_id = $1`,
+		id, picture,
+	)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ErrStaffNotFound
+	}
+	return nil
+}
+
+// LockStaff locks the staff member's row until the transaction ends and
+// returns its last_update, the version If-Match is checked against.
+func (r *repository) LockStaff(ctx context.Context, id int) (time.Time, error) {
+	var lastUpdate time.Time
+	err := r.conn(ctx).QueryRow(ctx,
+		`SELECT last_update FROM staff WHERE staff_id = $1 FOR UPDATE`, id,
+	).Scan(&lastUpdate)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return lastUpdate, ErrStaffNotFound
+	}
+	return lastUpdate, err
+}
@@ -0,0 +1,381 @@
+package staff
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/auth"
+	"github.com/rstoltzm-profile/video-rental-api/internal/etag"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrStaffNotFound     = errors.New("staff member not found")
+	ErrNoPicture         = errors.New("staff member has no picture")
+	ErrDuplicateUsername = errors.New("username already belongs to a staff member")
+	ErrStaffIsManager    = errors.New("staff member manages a store, reassign the manager first")
+	ErrInvalidReference  = errors.New("invalid store, address, inventory or rental id")
+	ErrInvalidLogin      = errors.New("invalid username or password")
+	ErrSessionNotFound   = auth.ErrSessionNotFound
+	ErrNotSelfOrManager  = errors.New("only the staff member or their store's manager can change them")
+
+	// ErrStaffNotAllowed is wrapped by every reason a staff member can't
+	// record a rental or payment.
+	ErrStaffNotAllowed = errors.New("staff member cannot record this")
+	ErrStaffUnknown    = fmt.Errorf("%w: unknown staff member", ErrStaffNotAllowed)
+	ErrStaffInactive   = fmt.Errorf("%w: staff member is not active", ErrStaffNotAllowed)
+	ErrStaffWrongStore = fmt.Errorf("%w: staff member does not work at the store that owns the inventory", ErrStaffNotAllowed)
+)
+
+// SessionDuration is how long a staff session lasts, about a working day
+const SessionDuration = 12 * time.Hour
+
+type Service interface {
+	GetStaff(ctx context.Context, filter StaffFilter) ([]Staff, int, error)
+	GetStaffByID(ctx context.Context, id int) (Staff, error)
+	CreateStaff(ctx context.Context, req CreateStaffRequest) (Staff, error)
+	UpdateStaff(ctx context.Context, id int, actingStaffID int, match etag.Precondition, req UpdateStaffRequest) (Staff, error)
+	DeactivateStaff(ctx context.Context, id int, actingStaffID int, match etag.Precondition) error
+	ReactivateStaff(ctx context.Context, id int, actingStaffID int, match etag.Precondition) (Staff, error)
+	MoveStaff(ctx context.Context, id int, actingStaffID int, match etag.Precondition, req MoveStaffRequest) (Staff, error)
+	SetPassword(ctx context.Context, id int, actingStaffID int, password string) error
+	// ResetPassword sets a password without a session, for operators on the
+	// database, as Pagila's staff have no password a login can match
+	ResetPassword(ctx context.Context, id int, password string) error
+	GetPicture(ctx context.Context, id int) ([]byte, error)
+	SetPicture(ctx context.Context, id int, picture []byte) error
+	Login(ctx context.Context, req LoginRequest) (Session, error)
+	SessionStaff(ctx context.Context, token string) (int, error)
+
+	// CheckStaffForInventory and CheckStaffForRental fail with an error
+	// wrapping ErrStaffNotAllowed unless the staff member is active and works
+	// at the store that owns the copy.
+	CheckStaffForInventory(ctx context.Context, staffID, inventoryID int) error
+	CheckStaffForRental(ctx context.Context, staffID, rentalID int) error
+}
+
+type service struct {
+	reader StaffReader
+	writer StaffWriter
+	tx     TransactionManager
+}
+
+func NewService(reader StaffReader, writer StaffWriter, tx TransactionManager) Service {
+	return &service{
+		reader: reader,
+		writer: writer,
+		tx:     tx,
+	}
+}
+
+func (s *service) GetStaff(ctx context.Context, filter StaffFilter) ([]Staff, int, error) {
+	return s.reader.GetStaff(ctx, filter)
+}
+
+func (s *service) GetStaffByID(ctx context.Context, id int) (Staff, error) {
+	return s.reader.GetByID(ctx, id)
+}
+
+func (s *service) CreateStaff(ctx context.Context, req CreateStaffRequest) (Staff, error) {
+	hash, err := hashPassword(req.Password)
+	if err != nil {
+		return Staff{}, err
+	}
+
+	var staff Staff
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.checkUsername(ctx, req.Username, 0); err != nil {
+			return err
+		}
+
+		// New staff live at the store's address until told otherwise
+		addressID := 0
+		if req.AddressID != nil {
+			addressID = *req.AddressID
+		} else {
+			var err error
+			if addressID, err = s.reader.GetStoreAddressID(ctx, req.StoreID); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		staff, err = s.writer.InsertStaff(ctx, req, addressID, hash)
+		return err
+	})
+	return staff, err
+}
+
+func (s *service) UpdateStaff(ctx context.Context, id int, actingStaffID int, match etag.Precondition, req UpdateStaffRequest) (Staff, error) {
+	var staff Staff
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.checkSelfOrManager(ctx, id, actingStaffID); err != nil {
+			return err
+		}
+		if err := s.checkVersion(ctx, id, match); err != nil {
+			return err
+		}
+
+		if req.Username != nil {
+			if err := s.checkUsername(ctx, *req.Username, id); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		staff, err = s.writer.UpdateStaff(ctx, id, req)
+		return err
+	})
+	return staff, err
+}
+
+func (s *service) DeactivateStaff(ctx context.Context, id int, actingStaffID int, match etag.Precondition) error {
+	return s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.checkSelfOrManager(ctx, id, actingStaffID); err != nil {
+			return err
+		}
+		if err := s.checkVersion(ctx, id, match); err != nil {
+			return err
+		}
+		if _, err := s.lockNonManager(ctx, id); err != nil {
+			return err
+		}
+		_, err := s.writer.SetActive(ctx, id, false)
+		return err
+	})
+}
+
+func (s *service) ReactivateStaff(ctx context.Context, id int, actingStaffID int, match etag.Precondition) (Staff, error) {
+	var staff Staff
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.checkSelfOrManager(ctx, id, actingStaffID); err != nil {
+			return err
+		}
+		if err := s.checkVersion(ctx, id, match); err != nil {
+			return err
+		}
+
+		var err error
+		staff, err = s.writer.SetActive(ctx, id, true)
+		return err
+	})
+	return staff, err
+}
+
+func (s *service) MoveStaff(ctx context.Context, id int, actingStaffID int, match etag.Precondition, req MoveStaffRequest) (Staff, error) {
+	var staff Staff
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.checkSelfOrManager(ctx, id, actingStaffID); err != nil {
+			return err
+		}
+		if err := s.checkVersion(ctx, id, match); err != nil {
+			return err
+		}
+
+		current, err := s.lockNonManager(ctx, id)
+		if err != nil {
+			return err
+		}
+		if current.StoreID == req.StoreID {
+			staff = current
+			return nil
+		}
+
+		staff, err = s.writer.UpdateStore(ctx, id, req.StoreID)
+		return err
+	})
+	return staff, err
+}
+
+func (s *service) SetPassword(ctx context.Context, id int, actingStaffID int, password string) error {
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+	return s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.checkSelfOrManager(ctx, id, actingStaffID); err != nil {
+			return err
+		}
+		return s.writer.UpdatePassword(ctx, id, hash)
+	})
+}
+
+func (s *service) ResetPassword(ctx context.Context, id int, password string) error {
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+	return s.writer.UpdatePassword(ctx, id, hash)
+}
+
+// Login checks the password against the staff member's bcrypt hash and opens
+// a session. An unknown or inactive username and a wrong password all fail
+// with ErrInvalidLogin.
+func (s *service) Login(ctx context.Context, req LoginRequest) (Session, error) {
+	id, hash, err := s.reader.GetLogin(ctx, req.Username)
+	if errors.Is(err, ErrStaffNotFound) {
+		return Session{}, ErrInvalidLogin
+	}
+	if err != nil {
+		return Session{}, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(req.Password)); err != nil {
+		return Session{}, ErrInvalidLogin
+	}
+
+	session := Session{Token: rand.Text(), StaffID: id, ExpiresAt: time.Now().Add(SessionDuration)}
+	if err := s.writer.InsertSession(ctx, id, hashToken(session.Token), session.ExpiresAt); err != nil {
+		return Session{}, err
+	}
+	return session, nil
+}
+
+// SessionStaff returns the staff member a session token was issued to, or
+// ErrSessionNotFound once it has expired or they were deactivated.
+func (s *service) SessionStaff(ctx context.Context, token string) (int, error) {
+	return s.reader.GetSessionStaffID(ctx, hashToken(token))
+}
+
+func (s *service) GetPicture(ctx context.Context, id int) ([]byte, error) {
+	picture, err := s.reader.GetPicture(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(picture) == 0 {
+		return nil, ErrNoPicture
+	}
+	return picture, nil
+}
+
+func (s *service) SetPicture(ctx context.Context, id int, picture []byte) error {
+	return s.writer.UpdatePicture(ctx, id, picture)
+}
+
+func (s *service) CheckStaffForInventory(ctx context.Context, staffID, inventoryID int) error {
+	storeID, err := s.reader.GetInventoryStoreID(ctx, inventoryID)
+	if err != nil {
+		return err
+	}
+	return s.checkStaffAtStore(ctx, staffID, storeID)
+}
+
+func (s *service) CheckStaffForRental(ctx context.Context, staffID, rentalID int) error {
+	storeID, err := s.reader.GetRentalStoreID(ctx, rentalID)
+	if err != nil {
+		return err
+	}
+	return s.checkStaffAtStore(ctx, staffID, storeID)
+}
+
+func (s *service) checkStaffAtStore(ctx context.Context, staffID, storeID int) error {
+	staff, err := s.reader.GetByID(ctx, staffID)
+	if errors.Is(err, ErrStaffNotFound) {
+		return ErrStaffUnknown
+	}
+	if err != nil {
+		return err
+	}
+	if !staff.Active {
+		return ErrStaffInactive
+	}
+	if staff.StoreID != storeID {
+		return ErrStaffWrongStore
+	}
+	return nil
+}
+
+// checkSelfOrManager fails with ErrNotSelfOrManager unless actingStaffID is
+// the staff member or the manager of their store. Must run inside WithTx, the
+// store lock keeps a manager reassignment or a move from changing the answer
+// until the transaction ends.
+func (s *service) checkSelfOrManager(ctx context.Context, id int, actingStaffID int) error {
+	if id == actingStaffID {
+		return nil
+	}
+	staff, err := s.reader.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.tx.Lock(ctx, "store:"+strconv.Itoa(staff.StoreID)); err != nil {
+		return err
+	}
+
+	manager, err := s.reader.ManagesStore(ctx, actingStaffID, staff.StoreID)
+	if err != nil {
+		return err
+	}
+	if !manager {
+		return ErrNotSelfOrManager
+	}
+	return nil
+}
+
+// checkVersion locks the staff member and fails with
+// etag.ErrPreconditionFailed unless it is the version the client read. Must
+// run inside WithTx.
+func (s *service) checkVersion(ctx context.Context, id int, match etag.Precondition) error {
+	lastUpdate, err := s.writer.LockStaff(ctx, id)
+	if err != nil {
+		return err
+	}
+	return match.Check(lastUpdate)
+}
+
+// lockNonManager takes the lock of the staff member's store, the same one a
+// manager reassignment takes, and fails with ErrStaffIsManager if they manage
+// a store. Must run inside WithTx.
+func (s *service) lockNonManager(ctx context.Context, id int) (Staff, error) {
+	staff, err := s.reader.GetByID(ctx, id)
+	if err != nil {
+		return Staff{}, err
+	}
+	if err := s.tx.Lock(ctx, "store:"+strconv.Itoa(staff.StoreID)); err != nil {
+		return Staff{}, err
+	}
+
+	manager, err := s.reader.IsStoreManager(ctx, id)
+	if err != nil {
+		return Staff{}, err
+	}
+	if manager {
+		return Staff{}, ErrStaffIsManager
+	}
+	return staff, nil
+}
+
+// checkUsername fails with ErrDuplicateUsername if another staff member has
+// the username. Must run inside WithTx, the lock is held until the insert or
+// update commits.
+func (s *service) checkUsername(ctx context.Context, username string, staffID int) error {
+	if err := s.tx.Lock(ctx, "staff-username:"+strings.ToLower(username)); err != nil {
+		return err
+	}
+	exists, err := s.reader.UsernameExists(ctx, username, staffID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrDuplicateUsername
+	}
+	return nil
+}
+
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// hashToken is how a session token is stored, like API keys
+func hashToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
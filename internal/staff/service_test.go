@@ -0,0 +1,362 @@
+package staff
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rstoltzm-profile/video-rental-api/internal/etag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type mockStaffReader struct {
+	mock.Mock
+}
+
+func (m *mockStaffReader) GetStaff(ctx context.Context, filter StaffFilter) ([]Staff, int, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]Staff), args.Int(1), args.Error(2)
+}
+
+func (m *mockStaffReader) GetByID(ctx context.Context, id int) (Staff, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Staff), args.Error(1)
+}
+
+func (m *mockStaffReader) GetPicture(ctx context.Context, id int) ([]byte, error) {
+	args := m.Called(ctx, id)
+	picture, _ := args.Get(0).([]byte)
+	return picture, args.Error(1)
+}
+
+func (m *mockStaffReader) UsernameExists(ctx context.Context, username string, excludeID int) (bool, error) {
+	args := m.Called(ctx, username, excludeID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockStaffReader) IsStoreManager(ctx context.Context, id int) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockStaffReader) ManagesStore(ctx context.Context, id int, storeID int) (bool, error) {
+	args := m.Called(ctx, id, storeID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockStaffReader) GetStoreAddressID(ctx context.Context, storeID int) (int, error) {
+	args := m.Called(ctx, storeID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockStaffReader) GetInventoryStoreID(ctx context.Context, inventoryID int) (int, error) {
+	args := m.Called(ctx, inventoryID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockStaffReader) GetRentalStoreID(ctx context.Context, rentalID int) (int, error) {
+	args := m.Called(ctx, rentalID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockStaffReader) GetLogin(ctx context.Context, username string) (int, string, error) {
+	args := m.Called(ctx, username)
+	return args.Int(0), args.String(1), args.Error(2)
+}
+
+func (m *mockStaffReader) GetSessionStaffID(ctx context.Context, tokenHash []byte) (int, error) {
+	args := m.Called(ctx, tokenHash)
+	return args.Int(0), args.Error(1)
+}
+
+type mockStaffWriter struct {
+	mock.Mock
+}
+
+func (m *mockStaffWriter) InsertStaff(ctx context.Context, req CreateStaffRequest, addressID int, passwordHash string) (Staff, error) {
+	args := m.Called(ctx, req, addressID, passwordHash)
+	return args.Get(0).(Staff), args.Error(1)
+}
+
+func (m *mockStaffWriter) UpdateStaff(ctx context.Context, id int, req UpdateStaffRequest) (Staff, error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(Staff), args.Error(1)
+}
+
+func (m *mockStaffWriter) SetActive(ctx context.Context, id int, active bool) (Staff, error) {
+	args := m.Called(ctx, id, active)
+	return args.Get(0).(Staff), args.Error(1)
+}
+
+func (m *mockStaffWriter) UpdateStore(ctx context.Context, id int, storeID int) (Staff, error) {
+	args := m.Called(ctx, id, storeID)
+	return args.Get(0).(Staff), args.Error(1)
+}
+
+func (m *mockStaffWriter) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
+	args := m.Called(ctx, id, passwordHash)
+	return args.Error(0)
+}
+
+func (m *mockStaffWriter) UpdatePicture(ctx context.Context, id int, picture []byte) error {
+	args := m.Called(ctx, id, picture)
+	return args.Error(0)
+}
+
+func (m *mockStaffWriter) InsertSession(ctx context.Context, staffID int, tokenHash []byte, expiresAt time.Time) error {
+	args := m.Called(ctx, staffID, tokenHash, expiresAt)
+	return args.Error(0)
+}
+
+func (m *mockStaffWriter) LockStaff(ctx context.Context, id int) (time.Time, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+type mockTxManager struct{}
+
+func (m *mockTxManager) BeginTx(ctx context.Context) (pgx.Tx, error) {
+	return nil, nil
+}
+
+// WithTx runs fn straight away, there is no database behind the mocks
+func (m *mockTxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (m *mockTxManager) Lock(ctx context.Context, key string) error {
+	return nil
+}
+
+var version = time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+
+// ifMatch is the precondition of a write made against version
+func ifMatch(version time.Time) etag.Precondition {
+	r := httptest.NewRequest(http.MethodPatch, "/staff/1", nil)
+	r.Header.Set("If-Match", etag.Of(version))
+	match, _ := etag.IfMatch(r)
+	return match
+}
+
+func TestService_CreateStaff_HashesPassword(t *testing.T) {
+	reader := new(mockStaffReader)
+	writer := new(mockStaffWriter)
+	svc := NewService(reader, writer, &mockTxManager{})
+
+	req := CreateStaffRequest{FirstName: "Jon", LastName: "Stephens", StoreID: 2, Username: "jon", Password: "correct horse"}
+	reader.On("UsernameExists", mock.Anything, "jon", 0).Return(false, nil)
+	reader.On("GetStoreAddressID", mock.Anything, 2).Return(2, nil)
+	writer.On("InsertStaff", mock.Anything, req, 2, mock.MatchedBy(func(hash string) bool {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte("correct horse")) == nil
+	})).Return(Staff{ID: 3, StoreID: 2, AddressID: 2}, nil)
+
+	staff, err := svc.CreateStaff(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, staff.ID)
+	writer.AssertExpectations(t)
+}
+
+func TestService_CreateStaff_DuplicateUsername(t *testing.T) {
+	reader := new(mockStaffReader)
+	writer := new(mockStaffWriter)
+	svc := NewService(reader, writer, &mockTxManager{})
+
+	reader.On("UsernameExists", mock.Anything, "Mike", 0).Return(true, nil)
+
+	_, err := svc.CreateStaff(context.Background(), CreateStaffRequest{StoreID: 1, Username: "Mike", Password: "password123"})
+
+	assert.ErrorIs(t, err, ErrDuplicateUsername)
+	writer.AssertNotCalled(t, "InsertStaff", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_DeactivateStaff_Manager(t *testing.T) {
+	reader := new(mockStaffReader)
+	writer := new(mockStaffWriter)
+	svc := NewService(reader, writer, &mockTxManager{})
+
+	writer.On("LockStaff", mock.Anything, 1).Return(version, nil)
+	reader.On("GetByID", mock.Anything, 1).Return(Staff{ID: 1, StoreID: 1, Active: true}, nil)
+	reader.On("IsStoreManager", mock.Anything, 1).Return(true, nil)
+
+	err := svc.DeactivateStaff(context.Background(), 1, 1, ifMatch(version))
+
+	assert.ErrorIs(t, err, ErrStaffIsManager)
+	writer.AssertNotCalled(t, "SetActive", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_UpdateStaff_StaleVersion(t *testing.T) {
+	reader := new(mockStaffReader)
+	writer := new(mockStaffWriter)
+	svc := NewService(reader, writer, &mockTxManager{})
+
+	username := "jon"
+	writer.On("LockStaff", mock.Anything, 2).Return(version.Add(time.Second), nil)
+
+	_, err := svc.UpdateStaff(context.Background(), 2, 2, ifMatch(version), UpdateStaffRequest{Username: &username})
+
+	assert.ErrorIs(t, err, etag.ErrPreconditionFailed)
+	reader.AssertNotCalled(t, "UsernameExists", mock.Anything, mock.Anything, mock.Anything)
+	writer.AssertNotCalled(t, "UpdateStaff", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_MoveStaff(t *testing.T) {
+	reader := new(mockStaffReader)
+	writer := new(mockStaffWriter)
+	svc := NewService(reader, writer, &mockTxManager{})
+
+	writer.On("LockStaff", mock.Anything, 3).Return(version, nil)
+	reader.On("GetByID", mock.Anything, 3).Return(Staff{ID: 3, StoreID: 1, Active: true}, nil)
+	reader.On("ManagesStore", mock.Anything, 1, 1).Return(true, nil)
+	reader.On("IsStoreManager", mock.Anything, 3).Return(false, nil)
+	writer.On("UpdateStore", mock.Anything, 3, 2).Return(Staff{ID: 3, StoreID: 2, Active: true}, nil)
+
+	// moved by the manager of store 1
+	staff, err := svc.MoveStaff(context.Background(), 3, 1, ifMatch(version), MoveStaffRequest{StoreID: 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, staff.StoreID)
+	writer.AssertExpectations(t)
+}
+
+func TestService_SetPassword(t *testing.T) {
+	tests := []struct {
+		name    string
+		actor   int
+		manager bool
+		wantErr error
+	}{
+		{name: "the staff member", actor: 3},
+		{name: "their store's manager", actor: 1, manager: true},
+		{name: "other staff", actor: 4, wantErr: ErrNotSelfOrManager},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := new(mockStaffReader)
+			writer := new(mockStaffWriter)
+			svc := NewService(reader, writer, &mockTxManager{})
+
+			reader.On("GetByID", mock.Anything, 3).Return(Staff{ID: 3, StoreID: 1, Active: true}, nil)
+			reader.On("ManagesStore", mock.Anything, tt.actor, 1).Return(tt.manager, nil)
+			writer.On("UpdatePassword", mock.Anything, 3, mock.Anything).Return(nil)
+
+			err := svc.SetPassword(context.Background(), 3, tt.actor, "correct horse")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				writer.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			writer.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_GetPicture_None(t *testing.T) {
+	reader := new(mockStaffReader)
+	svc := NewService(reader, new(mockStaffWriter), &mockTxManager{})
+
+	reader.On("GetPicture", mock.Anything, 3).Return(nil, nil)
+
+	_, err := svc.GetPicture(context.Background(), 3)
+
+	assert.ErrorIs(t, err, ErrNoPicture)
+}
+
+func TestService_CheckStaffForInventory(t *testing.T) {
+	tests := []struct {
+		name    string
+		staff   Staff
+		err     error
+		wantErr error
+	}{
+		{name: "active staff at the store", staff: Staff{ID: 1, StoreID: 1, Active: true}},
+		{name: "inactive staff", staff: Staff{ID: 1, StoreID: 1, Active: false}, wantErr: ErrStaffInactive},
+		{name: "staff at another store", staff: Staff{ID: 1, StoreID: 2, Active: true}, wantErr: ErrStaffWrongStore},
+		{name: "unknown staff", err: ErrStaffNotFound, wantErr: ErrStaffUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := new(mockStaffReader)
+			svc := NewService(reader, new(mockStaffWriter), &mockTxManager{})
+
+			reader.On("GetInventoryStoreID", mock.Anything, 42).Return(1, nil)
+			reader.On("GetByID", mock.Anything, 1).Return(tt.staff, tt.err)
+
+			err := svc.CheckStaffForInventory(context.Background(), 1, 42)
+
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.ErrorIs(t, err, ErrStaffNotAllowed)
+		})
+	}
+}
+
+func TestService_Login(t *testing.T) {
+	reader := new(mockStaffReader)
+	writer := new(mockStaffWriter)
+	svc := NewService(reader, writer, &mockTxManager{})
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	reader.On("GetLogin", mock.Anything, "mike").Return(1, string(hash), nil)
+	writer.On("InsertSession", mock.Anything, 1, mock.Anything, mock.Anything).Return(nil)
+
+	session, err := svc.Login(context.Background(), LoginRequest{Username: "mike", Password: "correct horse"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, session.StaffID)
+	assert.NotEmpty(t, session.Token)
+	// only the hash of the token is stored
+	writer.AssertCalled(t, "InsertSession", mock.Anything, 1, hashToken(session.Token), session.ExpiresAt)
+}
+
+func TestService_Login_WrongPassword(t *testing.T) {
+	reader := new(mockStaffReader)
+	writer := new(mockStaffWriter)
+	svc := NewService(reader, writer, &mockTxManager{})
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	reader.On("GetLogin", mock.Anything, "mike").Return(1, string(hash), nil)
+
+	_, err := svc.Login(context.Background(), LoginRequest{Username: "mike", Password: "battery staple"})
+
+	assert.ErrorIs(t, err, ErrInvalidLogin)
+	writer.AssertNotCalled(t, "InsertSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_Login_UnknownUser(t *testing.T) {
+	reader := new(mockStaffReader)
+	svc := NewService(reader, new(mockStaffWriter), &mockTxManager{})
+
+	reader.On("GetLogin", mock.Anything, "nobody").Return(0, "", ErrStaffNotFound)
+
+	_, err := svc.Login(context.Background(), LoginRequest{Username: "nobody", Password: "correct horse"})
+
+	assert.ErrorIs(t, err, ErrInvalidLogin)
+}
+
+func TestService_SessionStaff(t *testing.T) {
+	reader := new(mockStaffReader)
+	svc := NewService(reader, new(mockStaffWriter), &mockTxManager{})
+
+	reader.On("GetSessionStaffID", mock.Anything, hashToken("token")).Return(2, nil)
+	reader.On("GetSessionStaffID", mock.Anything, hashToken("expired")).Return(0, ErrSessionNotFound)
+
+	id, err := svc.SessionStaff(context.Background(), "token")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, id)
+
+	_, err = svc.SessionStaff(context.Background(), "expired")
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+}
@@ -2,18 +2,258 @@ package store
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
-	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/rstoltzm-profile/video-rental-api/internal/auth"
+	"github.com/rstoltzm-profile/video-rental-api/internal/etag"
 )
 
+var validate = validator.New()
+
 type Handler struct {
-	service Service
+	service  Service
+	sessions auth.Sessions
+}
+
+// sessions identifies the staff member making manager-only changes
+func NewHandler(service Service, sessions auth.Sessions) *Handler {
+	return &Handler{service: service, sessions: sessions}
+}
+
+// writeError maps service errors to a status code, anything unknown is a 500
+// with the fallback message.
+func writeError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, ErrStoreNotFound):
+		http.Error(w, "Store not found", http.StatusNotFound)
+	case errors.Is(err, auth.ErrMissingStaff):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, ErrNotManager):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ErrStaffNotAtStore):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrAlreadyManager):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, etag.ErrPreconditionFailed):
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+	default:
+		http.Error(w, fallback, http.StatusInternalServerError)
+	}
 }
 
-func NewHandler(service Service) *Handler {
-	return &Handler{service: service}
+// GetStores godoc
+// @Summary      List stores
+// @Description  Every store with its address and manager
+// @Tags         stores
+// @Produce      json
+// @Success      200  {array}   store.Store
+// @Failure      500  {string}  string "Internal Server Error"
+// @Security     ApiKeyAuth
+// @Router       /v1/stores [get]
+func (h *Handler) GetStores(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	stores, err := h.service.GetStores(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to fetch stores", http.StatusInternalServerError)
+		return
+	}
+
+	if stores == nil {
+		stores = []Store{}
+	}
+	json.NewEncoder(w).Encode(stores)
+}
+
+// GetStoreByID godoc
+// @Summary      Get store by ID
+// @Description  A store with its address and manager. The ETag is the version to send in If-Match when changing the store, If-None-Match with it answers 304 while the store is unchanged.
+// @Tags         stores
+// @Produce      json
+// @Param        id             path      int     true   "Store ID"
+// @Param        If-None-Match  header    string  false  "ETag of the copy the client has"
+// @Success      200  {object}  store.Store
+// @Success      304  {string}  string "Not Modified"
+// @Failure      400  {string}  string "Invalid store ID"
+// @Failure      404  {string}  string "Store not found"
+// @Security     ApiKeyAuth
+// @Router       /v1/stores/{id} [get]
+func (h *Handler) GetStoreByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid store ID", http.StatusBadRequest)
+		return
+	}
+
+	store, err := h.service.GetStoreByID(r.Context(), id)
+	if err != nil {
+		writeError(w, err, "Failed to fetch store")
+		return
+	}
+	if etag.NotModified(w, r, store.LastUpdate) {
+		return
+	}
+
+	json.NewEncoder(w).Encode(store)
+}
+
+// GetStoreStaff godoc
+// @Summary      Get store staff
+// @Description  Staff roster of a store, is_manager marks the manager
+// @Tags         stores
+// @Produce      json
+// @Param        id   path      int  true  "Store ID"
+// @Success      200  {array}   store.StaffMember
+// @Failure      400  {string}  string "Invalid store ID"
+// @Failure      404  {string}  string "Store not found"
+// @Security     ApiKeyAuth
+// @Router       /v1/stores/{id}/staff [get]
+func (h *Handler) GetStoreStaff(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid store ID", http.StatusBadRequest)
+		return
+	}
+
+	staff, err := h.service.GetStoreStaff(r.Context(), id)
+	if err != nil {
+		writeError(w, err, "Failed to fetch store staff")
+		return
+	}
+
+	if staff == nil {
+		staff = []StaffMember{}
+	}
+	json.NewEncoder(w).Encode(staff)
+}
+
+// UpdateStore godoc
+// @Summary      Update store
+// @Description  Partially update a store's address and phone. Only the store's manager, identified by the staff session in X-Staff-Token, may do this.
+// @Tags         stores
+// @Accept       json
+// @Produce      json
+// @Param        id          path      int                       true  "Store ID"
+// @Param        X-Staff-Token  header    string                    true  "Session token from POST /v1/staff/sessions"
+// @Param        If-Match    header    string                    true  "ETag from GET /v1/stores/{id}"
+// @Param        store       body      store.UpdateStoreRequest  true  "Fields to change"
+// @Success      200  {object}  store.Store
+// @Failure      400  {string}  string "Invalid input"
+// @Failure      401  {string}  string "Missing, invalid or expired X-Staff-Token"
+// @Failure      403  {string}  string "Not the store manager"
+// @Failure      404  {string}  string "Store not found"
+// @Failure      412  {string}  string "Store changed since it was read"
+// @Failure      428  {string}  string "If-Match is required"
+// @Security     ApiKeyAuth
+// @Router       /v1/stores/{id} [patch]
+func (h *Handler) UpdateStore(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid store ID", http.StatusBadRequest)
+		return
+	}
+
+	staffID, err := auth.StaffID(r, h.sessions)
+	if err != nil {
+		writeError(w, err, "Failed to update store")
+		return
+	}
+	match, err := etag.IfMatch(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusPreconditionRequired)
+		return
+	}
+
+	var req UpdateStoreRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		http.Error(w, fmt.Sprintf("Validation error: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.IsEmpty() {
+		http.Error(w, "Validation error: no fields to update", http.StatusBadRequest)
+		return
+	}
+
+	store, err := h.service.UpdateStore(r.Context(), id, staffID, match, req)
+	if err != nil {
+		writeError(w, err, "Failed to update store")
+		return
+	}
+	etag.Set(w, store.LastUpdate)
+
+	json.NewEncoder(w).Encode(store)
+}
+
+// ReassignManager godoc
+// @Summary      Reassign store manager
+// @Description  Hand the store over to another active staff member of the store. Only the current manager, identified by the staff session in X-Staff-Token, may do this.
+// @Tags         stores
+// @Accept       json
+// @Produce      json
+// @Param        id          path      int                           true  "Store ID"
+// @Param        X-Staff-Token  header    string                        true  "Session token from POST /v1/staff/sessions"
+// @Param        If-Match    header    string                        true  "ETag from GET /v1/stores/{id}"
+// @Param        manager     body      store.ReassignManagerRequest  true  "New manager"
+// @Success      200  {object}  store.Store
+// @Failure      400  {string}  string "Invalid input or staff not at this store"
+// @Failure      401  {string}  string "Missing, invalid or expired X-Staff-Token"
+// @Failure      403  {string}  string "Not the store manager"
+// @Failure      404  {string}  string "Store not found"
+// @Failure      409  {string}  string "Staff member already manages a store"
+// @Failure      412  {string}  string "Store changed since it was read"
+// @Failure      428  {string}  string "If-Match is required"
+// @Security     ApiKeyAuth
+// @Router       /v1/stores/{id}/manager [put]
+func (h *Handler) ReassignManager(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid store ID", http.StatusBadRequest)
+		return
+	}
+
+	staffID, err := auth.StaffID(r, h.sessions)
+	if err != nil {
+		writeError(w, err, "Failed to reassign manager")
+		return
+	}
+	match, err := etag.IfMatch(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusPreconditionRequired)
+		return
+	}
+
+	var req ReassignManagerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		http.Error(w, fmt.Sprintf("Validation error: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	store, err := h.service.ReassignManager(r.Context(), id, staffID, match, req)
+	if err != nil {
+		writeError(w, err, "Failed to reassign manager")
+		return
+	}
+	etag.Set(w, store.LastUpdate)
+
+	json.NewEncoder(w).Encode(store)
 }
 
 // GetStoreInventorySummary godoc
@@ -25,24 +265,17 @@ func NewHandler(service Service) *Handler {
 // @Success      200  {array}   store.StoreInventorySummary
 // @Failure      400  {string}  string "Invalid store ID"
 // @Failure      500  {string}  string "Internal Server Error"
-// @Router       /stores/{id}/inventory/summary [get]
+// @Security     ApiKeyAuth
+// @Router       /v1/stores/{id}/inventory/summary [get]
 func (h *Handler) GetStoreInventorySummary(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-
-	path := strings.TrimPrefix(r.URL.Path, "/stores/")
-	parts := strings.Split(path, "/")
-
-	storeIDStr := parts[0]
-
-	var inventory []StoreInventorySummary
-	var err error
-	storeID := -1
-
-	if storeIDStr != "" {
-		storeID, _ = strconv.Atoi(storeIDStr)
-		inventory, err = h.service.GetStoreInventorySummary(r.Context(), storeID)
+	storeID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid store ID", http.StatusBadRequest)
+		return
 	}
 
+	inventory, err := h.service.GetStoreInventorySummary(r.Context(), storeID)
 	if err != nil {
 		http.Error(w, fmt.Sprint(err), http.StatusInternalServerError)
 		return
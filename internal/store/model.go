@@ -1,7 +1,54 @@
 package store
 
+import "time"
+
 type StoreInventorySummary struct {
 	StoreID    int    `json:"store_id"`
 	Title      string `json:"title"`
 	TitleCount int    `json:"title_count"`
 }
+
+type Store struct {
+	ID         int          `json:"id"`
+	Address    StoreAddress `json:"address"`
+	Manager    StaffMember  `json:"manager"`
+	LastUpdate time.Time    `json:"last_update"`
+}
+
+type StoreAddress struct {
+	Address    string  `json:"address"`
+	Address2   *string `json:"address2"`
+	District   string  `json:"district"`
+	City       string  `json:"city"`
+	Country    string  `json:"country"`
+	PostalCode *string `json:"postal_code"`
+	Phone      string  `json:"phone"`
+}
+
+type StaffMember struct {
+	ID        int     `json:"id"`
+	FirstName string  `json:"first_name"`
+	LastName  string  `json:"last_name"`
+	Email     *string `json:"email"`
+	Username  string  `json:"username"`
+	Active    bool    `json:"active"`
+	IsManager bool    `json:"is_manager"`
+}
+
+// UpdateStoreRequest is a partial update of the store's address, nil fields
+// are left unchanged.
+type UpdateStoreRequest struct {
+	Address    *string `json:"address" validate:"omitempty,min=1,max=50"`
+	Address2   *string `json:"address2" validate:"omitempty,max=50"`
+	District   *string `json:"district" validate:"omitempty,min=1,max=20"`
+	PostalCode *string `json:"postal_code" validate:"omitempty,min=4,max=10"`
+	Phone      *string `json:"phone" validate:"omitempty,min=1,max=20"`
+}
+
+func (r UpdateStoreRequest) IsEmpty() bool {
+	return r.Address == nil && r.Address2 == nil && r.District == nil && r.PostalCode == nil && r.Phone == nil
+}
+
+type ReassignManagerRequest struct {
+	StaffID int `json:"staff_id" validate:"required,gt=0"`
+}
@@ -2,22 +2,64 @@ package store
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rstoltzm-profile/video-rental-api/internal/db"
 )
 
+const storeSelect = `
+	SELECT
+		store.store_id,
+		address.address,
+		address.address2,
+		address.district,
+		city.city,
+		country.country,
+		address.postal_code,
+		address.phone,
+		staff.staff_id,
+		staff.first_name,
+		staff.last_name,
+		staff.email,
+		staff.username,
+		staff.active,
+		store.last_update
+	FROM
+		store
+		INNER JOIN address ON store.address_id = address.address_id
+		INNER JOIN city ON address.city_id = city.city_id
+		INNER JOIN country ON city.country_id = country.country_id
+		INNER JOIN staff ON store.manager_staff_id = staff.staff_id
+`
+
 type StoreReader interface {
 	CountTitlesByStore(ctx context.Context, storeID int) ([]StoreInventorySummary, error)
+	GetStores(ctx context.Context) ([]Store, error)
+	GetStoreByID(ctx context.Context, id int) (Store, error)
+	GetStaffByStoreID(ctx context.Context, id int) ([]StaffMember, error)
+	IsActiveStaffAtStore(ctx context.Context, storeID, staffID int) (bool, error)
+}
+
+type StoreWriter interface {
+	UpdateStoreAddress(ctx context.Context, id int, req UpdateStoreRequest) error
+	UpdateStoreManager(ctx context.Context, id int, staffID int) error
+	LockStore(ctx context.Context, id int) (time.Time, error)
 }
 
 type Repository interface {
 	StoreReader
+	StoreWriter
 	TransactionManager
 }
 
 type TransactionManager interface {
 	BeginTx(ctx context.Context) (pgx.Tx, error)
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+	Lock(ctx context.Context, key string) error
 }
 
 type repository struct {
@@ -32,6 +74,19 @@ func (r *repository) BeginTx(ctx context.Context) (pgx.Tx, error) {
 	return r.pool.Begin(ctx)
 }
 
+func (r *repository) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return db.WithTx(ctx, r.pool, fn)
+}
+
+func (r *repository) Lock(ctx context.Context, key string) error {
+	return db.Lock(ctx, r.conn(ctx), key)
+}
+
+// conn returns the transaction started by WithTx, or the pool
+func (r *repository) conn(ctx context.Context) db.DBTX {
+	return db.Conn(ctx, r.pool)
+}
+
 func (r *repository) CountTitlesByStore(ctx context.Context, storeID int) ([]StoreInventorySummary, error) {
 	query := `
 		SELECT
@@ -61,3 +116,153 @@ func (r *repository) CountTitlesByStore(ctx context.Context, storeID int) ([]Sto
 	}
 	return storeInventorySummary, nil
 }
+
+func scanStore(row pgx.Row) (Store, error) {
+	var s Store
+	err := row.Scan(
+		&s.ID,
+		&s.Address.Address,
+		&s.Address.Address2,
+		&s.Address.District,
+		&s.Address.City,
+		&s.Address.Country,
+		&s.Address.PostalCode,
+		&s.Address.Phone,
+		&s.Manager.ID,
+		&s.Manager.FirstName,
+		&s.Manager.LastName,
+		&s.Manager.Email,
+		&s.Manager.Username,
+		&s.Manager.Active,
+		&s.LastUpdate,
+	)
+	s.Manager.IsManager = true
+	if errors.Is(err, pgx.ErrNoRows) {
+		return s, ErrStoreNotFound
+	}
+	return s, err
+}
+
+func (r *repository) GetStores(ctx context.Context) ([]Store, error) {
+	rows, err := r.conn(ctx).Query(ctx, storeSelect+` ORDER BY store.store_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stores []Store
+	for rows.Next() {
+		s, err := scanStore(rows)
+		if err != nil {
+			return nil, err
+		}
+		stores = append(stores, s)
+	}
+	return stores, rows.Err()
+}
+
+func (r *repository) GetStoreByID(ctx context.Context, id int) (Store, error) {
+	return scanStore(r.conn(ctx).QueryRow(ctx, storeSelect+` WHERE store.store_id = $1`, id))
+}
+
+func (r *repository) GetStaffByStoreID(ctx context.Context, id int) ([]StaffMember, error) {
+	query := `
+		SELECT
+			staff.staff_id,
+			staff.first_name,
+			staff.last_name,
+			staff.email,
+			staff.username,
+			staff.active,
+			staff.staff_id = store.manager_staff_id AS is_manager
+		FROM
+			staff
+			INNER JOIN store ON staff.store_id = store.store_id
+		WHERE
+			staff.store_id = $1
+		ORDER BY
+			staff.staff_id
+	`
+	rows, err := r.conn(ctx).Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var staff []StaffMember
+	for rows.Next() {
+		var s StaffMember
+		if err := rows.Scan(&s.ID, &s.FirstName, &s.LastName, &s.Email, &s.Username, &s.Active, &s.IsManager); err != nil {
+			return nil, err
+		}
+		staff = append(staff, s)
+	}
+	return staff, rows.Err()
+}
+
+func (r *repository) IsActiveStaffAtStore(ctx context.Context, storeID, staffID int) (bool, error) {
+	var exists bool
+	err := r.conn(ctx).QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM staff WHERE staff_id = $1 AND store_id = $2 AND active)`,
+		staffID, storeID,
+	).Scan(&exists)
+	return exists, err
+}
+
+func (r *repository) UpdateStoreAddress(ctx context.Context, id int, req UpdateStoreRequest) error {
+	query := `
+		WITH updated AS (
+			UPDATE address SET
+				address = COALESCE($2, address),
+				address2 = COALESCE($3, address2),
+				district = COALESCE($4, district),
+				postal_code = COALESCE($5, postal_code),
+				phone = COALESCE($6, phone),
+				last_update = CURRENT_TIMESTAMP
+			WHERE address_id = (SELECT address_id FROM store WHERE store_id = $1)
+			RETURNING address_id
+		)
+		UPDATE store SET last_update = CURRENT_TIMESTAMP
+		WHERE store_id = $1 AND address_id IN (SELECT address_id FROM updated)
+	`
+	cmdTag, err := r.conn(ctx).Exec(ctx, query, id, req.Address, req.Address2, req.District, req.PostalCode, req.Phone)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ErrStoreNotFound
+	}
+	return nil
+}
+
+func (r *repository) UpdateStoreManager(ctx context.Context, id int, staffID int) error {
+	cmdTag, err := r.conn(ctx).Exec(ctx,
+		`UPDATE store SET manager_staff_id = $2, last_update = CURRENT_TIMESTAMP WHERE store_id = $1`,
+		id, staffID,
+	)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		// unique_violation, Pagila allows one store per manager
+		return ErrAlreadyManager
+	}
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ErrStoreNotFound
+	}
+	return nil
+}
+
+// LockStore locks the store's row until the transaction ends and returns its
+// last_update, the version If-Match is checked against.
+func (r *repository) LockStore(ctx context.Context, id int) (time.Time, error) {
+	var lastUpdate time.Time
+	err := r.conn(ctx).QueryRow(ctx,
+		`SELECT last_update FROM store WHERE store_id = $1 FOR UPDATE`, id,
+	).Scan(&lastUpdate)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return lastUpdate, ErrStoreNotFound
+	}
+	return lastUpdate, err
+}
@@ -2,20 +2,38 @@ package store
 
 import (
 	"context"
+	"errors"
+	"strconv"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/etag"
+)
+
+var (
+	ErrStoreNotFound   = errors.New("store not found")
+	ErrNotManager      = errors.New("only the store manager can do this")
+	ErrStaffNotAtStore = errors.New("new manager must be active staff at this store")
+	ErrAlreadyManager  = errors.New("staff member already manages a store")
 )
 
 type Service interface {
 	GetStoreInventorySummary(ctx context.Context, storeID int) ([]StoreInventorySummary, error)
+	GetStores(ctx context.Context) ([]Store, error)
+	GetStoreByID(ctx context.Context, id int) (Store, error)
+	GetStoreStaff(ctx context.Context, id int) ([]StaffMember, error)
+	UpdateStore(ctx context.Context, id int, actingStaffID int, match etag.Precondition, req UpdateStoreRequest) (Store, error)
+	ReassignManager(ctx context.Context, id int, actingStaffID int, match etag.Precondition, req ReassignManagerRequest) (Store, error)
 }
 
 type service struct {
 	reader StoreReader
+	writer StoreWriter
 	tx     TransactionManager
 }
 
-func NewService(reader StoreReader, tx TransactionManager) Service {
+func NewService(reader StoreReader, writer StoreWriter, tx TransactionManager) Service {
 	return &service{
 		reader: reader,
+		writer: writer,
 		tx:     tx,
 	}
 }
@@ -23,3 +41,94 @@ func NewService(reader StoreReader, tx TransactionManager) Service {
 func (s *service) GetStoreInventorySummary(ctx context.Context, storeID int) ([]StoreInventorySummary, error) {
 	return s.reader.CountTitlesByStore(ctx, storeID)
 }
+
+func (s *service) GetStores(ctx context.Context) ([]Store, error) {
+	return s.reader.GetStores(ctx)
+}
+
+func (s *service) GetStoreByID(ctx context.Context, id int) (Store, error) {
+	return s.reader.GetStoreByID(ctx, id)
+}
+
+func (s *service) GetStoreStaff(ctx context.Context, id int) ([]StaffMember, error) {
+	// 404 for an unknown store rather than an empty roster
+	if _, err := s.reader.GetStoreByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.reader.GetStaffByStoreID(ctx, id)
+}
+
+func (s *service) UpdateStore(ctx context.Context, id int, actingStaffID int, match etag.Precondition, req UpdateStoreRequest) (Store, error) {
+	var store Store
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.checkManager(ctx, id, actingStaffID); err != nil {
+			return err
+		}
+		if err := s.checkVersion(ctx, id, match); err != nil {
+			return err
+		}
+		if err := s.writer.UpdateStoreAddress(ctx, id, req); err != nil {
+			return err
+		}
+
+		var err error
+		store, err = s.reader.GetStoreByID(ctx, id)
+		return err
+	})
+	return store, err
+}
+
+func (s *service) ReassignManager(ctx context.Context, id int, actingStaffID int, match etag.Precondition, req ReassignManagerRequest) (Store, error) {
+	var store Store
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.checkManager(ctx, id, actingStaffID); err != nil {
+			return err
+		}
+		if err := s.checkVersion(ctx, id, match); err != nil {
+			return err
+		}
+
+		ok, err := s.reader.IsActiveStaffAtStore(ctx, id, req.StaffID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrStaffNotAtStore
+		}
+
+		if err := s.writer.UpdateStoreManager(ctx, id, req.StaffID); err != nil {
+			return err
+		}
+
+		store, err = s.reader.GetStoreByID(ctx, id)
+		return err
+	})
+	return store, err
+}
+
+// checkVersion locks the store and fails with etag.ErrPreconditionFailed
+// unless it is the version the client read. Must run inside WithTx.
+func (s *service) checkVersion(ctx context.Context, id int, match etag.Precondition) error {
+	lastUpdate, err := s.writer.LockStore(ctx, id)
+	if err != nil {
+		return err
+	}
+	return match.Check(lastUpdate)
+}
+
+// checkManager fails with ErrNotManager unless staffID manages the store. Must
+// run inside WithTx, the store lock keeps a concurrent reassignment from
+// changing the manager until the transaction ends.
+func (s *service) checkManager(ctx context.Context, id int, staffID int) error {
+	if err := s.tx.Lock(ctx, "store:"+strconv.Itoa(id)); err != nil {
+		return err
+	}
+	store, err := s.reader.GetStoreByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if store.Manager.ID != staffID {
+		return ErrNotManager
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package store
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rstoltzm-profile/video-rental-api/internal/etag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockStoreReader struct {
+	mock.Mock
+}
+
+func (m *mockStoreReader) CountTitlesByStore(ctx context.Context, storeID int) ([]StoreInventorySummary, error) {
+	args := m.Called(ctx, storeID)
+	return args.Get(0).([]StoreInventorySummary), args.Error(1)
+}
+
+func (m *mockStoreReader) GetStores(ctx context.Context) ([]Store, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]Store), args.Error(1)
+}
+
+func (m *mockStoreReader) GetStoreByID(ctx context.Context, id int) (Store, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Store), args.Error(1)
+}
+
+func (m *mockStoreReader) GetStaffByStoreID(ctx context.Context, id int) ([]StaffMember, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]StaffMember), args.Error(1)
+}
+
+func (m *mockStoreReader) IsActiveStaffAtStore(ctx context.Context, storeID, staffID int) (bool, error) {
+	args := m.Called(ctx, storeID, staffID)
+	return args.Bool(0), args.Error(1)
+}
+
+type mockStoreWriter struct {
+	mock.Mock
+}
+
+func (m *mockStoreWriter) UpdateStoreAddress(ctx context.Context, id int, req UpdateStoreRequest) error {
+	args := m.Called(ctx, id, req)
+	return args.Error(0)
+}
+
+func (m *mockStoreWriter) UpdateStoreManager(ctx context.Context, id int, staffID int) error {
+	args := m.Called(ctx, id, staffID)
+	return args.Error(0)
+}
+
+func (m *mockStoreWriter) LockStore(ctx context.Context, id int) (time.Time, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+type mockTxManager struct{}
+
+func (m *mockTxManager) BeginTx(ctx context.Context) (pgx.Tx, error) {
+	return nil, nil
+}
+
+// WithTx runs fn straight away, there is no database behind the mocks
+func (m *mockTxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (m *mockTxManager) Lock(ctx context.Context, key string) error {
+	return nil
+}
+
+var version = time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+
+// ifMatch is the precondition of a write made against version
+func ifMatch(version time.Time) etag.Precondition {
+	r := httptest.NewRequest(http.MethodPatch, "/stores/1", nil)
+	r.Header.Set("If-Match", etag.Of(version))
+	match, _ := etag.IfMatch(r)
+	return match
+}
+
+func TestService_UpdateStore_NotManager(t *testing.T) {
+	reader := new(mockStoreReader)
+	writer := new(mockStoreWriter)
+	svc := NewService(reader, writer, &mockTxManager{})
+
+	reader.On("GetStoreByID", mock.Anything, 1).Return(Store{ID: 1, Manager: StaffMember{ID: 1}}, nil)
+
+	phone := "5551234"
+	_, err := svc.UpdateStore(context.Background(), 1, 2, ifMatch(version), UpdateStoreRequest{Phone: &phone})
+
+	assert.ErrorIs(t, err, ErrNotManager)
+	writer.AssertNotCalled(t, "UpdateStoreAddress", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_UpdateStore(t *testing.T) {
+	reader := new(mockStoreReader)
+	writer := new(mockStoreWriter)
+	svc := NewService(reader, writer, &mockTxManager{})
+
+	phone := "5551234"
+	req := UpdateStoreRequest{Phone: &phone}
+	reader.On("GetStoreByID", mock.Anything, 1).Return(Store{ID: 1, Manager: StaffMember{ID: 1}}, nil)
+	writer.On("LockStore", mock.Anything, 1).Return(version, nil)
+	writer.On("UpdateStoreAddress", mock.Anything, 1, req).Return(nil)
+
+	store, err := svc.UpdateStore(context.Background(), 1, 1, ifMatch(version), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, store.ID)
+	writer.AssertExpectations(t)
+}
+
+func TestService_UpdateStore_StaleVersion(t *testing.T) {
+	reader := new(mockStoreReader)
+	writer := new(mockStoreWriter)
+	svc := NewService(reader, writer, &mockTxManager{})
+
+	phone := "5551234"
+	reader.On("GetStoreByID", mock.Anything, 1).Return(Store{ID: 1, Manager: StaffMember{ID: 1}}, nil)
+	writer.On("LockStore", mock.Anything, 1).Return(version.Add(time.Second), nil)
+
+	_, err := svc.UpdateStore(context.Background(), 1, 1, ifMatch(version), UpdateStoreRequest{Phone: &phone})
+
+	assert.ErrorIs(t, err, etag.ErrPreconditionFailed)
+	writer.AssertNotCalled(t, "UpdateStoreAddress", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_ReassignManager(t *testing.T) {
+	tests := []struct {
+		name         string
+		staffAtStore bool
+		updateErr    error
+		wantErr      error
+	}{
+		{name: "active staff at store", staffAtStore: true},
+		{name: "staff at another store", staffAtStore: false, wantErr: ErrStaffNotAtStore},
+		{name: "staff already manages a store", staffAtStore: true, updateErr: ErrAlreadyManager, wantErr: ErrAlreadyManager},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := new(mockStoreReader)
+			writer := new(mockStoreWriter)
+			svc := NewService(reader, writer, &mockTxManager{})
+
+			reader.On("GetStoreByID", mock.Anything, 1).Return(Store{ID: 1, Manager: StaffMember{ID: 1}}, nil)
+			writer.On("LockStore", mock.Anything, 1).Return(version, nil)
+			reader.On("IsActiveStaffAtStore", mock.Anything, 1, 3).Return(tt.staffAtStore, nil)
+			writer.On("UpdateStoreManager", mock.Anything, 1, 3).Return(tt.updateErr)
+
+			_, err := svc.ReassignManager(context.Background(), 1, 1, ifMatch(version), ReassignManagerRequest{StaffID: 3})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			if !tt.staffAtStore {
+				writer.AssertNotCalled(t, "UpdateStoreManager", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestService_GetStoreStaff_UnknownStore(t *testing.T) {
+	reader := new(mockStoreReader)
+	svc := NewService(reader, new(mockStoreWriter), &mockTxManager{})
+
+	reader.On("GetStoreByID", mock.Anything, 99).Return(Store{}, ErrStoreNotFound)
+
+	_, err := svc.GetStoreStaff(context.Background(), 99)
+
+	assert.ErrorIs(t, err, ErrStoreNotFound)
+	reader.AssertNotCalled(t, "GetStaffByStoreID", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,206 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	MaxAttempts = 8 // a delivery fails for good after this many tries
+
+	fanOutBatch     = 500
+	deliveryBatch   = 20
+	deliveryLease   = time.Minute      // longer than sendTimeout, a dead dispatcher's claims expire
+	sendTimeout     = 10 * time.Second // per POST to a subscriber
+	retryBase       = 30 * time.Second // first retry delay, doubled every attempt
+	retryMax        = time.Hour
+	overdueInterval = time.Minute
+	recordTimeout   = 10 * time.Second
+	maxErrorBody    = 512 // bytes of a failed response kept in last_error
+)
+
+// Sign returns the X-Webhook-Signature value for body sent at timestamp:
+// t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>" keyed with secret>.
+// Subscribers recompute it to check the request came from us and reject old
+// timestamps to stop replays.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Dispatcher turns outbox events into deliveries and sends them. Several
+// servers can run one against the same database, claims skip deliveries
+// another dispatcher holds.
+type Dispatcher struct {
+	outbox   Outbox
+	client   *http.Client
+	interval time.Duration
+}
+
+func NewDispatcher(outbox Outbox, interval time.Duration, guard Guard) *Dispatcher {
+	return &Dispatcher{
+		outbox:   outbox,
+		client:   guard.client(),
+		interval: interval,
+	}
+}
+
+// Run dispatches every interval, and looks for newly overdue rentals every
+// minute, until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	overdue := time.NewTicker(overdueInterval)
+	defer overdue.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-overdue.C:
+			queued, err := d.outbox.QueueOverdueRentals(ctx)
+			if err != nil {
+				log.Printf("Webhook dispatcher failed to queue overdue rentals: %v", err)
+			}
+			if queued > 0 {
+				log.Printf("Webhook dispatcher queued %d overdue rentals", queued)
+			}
+		case <-ticker.C:
+			d.dispatch(ctx)
+		}
+	}
+}
+
+// dispatch fans out new events then sends due deliveries until none are left
+func (d *Dispatcher) dispatch(ctx context.Context) {
+	for {
+		n, err := d.outbox.FanOutEvents(ctx, fanOutBatch)
+		if err != nil {
+			log.Printf("Webhook dispatcher failed to fan out events: %v", err)
+			break
+		}
+		if n < fanOutBatch {
+			break
+		}
+	}
+
+	for ctx.Err() == nil {
+		claimed, err := d.outbox.ClaimDeliveries(ctx, deliveryBatch, deliveryLease)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Webhook dispatcher failed to claim deliveries: %v", err)
+			}
+			return
+		}
+
+		var wg sync.WaitGroup
+		for _, o := range claimed {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				d.deliver(ctx, o)
+			}()
+		}
+		wg.Wait()
+
+		if len(claimed) < deliveryBatch {
+			return
+		}
+	}
+}
+
+// deliver sends one delivery and records the outcome
+func (d *Dispatcher) deliver(ctx context.Context, o Outgoing) {
+	result := d.send(ctx, o)
+	if ctx.Err() != nil {
+		// the server is stopping, the lease runs out and the attempt is retried
+		return
+	}
+
+	recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), recordTimeout)
+	defer cancel()
+	if err := d.outbox.RecordAttempt(recordCtx, o.DeliveryID, o.Attempt, result); err != nil {
+		log.Printf("Failed to record webhook delivery %d: %v", o.DeliveryID, err)
+	}
+}
+
+// send POSTs the event and decides what happens next, a 2xx delivers it,
+// anything else is retried with backoff until MaxAttempts.
+func (d *Dispatcher) send(ctx context.Context, o Outgoing) AttemptResult {
+	var result AttemptResult
+	status, err := d.post(ctx, o)
+	if status != 0 {
+		result.StatusCode = &status
+	}
+	if err == nil {
+		result.Status = DeliveryDelivered
+		return result
+	}
+
+	message := err.Error()
+	result.Error = &message
+	if o.Attempt >= MaxAttempts {
+		result.Status = DeliveryFailed
+		return result
+	}
+	next := time.Now().Add(backoff(o.Attempt))
+	result.Status = DeliveryPending
+	result.NextAttemptAt = &next
+	return result
+}
+
+// post returns the response status, 0 when there was no response, and an
+// error unless the subscriber answered 2xx.
+func (d *Dispatcher) post(ctx context.Context, o Outgoing) (int, error) {
+	body, err := json.Marshal(o.Event)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "video-rental-api-webhooks")
+	req.Header.Set("X-Webhook-Event", o.Event.Type)
+	req.Header.Set("X-Webhook-Delivery", strconv.Itoa(o.DeliveryID))
+	req.Header.Set("X-Webhook-Signature", Sign(o.Secret, time.Now(), body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, resp.Body)
+		return resp.StatusCode, nil
+	}
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	return resp.StatusCode, fmt.Errorf("subscriber answered %s: %s", resp.Status, bytes.TrimSpace(snippet))
+}
+
+// backoff is the delay after a failed attempt, doubling from retryBase up to
+// retryMax.
+func backoff(attempt int) time.Duration {
+	delay := retryBase
+	for i := 1; i < attempt && delay < retryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, retryMax)
+}
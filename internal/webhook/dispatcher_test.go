@@ -0,0 +1,231 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockOutbox struct {
+	mock.Mock
+}
+
+func (m *mockOutbox) FanOutEvents(ctx context.Context, limit int) (int, error) {
+	args := m.Called(ctx, limit)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockOutbox) QueueOverdueRentals(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockOutbox) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]Outgoing, error) {
+	args := m.Called(ctx, limit, lease)
+	return args.Get(0).([]Outgoing), args.Error(1)
+}
+
+func (m *mockOutbox) RecordAttempt(ctx context.Context, id, attempt int, result AttemptResult) error {
+	return m.Called(ctx, id, attempt, result).Error(0)
+}
+
+// local lets the tests deliver to httptest servers on 127.0.0.1
+var local = Guard{AllowPrivate: true}
+
+func outgoing(url string, attempt int) Outgoing {
+	return Outgoing{
+		DeliveryID: 3,
+		Attempt:    attempt,
+		URL:        url,
+		Secret:     "0123456789abcdef",
+		Event: Event{
+			ID:        11,
+			Type:      RentalReturned,
+			CreatedAt: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC),
+			Data:      json.RawMessage(`{"rental_id":5}`),
+		},
+	}
+}
+
+func TestSign(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+
+	// echo -n '1700000000.{}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t,
+		"t=1700000000,v1=b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163",
+		Sign("secret", at, []byte("{}")))
+}
+
+func TestDispatcher_DeliversSignedEvent(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	outbox := new(mockOutbox)
+	d := NewDispatcher(outbox, time.Second, local)
+	o := outgoing(server.URL, 1)
+
+	outbox.On("RecordAttempt", mock.Anything, 3, 1, mock.MatchedBy(func(result AttemptResult) bool {
+		return result.Status == DeliveryDelivered && *result.StatusCode == http.StatusNoContent && result.Error == nil
+	})).Return(nil)
+
+	d.deliver(context.Background(), o)
+
+	outbox.AssertExpectations(t)
+	assert.Equal(t, RentalReturned, got.Header.Get("X-Webhook-Event"))
+	assert.Equal(t, "3", got.Header.Get("X-Webhook-Delivery"))
+	assert.JSONEq(t, `{"id":11,"type":"rental.returned","created_at":"2026-10-19T12:00:00Z","data":{"rental_id":5}}`, string(body))
+
+	// the subscriber can check the signature with its secret
+	signature := got.Header.Get("X-Webhook-Signature")
+	timestamp, _, _ := strings.Cut(strings.TrimPrefix(signature, "t="), ",")
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	assert.NoError(t, err)
+	assert.Equal(t, Sign(o.Secret, time.Unix(unix, 0), body), signature)
+}
+
+func TestDispatcher_RetriesWithBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down for maintenance", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	outbox := new(mockOutbox)
+	d := NewDispatcher(outbox, time.Second, local)
+	before := time.Now()
+
+	outbox.On("RecordAttempt", mock.Anything, 3, 2, mock.MatchedBy(func(result AttemptResult) bool {
+		return result.Status == DeliveryPending &&
+			*result.StatusCode == http.StatusServiceUnavailable &&
+			strings.Contains(*result.Error, "down for maintenance") &&
+			!result.NextAttemptAt.Before(before.Add(time.Minute))
+	})).Return(nil)
+
+	d.deliver(context.Background(), outgoing(server.URL, 2))
+
+	outbox.AssertExpectations(t)
+}
+
+func TestDispatcher_FailsAfterMaxAttempts(t *testing.T) {
+	outbox := new(mockOutbox)
+	d := NewDispatcher(outbox, time.Second, local)
+
+	// nothing listens on this port, there is no status code
+	outbox.On("RecordAttempt", mock.Anything, 3, MaxAttempts, mock.MatchedBy(func(result AttemptResult) bool {
+		return result.Status == DeliveryFailed && result.StatusCode == nil && result.Error != nil && result.NextAttemptAt == nil
+	})).Return(nil)
+
+	d.deliver(context.Background(), outgoing("http://127.0.0.1:1", MaxAttempts))
+
+	outbox.AssertExpectations(t)
+}
+
+func TestDispatcher_DispatchSendsUntilQueueIsEmpty(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	outbox := new(mockOutbox)
+	d := NewDispatcher(outbox, time.Second, local)
+
+	outbox.On("FanOutEvents", mock.Anything, fanOutBatch).Return(2, nil).Once()
+	outbox.On("ClaimDeliveries", mock.Anything, deliveryBatch, deliveryLease).Return([]Outgoing{outgoing(server.URL, 1)}, nil).Once()
+	outbox.On("RecordAttempt", mock.Anything, 3, 1, mock.Anything).Return(nil).Once()
+
+	d.dispatch(context.Background())
+
+	outbox.AssertExpectations(t)
+}
+
+func TestDispatcher_RefusesPrivateAddress(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	outbox := new(mockOutbox)
+	d := NewDispatcher(outbox, time.Second, Guard{})
+
+	// the URL was public when it was saved, it resolves to loopback now
+	outbox.On("RecordAttempt", mock.Anything, 3, 1, mock.MatchedBy(func(result AttemptResult) bool {
+		return result.StatusCode == nil && strings.Contains(*result.Error, ErrPrivateURL.Error())
+	})).Return(nil)
+
+	d.deliver(context.Background(), outgoing(server.URL, 1))
+
+	outbox.AssertExpectations(t)
+	assert.False(t, called)
+}
+
+func TestDispatcher_DoesNotFollowRedirects(t *testing.T) {
+	followed := false
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		followed = true
+	}))
+	defer internal.Close()
+	server := httptest.NewServer(http.RedirectHandler(internal.URL, http.StatusTemporaryRedirect))
+	defer server.Close()
+
+	outbox := new(mockOutbox)
+	d := NewDispatcher(outbox, time.Second, local)
+
+	outbox.On("RecordAttempt", mock.Anything, 3, 1, mock.MatchedBy(func(result AttemptResult) bool {
+		return result.Status == DeliveryPending && *result.StatusCode == http.StatusTemporaryRedirect
+	})).Return(nil)
+
+	d.deliver(context.Background(), outgoing(server.URL, 1))
+
+	outbox.AssertExpectations(t)
+	assert.False(t, followed)
+}
+
+func TestGuard_CheckURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://93.184.215.14/hooks", true},
+		{"http://[2606:2800:21f:cb07:6820:80da:af6b:8b2c]/hooks", true},
+		{"http://127.0.0.1:8080/hooks", false},
+		{"http://[::1]/hooks", false},
+		{"http://[::ffff:127.0.0.1]/hooks", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+		{"http://10.0.0.5/hooks", false},
+		{"http://172.16.0.1/hooks", false},
+		{"http://192.168.1.10/hooks", false},
+		{"http://100.64.0.1/hooks", false},
+		{"http://0.0.0.0/hooks", false},
+		{"http://[fd00::1]/hooks", false},
+	}
+
+	for _, tt := range tests {
+		err := Guard{}.CheckURL(context.Background(), tt.url)
+		if tt.want {
+			assert.NoError(t, err, tt.url)
+		} else {
+			assert.ErrorIs(t, err, ErrPrivateURL, tt.url)
+		}
+		assert.NoError(t, local.CheckURL(context.Background(), tt.url), tt.url)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, backoff(1))
+	assert.Equal(t, time.Minute, backoff(2))
+	assert.Equal(t, 4*time.Minute, backoff(4))
+	assert.Equal(t, time.Hour, backoff(20))
+}
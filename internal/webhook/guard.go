@@ -0,0 +1,99 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+)
+
+var ErrPrivateURL = errors.New("webhook URL must resolve to a public address")
+
+// sharedAddresses are neither private nor public, carrier-grade NAT and
+// "this network"
+var sharedAddresses = []netip.Prefix{
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("0.0.0.0/8"),
+}
+
+// Guard keeps webhooks from reaching the server's own network, like the
+// cloud metadata service on 169.254.169.254 or the database on a private
+// address. Subscription URLs are checked when they are saved and every
+// connection the dispatcher opens is checked again, a host can resolve to
+// something else by the time an event is sent. AllowPrivate turns both
+// checks off, for subscribers on localhost in development.
+type Guard struct {
+	AllowPrivate bool
+}
+
+// CheckURL resolves the URL's host and returns ErrPrivateURL unless every
+// address it has is public
+func (g Guard) CheckURL(ctx context.Context, rawURL string) error {
+	if g.AllowPrivate {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil {
+		return fmt.Errorf("%w, %s does not resolve", ErrPrivateURL, u.Hostname())
+	}
+	for _, addr := range addrs {
+		if !public(addr) {
+			return fmt.Errorf("%w, %s is %s", ErrPrivateURL, u.Hostname(), addr.Unmap())
+		}
+	}
+	return nil
+}
+
+// control is the dialer's Control, it runs on the resolved address right
+// before connecting
+func (g Guard) control(network, address string, _ syscall.RawConn) error {
+	if g.AllowPrivate {
+		return nil
+	}
+	addr, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !public(addr.Addr()) {
+		return fmt.Errorf("%w, refusing to connect to %s", ErrPrivateURL, addr.Addr().Unmap())
+	}
+	return nil
+}
+
+// client sends deliveries. It doesn't follow redirects, a 3xx is a failed
+// attempt, and doesn't use a proxy, which would be dialed instead of the
+// subscriber.
+func (g Guard) client() *http.Client {
+	dialer := &net.Dialer{Timeout: sendTimeout, Control: g.control}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   sendTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func public(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range sharedAddresses {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
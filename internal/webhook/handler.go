@@ -0,0 +1,270 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/rstoltzm-profile/video-rental-api/internal/export"
+	"github.com/rstoltzm-profile/video-rental-api/internal/pagination"
+)
+
+var validate = validator.New()
+
+var eventTypes = []string{RentalCreated, RentalReturned, RentalOverdue, PaymentCreated}
+
+type Handler struct {
+	service Service
+	guard   Guard
+}
+
+// guard checks subscription URLs before they are saved
+func NewHandler(service Service, guard Guard) *Handler {
+	return &Handler{service: service, guard: guard}
+}
+
+// writeError maps service errors to a status code, anything unknown is a 500
+// with the fallback message.
+func writeError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, ErrSubscriptionNotFound):
+		http.Error(w, "Webhook subscription not found", http.StatusNotFound)
+	case errors.Is(err, ErrPrivateURL):
+		http.Error(w, fmt.Sprintf("Validation error: %v", err), http.StatusBadRequest)
+	default:
+		http.Error(w, fallback, http.StatusInternalServerError)
+	}
+}
+
+// GetSubscriptions godoc
+// @Summary      List webhook subscriptions
+// @Description  Every webhook subscription, active or not. Secrets are not returned.
+// @Tags         webhooks
+// @Produce      json
+// @Success      200  {array}   webhook.Subscription
+// @Failure      500  {string}  string  "Failed to fetch webhook subscriptions"
+// @Security     ApiKeyAuth
+// @Router       /v1/webhooks [get]
+func (h *Handler) GetSubscriptions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	subscriptions, err := h.service.GetSubscriptions(r.Context())
+	if err != nil {
+		writeError(w, err, "Failed to fetch webhook subscriptions")
+		return
+	}
+	json.NewEncoder(w).Encode(subscriptions)
+}
+
+// GetSubscriptionByID godoc
+// @Summary      Get webhook subscription
+// @Description  Get a webhook subscription by ID. The secret is not returned.
+// @Tags         webhooks
+// @Produce      json
+// @Param        id   path      int  true  "Subscription ID"
+// @Success      200  {object}  webhook.Subscription
+// @Failure      400  {string}  string  "Invalid subscription ID"
+// @Failure      404  {string}  string  "Webhook subscription not found"
+// @Failure      500  {string}  string  "Failed to fetch webhook subscription"
+// @Security     ApiKeyAuth
+// @Router       /v1/webhooks/{id} [get]
+func (h *Handler) GetSubscriptionByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid subscription ID", http.StatusBadRequest)
+		return
+	}
+
+	subscription, err := h.service.GetSubscriptionByID(r.Context(), id)
+	if err != nil {
+		writeError(w, err, "Failed to fetch webhook subscription")
+		return
+	}
+	json.NewEncoder(w).Encode(subscription)
+}
+
+// CreateSubscription godoc
+// @Summary      Create webhook subscription
+// @Description  Register a URL for one or more events. Deliveries are signed with the secret, which is generated when not given and only returned here. The URL must resolve to public addresses, redirects are not followed.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        subscription  body      webhook.CreateSubscriptionRequest  true  "Subscription"
+// @Success      201  {object}  webhook.Subscription
+// @Failure      400  {string}  string  "Invalid input"
+// @Failure      500  {string}  string  "Failed to create webhook subscription"
+// @Security     ApiKeyAuth
+// @Router       /v1/webhooks [post]
+func (h *Handler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var req CreateSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		http.Error(w, fmt.Sprintf("Validation error: %v", err), http.StatusBadRequest)
+		return
+	}
+	if err := h.guard.CheckURL(r.Context(), req.URL); err != nil {
+		writeError(w, err, "Failed to check webhook URL")
+		return
+	}
+
+	subscription, err := h.service.CreateSubscription(r.Context(), req)
+	if err != nil {
+		writeError(w, err, "Failed to create webhook subscription")
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("/v1/webhooks/%d", subscription.ID))
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(subscription)
+}
+
+// UpdateSubscription godoc
+// @Summary      Update webhook subscription
+// @Description  Change a subscription's URL or events, or pause and resume it with active. A new URL must resolve to public addresses.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        id            path      int                                true  "Subscription ID"
+// @Param        subscription  body      webhook.UpdateSubscriptionRequest  true  "Fields to change"
+// @Success      200  {object}  webhook.Subscription
+// @Failure      400  {string}  string  "Invalid input"
+// @Failure      404  {string}  string  "Webhook subscription not found"
+// @Failure      500  {string}  string  "Failed to update webhook subscription"
+// @Security     ApiKeyAuth
+// @Router       /v1/webhooks/{id} [patch]
+func (h *Handler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid subscription ID", http.StatusBadRequest)
+		return
+	}
+
+	var req UpdateSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		http.Error(w, fmt.Sprintf("Validation error: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.IsEmpty() {
+		http.Error(w, "Validation error: no fields to update", http.StatusBadRequest)
+		return
+	}
+	if req.URL != nil {
+		if err := h.guard.CheckURL(r.Context(), *req.URL); err != nil {
+			writeError(w, err, "Failed to check webhook URL")
+			return
+		}
+	}
+
+	subscription, err := h.service.UpdateSubscription(r.Context(), id, req)
+	if err != nil {
+		writeError(w, err, "Failed to update webhook subscription")
+		return
+	}
+	json.NewEncoder(w).Encode(subscription)
+}
+
+// DeleteSubscription godoc
+// @Summary      Deactivate webhook subscription
+// @Description  Stop sending events to a subscription, its delivery log is kept
+// @Tags         webhooks
+// @Param        id   path      int  true  "Subscription ID"
+// @Success      204  {string}  string  "No Content"
+// @Failure      400  {string}  string  "Invalid subscription ID"
+// @Failure      404  {string}  string  "Webhook subscription not found"
+// @Failure      500  {string}  string  "Failed to delete webhook subscription"
+// @Security     ApiKeyAuth
+// @Router       /v1/webhooks/{id} [delete]
+func (h *Handler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid subscription ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteSubscription(r.Context(), id); err != nil {
+		writeError(w, err, "Failed to delete webhook subscription")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent) // 204
+}
+
+// GetDeliveries godoc
+// @Summary      List webhook deliveries
+// @Description  The delivery log of a subscription, newest first, with attempts and the last response. Total matches are in X-Total-Count.
+// @Tags         webhooks
+// @Produce      json,text/csv,application/x-ndjson
+// @Param        id      path      int     true   "Subscription ID"
+// @Param        status  query     string  false  "pending, delivered or failed"
+// @Param        event   query     string  false  "Event type, e.g. rental.created"
+// @Param        limit   query     int     false  "Page size (default 20, max 100)"
+// @Param        offset  query     int     false  "Rows to skip"
+// @Success      200  {array}   webhook.Delivery
+// @Failure      400  {string}  string  "Invalid input"
+// @Failure      404  {string}  string  "Webhook subscription not found"
+// @Failure      500  {string}  string  "Failed to fetch webhook deliveries"
+// @Security     ApiKeyAuth
+// @Router       /v1/webhooks/{id}/deliveries [get]
+func (h *Handler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	format := export.Negotiate(r)
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid subscription ID", http.StatusBadRequest)
+		return
+	}
+
+	filter, err := parseDeliveryFilter(r, format)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter.SubscriptionID = id
+
+	deliveries, total, err := h.service.GetDeliveries(r.Context(), filter)
+	if err != nil {
+		writeError(w, err, "Failed to fetch webhook deliveries")
+		return
+	}
+
+	pagination.WriteTotal(w, total)
+	export.Stream(w, format, deliveries, func(err error) {
+		http.Error(w, "Failed to fetch webhook deliveries", http.StatusInternalServerError)
+	})
+}
+
+func parseDeliveryFilter(r *http.Request, format export.Format) (DeliveryFilter, error) {
+	q := r.URL.Query()
+	filter := DeliveryFilter{Status: q.Get("status"), EventType: q.Get("event")}
+
+	switch filter.Status {
+	case "", DeliveryPending, DeliveryDelivered, DeliveryFailed:
+	default:
+		return filter, fmt.Errorf("status must be pending, delivered or failed")
+	}
+	if filter.EventType != "" && !slices.Contains(eventTypes, filter.EventType) {
+		return filter, fmt.Errorf("unknown event type %q", filter.EventType)
+	}
+
+	page, err := export.Page(r, format)
+	if err != nil {
+		return filter, err
+	}
+	filter.Page = page
+
+	return filter, nil
+}
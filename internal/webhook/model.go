@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/pagination"
+)
+
+// Event types subscribers can ask for
+const (
+	RentalCreated  = "rental.created"
+	RentalReturned = "rental.returned"
+	RentalOverdue  = "rental.overdue"
+	PaymentCreated = "payment.created"
+)
+
+const (
+	DeliveryPending   = "pending"   // waiting for its first or next attempt
+	DeliveryDelivered = "delivered" // the subscriber answered 2xx
+	DeliveryFailed    = "failed"    // out of attempts
+)
+
+// Subscription is a URL that receives events. Secret is only returned when
+// the subscription is created.
+type Subscription struct {
+	ID         int       `json:"id"`
+	URL        string    `json:"url"`
+	Events     []string  `json:"events"`
+	Secret     string    `json:"secret,omitempty"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUpdate time.Time `json:"last_update"`
+}
+
+type CreateSubscriptionRequest struct {
+	URL    string   `json:"url" validate:"required,http_url,max=2000"`
+	Events []string `json:"events" validate:"required,min=1,unique,dive,oneof=rental.created rental.returned rental.overdue payment.created"`
+	Secret string   `json:"secret" validate:"omitempty,min=16,max=128"` // generated when empty
+}
+
+// UpdateSubscriptionRequest is a partial update, nil fields are left
+// unchanged. Active false pauses deliveries, they resume when it is set back.
+type UpdateSubscriptionRequest struct {
+	URL    *string  `json:"url" validate:"omitempty,http_url,max=2000"`
+	Events []string `json:"events" validate:"omitempty,min=1,unique,dive,oneof=rental.created rental.returned rental.overdue payment.created"`
+	Active *bool    `json:"active"`
+}
+
+func (r UpdateSubscriptionRequest) IsEmpty() bool {
+	return r.URL == nil && r.Events == nil && r.Active == nil
+}
+
+// Delivery is one event sent, or being sent, to one subscription
+type Delivery struct {
+	ID             int        `json:"id"`
+	SubscriptionID int        `json:"subscription_id"`
+	EventID        int        `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	LastStatusCode *int       `json:"last_status_code"`
+	LastError      *string    `json:"last_error"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at"` // only while pending
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type DeliveryFilter struct {
+	SubscriptionID int
+	Status         string
+	EventType      string
+	Page           pagination.Page
+}
+
+// Event is the body POSTed to subscribers
+type Event struct {
+	ID        int             `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Outgoing is a claimed delivery with what is needed to send it
+type Outgoing struct {
+	DeliveryID int
+	Attempt    int
+	URL        string
+	Secret     string
+	Event      Event
+}
@@ -0,0 +1,294 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rstoltzm-profile/video-rental-api/internal/db"
+)
+
+const subscriptionColumns = `subscription_id, url, events, active, created_at, last_update`
+
+// deliveryWhere filters a subscription's deliveries, $1 subscription, $2
+// status and $3 event type, the last two may be empty.
+const deliveryWhere = `
+	FROM
+		webhook_delivery
+		INNER JOIN webhook_event ON webhook_delivery.event_id = webhook_event.event_id
+	WHERE
+		webhook_delivery.subscription_id = $1
+		AND ($2 = '' OR webhook_delivery.status = $2)
+		AND ($3 = '' OR webhook_event.type = $3)
+`
+
+type WebhookReader interface {
+	GetSubscriptions(ctx context.Context) ([]Subscription, error)
+	GetSubscriptionByID(ctx context.Context, id int) (Subscription, error)
+	CountDeliveries(ctx context.Context, filter DeliveryFilter) (int, error)
+	EachDelivery(ctx context.Context, filter DeliveryFilter) iter.Seq2[Delivery, error]
+}
+
+type WebhookWriter interface {
+	InsertSubscription(ctx context.Context, req CreateSubscriptionRequest) (Subscription, error)
+	UpdateSubscription(ctx context.Context, id int, req UpdateSubscriptionRequest) (Subscription, error)
+	DeactivateSubscription(ctx context.Context, id int) error
+	InsertEvent(ctx context.Context, eventType string, payload []byte) error
+}
+
+// Outbox is the dispatcher's side of the event and delivery tables
+type Outbox interface {
+	FanOutEvents(ctx context.Context, limit int) (int, error)
+	QueueOverdueRentals(ctx context.Context) (int, error)
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]Outgoing, error)
+	RecordAttempt(ctx context.Context, id, attempt int, result AttemptResult) error
+}
+
+// AttemptResult is the outcome of one POST to a subscriber
+type AttemptResult struct {
+	Status        string // DeliveryDelivered, or DeliveryPending to retry, or DeliveryFailed
+	StatusCode    *int
+	Error         *string
+	NextAttemptAt *time.Time
+}
+
+type Repository interface {
+	WebhookReader
+	WebhookWriter
+	Outbox
+}
+
+type repository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRepository(pool *pgxpool.Pool) Repository {
+	return &repository{pool: pool}
+}
+
+// conn returns the transaction started by db.WithTx, or the pool. Events
+// are written on the caller's transaction so they commit with the change
+// they describe.
+func (r *repository) conn(ctx context.Context) db.DBTX {
+	return db.Conn(ctx, r.pool)
+}
+
+func scanSubscription(row pgx.Row) (Subscription, error) {
+	var s Subscription
+	err := row.Scan(&s.ID, &s.URL, &s.Events, &s.Active, &s.CreatedAt, &s.LastUpdate)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return s, ErrSubscriptionNotFound
+	}
+	return s, err
+}
+
+func (r *repository) GetSubscriptions(ctx context.Context) ([]Subscription, error) {
+	rows, err := r.conn(ctx).Query(ctx, `SELECT `+subscriptionColumns+` FROM webhook_subscription ORDER BY subscription_id`)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (Subscription, error) {
+		return scanSubscription(row)
+	})
+}
+
+func (r *repository) GetSubscriptionByID(ctx context.Context, id int) (Subscription, error) {
+	return scanSubscription(r.conn(ctx).QueryRow(ctx,
+		`SELECT `+subscriptionColumns+` FROM webhook_subscription WHERE subscription_id = $1`, id,
+	))
+}
+
+func (r *repository) InsertSubscription(ctx context.Context, req CreateSubscriptionRequest) (Subscription, error) {
+	s, err := scanSubscription(r.conn(ctx).QueryRow(ctx, `
+		INSERT INTO webhook_subscription (url, events, secret)
+		VALUES ($1, $2, $3)
+		RETURNING `+subscriptionColumns,
+		req.URL, req.Events, req.Secret,
+	))
+	s.Secret = req.Secret
+	return s, err
+}
+
+func (r *repository) UpdateSubscription(ctx context.Context, id int, req UpdateSubscriptionRequest) (Subscription, error) {
+	return scanSubscription(r.conn(ctx).QueryRow(ctx, `
+		UPDATE webhook_subscription SET
+			url = COALESCE($2, url),
+			events = COALESCE($3, events),
+			active = COALESCE($4, active),
+			last_update = CURRENT_TIMESTAMP
+		WHERE subscription_id = $1
+		RETURNING `+subscriptionColumns,
+		id, req.URL, req.Events, req.Active,
+	))
+}
+
+func (r *repository) DeactivateSubscription(ctx context.Context, id int) error {
+	tag, err := r.conn(ctx).Exec(ctx,
+		`UPDATE webhook_subscription SET active = FALSE, last_update = CURRENT_TIMESTAMP WHERE subscription_id = $1`, id,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+func (r *repository) CountDeliveries(ctx context.Context, filter DeliveryFilter) (int, error) {
+	var total int
+	err := r.conn(ctx).QueryRow(ctx, `SELECT count(*)`+deliveryWhere,
+		filter.SubscriptionID, filter.Status, filter.EventType,
+	).Scan(&total)
+	return total, err
+}
+
+// EachDelivery iterates a subscription's deliveries, newest first
+func (r *repository) EachDelivery(ctx context.Context, filter DeliveryFilter) iter.Seq2[Delivery, error] {
+	query := `
+	SELECT
+		webhook_delivery.delivery_id,
+		webhook_delivery.subscription_id,
+		webhook_delivery.event_id,
+		webhook_event.type,
+		webhook_delivery.status,
+		webhook_delivery.attempts,
+		webhook_delivery.last_status_code,
+		webhook_delivery.last_error,
+		CASE WHEN webhook_delivery.status = 'pending' THEN webhook_delivery.next_attempt_at END,
+		webhook_delivery.delivered_at,
+		webhook_delivery.created_at
+	` + deliveryWhere + `
+	ORDER BY webhook_delivery.delivery_id DESC
+	LIMIT NULLIF($4, 0) OFFSET $5
+	`
+	return db.Seq(ctx, r.pool, func(row pgx.CollectableRow) (Delivery, error) {
+		var d Delivery
+		err := row.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Status, &d.Attempts,
+			&d.LastStatusCode, &d.LastError, &d.NextAttemptAt, &d.DeliveredAt, &d.CreatedAt)
+		return d, err
+	}, query, filter.SubscriptionID, filter.Status, filter.EventType, filter.Page.Limit, filter.Page.Offset)
+}
+
+func (r *repository) InsertEvent(ctx context.Context, eventType string, payload []byte) error {
+	_, err := r.conn(ctx).Exec(ctx,
+		`INSERT INTO webhook_event (type, payload) VALUES ($1, $2)`, eventType, payload,
+	)
+	return err
+}
+
+// FanOutEvents turns new outbox events into one delivery per active
+// subscription to their type. SKIP LOCKED lets several servers fan out at
+// once without doing an event twice.
+func (r *repository) FanOutEvents(ctx context.Context, limit int) (int, error) {
+	tag, err := r.conn(ctx).Exec(ctx, `
+		WITH events AS (
+			SELECT event_id, type FROM webhook_event
+			WHERE dispatched_at IS NULL
+			ORDER BY event_id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		), deliveries AS (
+			INSERT INTO webhook_delivery (event_id, subscription_id)
+			SELECT events.event_id, webhook_subscription.subscription_id
+			FROM events
+			INNER JOIN webhook_subscription ON webhook_subscription.active
+				AND events.type = ANY (webhook_subscription.events)
+		)
+		UPDATE webhook_event SET dispatched_at = CURRENT_TIMESTAMP
+		WHERE event_id IN (SELECT event_id FROM events)
+	`, limit)
+	return int(tag.RowsAffected()), err
+}
+
+// QueueOverdueRentals writes a rental.overdue event for every open rental
+// past its film's rental duration. The rental ID is the event's key, so each
+// rental is only reported once.
+func (r *repository) QueueOverdueRentals(ctx context.Context) (int, error) {
+	tag, err := r.conn(ctx).Exec(ctx, `
+		INSERT INTO webhook_event (type, key, payload)
+		SELECT
+			'rental.overdue',
+			rental.rental_id::text,
+			json_build_object(
+				'rental_id', rental.rental_id,
+				'inventory_id', rental.inventory_id,
+				'customer_id', rental.customer_id,
+				'staff_id', rental.staff_id,
+				'rental_date', rental.rental_date,
+				'due_date', rental.rental_date + film.rental_duration * INTERVAL '1 day'
+			)
+		FROM
+			rental
+			INNER JOIN inventory ON rental.inventory_id = inventory.inventory_id
+			INNER JOIN film ON inventory.film_id = film.film_id
+		WHERE
+			rental.return_date IS NULL
+			AND rental.rental_date + film.rental_duration * INTERVAL '1 day' < CURRENT_TIMESTAMP
+		ORDER BY rental.rental_id
+		ON CONFLICT (type, key) WHERE key IS NOT NULL DO NOTHING
+	`)
+	return int(tag.RowsAffected()), err
+}
+
+// ClaimDeliveries takes up to limit due deliveries for active subscriptions
+// and leases them, a delivery whose worker died is claimed again once the
+// lease runs out.
+func (r *repository) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]Outgoing, error) {
+	rows, err := r.conn(ctx).Query(ctx, `
+		UPDATE webhook_delivery SET
+			attempts = webhook_delivery.attempts + 1,
+			locked_until = CURRENT_TIMESTAMP + make_interval(secs => $2)
+		FROM webhook_event, webhook_subscription
+		WHERE webhook_delivery.event_id = webhook_event.event_id
+			AND webhook_delivery.subscription_id = webhook_subscription.subscription_id
+			AND webhook_delivery.delivery_id IN (
+				SELECT delivery_id FROM webhook_delivery
+				INNER JOIN webhook_subscription USING (subscription_id)
+				WHERE webhook_delivery.status = 'pending'
+					AND webhook_delivery.next_attempt_at <= CURRENT_TIMESTAMP
+					AND (webhook_delivery.locked_until IS NULL OR webhook_delivery.locked_until < CURRENT_TIMESTAMP)
+					AND webhook_subscription.active
+				ORDER BY webhook_delivery.next_attempt_at, webhook_delivery.delivery_id
+				LIMIT $1
+				FOR UPDATE OF webhook_delivery SKIP LOCKED
+			)
+		RETURNING
+			webhook_delivery.delivery_id,
+			webhook_delivery.attempts,
+			webhook_subscription.url,
+			webhook_subscription.secret,
+			webhook_event.event_id,
+			webhook_event.type,
+			webhook_event.created_at,
+			webhook_event.payload
+	`, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (Outgoing, error) {
+		var o Outgoing
+		err := row.Scan(&o.DeliveryID, &o.Attempt, &o.URL, &o.Secret,
+			&o.Event.ID, &o.Event.Type, &o.Event.CreatedAt, &o.Event.Data)
+		return o, err
+	})
+}
+
+// RecordAttempt saves the outcome of an attempt. It is fenced on the attempt
+// number so a worker whose lease ran out can't overwrite a later attempt.
+func (r *repository) RecordAttempt(ctx context.Context, id, attempt int, result AttemptResult) error {
+	_, err := r.conn(ctx).Exec(ctx, `
+		UPDATE webhook_delivery SET
+			status = $3,
+			last_status_code = $4,
+			last_error = $5,
+			next_attempt_at = COALESCE($6, next_attempt_at),
+			delivered_at = CASE WHEN $3 = 'delivered' THEN CURRENT_TIMESTAMP END,
+			locked_until = NULL
+		WHERE delivery_id = $1 AND attempts = $2
+	`, id, attempt, result.Status, result.StatusCode, result.Error, result.NextAttemptAt)
+	return err
+}
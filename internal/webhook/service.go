@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"iter"
+)
+
+var ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+
+// Publisher writes an event to the outbox. Call it inside the transaction of
+// the change the event describes, the event is only sent if that commits.
+type Publisher interface {
+	Publish(ctx context.Context, eventType string, payload any) error
+}
+
+type Service interface {
+	Publisher
+	GetSubscriptions(ctx context.Context) ([]Subscription, error)
+	GetSubscriptionByID(ctx context.Context, id int) (Subscription, error)
+	CreateSubscription(ctx context.Context, req CreateSubscriptionRequest) (Subscription, error)
+	UpdateSubscription(ctx context.Context, id int, req UpdateSubscriptionRequest) (Subscription, error)
+	DeleteSubscription(ctx context.Context, id int) error
+	GetDeliveries(ctx context.Context, filter DeliveryFilter) (iter.Seq2[Delivery, error], int, error)
+}
+
+type service struct {
+	reader WebhookReader
+	writer WebhookWriter
+}
+
+func NewService(reader WebhookReader, writer WebhookWriter) Service {
+	return &service{
+		reader: reader,
+		writer: writer,
+	}
+}
+
+func (s *service) Publish(ctx context.Context, eventType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return s.writer.InsertEvent(ctx, eventType, data)
+}
+
+func (s *service) GetSubscriptions(ctx context.Context) ([]Subscription, error) {
+	return s.reader.GetSubscriptions(ctx)
+}
+
+func (s *service) GetSubscriptionByID(ctx context.Context, id int) (Subscription, error) {
+	return s.reader.GetSubscriptionByID(ctx, id)
+}
+
+// CreateSubscription stores the subscription, generating a signing secret
+// when the request doesn't bring one.
+func (s *service) CreateSubscription(ctx context.Context, req CreateSubscriptionRequest) (Subscription, error) {
+	if req.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return Subscription{}, err
+		}
+		req.Secret = hex.EncodeToString(secret)
+	}
+	return s.writer.InsertSubscription(ctx, req)
+}
+
+func (s *service) UpdateSubscription(ctx context.Context, id int, req UpdateSubscriptionRequest) (Subscription, error) {
+	return s.writer.UpdateSubscription(ctx, id, req)
+}
+
+// DeleteSubscription deactivates the subscription, its delivery log is kept
+func (s *service) DeleteSubscription(ctx context.Context, id int) error {
+	return s.writer.DeactivateSubscription(ctx, id)
+}
+
+// GetDeliveries counts a subscription's matching deliveries and returns an
+// iterator over the page.
+func (s *service) GetDeliveries(ctx context.Context, filter DeliveryFilter) (iter.Seq2[Delivery, error], int, error) {
+	if _, err := s.reader.GetSubscriptionByID(ctx, filter.SubscriptionID); err != nil {
+		return nil, 0, err
+	}
+	total, err := s.reader.CountDeliveries(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.reader.EachDelivery(ctx, filter), total, nil
+}
@@ -0,0 +1,113 @@
+package webhook
+
+import (
+	"context"
+	"iter"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockReader struct {
+	mock.Mock
+}
+
+func (m *mockReader) GetSubscriptions(ctx context.Context) ([]Subscription, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]Subscription), args.Error(1)
+}
+
+func (m *mockReader) GetSubscriptionByID(ctx context.Context, id int) (Subscription, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Subscription), args.Error(1)
+}
+
+func (m *mockReader) CountDeliveries(ctx context.Context, filter DeliveryFilter) (int, error) {
+	args := m.Called(ctx, filter)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockReader) EachDelivery(ctx context.Context, filter DeliveryFilter) iter.Seq2[Delivery, error] {
+	return m.Called(ctx, filter).Get(0).(iter.Seq2[Delivery, error])
+}
+
+type mockWriter struct {
+	mock.Mock
+}
+
+func (m *mockWriter) InsertSubscription(ctx context.Context, req CreateSubscriptionRequest) (Subscription, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(Subscription), args.Error(1)
+}
+
+func (m *mockWriter) UpdateSubscription(ctx context.Context, id int, req UpdateSubscriptionRequest) (Subscription, error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(Subscription), args.Error(1)
+}
+
+func (m *mockWriter) DeactivateSubscription(ctx context.Context, id int) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *mockWriter) InsertEvent(ctx context.Context, eventType string, payload []byte) error {
+	return m.Called(ctx, eventType, payload).Error(0)
+}
+
+func TestService_Publish(t *testing.T) {
+	writer := new(mockWriter)
+	svc := NewService(new(mockReader), writer)
+
+	writer.On("InsertEvent", mock.Anything, RentalCreated, []byte(`{"rental_id":7}`)).Return(nil)
+
+	err := svc.Publish(context.Background(), RentalCreated, map[string]int{"rental_id": 7})
+
+	assert.NoError(t, err)
+	writer.AssertExpectations(t)
+}
+
+func TestService_CreateSubscription_GeneratesSecret(t *testing.T) {
+	writer := new(mockWriter)
+	svc := NewService(new(mockReader), writer)
+
+	writer.On("InsertSubscription", mock.Anything, mock.MatchedBy(func(req CreateSubscriptionRequest) bool {
+		return len(req.Secret) == 64
+	})).Return(Subscription{ID: 1}, nil)
+
+	_, err := svc.CreateSubscription(context.Background(), CreateSubscriptionRequest{
+		URL:    "https://example.com/hook",
+		Events: []string{RentalCreated},
+	})
+
+	assert.NoError(t, err)
+	writer.AssertExpectations(t)
+}
+
+func TestService_CreateSubscription_KeepsGivenSecret(t *testing.T) {
+	writer := new(mockWriter)
+	svc := NewService(new(mockReader), writer)
+
+	req := CreateSubscriptionRequest{
+		URL:    "https://example.com/hook",
+		Events: []string{PaymentCreated},
+		Secret: "a-secret-of-my-own",
+	}
+	writer.On("InsertSubscription", mock.Anything, req).Return(Subscription{ID: 1, Secret: req.Secret}, nil)
+
+	subscription, err := svc.CreateSubscription(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "a-secret-of-my-own", subscription.Secret)
+}
+
+func TestService_GetDeliveries_UnknownSubscription(t *testing.T) {
+	reader := new(mockReader)
+	svc := NewService(reader, new(mockWriter))
+
+	reader.On("GetSubscriptionByID", mock.Anything, 9).Return(Subscription{}, ErrSubscriptionNotFound)
+
+	_, _, err := svc.GetDeliveries(context.Background(), DeliveryFilter{SubscriptionID: 9})
+
+	assert.ErrorIs(t, err, ErrSubscriptionNotFound)
+	reader.AssertNotCalled(t, "CountDeliveries", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+func filmPath(id int) string {
+	return "/v1/films/" + strconv.Itoa(id)
+}
+
+func (c *Client) ListFilms(ctx context.Context) ([]Film, error) {
+	var films []Film
+	_, err := c.get(ctx, "/v1/films", nil, &films)
+	return films, err
+}
+
+func (c *Client) GetFilm(ctx context.Context, id int) (Film, error) {
+	var film Film
+	_, err := c.get(ctx, filmPath(id), nil, &film)
+	return film, err
+}
+
+// SearchFilms finds films by title
+func (c *Client) SearchFilms(ctx context.Context, title string) ([]Film, error) {
+	var films []Film
+	_, err := c.get(ctx, "/v1/films/search", url.Values{"title": {title}}, &films)
+	return films, err
+}
+
+func (c *Client) GetFilmWithActorsCategories(ctx context.Context, id int) (FilmWithActorsCategories, error) {
+	var film FilmWithActorsCategories
+	_, err := c.get(ctx, filmPath(id)+"/with-actors-categories", nil, &film)
+	return film, err
+}
+
+// GetFilmAvailability is a film's copies at every store
+func (c *Client) GetFilmAvailability(ctx context.Context, id int) (FilmAvailability, error) {
+	var availability FilmAvailability
+	_, err := c.get(ctx, filmPath(id)+"/availability", nil, &availability)
+	return availability, err
+}
+
+// ListInventory returns the copies of every store, or of one when storeID is
+// set
+func (c *Client) ListInventory(ctx context.Context, storeID *int) ([]Inventory, error) {
+	q := url.Values{}
+	setInt(q, "store_id", storeID)
+
+	var inventory []Inventory
+	_, err := c.get(ctx, "/v1/inventory", q, &inventory)
+	return inventory, err
+}
+
+// GetInventoryAvailable is whether a store has a copy of a film to rent
+func (c *Client) GetInventoryAvailable(ctx context.Context, storeID, filmID int) (InventoryAvailability, error) {
+	q := url.Values{"store_id": {strconv.Itoa(storeID)}, "film_id": {strconv.Itoa(filmID)}}
+
+	var availability InventoryAvailability
+	_, err := c.get(ctx, "/v1/inventory/available", q, &availability)
+	return availability, err
+}
@@ -0,0 +1,289 @@
+// Package client is a typed Go client for the video rental API.
+//
+//	c := client.New("http://localhost:8080", client.WithAPIKey(key))
+//	customer, err := c.GetCustomer(ctx, 1)
+//	if errors.Is(err, client.ErrNotFound) { ... }
+//
+// Writes carry an Idempotency-Key, so a write that failed on the network or
+// with a 5xx is retried with the same key and runs at most once.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultRetries = 2
+	DefaultBackoff = 200 * time.Millisecond
+)
+
+type Client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+	retries    int
+	backoff    time.Duration
+}
+
+type Option func(*Client)
+
+// WithAPIKey sends the key in X-API-Key, Login returns one
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithRetries sets how many times a request is retried after a network
+// error, a 5xx, a 429 or a 409 for an idempotency key still in use. The wait
+// starts at backoff and doubles, a Retry-After header overrides it.
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.backoff = backoff
+	}
+}
+
+// New returns a client of the server at baseURL, like http://localhost:8080
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		retries:    DefaultRetries,
+		backoff:    DefaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// request is one API call, body is sent as is with contentType
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	header      http.Header
+	body        []byte
+	contentType string
+}
+
+// do sends the request, retrying as WithRetries describes. A response of 400
+// or more is returned as an *Error, any other response's body is the
+// caller's to close.
+func (c *Client) do(ctx context.Context, r request) (*http.Response, error) {
+	if r.header == nil {
+		r.header = http.Header{}
+	}
+	if r.method != http.MethodGet && r.header.Get("Idempotency-Key") == "" {
+		r.header.Set("Idempotency-Key", rand.Text()) // the same key on every attempt
+	}
+	u := c.baseURL + r.path
+	if len(r.query) > 0 {
+		u += "?" + r.query.Encode()
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, r.method, u, bytes.NewReader(r.body))
+		if err != nil {
+			return nil, err
+		}
+		if r.body == nil {
+			req.Body = http.NoBody
+		}
+		req.Header = r.header.Clone()
+		if c.apiKey != "" {
+			req.Header.Set("X-API-Key", c.apiKey)
+		}
+		if r.contentType != "" {
+			req.Header.Set("Content-Type", r.contentType)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		var wait time.Duration
+		switch {
+		case err != nil:
+			if ctx.Err() != nil || attempt >= c.retries {
+				return nil, err
+			}
+		case retryable(resp) && attempt < c.retries:
+			wait = retryAfter(resp)
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		case resp.StatusCode >= 400:
+			defer resp.Body.Close()
+			return nil, readError(resp)
+		default:
+			return resp, nil
+		}
+
+		if wait == 0 {
+			wait = c.backoff << attempt
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func retryable(resp *http.Response) bool {
+	switch {
+	case resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented:
+		return true
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode == http.StatusConflict:
+		// the first attempt with this idempotency key is still running
+		return resp.Header.Get("Retry-After") != ""
+	}
+	return false
+}
+
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// call sends in as JSON, unless it is nil, and decodes the response into out,
+// unless it is nil. It returns the response headers.
+func (c *Client) call(ctx context.Context, method, path string, query url.Values, header http.Header, in, out any) (http.Header, error) {
+	r := request{method: method, path: path, query: query, header: header}
+	if in != nil {
+		body, err := json.Marshal(in)
+		if err != nil {
+			return nil, err
+		}
+		r.body, r.contentType = body, "application/json"
+	}
+
+	resp, err := c.do(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return nil, fmt.Errorf("decode %s %s response: %w", method, path, err)
+		}
+	}
+	return resp.Header, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, query url.Values, out any) (http.Header, error) {
+	return c.call(ctx, http.MethodGet, path, query, nil, nil, out)
+}
+
+// raw sends a body that isn't JSON, like a CSV file or a picture, and decodes
+// the JSON response into out
+func (c *Client) raw(ctx context.Context, method, path string, query url.Values, contentType string, body io.Reader, out any) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(ctx, request{method: method, path: path, query: query, body: data, contentType: contentType})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("decode %s %s response: %w", method, path, err)
+		}
+	}
+	return nil
+}
+
+// download returns a response body that isn't JSON and its content type
+func (c *Client) download(ctx context.Context, path string) ([]byte, string, error) {
+	resp, err := c.do(ctx, request{method: http.MethodGet, path: path})
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	return body, resp.Header.Get("Content-Type"), err
+}
+
+// Login exchanges staff credentials for the API key to pass to WithAPIKey
+func (c *Client) Login(ctx context.Context, username, password string) (string, error) {
+	var resp map[string]string
+	creds := LoginRequest{Username: username, Password: password}
+	if _, err := c.call(ctx, http.MethodPost, "/v1/login", nil, nil, creds, &resp); err != nil {
+		return "", err
+	}
+	return resp["token"], nil
+}
+
+// Health is the server's liveness check
+func (c *Client) Health(ctx context.Context) (map[string]string, error) {
+	var health map[string]string
+	_, err := c.get(ctx, "/health", nil, &health)
+	return health, err
+}
+
+// PoolHealth pings the database and returns the connection pool's counts. An
+// unhealthy database is an *Error with status 503.
+func (c *Client) PoolHealth(ctx context.Context) (map[string]any, error) {
+	var health map[string]any
+	_, err := c.get(ctx, "/health/pool", nil, &health)
+	return health, err
+}
+
+func (c *Client) CacheStats(ctx context.Context) (CacheStats, error) {
+	var stats CacheStats
+	_, err := c.get(ctx, "/health/cache", nil, &stats)
+	return stats, err
+}
+
+// OpenAPI returns the server's OpenAPI document
+func (c *Client) OpenAPI(ctx context.Context) (json.RawMessage, error) {
+	var doc json.RawMessage
+	_, err := c.get(ctx, "/openapi.json", nil, &doc)
+	return doc, err
+}
+
+// GraphQL runs a query and decodes its data into out. Errors the query
+// returns are a *GraphQLError.
+func (c *Client) GraphQL(ctx context.Context, query string, variables map[string]any, out any) error {
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	in := map[string]any{"query": query, "variables": variables}
+	if _, err := c.call(ctx, http.MethodPost, "/graphql", nil, nil, in, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		gqlErr := &GraphQLError{}
+		for _, e := range resp.Errors {
+			gqlErr.Messages = append(gqlErr.Messages, e.Message)
+		}
+		return gqlErr
+	}
+	if out == nil || len(resp.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(resp.Data, out)
+}
@@ -0,0 +1,279 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/api"
+	"github.com/rstoltzm-profile/video-rental-api/internal/config"
+	"github.com/rstoltzm-profile/video-rental-api/internal/etag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a test server that keeps the requests it got
+type recorder struct {
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func (rec *recorder) serve(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec.mu.Lock()
+		rec.requests = append(rec.requests, r.Clone(context.Background()))
+		rec.mu.Unlock()
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return New(srv.URL, WithAPIKey("secret"), WithRetries(2, time.Millisecond))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func TestClient_GetCustomer(t *testing.T) {
+	var rec recorder
+	c := rec.serve(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, Customer{ID: 7, FirstName: "Mary"})
+	})
+
+	customer, err := c.GetCustomer(context.Background(), 7)
+
+	require.NoError(t, err)
+	assert.Equal(t, Customer{ID: 7, FirstName: "Mary"}, customer)
+	require.Len(t, rec.requests, 1)
+	assert.Equal(t, "/v1/customers/7", rec.requests[0].URL.Path)
+	assert.Equal(t, "secret", rec.requests[0].Header.Get("X-API-Key"))
+	assert.Empty(t, rec.requests[0].Header.Get("Idempotency-Key"), "reads need no key")
+}
+
+func TestClient_RetriesWritesWithTheSameIdempotencyKey(t *testing.T) {
+	var rec recorder
+	calls := 0
+	c := rec.serve(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch calls {
+		case 1:
+			http.Error(w, "Failed to create rental", http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, "request with this key is in progress", http.StatusConflict)
+		default:
+			writeJSON(w, http.StatusCreated, map[string]int{"id": 42})
+		}
+	})
+
+	id, err := c.CreateRental(context.Background(), CreateRentalRequest{InventoryID: 1, CustomerID: 2, StaffID: 3})
+
+	require.NoError(t, err)
+	assert.Equal(t, 42, id)
+	require.Len(t, rec.requests, 3)
+	key := rec.requests[0].Header.Get("Idempotency-Key")
+	assert.NotEmpty(t, key)
+	for _, r := range rec.requests {
+		assert.Equal(t, key, r.Header.Get("Idempotency-Key"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+	}
+}
+
+func TestClient_GivesUpAfterRetries(t *testing.T) {
+	var rec recorder
+	c := rec.serve(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Failed to fetch films", http.StatusInternalServerError)
+	})
+
+	_, err := c.ListFilms(context.Background())
+
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.StatusCode)
+	assert.Equal(t, "Failed to fetch films", apiErr.Message)
+	assert.Len(t, rec.requests, 3, "the first attempt and two retries")
+}
+
+func TestClient_TypedErrors(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusConflict, ErrConflict},
+		{http.StatusPreconditionFailed, ErrPreconditionFailed},
+		{http.StatusUnauthorized, ErrUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.status), func(t *testing.T) {
+			var rec recorder
+			c := rec.serve(t, func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "nope", tt.status)
+			})
+
+			_, err := c.GetStaff(context.Background(), 1)
+
+			assert.ErrorIs(t, err, tt.want)
+			assert.NotErrorIs(t, err, ErrBadRequest)
+			assert.Len(t, rec.requests, 1, "client errors are not retried")
+		})
+	}
+}
+
+func TestClient_ValidationErrorFromTheRouter(t *testing.T) {
+	srv := httptest.NewServer(api.NewRouter(nil, config.Config{APIKey: "secret"}, api.NewCatalog(nil, config.Config{})))
+	defer srv.Close()
+	c := New(srv.URL, WithAPIKey("secret"))
+
+	_, err := c.CreateRental(context.Background(), CreateRentalRequest{InventoryID: 1, StaffID: 1})
+
+	require.ErrorIs(t, err, ErrBadRequest)
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "Validation failed", apiErr.Message)
+	assert.Equal(t, []FieldError{{In: "body", Field: "customer_id", Message: "must be greater than 0"}}, apiErr.Fields)
+}
+
+func TestClient_Customers(t *testing.T) {
+	var rec recorder
+	c := rec.serve(t, func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		var page []Customer
+		for id := offset + 1; id <= min(offset+limit, 5); id++ {
+			page = append(page, Customer{ID: id})
+		}
+		w.Header().Set("X-Total-Count", "5")
+		writeJSON(w, http.StatusOK, page)
+	})
+
+	storeID := 1
+	var ids []int
+	for customer, err := range c.Customers(context.Background(), CustomerFilter{StoreID: &storeID, Page: Page{Limit: 2}}) {
+		require.NoError(t, err)
+		ids = append(ids, customer.ID)
+	}
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, ids)
+	require.Len(t, rec.requests, 3)
+	for i, r := range rec.requests {
+		q := r.URL.Query()
+		assert.Equal(t, "1", q.Get("store_id"))
+		assert.Equal(t, "all", q.Get("active"), "a nil Active lists every customer")
+		assert.Equal(t, "2", q.Get("limit"))
+		if i > 0 {
+			assert.Equal(t, strconv.Itoa(i*2), q.Get("offset"))
+		}
+	}
+}
+
+func TestClient_IteratorStopsAtTheFirstError(t *testing.T) {
+	var rec recorder
+	c := rec.serve(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "offset must be 0 or greater", http.StatusBadRequest)
+	})
+
+	var errs []error
+	for _, err := range c.Payments(context.Background(), PaymentFilter{}) {
+		errs = append(errs, err)
+	}
+
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], ErrBadRequest)
+}
+
+func TestClient_VersionedWrites(t *testing.T) {
+	var rec recorder
+	c := rec.serve(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, Store{ID: 1})
+	})
+	lastUpdate := time.Date(2026, 10, 19, 12, 0, 0, 123456000, time.UTC)
+	phone := "555-0100"
+
+	_, err := c.UpdateStore(context.Background(), 1, "token", lastUpdate, UpdateStoreRequest{Phone: &phone})
+
+	require.NoError(t, err)
+	require.Len(t, rec.requests, 1)
+	r := rec.requests[0]
+	assert.Equal(t, http.MethodPatch, r.Method)
+	assert.Equal(t, etag.Of(lastUpdate), r.Header.Get("If-Match"))
+	assert.Equal(t, "token", r.Header.Get("X-Staff-Token"))
+}
+
+func TestClient_StoreEvents(t *testing.T) {
+	var rec recorder
+	c := rec.serve(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "retry: 3000\n\n")
+		fmt.Fprint(w, "id: 6\nevent: rental.created\ndata: {\"id\":6,\"type\":\"rental.created\",\"store_id\":1}\n\n")
+		fmt.Fprint(w, ": heartbeat\n\n")
+		fmt.Fprint(w, "id: 7\nevent: payment.created\ndata: {\"id\":7,\"type\":\"payment.created\",\"store_id\":1}\n\n")
+	})
+
+	var events []StoreEvent
+	for e, err := range c.StoreEvents(context.Background(), 1, 5) {
+		require.NoError(t, err)
+		events = append(events, e)
+	}
+
+	require.Len(t, events, 2)
+	assert.Equal(t, 6, events[0].ID)
+	assert.Equal(t, "payment.created", events[1].Type)
+	assert.Equal(t, "5", rec.requests[0].Header.Get("Last-Event-ID"))
+	assert.Equal(t, "/v1/stores/1/events", rec.requests[0].URL.Path)
+}
+
+func TestClient_ImportCustomers(t *testing.T) {
+	var rec recorder
+	var body string
+	c := rec.serve(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		writeJSON(w, http.StatusOK, ImportReport{DryRun: true, Rows: 1})
+	})
+
+	report, err := c.ImportCustomers(context.Background(), strings.NewReader("first_name\nMary\n"), true)
+
+	require.NoError(t, err)
+	assert.Equal(t, ImportReport{DryRun: true, Rows: 1}, report)
+	assert.Equal(t, "first_name\nMary\n", body)
+	assert.Equal(t, "text/csv", rec.requests[0].Header.Get("Content-Type"))
+	assert.Equal(t, "true", rec.requests[0].URL.Query().Get("dry_run"))
+}
+
+func TestClient_GraphQL(t *testing.T) {
+	var rec recorder
+	c := rec.serve(t, func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if strings.Contains(req.Query, "nope") {
+			writeJSON(w, http.StatusOK, map[string]any{"errors": []map[string]string{{"message": "unknown field nope"}}})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"data": map[string]any{"film": map[string]string{"title": "ACADEMY DINOSAUR"}}})
+	})
+
+	var out struct {
+		Film struct{ Title string } `json:"film"`
+	}
+	require.NoError(t, c.GraphQL(context.Background(), "{ film(id: 1) { title } }", nil, &out))
+	assert.Equal(t, "ACADEMY DINOSAUR", out.Film.Title)
+
+	err := c.GraphQL(context.Background(), "{ nope }", nil, nil)
+	var gqlErr *GraphQLError
+	require.True(t, errors.As(err, &gqlErr))
+	assert.Equal(t, []string{"unknown field nope"}, gqlErr.Messages)
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/etag"
+)
+
+// ifMatch sends the version a write expects, the LastUpdate of the copy the
+// caller read. A newer version on the server fails with ErrPreconditionFailed.
+func ifMatch(lastUpdate time.Time) http.Header {
+	return http.Header{"If-Match": {etag.Of(lastUpdate)}}
+}
+
+func customerPath(id int) string {
+	return "/v1/customers/" + strconv.Itoa(id)
+}
+
+// ListCustomers returns one page of customers and the total that match. A nil
+// filter.Active lists active and inactive customers.
+func (c *Client) ListCustomers(ctx context.Context, filter CustomerFilter) ([]Customer, int, error) {
+	q := url.Values{}
+	if filter.Query != "" {
+		q.Set("q", filter.Query)
+	}
+	setInt(q, "store_id", filter.StoreID)
+	setActive(q, filter.Active)
+	setPage(q, filter.Page)
+
+	var customers []Customer
+	h, err := c.get(ctx, "/v1/customers", q, &customers)
+	if err != nil {
+		return nil, 0, err
+	}
+	return customers, totalCount(h), nil
+}
+
+// Customers yields every customer matching the filter, a page at a time
+func (c *Client) Customers(ctx context.Context, filter CustomerFilter) iter.Seq2[Customer, error] {
+	return pages(ctx, filter.Page, func(ctx context.Context, page Page) ([]Customer, int, error) {
+		filter.Page = page
+		return c.ListCustomers(ctx, filter)
+	})
+}
+
+func (c *Client) GetCustomer(ctx context.Context, id int) (Customer, error) {
+	var customer Customer
+	_, err := c.get(ctx, customerPath(id), nil, &customer)
+	return customer, err
+}
+
+func (c *Client) CreateCustomer(ctx context.Context, req CreateCustomerRequest) (Customer, error) {
+	var customer Customer
+	_, err := c.call(ctx, http.MethodPost, "/v1/customers", nil, nil, req, &customer)
+	return customer, err
+}
+
+// UpdateCustomer changes the fields set in req. lastUpdate is the version the
+// change is based on, from GetCustomer.
+func (c *Client) UpdateCustomer(ctx context.Context, id int, lastUpdate time.Time, req UpdateCustomerRequest) (Customer, error) {
+	var customer Customer
+	_, err := c.call(ctx, http.MethodPatch, customerPath(id), nil, ifMatch(lastUpdate), req, &customer)
+	return customer, err
+}
+
+// DeleteCustomer deactivates the customer
+func (c *Client) DeleteCustomer(ctx context.Context, id int, lastUpdate time.Time) error {
+	_, err := c.call(ctx, http.MethodDelete, customerPath(id), nil, ifMatch(lastUpdate), nil, nil)
+	return err
+}
+
+func (c *Client) ReactivateCustomer(ctx context.Context, id int, lastUpdate time.Time) (Customer, error) {
+	var customer Customer
+	_, err := c.call(ctx, http.MethodPost, customerPath(id)+"/reactivate", nil, ifMatch(lastUpdate), nil, &customer)
+	return customer, err
+}
+
+// ListCustomerRentals returns one page of a customer's rental history and the
+// total that match
+func (c *Client) ListCustomerRentals(ctx context.Context, id int, filter RentalHistoryFilter) ([]CustomerRental, int, error) {
+	q := url.Values{}
+	if filter.Status != "" {
+		q.Set("status", filter.Status)
+	}
+	setRange(q, DateRange{From: filter.From, To: filter.To})
+	setPage(q, filter.Page)
+
+	var rentals []CustomerRental
+	h, err := c.get(ctx, customerPath(id)+"/rentals", q, &rentals)
+	if err != nil {
+		return nil, 0, err
+	}
+	return rentals, totalCount(h), nil
+}
+
+// CustomerRentals yields a customer's whole rental history matching the filter
+func (c *Client) CustomerRentals(ctx context.Context, id int, filter RentalHistoryFilter) iter.Seq2[CustomerRental, error] {
+	return pages(ctx, filter.Page, func(ctx context.Context, page Page) ([]CustomerRental, int, error) {
+		filter.Page = page
+		return c.ListCustomerRentals(ctx, id, filter)
+	})
+}
+
+func (c *Client) GetCustomerSummary(ctx context.Context, id int) (CustomerSummary, error) {
+	var summary CustomerSummary
+	_, err := c.get(ctx, customerPath(id)+"/summary", nil, &summary)
+	return summary, err
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/openapi"
+)
+
+// Error is a response of 400 or more. Fields lists what was wrong with the
+// request when the server's validation rejected it.
+type Error struct {
+	StatusCode int
+	Message    string
+	Fields     []FieldError
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	for _, f := range e.Fields {
+		msg += fmt.Sprintf("; %s %s %s", f.In, f.Field, f.Message)
+	}
+	return msg
+}
+
+// Is matches the status errors below, errors.Is(err, ErrNotFound)
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Message == "" && t.Fields == nil && t.StatusCode == e.StatusCode
+}
+
+var (
+	ErrBadRequest           = &Error{StatusCode: http.StatusBadRequest}
+	ErrUnauthorized         = &Error{StatusCode: http.StatusUnauthorized}
+	ErrForbidden            = &Error{StatusCode: http.StatusForbidden}
+	ErrNotFound             = &Error{StatusCode: http.StatusNotFound}
+	ErrConflict             = &Error{StatusCode: http.StatusConflict}
+	ErrPreconditionFailed   = &Error{StatusCode: http.StatusPreconditionFailed}
+	ErrUnprocessable        = &Error{StatusCode: http.StatusUnprocessableEntity}
+	ErrPreconditionRequired = &Error{StatusCode: http.StatusPreconditionRequired}
+)
+
+// readError reads the plain text or validation error body of a response
+func readError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	e := &Error{StatusCode: resp.StatusCode}
+
+	var validation openapi.ValidationError
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") && json.Unmarshal(body, &validation) == nil && validation.Error != "" {
+		e.Message, e.Fields = validation.Error, validation.Fields
+		return e
+	}
+	e.Message = strings.TrimSpace(string(body))
+	return e
+}
+
+// GraphQLError holds the errors a GraphQL query returned
+type GraphQLError struct {
+	Messages []string
+}
+
+func (e *GraphQLError) Error() string {
+	return "graphql: " + strings.Join(e.Messages, "; ")
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/job"
+)
+
+// JobPollInterval is how often WaitJob checks a job
+const JobPollInterval = time.Second
+
+func jobPath(id int) string {
+	return "/v1/jobs/" + strconv.Itoa(id)
+}
+
+func (c *Client) GetJob(ctx context.Context, id int) (Job, error) {
+	var j Job
+	_, err := c.get(ctx, jobPath(id), nil, &j)
+	return j, err
+}
+
+// CancelJob cancels a queued job, a running one stops within a few seconds
+func (c *Client) CancelJob(ctx context.Context, id int) (Job, error) {
+	var j Job
+	_, err := c.call(ctx, http.MethodDelete, jobPath(id), nil, nil, nil, &j)
+	return j, err
+}
+
+// JobResult returns a finished job's output and its content type
+func (c *Client) JobResult(ctx context.Context, id int) ([]byte, string, error) {
+	return c.download(ctx, jobPath(id)+"/result")
+}
+
+// WaitJob polls a job until it succeeds, fails or is cancelled
+func (c *Client) WaitJob(ctx context.Context, id int) (Job, error) {
+	ticker := time.NewTicker(JobPollInterval)
+	defer ticker.Stop()
+	for {
+		j, err := c.GetJob(ctx, id)
+		if err != nil {
+			return j, err
+		}
+		switch j.Status {
+		case job.StatusSucceeded, job.StatusFailed, job.StatusCancelled:
+			return j, nil
+		}
+		select {
+		case <-ctx.Done():
+			return j, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ImportCustomers loads customers from CSV, with dryRun only checking the
+// rows. Nothing is imported unless every row is valid, ImportReport.Errors
+// lists the rows that aren't.
+func (c *Client) ImportCustomers(ctx context.Context, csv io.Reader, dryRun bool) (ImportReport, error) {
+	return c.importCSV(ctx, "/v1/imports/customers", csv, dryRun)
+}
+
+// ImportInventory adds copies of films to stores from CSV
+func (c *Client) ImportInventory(ctx context.Context, csv io.Reader, dryRun bool) (ImportReport, error) {
+	return c.importCSV(ctx, "/v1/imports/inventory", csv, dryRun)
+}
+
+// StartImport runs a customers or inventory import as a background job, the
+// job's result is the ImportReport
+func (c *Client) StartImport(ctx context.Context, kind string, csv io.Reader, dryRun bool) (Job, error) {
+	q := url.Values{"async": {"true"}, "dry_run": {strconv.FormatBool(dryRun)}}
+
+	var j Job
+	err := c.raw(ctx, http.MethodPost, "/v1/imports/"+kind, q, "text/csv", csv, &j)
+	return j, err
+}
+
+func (c *Client) importCSV(ctx context.Context, path string, csv io.Reader, dryRun bool) (ImportReport, error) {
+	q := url.Values{"dry_run": {strconv.FormatBool(dryRun)}}
+
+	var report ImportReport
+	err := c.raw(ctx, http.MethodPost, path, q, "text/csv", csv, &report)
+	return report, err
+}
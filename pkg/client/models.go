@@ -0,0 +1,100 @@
+package client
+
+import (
+	"github.com/rstoltzm-profile/video-rental-api/internal/activity"
+	"github.com/rstoltzm-profile/video-rental-api/internal/auth"
+	"github.com/rstoltzm-profile/video-rental-api/internal/cache"
+	"github.com/rstoltzm-profile/video-rental-api/internal/customer"
+	"github.com/rstoltzm-profile/video-rental-api/internal/daterange"
+	"github.com/rstoltzm-profile/video-rental-api/internal/film"
+	"github.com/rstoltzm-profile/video-rental-api/internal/imports"
+	"github.com/rstoltzm-profile/video-rental-api/internal/inventory"
+	"github.com/rstoltzm-profile/video-rental-api/internal/job"
+	"github.com/rstoltzm-profile/video-rental-api/internal/openapi"
+	"github.com/rstoltzm-profile/video-rental-api/internal/pagination"
+	"github.com/rstoltzm-profile/video-rental-api/internal/payment"
+	"github.com/rstoltzm-profile/video-rental-api/internal/rental"
+	"github.com/rstoltzm-profile/video-rental-api/internal/report"
+	"github.com/rstoltzm-profile/video-rental-api/internal/reservation"
+	"github.com/rstoltzm-profile/video-rental-api/internal/staff"
+	"github.com/rstoltzm-profile/video-rental-api/internal/store"
+	"github.com/rstoltzm-profile/video-rental-api/internal/webhook"
+)
+
+// The client sends and returns the server's own models. They are aliased here
+// so code outside this module, which can't import internal packages, can
+// name them.
+
+type (
+	Page         = pagination.Page
+	DateRange    = daterange.Range
+	LoginRequest = auth.LoginRequest
+
+	Customer              = customer.Customer
+	CustomerFilter        = customer.CustomerFilter
+	CreateCustomerRequest = customer.CreateCustomerRequest
+	AddressInput          = customer.AddressInput
+	UpdateCustomerRequest = customer.UpdateCustomerRequest
+	AddressUpdate         = customer.AddressUpdate
+	CustomerRental        = customer.CustomerRentals
+	RentalHistoryFilter   = customer.RentalHistoryFilter
+	CustomerSummary       = customer.CustomerSummary
+	CategoryCount         = customer.CategoryCount
+
+	Film                     = film.Film
+	FilmWithActorsCategories = film.FilmWithActorsCategories
+
+	Inventory             = inventory.Inventory
+	InventoryAvailability = inventory.InventoryAvailability
+	FilmAvailability      = inventory.FilmAvailability
+
+	Rental              = rental.Rental
+	CreateRentalRequest = rental.CreateRentalRequest
+
+	Payment       = payment.Payment
+	PaymentRecord = payment.PaymentRecord
+	PaymentFilter = payment.PaymentFilter
+
+	Reservation              = reservation.Reservation
+	CreateReservationRequest = reservation.CreateReservationRequest
+	ReservationFilter        = reservation.ReservationFilter
+
+	Staff              = staff.Staff
+	StaffFilter        = staff.StaffFilter
+	CreateStaffRequest = staff.CreateStaffRequest
+	UpdateStaffRequest = staff.UpdateStaffRequest
+	MoveStaffRequest   = staff.MoveStaffRequest
+	SetPasswordRequest = staff.SetPasswordRequest
+	StaffLoginRequest  = staff.LoginRequest
+	StaffSession       = staff.Session
+
+	Store                  = store.Store
+	StoreAddress           = store.StoreAddress
+	StaffMember            = store.StaffMember
+	StoreInventorySummary  = store.StoreInventorySummary
+	UpdateStoreRequest     = store.UpdateStoreRequest
+	ReassignManagerRequest = store.ReassignManagerRequest
+	StoreEvent             = activity.Event
+
+	ReportFilter       = report.ReportFilter
+	StoreRevenue       = report.StoreRevenue
+	FilmRank           = report.FilmRank
+	CustomerRank       = report.CustomerRank
+	CategoryPopularity = report.CategoryPopularity
+	LateReturnRate     = report.LateReturnRate
+	CopyUtilization    = report.CopyUtilization
+
+	Subscription              = webhook.Subscription
+	CreateSubscriptionRequest = webhook.CreateSubscriptionRequest
+	UpdateSubscriptionRequest = webhook.UpdateSubscriptionRequest
+	Delivery                  = webhook.Delivery
+	DeliveryFilter            = webhook.DeliveryFilter
+
+	Job          = job.Job
+	JobStatus    = job.Status
+	ImportReport = imports.Report
+	ImportError  = imports.RowError
+
+	CacheStats = cache.Stats
+	FieldError = openapi.FieldError
+)
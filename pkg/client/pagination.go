@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/rstoltzm-profile/video-rental-api/internal/pagination"
+)
+
+// pages yields every item of a paged list from page.Offset on, page.Limit at
+// a time or pagination.MaxLimit when it is 0. fetch returns a page and the
+// total from X-Total-Count.
+func pages[T any](ctx context.Context, page Page, fetch func(context.Context, Page) ([]T, int, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		if page.Limit == 0 {
+			page.Limit = pagination.MaxLimit
+		}
+		for {
+			items, total, err := fetch(ctx, page)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			page.Offset += len(items)
+			if len(items) == 0 || page.Offset >= total {
+				return
+			}
+		}
+	}
+}
+
+func totalCount(h http.Header) int {
+	total, _ := strconv.Atoi(h.Get("X-Total-Count"))
+	return total
+}
+
+func setPage(q url.Values, page Page) {
+	if page.Limit > 0 {
+		q.Set("limit", strconv.Itoa(page.Limit))
+	}
+	if page.Offset > 0 {
+		q.Set("offset", strconv.Itoa(page.Offset))
+	}
+}
+
+func setInt(q url.Values, name string, v *int) {
+	if v != nil {
+		q.Set(name, strconv.Itoa(*v))
+	}
+}
+
+func setRange(q url.Values, rng DateRange) {
+	if rng.From != nil {
+		q.Set("from", rng.From.Format(time.RFC3339))
+	}
+	if rng.To != nil {
+		q.Set("to", rng.To.Format(time.RFC3339))
+	}
+}
+
+// setActive sends active=all for nil, the filters' "active and inactive"
+func setActive(q url.Values, active *bool) {
+	if active == nil {
+		q.Set("active", "all")
+		return
+	}
+	q.Set("active", strconv.FormatBool(*active))
+}
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ListRentals returns every rental, or only the late ones
+func (c *Client) ListRentals(ctx context.Context, late bool) ([]Rental, error) {
+	q := url.Values{}
+	if late {
+		q.Set("late", "true")
+	}
+
+	var rentals []Rental
+	_, err := c.get(ctx, "/v1/rentals", q, &rentals)
+	return rentals, err
+}
+
+// CreateRental rents a copy out and returns the rental ID
+func (c *Client) CreateRental(ctx context.Context, req CreateRentalRequest) (int, error) {
+	var created struct {
+		ID int `json:"id"`
+	}
+	_, err := c.call(ctx, http.MethodPost, "/v1/rentals", nil, nil, req, &created)
+	return created.ID, err
+}
+
+func (c *Client) ReturnRental(ctx context.Context, id int) error {
+	_, err := c.call(ctx, http.MethodPost, "/v1/rentals/"+strconv.Itoa(id)+"/return", nil, nil, nil, nil)
+	return err
+}
+
+// ListPayments returns one page of payments and the total that match
+func (c *Client) ListPayments(ctx context.Context, filter PaymentFilter) ([]PaymentRecord, int, error) {
+	q := url.Values{}
+	setRange(q, filter.Range)
+	setInt(q, "customer_id", filter.CustomerID)
+	setInt(q, "staff_id", filter.StaffID)
+	setInt(q, "store_id", filter.StoreID)
+	setPage(q, filter.Page)
+
+	var payments []PaymentRecord
+	h, err := c.get(ctx, "/v1/payments", q, &payments)
+	if err != nil {
+		return nil, 0, err
+	}
+	return payments, totalCount(h), nil
+}
+
+// Payments yields every payment matching the filter, a page at a time
+func (c *Client) Payments(ctx context.Context, filter PaymentFilter) iter.Seq2[PaymentRecord, error] {
+	return pages(ctx, filter.Page, func(ctx context.Context, page Page) ([]PaymentRecord, int, error) {
+		filter.Page = page
+		return c.ListPayments(ctx, filter)
+	})
+}
+
+// MakePayment records a payment and returns its ID
+func (c *Client) MakePayment(ctx context.Context, req Payment) (int, error) {
+	var id int
+	_, err := c.call(ctx, http.MethodPost, "/v1/payments", nil, nil, req, &id)
+	return id, err
+}
+
+func reservationPath(id int) string {
+	return "/v1/reservations/" + strconv.Itoa(id)
+}
+
+// ListReservations returns one page of reservations and the total that match
+func (c *Client) ListReservations(ctx context.Context, filter ReservationFilter) ([]Reservation, int, error) {
+	q := url.Values{}
+	setInt(q, "customer_id", filter.CustomerID)
+	setInt(q, "film_id", filter.FilmID)
+	setInt(q, "store_id", filter.StoreID)
+	if filter.Status != "" {
+		q.Set("status", filter.Status)
+	}
+	setPage(q, filter.Page)
+
+	var reservations []Reservation
+	h, err := c.get(ctx, "/v1/reservations", q, &reservations)
+	if err != nil {
+		return nil, 0, err
+	}
+	return reservations, totalCount(h), nil
+}
+
+// Reservations yields every reservation matching the filter, a page at a time
+func (c *Client) Reservations(ctx context.Context, filter ReservationFilter) iter.Seq2[Reservation, error] {
+	return pages(ctx, filter.Page, func(ctx context.Context, page Page) ([]Reservation, int, error) {
+		filter.Page = page
+		return c.ListReservations(ctx, filter)
+	})
+}
+
+func (c *Client) GetReservation(ctx context.Context, id int) (Reservation, error) {
+	var reservation Reservation
+	_, err := c.get(ctx, reservationPath(id), nil, &reservation)
+	return reservation, err
+}
+
+// CreateReservation queues the customer for a film at a store, or holds a
+// copy straight away when one is free
+func (c *Client) CreateReservation(ctx context.Context, req CreateReservationRequest) (Reservation, error) {
+	var reservation Reservation
+	_, err := c.call(ctx, http.MethodPost, "/v1/reservations", nil, nil, req, &reservation)
+	return reservation, err
+}
+
+func (c *Client) CancelReservation(ctx context.Context, id int) error {
+	_, err := c.call(ctx, http.MethodDelete, reservationPath(id), nil, nil, nil, nil)
+	return err
+}
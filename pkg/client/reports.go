@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"strconv"
+)
+
+// Report names a report for StartReport
+type Report string
+
+const (
+	ReportRevenue      Report = "revenue"
+	ReportTopFilms     Report = "top-films"
+	ReportTopCustomers Report = "top-customers"
+	ReportCategories   Report = "categories"
+	ReportLateReturns  Report = "late-returns"
+	ReportUtilization  Report = "utilization"
+)
+
+func reportQuery(filter ReportFilter) url.Values {
+	q := url.Values{}
+	setRange(q, filter.Range)
+	setInt(q, "store_id", filter.StoreID)
+	if filter.Top > 0 {
+		q.Set("limit", strconv.Itoa(filter.Top))
+	}
+	return q
+}
+
+func (c *Client) GetRevenue(ctx context.Context, filter ReportFilter) ([]StoreRevenue, error) {
+	var rows []StoreRevenue
+	_, err := c.get(ctx, "/v1/reports/revenue", reportQuery(filter), &rows)
+	return rows, err
+}
+
+// GetTopFilms ranks films by rentals, filter.Top of them
+func (c *Client) GetTopFilms(ctx context.Context, filter ReportFilter) ([]FilmRank, error) {
+	var rows []FilmRank
+	_, err := c.get(ctx, "/v1/reports/top-films", reportQuery(filter), &rows)
+	return rows, err
+}
+
+// GetTopCustomers ranks customers by spend, filter.Top of them
+func (c *Client) GetTopCustomers(ctx context.Context, filter ReportFilter) ([]CustomerRank, error) {
+	var rows []CustomerRank
+	_, err := c.get(ctx, "/v1/reports/top-customers", reportQuery(filter), &rows)
+	return rows, err
+}
+
+func (c *Client) GetCategoryPopularity(ctx context.Context, filter ReportFilter) ([]CategoryPopularity, error) {
+	var rows []CategoryPopularity
+	_, err := c.get(ctx, "/v1/reports/categories", reportQuery(filter), &rows)
+	return rows, err
+}
+
+func (c *Client) GetLateReturnRates(ctx context.Context, filter ReportFilter) ([]LateReturnRate, error) {
+	var rows []LateReturnRate
+	_, err := c.get(ctx, "/v1/reports/late-returns", reportQuery(filter), &rows)
+	return rows, err
+}
+
+// ListCopyUtilization returns one page of the utilization report and the
+// total number of copies
+func (c *Client) ListCopyUtilization(ctx context.Context, filter ReportFilter) ([]CopyUtilization, int, error) {
+	q := reportQuery(ReportFilter{Range: filter.Range, StoreID: filter.StoreID})
+	setPage(q, filter.Page)
+
+	var rows []CopyUtilization
+	h, err := c.get(ctx, "/v1/reports/utilization", q, &rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return rows, totalCount(h), nil
+}
+
+// CopyUtilization yields the utilization of every copy, a page at a time
+func (c *Client) CopyUtilization(ctx context.Context, filter ReportFilter) iter.Seq2[CopyUtilization, error] {
+	return pages(ctx, filter.Page, func(ctx context.Context, page Page) ([]CopyUtilization, int, error) {
+		filter.Page = page
+		return c.ListCopyUtilization(ctx, filter)
+	})
+}
+
+// StartReport runs a report as a background job, WaitJob waits for it and
+// JobResult returns its rows as JSON
+func (c *Client) StartReport(ctx context.Context, report Report, filter ReportFilter) (Job, error) {
+	q := reportQuery(filter)
+	if report == ReportUtilization {
+		q = reportQuery(ReportFilter{Range: filter.Range, StoreID: filter.StoreID})
+		setPage(q, filter.Page)
+	}
+	q.Set("async", "true")
+
+	var j Job
+	_, err := c.get(ctx, "/v1/reports/"+string(report), q, &j)
+	return j, err
+}
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+func staffPath(id int) string {
+	return "/v1/staff/" + strconv.Itoa(id)
+}
+
+// ListStaff returns one page of staff and the total that match. A nil
+// filter.Active lists active and inactive staff.
+func (c *Client) ListStaff(ctx context.Context, filter StaffFilter) ([]Staff, int, error) {
+	q := url.Values{}
+	setInt(q, "store_id", filter.StoreID)
+	setActive(q, filter.Active)
+	setPage(q, filter.Page)
+
+	var staff []Staff
+	h, err := c.get(ctx, "/v1/staff", q, &staff)
+	if err != nil {
+		return nil, 0, err
+	}
+	return staff, totalCount(h), nil
+}
+
+// AllStaff yields every staff member matching the filter, a page at a time
+func (c *Client) AllStaff(ctx context.Context, filter StaffFilter) iter.Seq2[Staff, error] {
+	return pages(ctx, filter.Page, func(ctx context.Context, page Page) ([]Staff, int, error) {
+		filter.Page = page
+		return c.ListStaff(ctx, filter)
+	})
+}
+
+func (c *Client) GetStaff(ctx context.Context, id int) (Staff, error) {
+	var staff Staff
+	_, err := c.get(ctx, staffPath(id), nil, &staff)
+	return staff, err
+}
+
+func (c *Client) CreateStaff(ctx context.Context, req CreateStaffRequest) (Staff, error) {
+	var staff Staff
+	_, err := c.call(ctx, http.MethodPost, "/v1/staff", nil, nil, req, &staff)
+	return staff, err
+}
+
+// UpdateStaff changes the fields set in req, staffToken must be a session of
+// the staff member or their store's manager, from StaffLogin. lastUpdate is
+// the version the change is based on, from GetStaff.
+func (c *Client) UpdateStaff(ctx context.Context, id int, staffToken string, lastUpdate time.Time, req UpdateStaffRequest) (Staff, error) {
+	var staff Staff
+	_, err := c.call(ctx, http.MethodPatch, staffPath(id), nil, asStaff(staffToken, ifMatch(lastUpdate)), req, &staff)
+	return staff, err
+}
+
+func (c *Client) DeactivateStaff(ctx context.Context, id int, staffToken string, lastUpdate time.Time) error {
+	_, err := c.call(ctx, http.MethodDelete, staffPath(id), nil, asStaff(staffToken, ifMatch(lastUpdate)), nil, nil)
+	return err
+}
+
+func (c *Client) ReactivateStaff(ctx context.Context, id int, staffToken string, lastUpdate time.Time) (Staff, error) {
+	var staff Staff
+	_, err := c.call(ctx, http.MethodPost, staffPath(id)+"/reactivate", nil, asStaff(staffToken, ifMatch(lastUpdate)), nil, &staff)
+	return staff, err
+}
+
+// MoveStaff transfers a staff member to another store
+func (c *Client) MoveStaff(ctx context.Context, id int, staffToken string, lastUpdate time.Time, storeID int) (Staff, error) {
+	var staff Staff
+	req := MoveStaffRequest{StoreID: storeID}
+	_, err := c.call(ctx, http.MethodPut, staffPath(id)+"/store", nil, asStaff(staffToken, ifMatch(lastUpdate)), req, &staff)
+	return staff, err
+}
+
+func (c *Client) SetStaffPassword(ctx context.Context, id int, staffToken string, password string) error {
+	req := SetPasswordRequest{Password: password}
+	_, err := c.call(ctx, http.MethodPut, staffPath(id)+"/password", nil, asStaff(staffToken, http.Header{}), req, nil)
+	return err
+}
+
+// StaffLogin opens a session for the staff member, its token is what the
+// staff writes, UpdateStore and ReassignManager take
+func (c *Client) StaffLogin(ctx context.Context, username, password string) (StaffSession, error) {
+	var session StaffSession
+	req := StaffLoginRequest{Username: username, Password: password}
+	_, err := c.call(ctx, http.MethodPost, "/v1/staff/sessions", nil, nil, req, &session)
+	return session, err
+}
+
+// GetStaffPicture returns the picture and its content type, like image/png
+func (c *Client) GetStaffPicture(ctx context.Context, id int) ([]byte, string, error) {
+	return c.download(ctx, staffPath(id)+"/picture")
+}
+
+// SetStaffPicture uploads a PNG, JPEG, GIF or WebP picture of up to 1MB
+func (c *Client) SetStaffPicture(ctx context.Context, id int, contentType string, picture io.Reader) error {
+	err := c.raw(ctx, http.MethodPut, staffPath(id)+"/picture", nil, contentType, picture, nil)
+	return err
+}
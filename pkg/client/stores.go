@@ -0,0 +1,114 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func storePath(id int) string {
+	return "/v1/stores/" + strconv.Itoa(id)
+}
+
+// asStaff sends the session of the acting staff member, store writes are
+// for its manager, staff writes for the staff member or their manager
+func asStaff(staffToken string, header http.Header) http.Header {
+	header.Set("X-Staff-Token", staffToken)
+	return header
+}
+
+func (c *Client) ListStores(ctx context.Context) ([]Store, error) {
+	var stores []Store
+	_, err := c.get(ctx, "/v1/stores", nil, &stores)
+	return stores, err
+}
+
+func (c *Client) GetStore(ctx context.Context, id int) (Store, error) {
+	var store Store
+	_, err := c.get(ctx, storePath(id), nil, &store)
+	return store, err
+}
+
+func (c *Client) GetStoreStaff(ctx context.Context, id int) ([]StaffMember, error) {
+	var staff []StaffMember
+	_, err := c.get(ctx, storePath(id)+"/staff", nil, &staff)
+	return staff, err
+}
+
+func (c *Client) GetStoreInventorySummary(ctx context.Context, id int) ([]StoreInventorySummary, error) {
+	var summary []StoreInventorySummary
+	_, err := c.get(ctx, storePath(id)+"/inventory/summary", nil, &summary)
+	return summary, err
+}
+
+// UpdateStore changes the fields set in req, staffToken must be a session of
+// the store's manager, from StaffLogin. lastUpdate is the version the change
+// is based on, from GetStore.
+func (c *Client) UpdateStore(ctx context.Context, id int, staffToken string, lastUpdate time.Time, req UpdateStoreRequest) (Store, error) {
+	var store Store
+	_, err := c.call(ctx, http.MethodPatch, storePath(id), nil, asStaff(staffToken, ifMatch(lastUpdate)), req, &store)
+	return store, err
+}
+
+// ReassignManager hands the store to newManagerID, staffToken must be a
+// session of the current manager
+func (c *Client) ReassignManager(ctx context.Context, id int, staffToken string, lastUpdate time.Time, newManagerID int) (Store, error) {
+	var store Store
+	req := ReassignManagerRequest{StaffID: newManagerID}
+	_, err := c.call(ctx, http.MethodPut, storePath(id)+"/manager", nil, asStaff(staffToken, ifMatch(lastUpdate)), req, &store)
+	return store, err
+}
+
+// StoreEvents streams a store's rentals, returns and payments as they commit.
+// With lastEventID above 0 the events after it are sent first. The stream
+// ends when ctx is done or the server closes it, resume it with the ID of
+// the last event received.
+func (c *Client) StoreEvents(ctx context.Context, id, lastEventID int) iter.Seq2[StoreEvent, error] {
+	return func(yield func(StoreEvent, error) bool) {
+		header := http.Header{"Accept": {"text/event-stream"}}
+		if lastEventID > 0 {
+			header.Set("Last-Event-ID", strconv.Itoa(lastEventID))
+		}
+		resp, err := c.do(ctx, request{method: http.MethodGet, path: storePath(id) + "/events", header: header})
+		if err != nil {
+			yield(StoreEvent{}, err)
+			return
+		}
+		defer resp.Body.Close()
+
+		// an event is its data lines, ended by a blank line; comments,
+		// like the heartbeat, start with a colon
+		var data strings.Builder
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if line != "" {
+				if v, ok := strings.CutPrefix(line, "data:"); ok {
+					data.WriteString(strings.TrimPrefix(v, " "))
+				}
+				continue
+			}
+			if data.Len() == 0 {
+				continue
+			}
+			var e StoreEvent
+			if err := json.Unmarshal([]byte(data.String()), &e); err != nil {
+				yield(StoreEvent{}, fmt.Errorf("decode store event: %w", err))
+				return
+			}
+			data.Reset()
+			if !yield(e, nil) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil && ctx.Err() == nil {
+			yield(StoreEvent{}, err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+func webhookPath(id int) string {
+	return "/v1/webhooks/" + strconv.Itoa(id)
+}
+
+func (c *Client) ListWebhooks(ctx context.Context) ([]Subscription, error) {
+	var subscriptions []Subscription
+	_, err := c.get(ctx, "/v1/webhooks", nil, &subscriptions)
+	return subscriptions, err
+}
+
+func (c *Client) GetWebhook(ctx context.Context, id int) (Subscription, error) {
+	var subscription Subscription
+	_, err := c.get(ctx, webhookPath(id), nil, &subscription)
+	return subscription, err
+}
+
+// CreateWebhook subscribes a URL to events. The returned Secret signs the
+// deliveries, it is only returned here.
+func (c *Client) CreateWebhook(ctx context.Context, req CreateSubscriptionRequest) (Subscription, error) {
+	var subscription Subscription
+	_, err := c.call(ctx, http.MethodPost, "/v1/webhooks", nil, nil, req, &subscription)
+	return subscription, err
+}
+
+func (c *Client) UpdateWebhook(ctx context.Context, id int, req UpdateSubscriptionRequest) (Subscription, error) {
+	var subscription Subscription
+	_, err := c.call(ctx, http.MethodPatch, webhookPath(id), nil, nil, req, &subscription)
+	return subscription, err
+}
+
+func (c *Client) DeleteWebhook(ctx context.Context, id int) error {
+	_, err := c.call(ctx, http.MethodDelete, webhookPath(id), nil, nil, nil, nil)
+	return err
+}
+
+// ListDeliveries returns one page of a subscription's delivery log, newest
+// first, and the total that match. filter.SubscriptionID is the id.
+func (c *Client) ListDeliveries(ctx context.Context, filter DeliveryFilter) ([]Delivery, int, error) {
+	q := url.Values{}
+	if filter.Status != "" {
+		q.Set("status", filter.Status)
+	}
+	if filter.EventType != "" {
+		q.Set("event", filter.EventType)
+	}
+	setPage(q, filter.Page)
+
+	var deliveries []Delivery
+	h, err := c.get(ctx, webhookPath(filter.SubscriptionID)+"/deliveries", q, &deliveries)
+	if err != nil {
+		return nil, 0, err
+	}
+	return deliveries, totalCount(h), nil
+}
+
+// Deliveries yields a subscription's whole delivery log matching the filter
+func (c *Client) Deliveries(ctx context.Context, filter DeliveryFilter) iter.Seq2[Delivery, error] {
+	return pages(ctx, filter.Page, func(ctx context.Context, page Page) ([]Delivery, int, error) {
+		filter.Page = page
+		return c.ListDeliveries(ctx, filter)
+	})
+}
@@ -0,0 +1,133 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: videorental/v1/common.proto
+
+package videorentalv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Page selects a page of a list like ?limit= and ?offset= on /v1. A zero
+// limit is the default of 20, the most is 100.
+type Page struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Page) Reset() {
+	*x = Page{}
+	mi := &file_videorental_v1_common_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Page) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Page) ProtoMessage() {}
+
+func (x *Page) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_common_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Page.ProtoReflect.Descriptor instead.
+func (*Page) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_common_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Page) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *Page) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+var File_videorental_v1_common_proto protoreflect.FileDescriptor
+
+const file_videorental_v1_common_proto_rawDesc = "" +
+	"\n" +
+	"\x1bvideorental/v1/common.proto\x12\x0evideorental.v1\"4\n" +
+	"\x04Page\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offsetBRZPgithub.com/rstoltzm-profile/video-rental-api/pkg/pb/videorental/v1;videorentalv1b\x06proto3"
+
+var (
+	file_videorental_v1_common_proto_rawDescOnce sync.Once
+	file_videorental_v1_common_proto_rawDescData []byte
+)
+
+func file_videorental_v1_common_proto_rawDescGZIP() []byte {
+	file_videorental_v1_common_proto_rawDescOnce.Do(func() {
+		file_videorental_v1_common_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_videorental_v1_common_proto_rawDesc), len(file_videorental_v1_common_proto_rawDesc)))
+	})
+	return file_videorental_v1_common_proto_rawDescData
+}
+
+var file_videorental_v1_common_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_videorental_v1_common_proto_goTypes = []any{
+	(*Page)(nil), // 0: videorental.v1.Page
+}
+var file_videorental_v1_common_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_videorental_v1_common_proto_init() }
+func file_videorental_v1_common_proto_init() {
+	if File_videorental_v1_common_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_videorental_v1_common_proto_rawDesc), len(file_videorental_v1_common_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_videorental_v1_common_proto_goTypes,
+		DependencyIndexes: file_videorental_v1_common_proto_depIdxs,
+		MessageInfos:      file_videorental_v1_common_proto_msgTypes,
+	}.Build()
+	File_videorental_v1_common_proto = out.File
+	file_videorental_v1_common_proto_goTypes = nil
+	file_videorental_v1_common_proto_depIdxs = nil
+}
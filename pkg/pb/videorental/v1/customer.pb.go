@@ -0,0 +1,1150 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: videorental/v1/customer.proto
+
+package videorentalv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RentalStatus int32
+
+const (
+	RentalStatus_RENTAL_STATUS_UNSPECIFIED RentalStatus = 0 // every rental
+	RentalStatus_RENTAL_STATUS_OPEN        RentalStatus = 1
+	RentalStatus_RENTAL_STATUS_RETURNED    RentalStatus = 2
+	RentalStatus_RENTAL_STATUS_LATE        RentalStatus = 3
+)
+
+// Enum value maps for RentalStatus.
+var (
+	RentalStatus_name = map[int32]string{
+		0: "RENTAL_STATUS_UNSPECIFIED",
+		1: "RENTAL_STATUS_OPEN",
+		2: "RENTAL_STATUS_RETURNED",
+		3: "RENTAL_STATUS_LATE",
+	}
+	RentalStatus_value = map[string]int32{
+		"RENTAL_STATUS_UNSPECIFIED": 0,
+		"RENTAL_STATUS_OPEN":        1,
+		"RENTAL_STATUS_RETURNED":    2,
+		"RENTAL_STATUS_LATE":        3,
+	}
+)
+
+func (x RentalStatus) Enum() *RentalStatus {
+	p := new(RentalStatus)
+	*p = x
+	return p
+}
+
+func (x RentalStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RentalStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_videorental_v1_customer_proto_enumTypes[0].Descriptor()
+}
+
+func (RentalStatus) Type() protoreflect.EnumType {
+	return &file_videorental_v1_customer_proto_enumTypes[0]
+}
+
+func (x RentalStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RentalStatus.Descriptor instead.
+func (RentalStatus) EnumDescriptor() ([]byte, []int) {
+	return file_videorental_v1_customer_proto_rawDescGZIP(), []int{0}
+}
+
+type Customer struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	FirstName  string                 `protobuf:"bytes,2,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName   string                 `protobuf:"bytes,3,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Email      string                 `protobuf:"bytes,4,opt,name=email,proto3" json:"email,omitempty"`
+	Phone      string                 `protobuf:"bytes,5,opt,name=phone,proto3" json:"phone,omitempty"`
+	StoreId    int32                  `protobuf:"varint,6,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+	Active     bool                   `protobuf:"varint,7,opt,name=active,proto3" json:"active,omitempty"`
+	LastUpdate *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=last_update,json=lastUpdate,proto3" json:"last_update,omitempty"`
+	// the ETag /v1 writes take as If-Match
+	Etag          string `protobuf:"bytes,9,opt,name=etag,proto3" json:"etag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Customer) Reset() {
+	*x = Customer{}
+	mi := &file_videorental_v1_customer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Customer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Customer) ProtoMessage() {}
+
+func (x *Customer) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_customer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Customer.ProtoReflect.Descriptor instead.
+func (*Customer) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_customer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Customer) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Customer) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *Customer) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *Customer) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Customer) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *Customer) GetStoreId() int32 {
+	if x != nil {
+		return x.StoreId
+	}
+	return 0
+}
+
+func (x *Customer) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *Customer) GetLastUpdate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUpdate
+	}
+	return nil
+}
+
+func (x *Customer) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+type ListCustomersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// name, email, phone or address, best match first
+	Query   string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	StoreId *int32 `protobuf:"varint,2,opt,name=store_id,json=storeId,proto3,oneof" json:"store_id,omitempty"`
+	// unset lists active and inactive customers
+	Active        *bool `protobuf:"varint,3,opt,name=active,proto3,oneof" json:"active,omitempty"`
+	Page          *Page `protobuf:"bytes,4,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCustomersRequest) Reset() {
+	*x = ListCustomersRequest{}
+	mi := &file_videorental_v1_customer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCustomersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCustomersRequest) ProtoMessage() {}
+
+func (x *ListCustomersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_customer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCustomersRequest.ProtoReflect.Descriptor instead.
+func (*ListCustomersRequest) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_customer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListCustomersRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *ListCustomersRequest) GetStoreId() int32 {
+	if x != nil && x.StoreId != nil {
+		return *x.StoreId
+	}
+	return 0
+}
+
+func (x *ListCustomersRequest) GetActive() bool {
+	if x != nil && x.Active != nil {
+		return *x.Active
+	}
+	return false
+}
+
+func (x *ListCustomersRequest) GetPage() *Page {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+type ListCustomersResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Customers []*Customer            `protobuf:"bytes,1,rep,name=customers,proto3" json:"customers,omitempty"`
+	// every match, not only this page
+	Total         int32 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCustomersResponse) Reset() {
+	*x = ListCustomersResponse{}
+	mi := &file_videorental_v1_customer_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCustomersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCustomersResponse) ProtoMessage() {}
+
+func (x *ListCustomersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_customer_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCustomersResponse.ProtoReflect.Descriptor instead.
+func (*ListCustomersResponse) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_customer_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListCustomersResponse) GetCustomers() []*Customer {
+	if x != nil {
+		return x.Customers
+	}
+	return nil
+}
+
+func (x *ListCustomersResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type GetCustomerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCustomerRequest) Reset() {
+	*x = GetCustomerRequest{}
+	mi := &file_videorental_v1_customer_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCustomerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCustomerRequest) ProtoMessage() {}
+
+func (x *GetCustomerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_customer_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCustomerRequest.ProtoReflect.Descriptor instead.
+func (*GetCustomerRequest) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_customer_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetCustomerRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type CreateCustomerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FirstName     string                 `protobuf:"bytes,1,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName      string                 `protobuf:"bytes,2,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	StoreId       int32                  `protobuf:"varint,4,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+	Address       *Address               `protobuf:"bytes,5,opt,name=address,proto3" json:"address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCustomerRequest) Reset() {
+	*x = CreateCustomerRequest{}
+	mi := &file_videorental_v1_customer_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCustomerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCustomerRequest) ProtoMessage() {}
+
+func (x *CreateCustomerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_customer_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCustomerRequest.ProtoReflect.Descriptor instead.
+func (*CreateCustomerRequest) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_customer_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateCustomerRequest) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *CreateCustomerRequest) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *CreateCustomerRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *CreateCustomerRequest) GetStoreId() int32 {
+	if x != nil {
+		return x.StoreId
+	}
+	return 0
+}
+
+func (x *CreateCustomerRequest) GetAddress() *Address {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+type Address struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Address  string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Address2 string                 `protobuf:"bytes,2,opt,name=address2,proto3" json:"address2,omitempty"`
+	District string                 `protobuf:"bytes,3,opt,name=district,proto3" json:"district,omitempty"`
+	CityName string                 `protobuf:"bytes,4,opt,name=city_name,json=cityName,proto3" json:"city_name,omitempty"`
+	// needed when the city is new or its name is in more than one country
+	Country    string `protobuf:"bytes,5,opt,name=country,proto3" json:"country,omitempty"`
+	PostalCode string `protobuf:"bytes,6,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	// E.164, like +14155550100
+	Phone         string `protobuf:"bytes,7,opt,name=phone,proto3" json:"phone,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	mi := &file_videorental_v1_customer_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_customer_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_customer_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Address) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Address) GetAddress2() string {
+	if x != nil {
+		return x.Address2
+	}
+	return ""
+}
+
+func (x *Address) GetDistrict() string {
+	if x != nil {
+		return x.District
+	}
+	return ""
+}
+
+func (x *Address) GetCityName() string {
+	if x != nil {
+		return x.CityName
+	}
+	return ""
+}
+
+func (x *Address) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Address) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+func (x *Address) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+type ListCustomerRentalsRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	CustomerId int32                  `protobuf:"varint,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	Status     RentalStatus           `protobuf:"varint,2,opt,name=status,proto3,enum=videorental.v1.RentalStatus" json:"status,omitempty"`
+	// bound the rental date, to is exclusive
+	From          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	Page          *Page                  `protobuf:"bytes,5,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCustomerRentalsRequest) Reset() {
+	*x = ListCustomerRentalsRequest{}
+	mi := &file_videorental_v1_customer_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCustomerRentalsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCustomerRentalsRequest) ProtoMessage() {}
+
+func (x *ListCustomerRentalsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_customer_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCustomerRentalsRequest.ProtoReflect.Descriptor instead.
+func (*ListCustomerRentalsRequest) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_customer_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListCustomerRentalsRequest) GetCustomerId() int32 {
+	if x != nil {
+		return x.CustomerId
+	}
+	return 0
+}
+
+func (x *ListCustomerRentalsRequest) GetStatus() RentalStatus {
+	if x != nil {
+		return x.Status
+	}
+	return RentalStatus_RENTAL_STATUS_UNSPECIFIED
+}
+
+func (x *ListCustomerRentalsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ListCustomerRentalsRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *ListCustomerRentalsRequest) GetPage() *Page {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+type ListCustomerRentalsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rentals       []*CustomerRental      `protobuf:"bytes,1,rep,name=rentals,proto3" json:"rentals,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCustomerRentalsResponse) Reset() {
+	*x = ListCustomerRentalsResponse{}
+	mi := &file_videorental_v1_customer_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCustomerRentalsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCustomerRentalsResponse) ProtoMessage() {}
+
+func (x *ListCustomerRentalsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_customer_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCustomerRentalsResponse.ProtoReflect.Descriptor instead.
+func (*ListCustomerRentalsResponse) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_customer_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListCustomerRentalsResponse) GetRentals() []*CustomerRental {
+	if x != nil {
+		return x.Rentals
+	}
+	return nil
+}
+
+func (x *ListCustomerRentalsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type CustomerRental struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RentalId      int32                  `protobuf:"varint,1,opt,name=rental_id,json=rentalId,proto3" json:"rental_id,omitempty"`
+	InventoryId   int32                  `protobuf:"varint,2,opt,name=inventory_id,json=inventoryId,proto3" json:"inventory_id,omitempty"`
+	FilmId        int32                  `protobuf:"varint,3,opt,name=film_id,json=filmId,proto3" json:"film_id,omitempty"`
+	StoreId       int32                  `protobuf:"varint,4,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+	Title         string                 `protobuf:"bytes,5,opt,name=title,proto3" json:"title,omitempty"`
+	RentalDate    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=rental_date,json=rentalDate,proto3" json:"rental_date,omitempty"`
+	DueDate       *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=due_date,json=dueDate,proto3" json:"due_date,omitempty"`
+	ReturnDate    *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=return_date,json=returnDate,proto3" json:"return_date,omitempty"`
+	Overdue       bool                   `protobuf:"varint,9,opt,name=overdue,proto3" json:"overdue,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CustomerRental) Reset() {
+	*x = CustomerRental{}
+	mi := &file_videorental_v1_customer_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CustomerRental) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CustomerRental) ProtoMessage() {}
+
+func (x *CustomerRental) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_customer_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CustomerRental.ProtoReflect.Descriptor instead.
+func (*CustomerRental) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_customer_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CustomerRental) GetRentalId() int32 {
+	if x != nil {
+		return x.RentalId
+	}
+	return 0
+}
+
+func (x *CustomerRental) GetInventoryId() int32 {
+	if x != nil {
+		return x.InventoryId
+	}
+	return 0
+}
+
+func (x *CustomerRental) GetFilmId() int32 {
+	if x != nil {
+		return x.FilmId
+	}
+	return 0
+}
+
+func (x *CustomerRental) GetStoreId() int32 {
+	if x != nil {
+		return x.StoreId
+	}
+	return 0
+}
+
+func (x *CustomerRental) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CustomerRental) GetRentalDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RentalDate
+	}
+	return nil
+}
+
+func (x *CustomerRental) GetDueDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueDate
+	}
+	return nil
+}
+
+func (x *CustomerRental) GetReturnDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReturnDate
+	}
+	return nil
+}
+
+func (x *CustomerRental) GetOverdue() bool {
+	if x != nil {
+		return x.Overdue
+	}
+	return false
+}
+
+type GetCustomerSummaryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CustomerId    int32                  `protobuf:"varint,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCustomerSummaryRequest) Reset() {
+	*x = GetCustomerSummaryRequest{}
+	mi := &file_videorental_v1_customer_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCustomerSummaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCustomerSummaryRequest) ProtoMessage() {}
+
+func (x *GetCustomerSummaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_customer_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCustomerSummaryRequest.ProtoReflect.Descriptor instead.
+func (*GetCustomerSummaryRequest) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_customer_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetCustomerSummaryRequest) GetCustomerId() int32 {
+	if x != nil {
+		return x.CustomerId
+	}
+	return 0
+}
+
+type CustomerSummary struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	CustomerId         int32                  `protobuf:"varint,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	LifetimeRentals    int32                  `protobuf:"varint,2,opt,name=lifetime_rentals,json=lifetimeRentals,proto3" json:"lifetime_rentals,omitempty"`
+	OpenRentals        int32                  `protobuf:"varint,3,opt,name=open_rentals,json=openRentals,proto3" json:"open_rentals,omitempty"`
+	LateRentals        int32                  `protobuf:"varint,4,opt,name=late_rentals,json=lateRentals,proto3" json:"late_rentals,omitempty"`
+	FirstRentalDate    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=first_rental_date,json=firstRentalDate,proto3" json:"first_rental_date,omitempty"`
+	LastRentalDate     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_rental_date,json=lastRentalDate,proto3" json:"last_rental_date,omitempty"`
+	TotalSpend         float64                `protobuf:"fixed64,7,opt,name=total_spend,json=totalSpend,proto3" json:"total_spend,omitempty"`
+	OutstandingBalance float64                `protobuf:"fixed64,8,opt,name=outstanding_balance,json=outstandingBalance,proto3" json:"outstanding_balance,omitempty"`
+	FavoriteCategories []*CategoryCount       `protobuf:"bytes,9,rep,name=favorite_categories,json=favoriteCategories,proto3" json:"favorite_categories,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CustomerSummary) Reset() {
+	*x = CustomerSummary{}
+	mi := &file_videorental_v1_customer_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CustomerSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CustomerSummary) ProtoMessage() {}
+
+func (x *CustomerSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_customer_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CustomerSummary.ProtoReflect.Descriptor instead.
+func (*CustomerSummary) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_customer_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CustomerSummary) GetCustomerId() int32 {
+	if x != nil {
+		return x.CustomerId
+	}
+	return 0
+}
+
+func (x *CustomerSummary) GetLifetimeRentals() int32 {
+	if x != nil {
+		return x.LifetimeRentals
+	}
+	return 0
+}
+
+func (x *CustomerSummary) GetOpenRentals() int32 {
+	if x != nil {
+		return x.OpenRentals
+	}
+	return 0
+}
+
+func (x *CustomerSummary) GetLateRentals() int32 {
+	if x != nil {
+		return x.LateRentals
+	}
+	return 0
+}
+
+func (x *CustomerSummary) GetFirstRentalDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FirstRentalDate
+	}
+	return nil
+}
+
+func (x *CustomerSummary) GetLastRentalDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastRentalDate
+	}
+	return nil
+}
+
+func (x *CustomerSummary) GetTotalSpend() float64 {
+	if x != nil {
+		return x.TotalSpend
+	}
+	return 0
+}
+
+func (x *CustomerSummary) GetOutstandingBalance() float64 {
+	if x != nil {
+		return x.OutstandingBalance
+	}
+	return 0
+}
+
+func (x *CustomerSummary) GetFavoriteCategories() []*CategoryCount {
+	if x != nil {
+		return x.FavoriteCategories
+	}
+	return nil
+}
+
+type CategoryCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Category      string                 `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	Rentals       int32                  `protobuf:"varint,2,opt,name=rentals,proto3" json:"rentals,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CategoryCount) Reset() {
+	*x = CategoryCount{}
+	mi := &file_videorental_v1_customer_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CategoryCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoryCount) ProtoMessage() {}
+
+func (x *CategoryCount) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_customer_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoryCount.ProtoReflect.Descriptor instead.
+func (*CategoryCount) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_customer_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CategoryCount) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *CategoryCount) GetRentals() int32 {
+	if x != nil {
+		return x.Rentals
+	}
+	return 0
+}
+
+var File_videorental_v1_customer_proto protoreflect.FileDescriptor
+
+const file_videorental_v1_customer_proto_rawDesc = "" +
+	"\n" +
+	"\x1dvideorental/v1/customer.proto\x12\x0evideorental.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bvideorental/v1/common.proto\"\x86\x02\n" +
+	"\bCustomer\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x02 \x01(\tR\tfirstName\x12\x1b\n" +
+	"\tlast_name\x18\x03 \x01(\tR\blastName\x12\x14\n" +
+	"\x05email\x18\x04 \x01(\tR\x05email\x12\x14\n" +
+	"\x05phone\x18\x05 \x01(\tR\x05phone\x12\x19\n" +
+	"\bstore_id\x18\x06 \x01(\x05R\astoreId\x12\x16\n" +
+	"\x06active\x18\a \x01(\bR\x06active\x12;\n" +
+	"\vlast_update\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"lastUpdate\x12\x12\n" +
+	"\x04etag\x18\t \x01(\tR\x04etag\"\xab\x01\n" +
+	"\x14ListCustomersRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1e\n" +
+	"\bstore_id\x18\x02 \x01(\x05H\x00R\astoreId\x88\x01\x01\x12\x1b\n" +
+	"\x06active\x18\x03 \x01(\bH\x01R\x06active\x88\x01\x01\x12(\n" +
+	"\x04page\x18\x04 \x01(\v2\x14.videorental.v1.PageR\x04pageB\v\n" +
+	"\t_store_idB\t\n" +
+	"\a_active\"e\n" +
+	"\x15ListCustomersResponse\x126\n" +
+	"\tcustomers\x18\x01 \x03(\v2\x18.videorental.v1.CustomerR\tcustomers\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"$\n" +
+	"\x12GetCustomerRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"\xb7\x01\n" +
+	"\x15CreateCustomerRequest\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x01 \x01(\tR\tfirstName\x12\x1b\n" +
+	"\tlast_name\x18\x02 \x01(\tR\blastName\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x19\n" +
+	"\bstore_id\x18\x04 \x01(\x05R\astoreId\x121\n" +
+	"\aaddress\x18\x05 \x01(\v2\x17.videorental.v1.AddressR\aaddress\"\xc9\x01\n" +
+	"\aAddress\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x1a\n" +
+	"\baddress2\x18\x02 \x01(\tR\baddress2\x12\x1a\n" +
+	"\bdistrict\x18\x03 \x01(\tR\bdistrict\x12\x1b\n" +
+	"\tcity_name\x18\x04 \x01(\tR\bcityName\x12\x18\n" +
+	"\acountry\x18\x05 \x01(\tR\acountry\x12\x1f\n" +
+	"\vpostal_code\x18\x06 \x01(\tR\n" +
+	"postalCode\x12\x14\n" +
+	"\x05phone\x18\a \x01(\tR\x05phone\"\xf9\x01\n" +
+	"\x1aListCustomerRentalsRequest\x12\x1f\n" +
+	"\vcustomer_id\x18\x01 \x01(\x05R\n" +
+	"customerId\x124\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x1c.videorental.v1.RentalStatusR\x06status\x12.\n" +
+	"\x04from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12(\n" +
+	"\x04page\x18\x05 \x01(\v2\x14.videorental.v1.PageR\x04page\"m\n" +
+	"\x1bListCustomerRentalsResponse\x128\n" +
+	"\arentals\x18\x01 \x03(\v2\x1e.videorental.v1.CustomerRentalR\arentals\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"\xe5\x02\n" +
+	"\x0eCustomerRental\x12\x1b\n" +
+	"\trental_id\x18\x01 \x01(\x05R\brentalId\x12!\n" +
+	"\finventory_id\x18\x02 \x01(\x05R\vinventoryId\x12\x17\n" +
+	"\afilm_id\x18\x03 \x01(\x05R\x06filmId\x12\x19\n" +
+	"\bstore_id\x18\x04 \x01(\x05R\astoreId\x12\x14\n" +
+	"\x05title\x18\x05 \x01(\tR\x05title\x12;\n" +
+	"\vrental_date\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"rentalDate\x125\n" +
+	"\bdue_date\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\adueDate\x12;\n" +
+	"\vreturn_date\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"returnDate\x12\x18\n" +
+	"\aoverdue\x18\t \x01(\bR\aoverdue\"<\n" +
+	"\x19GetCustomerSummaryRequest\x12\x1f\n" +
+	"\vcustomer_id\x18\x01 \x01(\x05R\n" +
+	"customerId\"\xd3\x03\n" +
+	"\x0fCustomerSummary\x12\x1f\n" +
+	"\vcustomer_id\x18\x01 \x01(\x05R\n" +
+	"customerId\x12)\n" +
+	"\x10lifetime_rentals\x18\x02 \x01(\x05R\x0flifetimeRentals\x12!\n" +
+	"\fopen_rentals\x18\x03 \x01(\x05R\vopenRentals\x12!\n" +
+	"\flate_rentals\x18\x04 \x01(\x05R\vlateRentals\x12F\n" +
+	"\x11first_rental_date\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x0ffirstRentalDate\x12D\n" +
+	"\x10last_rental_date\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x0elastRentalDate\x12\x1f\n" +
+	"\vtotal_spend\x18\a \x01(\x01R\n" +
+	"totalSpend\x12/\n" +
+	"\x13outstanding_balance\x18\b \x01(\x01R\x12outstandingBalance\x12N\n" +
+	"\x13favorite_categories\x18\t \x03(\v2\x1d.videorental.v1.CategoryCountR\x12favoriteCategories\"E\n" +
+	"\rCategoryCount\x12\x1a\n" +
+	"\bcategory\x18\x01 \x01(\tR\bcategory\x12\x18\n" +
+	"\arentals\x18\x02 \x01(\x05R\arentals*y\n" +
+	"\fRentalStatus\x12\x1d\n" +
+	"\x19RENTAL_STATUS_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12RENTAL_STATUS_OPEN\x10\x01\x12\x1a\n" +
+	"\x16RENTAL_STATUS_RETURNED\x10\x02\x12\x16\n" +
+	"\x12RENTAL_STATUS_LATE\x10\x032\xe1\x03\n" +
+	"\x0fCustomerService\x12\\\n" +
+	"\rListCustomers\x12$.videorental.v1.ListCustomersRequest\x1a%.videorental.v1.ListCustomersResponse\x12K\n" +
+	"\vGetCustomer\x12\".videorental.v1.GetCustomerRequest\x1a\x18.videorental.v1.Customer\x12Q\n" +
+	"\x0eCreateCustomer\x12%.videorental.v1.CreateCustomerRequest\x1a\x18.videorental.v1.Customer\x12n\n" +
+	"\x13ListCustomerRentals\x12*.videorental.v1.ListCustomerRentalsRequest\x1a+.videorental.v1.ListCustomerRentalsResponse\x12`\n" +
+	"\x12GetCustomerSummary\x12).videorental.v1.GetCustomerSummaryRequest\x1a\x1f.videorental.v1.CustomerSummaryBRZPgithub.com/rstoltzm-profile/video-rental-api/pkg/pb/videorental/v1;videorentalv1b\x06proto3"
+
+var (
+	file_videorental_v1_customer_proto_rawDescOnce sync.Once
+	file_videorental_v1_customer_proto_rawDescData []byte
+)
+
+func file_videorental_v1_customer_proto_rawDescGZIP() []byte {
+	file_videorental_v1_customer_proto_rawDescOnce.Do(func() {
+		file_videorental_v1_customer_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_videorental_v1_customer_proto_rawDesc), len(file_videorental_v1_customer_proto_rawDesc)))
+	})
+	return file_videorental_v1_customer_proto_rawDescData
+}
+
+var file_videorental_v1_customer_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_videorental_v1_customer_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_videorental_v1_customer_proto_goTypes = []any{
+	(RentalStatus)(0),                   // 0: videorental.v1.RentalStatus
+	(*Customer)(nil),                    // 1: videorental.v1.Customer
+	(*ListCustomersRequest)(nil),        // 2: videorental.v1.ListCustomersRequest
+	(*ListCustomersResponse)(nil),       // 3: videorental.v1.ListCustomersResponse
+	(*GetCustomerRequest)(nil),          // 4: videorental.v1.GetCustomerRequest
+	(*CreateCustomerRequest)(nil),       // 5: videorental.v1.CreateCustomerRequest
+	(*Address)(nil),                     // 6: videorental.v1.Address
+	(*ListCustomerRentalsRequest)(nil),  // 7: videorental.v1.ListCustomerRentalsRequest
+	(*ListCustomerRentalsResponse)(nil), // 8: videorental.v1.ListCustomerRentalsResponse
+	(*CustomerRental)(nil),              // 9: videorental.v1.CustomerRental
+	(*GetCustomerSummaryRequest)(nil),   // 10: videorental.v1.GetCustomerSummaryRequest
+	(*CustomerSummary)(nil),             // 11: videorental.v1.CustomerSummary
+	(*CategoryCount)(nil),               // 12: videorental.v1.CategoryCount
+	(*timestamppb.Timestamp)(nil),       // 13: google.protobuf.Timestamp
+	(*Page)(nil),                        // 14: videorental.v1.Page
+}
+var file_videorental_v1_customer_proto_depIdxs = []int32{
+	13, // 0: videorental.v1.Customer.last_update:type_name -> google.protobuf.Timestamp
+	14, // 1: videorental.v1.ListCustomersRequest.page:type_name -> videorental.v1.Page
+	1,  // 2: videorental.v1.ListCustomersResponse.customers:type_name -> videorental.v1.Customer
+	6,  // 3: videorental.v1.CreateCustomerRequest.address:type_name -> videorental.v1.Address
+	0,  // 4: videorental.v1.ListCustomerRentalsRequest.status:type_name -> videorental.v1.RentalStatus
+	13, // 5: videorental.v1.ListCustomerRentalsRequest.from:type_name -> google.protobuf.Timestamp
+	13, // 6: videorental.v1.ListCustomerRentalsRequest.to:type_name -> google.protobuf.Timestamp
+	14, // 7: videorental.v1.ListCustomerRentalsRequest.page:type_name -> videorental.v1.Page
+	9,  // 8: videorental.v1.ListCustomerRentalsResponse.rentals:type_name -> videorental.v1.CustomerRental
+	13, // 9: videorental.v1.CustomerRental.rental_date:type_name -> google.protobuf.Timestamp
+	13, // 10: videorental.v1.CustomerRental.due_date:type_name -> google.protobuf.Timestamp
+	13, // 11: videorental.v1.CustomerRental.return_date:type_name -> google.protobuf.Timestamp
+	13, // 12: videorental.v1.CustomerSummary.first_rental_date:type_name -> google.protobuf.Timestamp
+	13, // 13: videorental.v1.CustomerSummary.last_rental_date:type_name -> google.protobuf.Timestamp
+	12, // 14: videorental.v1.CustomerSummary.favorite_categories:type_name -> videorental.v1.CategoryCount
+	2,  // 15: videorental.v1.CustomerService.ListCustomers:input_type -> videorental.v1.ListCustomersRequest
+	4,  // 16: videorental.v1.CustomerService.GetCustomer:input_type -> videorental.v1.GetCustomerRequest
+	5,  // 17: videorental.v1.CustomerService.CreateCustomer:input_type -> videorental.v1.CreateCustomerRequest
+	7,  // 18: videorental.v1.CustomerService.ListCustomerRentals:input_type -> videorental.v1.ListCustomerRentalsRequest
+	10, // 19: videorental.v1.CustomerService.GetCustomerSummary:input_type -> videorental.v1.GetCustomerSummaryRequest
+	3,  // 20: videorental.v1.CustomerService.ListCustomers:output_type -> videorental.v1.ListCustomersResponse
+	1,  // 21: videorental.v1.CustomerService.GetCustomer:output_type -> videorental.v1.Customer
+	1,  // 22: videorental.v1.CustomerService.CreateCustomer:output_type -> videorental.v1.Customer
+	8,  // 23: videorental.v1.CustomerService.ListCustomerRentals:output_type -> videorental.v1.ListCustomerRentalsResponse
+	11, // 24: videorental.v1.CustomerService.GetCustomerSummary:output_type -> videorental.v1.CustomerSummary
+	20, // [20:25] is the sub-list for method output_type
+	15, // [15:20] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
+}
+
+func init() { file_videorental_v1_customer_proto_init() }
+func file_videorental_v1_customer_proto_init() {
+	if File_videorental_v1_customer_proto != nil {
+		return
+	}
+	file_videorental_v1_common_proto_init()
+	file_videorental_v1_customer_proto_msgTypes[1].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_videorental_v1_customer_proto_rawDesc), len(file_videorental_v1_customer_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_videorental_v1_customer_proto_goTypes,
+		DependencyIndexes: file_videorental_v1_customer_proto_depIdxs,
+		EnumInfos:         file_videorental_v1_customer_proto_enumTypes,
+		MessageInfos:      file_videorental_v1_customer_proto_msgTypes,
+	}.Build()
+	File_videorental_v1_customer_proto = out.File
+	file_videorental_v1_customer_proto_goTypes = nil
+	file_videorental_v1_customer_proto_depIdxs = nil
+}
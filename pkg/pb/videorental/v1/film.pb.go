@@ -0,0 +1,336 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: videorental/v1/film.proto
+
+package videorentalv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Film struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	ReleaseYear   int32                  `protobuf:"varint,4,opt,name=release_year,json=releaseYear,proto3" json:"release_year,omitempty"`
+	Language      string                 `protobuf:"bytes,5,opt,name=language,proto3" json:"language,omitempty"`
+	Rating        string                 `protobuf:"bytes,6,opt,name=rating,proto3" json:"rating,omitempty"`
+	Actors        []string               `protobuf:"bytes,7,rep,name=actors,proto3" json:"actors,omitempty"`
+	Categories    []string               `protobuf:"bytes,8,rep,name=categories,proto3" json:"categories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Film) Reset() {
+	*x = Film{}
+	mi := &file_videorental_v1_film_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Film) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Film) ProtoMessage() {}
+
+func (x *Film) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_film_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Film.ProtoReflect.Descriptor instead.
+func (*Film) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_film_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Film) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Film) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Film) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Film) GetReleaseYear() int32 {
+	if x != nil {
+		return x.ReleaseYear
+	}
+	return 0
+}
+
+func (x *Film) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *Film) GetRating() string {
+	if x != nil {
+		return x.Rating
+	}
+	return ""
+}
+
+func (x *Film) GetActors() []string {
+	if x != nil {
+		return x.Actors
+	}
+	return nil
+}
+
+func (x *Film) GetCategories() []string {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+type ListFilmsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFilmsRequest) Reset() {
+	*x = ListFilmsRequest{}
+	mi := &file_videorental_v1_film_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFilmsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFilmsRequest) ProtoMessage() {}
+
+func (x *ListFilmsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_film_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFilmsRequest.ProtoReflect.Descriptor instead.
+func (*ListFilmsRequest) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_film_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListFilmsRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+type ListFilmsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Films         []*Film                `protobuf:"bytes,1,rep,name=films,proto3" json:"films,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFilmsResponse) Reset() {
+	*x = ListFilmsResponse{}
+	mi := &file_videorental_v1_film_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFilmsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFilmsResponse) ProtoMessage() {}
+
+func (x *ListFilmsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_film_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFilmsResponse.ProtoReflect.Descriptor instead.
+func (*ListFilmsResponse) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_film_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListFilmsResponse) GetFilms() []*Film {
+	if x != nil {
+		return x.Films
+	}
+	return nil
+}
+
+type GetFilmRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFilmRequest) Reset() {
+	*x = GetFilmRequest{}
+	mi := &file_videorental_v1_film_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFilmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFilmRequest) ProtoMessage() {}
+
+func (x *GetFilmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_film_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFilmRequest.ProtoReflect.Descriptor instead.
+func (*GetFilmRequest) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_film_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetFilmRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+var File_videorental_v1_film_proto protoreflect.FileDescriptor
+
+const file_videorental_v1_film_proto_rawDesc = "" +
+	"\n" +
+	"\x19videorental/v1/film.proto\x12\x0evideorental.v1\"\xdd\x01\n" +
+	"\x04Film\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12!\n" +
+	"\frelease_year\x18\x04 \x01(\x05R\vreleaseYear\x12\x1a\n" +
+	"\blanguage\x18\x05 \x01(\tR\blanguage\x12\x16\n" +
+	"\x06rating\x18\x06 \x01(\tR\x06rating\x12\x16\n" +
+	"\x06actors\x18\a \x03(\tR\x06actors\x12\x1e\n" +
+	"\n" +
+	"categories\x18\b \x03(\tR\n" +
+	"categories\"(\n" +
+	"\x10ListFilmsRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\"?\n" +
+	"\x11ListFilmsResponse\x12*\n" +
+	"\x05films\x18\x01 \x03(\v2\x14.videorental.v1.FilmR\x05films\" \n" +
+	"\x0eGetFilmRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id2\xa0\x01\n" +
+	"\vFilmService\x12P\n" +
+	"\tListFilms\x12 .videorental.v1.ListFilmsRequest\x1a!.videorental.v1.ListFilmsResponse\x12?\n" +
+	"\aGetFilm\x12\x1e.videorental.v1.GetFilmRequest\x1a\x14.videorental.v1.FilmBRZPgithub.com/rstoltzm-profile/video-rental-api/pkg/pb/videorental/v1;videorentalv1b\x06proto3"
+
+var (
+	file_videorental_v1_film_proto_rawDescOnce sync.Once
+	file_videorental_v1_film_proto_rawDescData []byte
+)
+
+func file_videorental_v1_film_proto_rawDescGZIP() []byte {
+	file_videorental_v1_film_proto_rawDescOnce.Do(func() {
+		file_videorental_v1_film_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_videorental_v1_film_proto_rawDesc), len(file_videorental_v1_film_proto_rawDesc)))
+	})
+	return file_videorental_v1_film_proto_rawDescData
+}
+
+var file_videorental_v1_film_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_videorental_v1_film_proto_goTypes = []any{
+	(*Film)(nil),              // 0: videorental.v1.Film
+	(*ListFilmsRequest)(nil),  // 1: videorental.v1.ListFilmsRequest
+	(*ListFilmsResponse)(nil), // 2: videorental.v1.ListFilmsResponse
+	(*GetFilmRequest)(nil),    // 3: videorental.v1.GetFilmRequest
+}
+var file_videorental_v1_film_proto_depIdxs = []int32{
+	0, // 0: videorental.v1.ListFilmsResponse.films:type_name -> videorental.v1.Film
+	1, // 1: videorental.v1.FilmService.ListFilms:input_type -> videorental.v1.ListFilmsRequest
+	3, // 2: videorental.v1.FilmService.GetFilm:input_type -> videorental.v1.GetFilmRequest
+	2, // 3: videorental.v1.FilmService.ListFilms:output_type -> videorental.v1.ListFilmsResponse
+	0, // 4: videorental.v1.FilmService.GetFilm:output_type -> videorental.v1.Film
+	3, // [3:5] is the sub-list for method output_type
+	1, // [1:3] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_videorental_v1_film_proto_init() }
+func file_videorental_v1_film_proto_init() {
+	if File_videorental_v1_film_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_videorental_v1_film_proto_rawDesc), len(file_videorental_v1_film_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_videorental_v1_film_proto_goTypes,
+		DependencyIndexes: file_videorental_v1_film_proto_depIdxs,
+		MessageInfos:      file_videorental_v1_film_proto_msgTypes,
+	}.Build()
+	File_videorental_v1_film_proto = out.File
+	file_videorental_v1_film_proto_goTypes = nil
+	file_videorental_v1_film_proto_depIdxs = nil
+}
@@ -0,0 +1,163 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: videorental/v1/film.proto
+
+package videorentalv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	FilmService_ListFilms_FullMethodName = "/videorental.v1.FilmService/ListFilms"
+	FilmService_GetFilm_FullMethodName   = "/videorental.v1.FilmService/GetFilm"
+)
+
+// FilmServiceClient is the client API for FilmService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type FilmServiceClient interface {
+	// Every film, or the films whose title matches. Actors and categories are
+	// left empty, GetFilm has them.
+	ListFilms(ctx context.Context, in *ListFilmsRequest, opts ...grpc.CallOption) (*ListFilmsResponse, error)
+	GetFilm(ctx context.Context, in *GetFilmRequest, opts ...grpc.CallOption) (*Film, error)
+}
+
+type filmServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFilmServiceClient(cc grpc.ClientConnInterface) FilmServiceClient {
+	return &filmServiceClient{cc}
+}
+
+func (c *filmServiceClient) ListFilms(ctx context.Context, in *ListFilmsRequest, opts ...grpc.CallOption) (*ListFilmsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFilmsResponse)
+	err := c.cc.Invoke(ctx, FilmService_ListFilms_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *filmServiceClient) GetFilm(ctx context.Context, in *GetFilmRequest, opts ...grpc.CallOption) (*Film, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Film)
+	err := c.cc.Invoke(ctx, FilmService_GetFilm_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FilmServiceServer is the server API for FilmService service.
+// All implementations must embed UnimplementedFilmServiceServer
+// for forward compatibility.
+type FilmServiceServer interface {
+	// Every film, or the films whose title matches. Actors and categories are
+	// left empty, GetFilm has them.
+	ListFilms(context.Context, *ListFilmsRequest) (*ListFilmsResponse, error)
+	GetFilm(context.Context, *GetFilmRequest) (*Film, error)
+	mustEmbedUnimplementedFilmServiceServer()
+}
+
+// UnimplementedFilmServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFilmServiceServer struct{}
+
+func (UnimplementedFilmServiceServer) ListFilms(context.Context, *ListFilmsRequest) (*ListFilmsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFilms not implemented")
+}
+func (UnimplementedFilmServiceServer) GetFilm(context.Context, *GetFilmRequest) (*Film, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFilm not implemented")
+}
+func (UnimplementedFilmServiceServer) mustEmbedUnimplementedFilmServiceServer() {}
+func (UnimplementedFilmServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeFilmServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FilmServiceServer will
+// result in compilation errors.
+type UnsafeFilmServiceServer interface {
+	mustEmbedUnimplementedFilmServiceServer()
+}
+
+func RegisterFilmServiceServer(s grpc.ServiceRegistrar, srv FilmServiceServer) {
+	// If the following call pancis, it indicates UnimplementedFilmServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FilmService_ServiceDesc, srv)
+}
+
+func _FilmService_ListFilms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFilmsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FilmServiceServer).ListFilms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FilmService_ListFilms_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FilmServiceServer).ListFilms(ctx, req.(*ListFilmsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FilmService_GetFilm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFilmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FilmServiceServer).GetFilm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FilmService_GetFilm_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FilmServiceServer).GetFilm(ctx, req.(*GetFilmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FilmService_ServiceDesc is the grpc.ServiceDesc for FilmService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FilmService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "videorental.v1.FilmService",
+	HandlerType: (*FilmServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListFilms",
+			Handler:    _FilmService_ListFilms_Handler,
+		},
+		{
+			MethodName: "GetFilm",
+			Handler:    _FilmService_GetFilm_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "videorental/v1/film.proto",
+}
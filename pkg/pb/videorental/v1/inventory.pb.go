@@ -0,0 +1,550 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: videorental/v1/inventory.proto
+
+package videorentalv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type InventoryItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InventoryId   int32                  `protobuf:"varint,1,opt,name=inventory_id,json=inventoryId,proto3" json:"inventory_id,omitempty"`
+	FilmId        int32                  `protobuf:"varint,2,opt,name=film_id,json=filmId,proto3" json:"film_id,omitempty"`
+	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	StoreId       int32                  `protobuf:"varint,4,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+	LastUpdate    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_update,json=lastUpdate,proto3" json:"last_update,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InventoryItem) Reset() {
+	*x = InventoryItem{}
+	mi := &file_videorental_v1_inventory_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InventoryItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InventoryItem) ProtoMessage() {}
+
+func (x *InventoryItem) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_inventory_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InventoryItem.ProtoReflect.Descriptor instead.
+func (*InventoryItem) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_inventory_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *InventoryItem) GetInventoryId() int32 {
+	if x != nil {
+		return x.InventoryId
+	}
+	return 0
+}
+
+func (x *InventoryItem) GetFilmId() int32 {
+	if x != nil {
+		return x.FilmId
+	}
+	return 0
+}
+
+func (x *InventoryItem) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *InventoryItem) GetStoreId() int32 {
+	if x != nil {
+		return x.StoreId
+	}
+	return 0
+}
+
+func (x *InventoryItem) GetLastUpdate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUpdate
+	}
+	return nil
+}
+
+type ListInventoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StoreId       *int32                 `protobuf:"varint,1,opt,name=store_id,json=storeId,proto3,oneof" json:"store_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListInventoryRequest) Reset() {
+	*x = ListInventoryRequest{}
+	mi := &file_videorental_v1_inventory_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInventoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInventoryRequest) ProtoMessage() {}
+
+func (x *ListInventoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_inventory_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInventoryRequest.ProtoReflect.Descriptor instead.
+func (*ListInventoryRequest) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_inventory_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListInventoryRequest) GetStoreId() int32 {
+	if x != nil && x.StoreId != nil {
+		return *x.StoreId
+	}
+	return 0
+}
+
+type GetFilmAvailabilityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FilmId        int32                  `protobuf:"varint,1,opt,name=film_id,json=filmId,proto3" json:"film_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFilmAvailabilityRequest) Reset() {
+	*x = GetFilmAvailabilityRequest{}
+	mi := &file_videorental_v1_inventory_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFilmAvailabilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFilmAvailabilityRequest) ProtoMessage() {}
+
+func (x *GetFilmAvailabilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_inventory_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFilmAvailabilityRequest.ProtoReflect.Descriptor instead.
+func (*GetFilmAvailabilityRequest) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_inventory_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetFilmAvailabilityRequest) GetFilmId() int32 {
+	if x != nil {
+		return x.FilmId
+	}
+	return 0
+}
+
+type GetStoreAvailabilityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StoreId       int32                  `protobuf:"varint,1,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+	FilmId        int32                  `protobuf:"varint,2,opt,name=film_id,json=filmId,proto3" json:"film_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStoreAvailabilityRequest) Reset() {
+	*x = GetStoreAvailabilityRequest{}
+	mi := &file_videorental_v1_inventory_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStoreAvailabilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStoreAvailabilityRequest) ProtoMessage() {}
+
+func (x *GetStoreAvailabilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_inventory_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStoreAvailabilityRequest.ProtoReflect.Descriptor instead.
+func (*GetStoreAvailabilityRequest) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_inventory_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetStoreAvailabilityRequest) GetStoreId() int32 {
+	if x != nil {
+		return x.StoreId
+	}
+	return 0
+}
+
+func (x *GetStoreAvailabilityRequest) GetFilmId() int32 {
+	if x != nil {
+		return x.FilmId
+	}
+	return 0
+}
+
+// A film at one store. Copies held for a reservation are not available.
+type StoreAvailability struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	StoreId               int32                  `protobuf:"varint,1,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+	FilmId                int32                  `protobuf:"varint,2,opt,name=film_id,json=filmId,proto3" json:"film_id,omitempty"`
+	Title                 string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Available             bool                   `protobuf:"varint,4,opt,name=available,proto3" json:"available,omitempty"`
+	AvailableInventoryIds []int32                `protobuf:"varint,5,rep,packed,name=available_inventory_ids,json=availableInventoryIds,proto3" json:"available_inventory_ids,omitempty"`
+	AvailableCopies       int32                  `protobuf:"varint,6,opt,name=available_copies,json=availableCopies,proto3" json:"available_copies,omitempty"`
+	HeldCopies            int32                  `protobuf:"varint,7,opt,name=held_copies,json=heldCopies,proto3" json:"held_copies,omitempty"`
+	TotalCopies           int32                  `protobuf:"varint,8,opt,name=total_copies,json=totalCopies,proto3" json:"total_copies,omitempty"`
+	// soonest due date of a rented out copy
+	NextReturnDue *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=next_return_due,json=nextReturnDue,proto3" json:"next_return_due,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoreAvailability) Reset() {
+	*x = StoreAvailability{}
+	mi := &file_videorental_v1_inventory_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoreAvailability) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreAvailability) ProtoMessage() {}
+
+func (x *StoreAvailability) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_inventory_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreAvailability.ProtoReflect.Descriptor instead.
+func (*StoreAvailability) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_inventory_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StoreAvailability) GetStoreId() int32 {
+	if x != nil {
+		return x.StoreId
+	}
+	return 0
+}
+
+func (x *StoreAvailability) GetFilmId() int32 {
+	if x != nil {
+		return x.FilmId
+	}
+	return 0
+}
+
+func (x *StoreAvailability) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *StoreAvailability) GetAvailable() bool {
+	if x != nil {
+		return x.Available
+	}
+	return false
+}
+
+func (x *StoreAvailability) GetAvailableInventoryIds() []int32 {
+	if x != nil {
+		return x.AvailableInventoryIds
+	}
+	return nil
+}
+
+func (x *StoreAvailability) GetAvailableCopies() int32 {
+	if x != nil {
+		return x.AvailableCopies
+	}
+	return 0
+}
+
+func (x *StoreAvailability) GetHeldCopies() int32 {
+	if x != nil {
+		return x.HeldCopies
+	}
+	return 0
+}
+
+func (x *StoreAvailability) GetTotalCopies() int32 {
+	if x != nil {
+		return x.TotalCopies
+	}
+	return 0
+}
+
+func (x *StoreAvailability) GetNextReturnDue() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextReturnDue
+	}
+	return nil
+}
+
+type FilmAvailability struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	FilmId          int32                  `protobuf:"varint,1,opt,name=film_id,json=filmId,proto3" json:"film_id,omitempty"`
+	Title           string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Available       bool                   `protobuf:"varint,3,opt,name=available,proto3" json:"available,omitempty"`
+	AvailableCopies int32                  `protobuf:"varint,4,opt,name=available_copies,json=availableCopies,proto3" json:"available_copies,omitempty"`
+	TotalCopies     int32                  `protobuf:"varint,5,opt,name=total_copies,json=totalCopies,proto3" json:"total_copies,omitempty"`
+	NextReturnDue   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=next_return_due,json=nextReturnDue,proto3" json:"next_return_due,omitempty"`
+	Stores          []*StoreAvailability   `protobuf:"bytes,7,rep,name=stores,proto3" json:"stores,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *FilmAvailability) Reset() {
+	*x = FilmAvailability{}
+	mi := &file_videorental_v1_inventory_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FilmAvailability) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilmAvailability) ProtoMessage() {}
+
+func (x *FilmAvailability) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_inventory_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilmAvailability.ProtoReflect.Descriptor instead.
+func (*FilmAvailability) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_inventory_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *FilmAvailability) GetFilmId() int32 {
+	if x != nil {
+		return x.FilmId
+	}
+	return 0
+}
+
+func (x *FilmAvailability) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *FilmAvailability) GetAvailable() bool {
+	if x != nil {
+		return x.Available
+	}
+	return false
+}
+
+func (x *FilmAvailability) GetAvailableCopies() int32 {
+	if x != nil {
+		return x.AvailableCopies
+	}
+	return 0
+}
+
+func (x *FilmAvailability) GetTotalCopies() int32 {
+	if x != nil {
+		return x.TotalCopies
+	}
+	return 0
+}
+
+func (x *FilmAvailability) GetNextReturnDue() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextReturnDue
+	}
+	return nil
+}
+
+func (x *FilmAvailability) GetStores() []*StoreAvailability {
+	if x != nil {
+		return x.Stores
+	}
+	return nil
+}
+
+var File_videorental_v1_inventory_proto protoreflect.FileDescriptor
+
+const file_videorental_v1_inventory_proto_rawDesc = "" +
+	"\n" +
+	"\x1evideorental/v1/inventory.proto\x12\x0evideorental.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb9\x01\n" +
+	"\rInventoryItem\x12!\n" +
+	"\finventory_id\x18\x01 \x01(\x05R\vinventoryId\x12\x17\n" +
+	"\afilm_id\x18\x02 \x01(\x05R\x06filmId\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x19\n" +
+	"\bstore_id\x18\x04 \x01(\x05R\astoreId\x12;\n" +
+	"\vlast_update\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"lastUpdate\"C\n" +
+	"\x14ListInventoryRequest\x12\x1e\n" +
+	"\bstore_id\x18\x01 \x01(\x05H\x00R\astoreId\x88\x01\x01B\v\n" +
+	"\t_store_id\"5\n" +
+	"\x1aGetFilmAvailabilityRequest\x12\x17\n" +
+	"\afilm_id\x18\x01 \x01(\x05R\x06filmId\"Q\n" +
+	"\x1bGetStoreAvailabilityRequest\x12\x19\n" +
+	"\bstore_id\x18\x01 \x01(\x05R\astoreId\x12\x17\n" +
+	"\afilm_id\x18\x02 \x01(\x05R\x06filmId\"\xe6\x02\n" +
+	"\x11StoreAvailability\x12\x19\n" +
+	"\bstore_id\x18\x01 \x01(\x05R\astoreId\x12\x17\n" +
+	"\afilm_id\x18\x02 \x01(\x05R\x06filmId\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x1c\n" +
+	"\tavailable\x18\x04 \x01(\bR\tavailable\x126\n" +
+	"\x17available_inventory_ids\x18\x05 \x03(\x05R\x15availableInventoryIds\x12)\n" +
+	"\x10available_copies\x18\x06 \x01(\x05R\x0favailableCopies\x12\x1f\n" +
+	"\vheld_copies\x18\a \x01(\x05R\n" +
+	"heldCopies\x12!\n" +
+	"\ftotal_copies\x18\b \x01(\x05R\vtotalCopies\x12B\n" +
+	"\x0fnext_return_due\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\rnextReturnDue\"\xac\x02\n" +
+	"\x10FilmAvailability\x12\x17\n" +
+	"\afilm_id\x18\x01 \x01(\x05R\x06filmId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x1c\n" +
+	"\tavailable\x18\x03 \x01(\bR\tavailable\x12)\n" +
+	"\x10available_copies\x18\x04 \x01(\x05R\x0favailableCopies\x12!\n" +
+	"\ftotal_copies\x18\x05 \x01(\x05R\vtotalCopies\x12B\n" +
+	"\x0fnext_return_due\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\rnextReturnDue\x129\n" +
+	"\x06stores\x18\a \x03(\v2!.videorental.v1.StoreAvailabilityR\x06stores2\xb7\x02\n" +
+	"\x10InventoryService\x12V\n" +
+	"\rListInventory\x12$.videorental.v1.ListInventoryRequest\x1a\x1d.videorental.v1.InventoryItem0\x01\x12c\n" +
+	"\x13GetFilmAvailability\x12*.videorental.v1.GetFilmAvailabilityRequest\x1a .videorental.v1.FilmAvailability\x12f\n" +
+	"\x14GetStoreAvailability\x12+.videorental.v1.GetStoreAvailabilityRequest\x1a!.videorental.v1.StoreAvailabilityBRZPgithub.com/rstoltzm-profile/video-rental-api/pkg/pb/videorental/v1;videorentalv1b\x06proto3"
+
+var (
+	file_videorental_v1_inventory_proto_rawDescOnce sync.Once
+	file_videorental_v1_inventory_proto_rawDescData []byte
+)
+
+func file_videorental_v1_inventory_proto_rawDescGZIP() []byte {
+	file_videorental_v1_inventory_proto_rawDescOnce.Do(func() {
+		file_videorental_v1_inventory_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_videorental_v1_inventory_proto_rawDesc), len(file_videorental_v1_inventory_proto_rawDesc)))
+	})
+	return file_videorental_v1_inventory_proto_rawDescData
+}
+
+var file_videorental_v1_inventory_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_videorental_v1_inventory_proto_goTypes = []any{
+	(*InventoryItem)(nil),               // 0: videorental.v1.InventoryItem
+	(*ListInventoryRequest)(nil),        // 1: videorental.v1.ListInventoryRequest
+	(*GetFilmAvailabilityRequest)(nil),  // 2: videorental.v1.GetFilmAvailabilityRequest
+	(*GetStoreAvailabilityRequest)(nil), // 3: videorental.v1.GetStoreAvailabilityRequest
+	(*StoreAvailability)(nil),           // 4: videorental.v1.StoreAvailability
+	(*FilmAvailability)(nil),            // 5: videorental.v1.FilmAvailability
+	(*timestamppb.Timestamp)(nil),       // 6: google.protobuf.Timestamp
+}
+var file_videorental_v1_inventory_proto_depIdxs = []int32{
+	6, // 0: videorental.v1.InventoryItem.last_update:type_name -> google.protobuf.Timestamp
+	6, // 1: videorental.v1.StoreAvailability.next_return_due:type_name -> google.protobuf.Timestamp
+	6, // 2: videorental.v1.FilmAvailability.next_return_due:type_name -> google.protobuf.Timestamp
+	4, // 3: videorental.v1.FilmAvailability.stores:type_name -> videorental.v1.StoreAvailability
+	1, // 4: videorental.v1.InventoryService.ListInventory:input_type -> videorental.v1.ListInventoryRequest
+	2, // 5: videorental.v1.InventoryService.GetFilmAvailability:input_type -> videorental.v1.GetFilmAvailabilityRequest
+	3, // 6: videorental.v1.InventoryService.GetStoreAvailability:input_type -> videorental.v1.GetStoreAvailabilityRequest
+	0, // 7: videorental.v1.InventoryService.ListInventory:output_type -> videorental.v1.InventoryItem
+	5, // 8: videorental.v1.InventoryService.GetFilmAvailability:output_type -> videorental.v1.FilmAvailability
+	4, // 9: videorental.v1.InventoryService.GetStoreAvailability:output_type -> videorental.v1.StoreAvailability
+	7, // [7:10] is the sub-list for method output_type
+	4, // [4:7] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_videorental_v1_inventory_proto_init() }
+func file_videorental_v1_inventory_proto_init() {
+	if File_videorental_v1_inventory_proto != nil {
+		return
+	}
+	file_videorental_v1_inventory_proto_msgTypes[1].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_videorental_v1_inventory_proto_rawDesc), len(file_videorental_v1_inventory_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_videorental_v1_inventory_proto_goTypes,
+		DependencyIndexes: file_videorental_v1_inventory_proto_depIdxs,
+		MessageInfos:      file_videorental_v1_inventory_proto_msgTypes,
+	}.Build()
+	File_videorental_v1_inventory_proto = out.File
+	file_videorental_v1_inventory_proto_goTypes = nil
+	file_videorental_v1_inventory_proto_depIdxs = nil
+}
@@ -0,0 +1,203 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: videorental/v1/inventory.proto
+
+package videorentalv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	InventoryService_ListInventory_FullMethodName        = "/videorental.v1.InventoryService/ListInventory"
+	InventoryService_GetFilmAvailability_FullMethodName  = "/videorental.v1.InventoryService/GetFilmAvailability"
+	InventoryService_GetStoreAvailability_FullMethodName = "/videorental.v1.InventoryService/GetStoreAvailability"
+)
+
+// InventoryServiceClient is the client API for InventoryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type InventoryServiceClient interface {
+	// Streams every copy, or one store's copies, in inventory id order.
+	ListInventory(ctx context.Context, in *ListInventoryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[InventoryItem], error)
+	GetFilmAvailability(ctx context.Context, in *GetFilmAvailabilityRequest, opts ...grpc.CallOption) (*FilmAvailability, error)
+	GetStoreAvailability(ctx context.Context, in *GetStoreAvailabilityRequest, opts ...grpc.CallOption) (*StoreAvailability, error)
+}
+
+type inventoryServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewInventoryServiceClient(cc grpc.ClientConnInterface) InventoryServiceClient {
+	return &inventoryServiceClient{cc}
+}
+
+func (c *inventoryServiceClient) ListInventory(ctx context.Context, in *ListInventoryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[InventoryItem], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &InventoryService_ServiceDesc.Streams[0], InventoryService_ListInventory_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListInventoryRequest, InventoryItem]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type InventoryService_ListInventoryClient = grpc.ServerStreamingClient[InventoryItem]
+
+func (c *inventoryServiceClient) GetFilmAvailability(ctx context.Context, in *GetFilmAvailabilityRequest, opts ...grpc.CallOption) (*FilmAvailability, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FilmAvailability)
+	err := c.cc.Invoke(ctx, InventoryService_GetFilmAvailability_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) GetStoreAvailability(ctx context.Context, in *GetStoreAvailabilityRequest, opts ...grpc.CallOption) (*StoreAvailability, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StoreAvailability)
+	err := c.cc.Invoke(ctx, InventoryService_GetStoreAvailability_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InventoryServiceServer is the server API for InventoryService service.
+// All implementations must embed UnimplementedInventoryServiceServer
+// for forward compatibility.
+type InventoryServiceServer interface {
+	// Streams every copy, or one store's copies, in inventory id order.
+	ListInventory(*ListInventoryRequest, grpc.ServerStreamingServer[InventoryItem]) error
+	GetFilmAvailability(context.Context, *GetFilmAvailabilityRequest) (*FilmAvailability, error)
+	GetStoreAvailability(context.Context, *GetStoreAvailabilityRequest) (*StoreAvailability, error)
+	mustEmbedUnimplementedInventoryServiceServer()
+}
+
+// UnimplementedInventoryServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedInventoryServiceServer struct{}
+
+func (UnimplementedInventoryServiceServer) ListInventory(*ListInventoryRequest, grpc.ServerStreamingServer[InventoryItem]) error {
+	return status.Errorf(codes.Unimplemented, "method ListInventory not implemented")
+}
+func (UnimplementedInventoryServiceServer) GetFilmAvailability(context.Context, *GetFilmAvailabilityRequest) (*FilmAvailability, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFilmAvailability not implemented")
+}
+func (UnimplementedInventoryServiceServer) GetStoreAvailability(context.Context, *GetStoreAvailabilityRequest) (*StoreAvailability, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStoreAvailability not implemented")
+}
+func (UnimplementedInventoryServiceServer) mustEmbedUnimplementedInventoryServiceServer() {}
+func (UnimplementedInventoryServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeInventoryServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to InventoryServiceServer will
+// result in compilation errors.
+type UnsafeInventoryServiceServer interface {
+	mustEmbedUnimplementedInventoryServiceServer()
+}
+
+func RegisterInventoryServiceServer(s grpc.ServiceRegistrar, srv InventoryServiceServer) {
+	// If the following call pancis, it indicates UnimplementedInventoryServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&InventoryService_ServiceDesc, srv)
+}
+
+func _InventoryService_ListInventory_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListInventoryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InventoryServiceServer).ListInventory(m, &grpc.GenericServerStream[ListInventoryRequest, InventoryItem]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type InventoryService_ListInventoryServer = grpc.ServerStreamingServer[InventoryItem]
+
+func _InventoryService_GetFilmAvailability_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFilmAvailabilityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).GetFilmAvailability(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_GetFilmAvailability_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).GetFilmAvailability(ctx, req.(*GetFilmAvailabilityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_GetStoreAvailability_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStoreAvailabilityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).GetStoreAvailability(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_GetStoreAvailability_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).GetStoreAvailability(ctx, req.(*GetStoreAvailabilityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// InventoryService_ServiceDesc is the grpc.ServiceDesc for InventoryService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var InventoryService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "videorental.v1.InventoryService",
+	HandlerType: (*InventoryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetFilmAvailability",
+			Handler:    _InventoryService_GetFilmAvailability_Handler,
+		},
+		{
+			MethodName: "GetStoreAvailability",
+			Handler:    _InventoryService_GetStoreAvailability_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListInventory",
+			Handler:       _InventoryService_ListInventory_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "videorental/v1/inventory.proto",
+}
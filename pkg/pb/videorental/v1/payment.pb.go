@@ -0,0 +1,472 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: videorental/v1/payment.proto
+
+package videorentalv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Payment struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	CustomerId int32                  `protobuf:"varint,2,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	StaffId    int32                  `protobuf:"varint,3,opt,name=staff_id,json=staffId,proto3" json:"staff_id,omitempty"`
+	RentalId   int32                  `protobuf:"varint,4,opt,name=rental_id,json=rentalId,proto3" json:"rental_id,omitempty"`
+	// the store that owns the rented copy
+	StoreId       int32                  `protobuf:"varint,5,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+	Amount        float64                `protobuf:"fixed64,6,opt,name=amount,proto3" json:"amount,omitempty"`
+	PaymentDate   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=payment_date,json=paymentDate,proto3" json:"payment_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Payment) Reset() {
+	*x = Payment{}
+	mi := &file_videorental_v1_payment_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Payment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Payment) ProtoMessage() {}
+
+func (x *Payment) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_payment_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Payment.ProtoReflect.Descriptor instead.
+func (*Payment) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_payment_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Payment) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Payment) GetCustomerId() int32 {
+	if x != nil {
+		return x.CustomerId
+	}
+	return 0
+}
+
+func (x *Payment) GetStaffId() int32 {
+	if x != nil {
+		return x.StaffId
+	}
+	return 0
+}
+
+func (x *Payment) GetRentalId() int32 {
+	if x != nil {
+		return x.RentalId
+	}
+	return 0
+}
+
+func (x *Payment) GetStoreId() int32 {
+	if x != nil {
+		return x.StoreId
+	}
+	return 0
+}
+
+func (x *Payment) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Payment) GetPaymentDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PaymentDate
+	}
+	return nil
+}
+
+type ListPaymentsRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	CustomerId *int32                 `protobuf:"varint,1,opt,name=customer_id,json=customerId,proto3,oneof" json:"customer_id,omitempty"`
+	StaffId    *int32                 `protobuf:"varint,2,opt,name=staff_id,json=staffId,proto3,oneof" json:"staff_id,omitempty"`
+	StoreId    *int32                 `protobuf:"varint,3,opt,name=store_id,json=storeId,proto3,oneof" json:"store_id,omitempty"`
+	// bound the payment date, to is exclusive
+	From          *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=to,proto3" json:"to,omitempty"`
+	Page          *Page                  `protobuf:"bytes,6,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPaymentsRequest) Reset() {
+	*x = ListPaymentsRequest{}
+	mi := &file_videorental_v1_payment_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPaymentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPaymentsRequest) ProtoMessage() {}
+
+func (x *ListPaymentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_payment_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPaymentsRequest.ProtoReflect.Descriptor instead.
+func (*ListPaymentsRequest) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_payment_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListPaymentsRequest) GetCustomerId() int32 {
+	if x != nil && x.CustomerId != nil {
+		return *x.CustomerId
+	}
+	return 0
+}
+
+func (x *ListPaymentsRequest) GetStaffId() int32 {
+	if x != nil && x.StaffId != nil {
+		return *x.StaffId
+	}
+	return 0
+}
+
+func (x *ListPaymentsRequest) GetStoreId() int32 {
+	if x != nil && x.StoreId != nil {
+		return *x.StoreId
+	}
+	return 0
+}
+
+func (x *ListPaymentsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ListPaymentsRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *ListPaymentsRequest) GetPage() *Page {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+type ListPaymentsResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Payments []*Payment             `protobuf:"bytes,1,rep,name=payments,proto3" json:"payments,omitempty"`
+	// every match, not only this page
+	Total         int32 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPaymentsResponse) Reset() {
+	*x = ListPaymentsResponse{}
+	mi := &file_videorental_v1_payment_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPaymentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPaymentsResponse) ProtoMessage() {}
+
+func (x *ListPaymentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_payment_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPaymentsResponse.ProtoReflect.Descriptor instead.
+func (*ListPaymentsResponse) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_payment_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListPaymentsResponse) GetPayments() []*Payment {
+	if x != nil {
+		return x.Payments
+	}
+	return nil
+}
+
+func (x *ListPaymentsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type CreatePaymentRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	CustomerId int32                  `protobuf:"varint,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	// must work at the store that owns the rented copy
+	StaffId       int32   `protobuf:"varint,2,opt,name=staff_id,json=staffId,proto3" json:"staff_id,omitempty"`
+	RentalId      int32   `protobuf:"varint,3,opt,name=rental_id,json=rentalId,proto3" json:"rental_id,omitempty"`
+	Amount        float32 `protobuf:"fixed32,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePaymentRequest) Reset() {
+	*x = CreatePaymentRequest{}
+	mi := &file_videorental_v1_payment_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePaymentRequest) ProtoMessage() {}
+
+func (x *CreatePaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_payment_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePaymentRequest.ProtoReflect.Descriptor instead.
+func (*CreatePaymentRequest) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_payment_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreatePaymentRequest) GetCustomerId() int32 {
+	if x != nil {
+		return x.CustomerId
+	}
+	return 0
+}
+
+func (x *CreatePaymentRequest) GetStaffId() int32 {
+	if x != nil {
+		return x.StaffId
+	}
+	return 0
+}
+
+func (x *CreatePaymentRequest) GetRentalId() int32 {
+	if x != nil {
+		return x.RentalId
+	}
+	return 0
+}
+
+func (x *CreatePaymentRequest) GetAmount() float32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type CreatePaymentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePaymentResponse) Reset() {
+	*x = CreatePaymentResponse{}
+	mi := &file_videorental_v1_payment_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePaymentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePaymentResponse) ProtoMessage() {}
+
+func (x *CreatePaymentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_payment_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePaymentResponse.ProtoReflect.Descriptor instead.
+func (*CreatePaymentResponse) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_payment_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreatePaymentResponse) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+var File_videorental_v1_payment_proto protoreflect.FileDescriptor
+
+const file_videorental_v1_payment_proto_rawDesc = "" +
+	"\n" +
+	"\x1cvideorental/v1/payment.proto\x12\x0evideorental.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bvideorental/v1/common.proto\"\xe4\x01\n" +
+	"\aPayment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x1f\n" +
+	"\vcustomer_id\x18\x02 \x01(\x05R\n" +
+	"customerId\x12\x19\n" +
+	"\bstaff_id\x18\x03 \x01(\x05R\astaffId\x12\x1b\n" +
+	"\trental_id\x18\x04 \x01(\x05R\brentalId\x12\x19\n" +
+	"\bstore_id\x18\x05 \x01(\x05R\astoreId\x12\x16\n" +
+	"\x06amount\x18\x06 \x01(\x01R\x06amount\x12=\n" +
+	"\fpayment_date\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\vpaymentDate\"\xab\x02\n" +
+	"\x13ListPaymentsRequest\x12$\n" +
+	"\vcustomer_id\x18\x01 \x01(\x05H\x00R\n" +
+	"customerId\x88\x01\x01\x12\x1e\n" +
+	"\bstaff_id\x18\x02 \x01(\x05H\x01R\astaffId\x88\x01\x01\x12\x1e\n" +
+	"\bstore_id\x18\x03 \x01(\x05H\x02R\astoreId\x88\x01\x01\x12.\n" +
+	"\x04from\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12(\n" +
+	"\x04page\x18\x06 \x01(\v2\x14.videorental.v1.PageR\x04pageB\x0e\n" +
+	"\f_customer_idB\v\n" +
+	"\t_staff_idB\v\n" +
+	"\t_store_id\"a\n" +
+	"\x14ListPaymentsResponse\x123\n" +
+	"\bpayments\x18\x01 \x03(\v2\x17.videorental.v1.PaymentR\bpayments\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"\x87\x01\n" +
+	"\x14CreatePaymentRequest\x12\x1f\n" +
+	"\vcustomer_id\x18\x01 \x01(\x05R\n" +
+	"customerId\x12\x19\n" +
+	"\bstaff_id\x18\x02 \x01(\x05R\astaffId\x12\x1b\n" +
+	"\trental_id\x18\x03 \x01(\x05R\brentalId\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x02R\x06amount\"'\n" +
+	"\x15CreatePaymentResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id2\xc9\x01\n" +
+	"\x0ePaymentService\x12Y\n" +
+	"\fListPayments\x12#.videorental.v1.ListPaymentsRequest\x1a$.videorental.v1.ListPaymentsResponse\x12\\\n" +
+	"\rCreatePayment\x12$.videorental.v1.CreatePaymentRequest\x1a%.videorental.v1.CreatePaymentResponseBRZPgithub.com/rstoltzm-profile/video-rental-api/pkg/pb/videorental/v1;videorentalv1b\x06proto3"
+
+var (
+	file_videorental_v1_payment_proto_rawDescOnce sync.Once
+	file_videorental_v1_payment_proto_rawDescData []byte
+)
+
+func file_videorental_v1_payment_proto_rawDescGZIP() []byte {
+	file_videorental_v1_payment_proto_rawDescOnce.Do(func() {
+		file_videorental_v1_payment_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_videorental_v1_payment_proto_rawDesc), len(file_videorental_v1_payment_proto_rawDesc)))
+	})
+	return file_videorental_v1_payment_proto_rawDescData
+}
+
+var file_videorental_v1_payment_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_videorental_v1_payment_proto_goTypes = []any{
+	(*Payment)(nil),               // 0: videorental.v1.Payment
+	(*ListPaymentsRequest)(nil),   // 1: videorental.v1.ListPaymentsRequest
+	(*ListPaymentsResponse)(nil),  // 2: videorental.v1.ListPaymentsResponse
+	(*CreatePaymentRequest)(nil),  // 3: videorental.v1.CreatePaymentRequest
+	(*CreatePaymentResponse)(nil), // 4: videorental.v1.CreatePaymentResponse
+	(*timestamppb.Timestamp)(nil), // 5: google.protobuf.Timestamp
+	(*Page)(nil),                  // 6: videorental.v1.Page
+}
+var file_videorental_v1_payment_proto_depIdxs = []int32{
+	5, // 0: videorental.v1.Payment.payment_date:type_name -> google.protobuf.Timestamp
+	5, // 1: videorental.v1.ListPaymentsRequest.from:type_name -> google.protobuf.Timestamp
+	5, // 2: videorental.v1.ListPaymentsRequest.to:type_name -> google.protobuf.Timestamp
+	6, // 3: videorental.v1.ListPaymentsRequest.page:type_name -> videorental.v1.Page
+	0, // 4: videorental.v1.ListPaymentsResponse.payments:type_name -> videorental.v1.Payment
+	1, // 5: videorental.v1.PaymentService.ListPayments:input_type -> videorental.v1.ListPaymentsRequest
+	3, // 6: videorental.v1.PaymentService.CreatePayment:input_type -> videorental.v1.CreatePaymentRequest
+	2, // 7: videorental.v1.PaymentService.ListPayments:output_type -> videorental.v1.ListPaymentsResponse
+	4, // 8: videorental.v1.PaymentService.CreatePayment:output_type -> videorental.v1.CreatePaymentResponse
+	7, // [7:9] is the sub-list for method output_type
+	5, // [5:7] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_videorental_v1_payment_proto_init() }
+func file_videorental_v1_payment_proto_init() {
+	if File_videorental_v1_payment_proto != nil {
+		return
+	}
+	file_videorental_v1_common_proto_init()
+	file_videorental_v1_payment_proto_msgTypes[1].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_videorental_v1_payment_proto_rawDesc), len(file_videorental_v1_payment_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_videorental_v1_payment_proto_goTypes,
+		DependencyIndexes: file_videorental_v1_payment_proto_depIdxs,
+		MessageInfos:      file_videorental_v1_payment_proto_msgTypes,
+	}.Build()
+	File_videorental_v1_payment_proto = out.File
+	file_videorental_v1_payment_proto_goTypes = nil
+	file_videorental_v1_payment_proto_depIdxs = nil
+}
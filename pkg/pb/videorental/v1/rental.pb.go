@@ -0,0 +1,419 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: videorental/v1/rental.proto
+
+package videorentalv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Rental struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FirstName     string                 `protobuf:"bytes,1,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName      string                 `protobuf:"bytes,2,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Phone         string                 `protobuf:"bytes,3,opt,name=phone,proto3" json:"phone,omitempty"`
+	RentalDate    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=rental_date,json=rentalDate,proto3" json:"rental_date,omitempty"`
+	Title         string                 `protobuf:"bytes,5,opt,name=title,proto3" json:"title,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rental) Reset() {
+	*x = Rental{}
+	mi := &file_videorental_v1_rental_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rental) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rental) ProtoMessage() {}
+
+func (x *Rental) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_rental_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rental.ProtoReflect.Descriptor instead.
+func (*Rental) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_rental_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Rental) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *Rental) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *Rental) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *Rental) GetRentalDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RentalDate
+	}
+	return nil
+}
+
+func (x *Rental) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+type ListRentalsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Late          bool                   `protobuf:"varint,1,opt,name=late,proto3" json:"late,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRentalsRequest) Reset() {
+	*x = ListRentalsRequest{}
+	mi := &file_videorental_v1_rental_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRentalsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRentalsRequest) ProtoMessage() {}
+
+func (x *ListRentalsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_rental_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRentalsRequest.ProtoReflect.Descriptor instead.
+func (*ListRentalsRequest) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_rental_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListRentalsRequest) GetLate() bool {
+	if x != nil {
+		return x.Late
+	}
+	return false
+}
+
+type CreateRentalRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	InventoryId int32                  `protobuf:"varint,1,opt,name=inventory_id,json=inventoryId,proto3" json:"inventory_id,omitempty"`
+	CustomerId  int32                  `protobuf:"varint,2,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	// must work at the store that owns the copy
+	StaffId       int32 `protobuf:"varint,3,opt,name=staff_id,json=staffId,proto3" json:"staff_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRentalRequest) Reset() {
+	*x = CreateRentalRequest{}
+	mi := &file_videorental_v1_rental_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRentalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRentalRequest) ProtoMessage() {}
+
+func (x *CreateRentalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_rental_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRentalRequest.ProtoReflect.Descriptor instead.
+func (*CreateRentalRequest) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_rental_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateRentalRequest) GetInventoryId() int32 {
+	if x != nil {
+		return x.InventoryId
+	}
+	return 0
+}
+
+func (x *CreateRentalRequest) GetCustomerId() int32 {
+	if x != nil {
+		return x.CustomerId
+	}
+	return 0
+}
+
+func (x *CreateRentalRequest) GetStaffId() int32 {
+	if x != nil {
+		return x.StaffId
+	}
+	return 0
+}
+
+type CreateRentalResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRentalResponse) Reset() {
+	*x = CreateRentalResponse{}
+	mi := &file_videorental_v1_rental_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRentalResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRentalResponse) ProtoMessage() {}
+
+func (x *CreateRentalResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_rental_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRentalResponse.ProtoReflect.Descriptor instead.
+func (*CreateRentalResponse) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_rental_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateRentalResponse) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ReturnRentalRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReturnRentalRequest) Reset() {
+	*x = ReturnRentalRequest{}
+	mi := &file_videorental_v1_rental_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReturnRentalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReturnRentalRequest) ProtoMessage() {}
+
+func (x *ReturnRentalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_rental_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReturnRentalRequest.ProtoReflect.Descriptor instead.
+func (*ReturnRentalRequest) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_rental_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ReturnRentalRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ReturnRentalResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReturnRentalResponse) Reset() {
+	*x = ReturnRentalResponse{}
+	mi := &file_videorental_v1_rental_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReturnRentalResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReturnRentalResponse) ProtoMessage() {}
+
+func (x *ReturnRentalResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_videorental_v1_rental_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReturnRentalResponse.ProtoReflect.Descriptor instead.
+func (*ReturnRentalResponse) Descriptor() ([]byte, []int) {
+	return file_videorental_v1_rental_proto_rawDescGZIP(), []int{5}
+}
+
+var File_videorental_v1_rental_proto protoreflect.FileDescriptor
+
+const file_videorental_v1_rental_proto_rawDesc = "" +
+	"\n" +
+	"\x1bvideorental/v1/rental.proto\x12\x0evideorental.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xad\x01\n" +
+	"\x06Rental\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x01 \x01(\tR\tfirstName\x12\x1b\n" +
+	"\tlast_name\x18\x02 \x01(\tR\blastName\x12\x14\n" +
+	"\x05phone\x18\x03 \x01(\tR\x05phone\x12;\n" +
+	"\vrental_date\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"rentalDate\x12\x14\n" +
+	"\x05title\x18\x05 \x01(\tR\x05title\"(\n" +
+	"\x12ListRentalsRequest\x12\x12\n" +
+	"\x04late\x18\x01 \x01(\bR\x04late\"t\n" +
+	"\x13CreateRentalRequest\x12!\n" +
+	"\finventory_id\x18\x01 \x01(\x05R\vinventoryId\x12\x1f\n" +
+	"\vcustomer_id\x18\x02 \x01(\x05R\n" +
+	"customerId\x12\x19\n" +
+	"\bstaff_id\x18\x03 \x01(\x05R\astaffId\"&\n" +
+	"\x14CreateRentalResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"%\n" +
+	"\x13ReturnRentalRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"\x16\n" +
+	"\x14ReturnRentalResponse2\x92\x02\n" +
+	"\rRentalService\x12K\n" +
+	"\vListRentals\x12\".videorental.v1.ListRentalsRequest\x1a\x16.videorental.v1.Rental0\x01\x12Y\n" +
+	"\fCreateRental\x12#.videorental.v1.CreateRentalRequest\x1a$.videorental.v1.CreateRentalResponse\x12Y\n" +
+	"\fReturnRental\x12#.videorental.v1.ReturnRentalRequest\x1a$.videorental.v1.ReturnRentalResponseBRZPgithub.com/rstoltzm-profile/video-rental-api/pkg/pb/videorental/v1;videorentalv1b\x06proto3"
+
+var (
+	file_videorental_v1_rental_proto_rawDescOnce sync.Once
+	file_videorental_v1_rental_proto_rawDescData []byte
+)
+
+func file_videorental_v1_rental_proto_rawDescGZIP() []byte {
+	file_videorental_v1_rental_proto_rawDescOnce.Do(func() {
+		file_videorental_v1_rental_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_videorental_v1_rental_proto_rawDesc), len(file_videorental_v1_rental_proto_rawDesc)))
+	})
+	return file_videorental_v1_rental_proto_rawDescData
+}
+
+var file_videorental_v1_rental_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_videorental_v1_rental_proto_goTypes = []any{
+	(*Rental)(nil),                // 0: videorental.v1.Rental
+	(*ListRentalsRequest)(nil),    // 1: videorental.v1.ListRentalsRequest
+	(*CreateRentalRequest)(nil),   // 2: videorental.v1.CreateRentalRequest
+	(*CreateRentalResponse)(nil),  // 3: videorental.v1.CreateRentalResponse
+	(*ReturnRentalRequest)(nil),   // 4: videorental.v1.ReturnRentalRequest
+	(*ReturnRentalResponse)(nil),  // 5: videorental.v1.ReturnRentalResponse
+	(*timestamppb.Timestamp)(nil), // 6: google.protobuf.Timestamp
+}
+var file_videorental_v1_rental_proto_depIdxs = []int32{
+	6, // 0: videorental.v1.Rental.rental_date:type_name -> google.protobuf.Timestamp
+	1, // 1: videorental.v1.RentalService.ListRentals:input_type -> videorental.v1.ListRentalsRequest
+	2, // 2: videorental.v1.RentalService.CreateRental:input_type -> videorental.v1.CreateRentalRequest
+	4, // 3: videorental.v1.RentalService.ReturnRental:input_type -> videorental.v1.ReturnRentalRequest
+	0, // 4: videorental.v1.RentalService.ListRentals:output_type -> videorental.v1.Rental
+	3, // 5: videorental.v1.RentalService.CreateRental:output_type -> videorental.v1.CreateRentalResponse
+	5, // 6: videorental.v1.RentalService.ReturnRental:output_type -> videorental.v1.ReturnRentalResponse
+	4, // [4:7] is the sub-list for method output_type
+	1, // [1:4] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_videorental_v1_rental_proto_init() }
+func file_videorental_v1_rental_proto_init() {
+	if File_videorental_v1_rental_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_videorental_v1_rental_proto_rawDesc), len(file_videorental_v1_rental_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_videorental_v1_rental_proto_goTypes,
+		DependencyIndexes: file_videorental_v1_rental_proto_depIdxs,
+		MessageInfos:      file_videorental_v1_rental_proto_msgTypes,
+	}.Build()
+	File_videorental_v1_rental_proto = out.File
+	file_videorental_v1_rental_proto_goTypes = nil
+	file_videorental_v1_rental_proto_depIdxs = nil
+}
@@ -0,0 +1,207 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: videorental/v1/rental.proto
+
+package videorentalv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	RentalService_ListRentals_FullMethodName  = "/videorental.v1.RentalService/ListRentals"
+	RentalService_CreateRental_FullMethodName = "/videorental.v1.RentalService/CreateRental"
+	RentalService_ReturnRental_FullMethodName = "/videorental.v1.RentalService/ReturnRental"
+)
+
+// RentalServiceClient is the client API for RentalService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RentalServiceClient interface {
+	// Streams every rental, or only the late ones.
+	ListRentals(ctx context.Context, in *ListRentalsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Rental], error)
+	CreateRental(ctx context.Context, in *CreateRentalRequest, opts ...grpc.CallOption) (*CreateRentalResponse, error)
+	// Marks a rental returned, the copy is held for the next reservation in
+	// line.
+	ReturnRental(ctx context.Context, in *ReturnRentalRequest, opts ...grpc.CallOption) (*ReturnRentalResponse, error)
+}
+
+type rentalServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRentalServiceClient(cc grpc.ClientConnInterface) RentalServiceClient {
+	return &rentalServiceClient{cc}
+}
+
+func (c *rentalServiceClient) ListRentals(ctx context.Context, in *ListRentalsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Rental], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &RentalService_ServiceDesc.Streams[0], RentalService_ListRentals_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListRentalsRequest, Rental]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RentalService_ListRentalsClient = grpc.ServerStreamingClient[Rental]
+
+func (c *rentalServiceClient) CreateRental(ctx context.Context, in *CreateRentalRequest, opts ...grpc.CallOption) (*CreateRentalResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateRentalResponse)
+	err := c.cc.Invoke(ctx, RentalService_CreateRental_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rentalServiceClient) ReturnRental(ctx context.Context, in *ReturnRentalRequest, opts ...grpc.CallOption) (*ReturnRentalResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReturnRentalResponse)
+	err := c.cc.Invoke(ctx, RentalService_ReturnRental_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RentalServiceServer is the server API for RentalService service.
+// All implementations must embed UnimplementedRentalServiceServer
+// for forward compatibility.
+type RentalServiceServer interface {
+	// Streams every rental, or only the late ones.
+	ListRentals(*ListRentalsRequest, grpc.ServerStreamingServer[Rental]) error
+	CreateRental(context.Context, *CreateRentalRequest) (*CreateRentalResponse, error)
+	// Marks a rental returned, the copy is held for the next reservation in
+	// line.
+	ReturnRental(context.Context, *ReturnRentalRequest) (*ReturnRentalResponse, error)
+	mustEmbedUnimplementedRentalServiceServer()
+}
+
+// UnimplementedRentalServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRentalServiceServer struct{}
+
+func (UnimplementedRentalServiceServer) ListRentals(*ListRentalsRequest, grpc.ServerStreamingServer[Rental]) error {
+	return status.Errorf(codes.Unimplemented, "method ListRentals not implemented")
+}
+func (UnimplementedRentalServiceServer) CreateRental(context.Context, *CreateRentalRequest) (*CreateRentalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRental not implemented")
+}
+func (UnimplementedRentalServiceServer) ReturnRental(context.Context, *ReturnRentalRequest) (*ReturnRentalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReturnRental not implemented")
+}
+func (UnimplementedRentalServiceServer) mustEmbedUnimplementedRentalServiceServer() {}
+func (UnimplementedRentalServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeRentalServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RentalServiceServer will
+// result in compilation errors.
+type UnsafeRentalServiceServer interface {
+	mustEmbedUnimplementedRentalServiceServer()
+}
+
+func RegisterRentalServiceServer(s grpc.ServiceRegistrar, srv RentalServiceServer) {
+	// If the following call pancis, it indicates UnimplementedRentalServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&RentalService_ServiceDesc, srv)
+}
+
+func _RentalService_ListRentals_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListRentalsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RentalServiceServer).ListRentals(m, &grpc.GenericServerStream[ListRentalsRequest, Rental]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RentalService_ListRentalsServer = grpc.ServerStreamingServer[Rental]
+
+func _RentalService_CreateRental_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRentalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RentalServiceServer).CreateRental(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RentalService_CreateRental_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RentalServiceServer).CreateRental(ctx, req.(*CreateRentalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RentalService_ReturnRental_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReturnRentalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RentalServiceServer).ReturnRental(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RentalService_ReturnRental_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RentalServiceServer).ReturnRental(ctx, req.(*ReturnRentalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RentalService_ServiceDesc is the grpc.ServiceDesc for RentalService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RentalService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "videorental.v1.RentalService",
+	HandlerType: (*RentalServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateRental",
+			Handler:    _RentalService_CreateRental_Handler,
+		},
+		{
+			MethodName: "ReturnRental",
+			Handler:    _RentalService_ReturnRental_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListRentals",
+			Handler:       _RentalService_ListRentals_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "videorental/v1/rental.proto",
+}
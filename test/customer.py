@@ -1,6 +1,7 @@
 import unittest
 import json
 import os
+import uuid
 import requests
 
 
@@ -37,6 +38,18 @@ class APITestCase(unittest.TestCase):
         print("\n✅ First customer Exists in list of customers")
 
 
+    def test_search_customers(self):
+        """Test search customers by partial name, ranked and paginated"""
+        url = f"{self.BASE_URL}/v1/customers?q=mary&limit=5"
+        response = requests.get(url, headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 200, f"search customers failed: {response.text}")
+        customers = response.json()
+        self.assertGreater(len(customers), 0, "Expected matches for 'mary'")
+        self.assertLessEqual(len(customers), 5, "Expected at most one page")
+        self.assertGreaterEqual(int(response.headers["X-Total-Count"]), len(customers))
+        self.assertTrue(customers[0]["first_name"].lower().startswith("mary"), "Best match should be first")
+        print(f"\n✅ Search returned {response.headers['X-Total-Count']} matches, first: {customers[0]['first_name']}")
+
     def test_get_customer(self):
         """Test get a customer"""
         url = f"{self.BASE_URL}/v1/customers/1"
@@ -55,9 +68,49 @@ class APITestCase(unittest.TestCase):
         self.assertEqual(delete_response, 204)
         print(f"\n✅ Deleted customer {customer_id}: response {delete_response}")
 
+    def test_update_deactivate_reactivate_customer(self):
+        """Test update, soft delete and reactivate a customer"""
+        customer_id = self.create_customer()
+        url = f"{self.BASE_URL}/v1/customers/{customer_id}"
+
+        response = requests.patch(url, json={"last_name": "Updated"}, headers=self.if_match(url), timeout=60)
+        self.assertEqual(response.status_code, 200, f"Update customer failed: {response.text}")
+        self.assertEqual(response.json().get("last_name"), "Updated")
+        print(f"\n✅ Updated customer {customer_id}")
+
+        self.assertEqual(self.delete_customer(customer_id), 204)
+        response = requests.get(url, headers=self.HEADERS, timeout=60)
+        self.assertFalse(response.json().get("active"), "Customer should be inactive")
+        listed = requests.get(f"{self.BASE_URL}/v1/customers", headers=self.HEADERS, timeout=60).json()
+        self.assertNotIn(customer_id, [c["id"] for c in listed], "Inactive customer should be hidden")
+        print(f"✅ Deactivated customer {customer_id}")
+
+        response = requests.post(f"{url}/reactivate", headers=self.if_match(url), timeout=60)
+        self.assertEqual(response.status_code, 200, f"Reactivate failed: {response.text}")
+        self.assertTrue(response.json().get("active"))
+        print(f"✅ Reactivated customer {customer_id}")
+
+        self.delete_customer(customer_id)
+
+    def test_create_customer_duplicate_email(self):
+        """Test creating a second customer with the same email returns 409"""
+        body = self.read_json("payloads/customer.json")
+        body["email"] = f"dup-{uuid.uuid4().hex[:8]}@example.com"
+        url = f"{self.BASE_URL}/v1/customers"
+
+        first = requests.post(url, json=body, headers=self.HEADERS, timeout=60)
+        self.assertEqual(first.status_code, 201, f"Create customer failed: {first.text}")
+        second = requests.post(url, json=body, headers=self.HEADERS, timeout=60)
+        self.assertEqual(second.status_code, 409, f"Expected 409, got {second.status_code}")
+        print(f"\n✅ Duplicate email rejected: {second.status_code}")
+
+        self.delete_customer(first.json().get("id"))
+
     def create_customer(self):
         url = f"{self.BASE_URL}/v1/customers"
         body = self.read_json("payloads/customer.json")
+        # emails are unique, soft deleted customers keep theirs
+        body["email"] = f"test-{uuid.uuid4().hex[:8]}@example.com"
         response = requests.post(url, json=body, headers=self.HEADERS, timeout=60)
         self.assertEqual(response.status_code, 201, f"Create customer failed: {response.text}")
         customer_id = response.json().get("id")
@@ -65,9 +118,48 @@ class APITestCase(unittest.TestCase):
 
     def delete_customer(self, customer_id):
         url = f"{self.BASE_URL}/v1/customers/{customer_id}"
-        response = requests.delete(url, headers=self.HEADERS, timeout=60)
+        response = requests.delete(url, headers=self.if_match(url), timeout=60)
         return response.status_code
 
+    def if_match(self, url):
+        """Headers for a write against the current version of url"""
+        response = requests.get(url, headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 200, f"Get {url} failed: {response.text}")
+        return {**self.HEADERS, "If-Match": response.headers["ETag"]}
+
+    def test_conditional_requests(self):
+        """Test ETag, 304 on If-None-Match, 428 without If-Match and 412 on a stale one"""
+        customer_id = self.create_customer()
+        url = f"{self.BASE_URL}/v1/customers/{customer_id}"
+
+        response = requests.get(url, headers=self.HEADERS, timeout=60)
+        tag = response.headers.get("ETag")
+        self.assertIsNotNone(tag, "Expected an ETag")
+        self.assertIn("Last-Modified", response.headers)
+
+        response = requests.get(url, headers={**self.HEADERS, "If-None-Match": tag}, timeout=60)
+        self.assertEqual(response.status_code, 304, f"Expected 304, got {response.status_code}")
+        print(f"\n✅ Unchanged customer {customer_id} answered 304")
+
+        response = requests.patch(url, json={"last_name": "NoVersion"}, headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 428, f"Expected 428, got {response.status_code}")
+        print(f"✅ Update without If-Match rejected: {response.status_code}")
+
+        stale = {**self.HEADERS, "If-Match": tag}
+        response = requests.patch(url, json={"last_name": "First"}, headers=stale, timeout=60)
+        self.assertEqual(response.status_code, 200, f"Update customer failed: {response.text}")
+        self.assertNotEqual(response.headers.get("ETag"), tag, "Update should change the ETag")
+
+        response = requests.patch(url, json={"last_name": "Second"}, headers=stale, timeout=60)
+        self.assertEqual(response.status_code, 412, f"Expected 412, got {response.status_code}")
+        print(f"✅ Update with a stale If-Match rejected: {response.status_code}")
+
+        response = requests.get(url, headers={**self.HEADERS, "If-None-Match": tag}, timeout=60)
+        self.assertEqual(response.status_code, 200, "Changed customer should not be 304")
+        self.assertEqual(response.json().get("last_name"), "First")
+
+        self.delete_customer(customer_id)
+
     def test_create_customer_validation_failure(self):
         """Test create customer with invalid data returns validation error"""
         url = f"{self.BASE_URL}/v1/customers"
@@ -109,7 +201,7 @@ class APITestCase(unittest.TestCase):
 
         response_text = response.text
         self.assertIn("email", response_text.lower(), "Should complain about missing email")
-        self.assertIn("storeid", response_text.lower(), "Should complain about missing storeid")
+        self.assertIn("store_id", response_text.lower(), "Should complain about missing store_id")
         self.assertIn("address", response_text.lower(), "Should complain about missing address")
 
         print(f"\n✅ Partial validation failure test passed: {response.status_code}")
@@ -131,6 +223,40 @@ class APITestCase(unittest.TestCase):
         self.assertIn("first_name", customer_rentals, "Missing 'first_name' in customer data")
         print(f"\n✅ Customer late rentals returned from {url}: {customer_rentals['first_name']}")
 
+    def test_late_filter_matches_overdue_flag(self):
+        """Test status=late lists exactly the rentals flagged overdue"""
+        url = f"{self.BASE_URL}/v1/customers/1/rentals?status=all&limit=100"
+        rentals = requests.get(url, headers=self.HEADERS, timeout=60).json()
+        response = requests.get(f"{self.BASE_URL}/v1/customers/1/rentals?status=late&limit=100", headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 200)
+        late = {r["rental_id"] for r in response.json()}
+        self.assertEqual(late, {r["rental_id"] for r in rentals if r["overdue"]})
+        summary = requests.get(f"{self.BASE_URL}/v1/customers/1/summary", headers=self.HEADERS, timeout=60).json()
+        self.assertEqual(summary["late_rentals"], len(late))
+        print(f"\n✅ {len(late)} late rentals, the same in the filter, the flag and the summary")
+
+    def test_get_customer_rental_history(self):
+        """Test get customer's full rental history"""
+        url = f"{self.BASE_URL}/v1/customers/1/rentals?status=all&from=2022-01-01&limit=5"
+        response = requests.get(url, headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 200, f"rental history failed: {response.text}")
+        rentals = response.json()
+        self.assertGreater(len(rentals), 0, "Expected rental history")
+        self.assertLessEqual(len(rentals), 5)
+        self.assertIn("return_date", rentals[0], "Missing 'return_date' in rental data")
+        print(f"\n✅ Customer rental history from {url}: {response.headers['X-Total-Count']} rentals")
+
+    def test_get_customer_summary(self):
+        """Test get customer's spend summary"""
+        url = f"{self.BASE_URL}/v1/customers/1/summary"
+        response = requests.get(url, headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 200, f"customer summary failed: {response.text}")
+        summary = response.json()
+        self.assertGreater(summary["lifetime_rentals"], 0)
+        self.assertGreater(summary["total_spend"], 0)
+        self.assertIn("favorite_categories", summary)
+        print(f"\n✅ Customer summary from {url}: {summary['lifetime_rentals']} rentals, ${summary['total_spend']}")
+
     def test_make_payment(self):
         url = f"{self.BASE_URL}/v1/payments"
         body = self.read_json("payloads/payment.json")
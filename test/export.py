@@ -0,0 +1,68 @@
+import csv
+import io
+import json
+import unittest
+import requests
+
+class ExportTests(unittest.TestCase):
+    BASE_URL = "http://localhost:8080"
+    HEADERS = {
+        "Content-Type": "application/json",
+        "X-API-Key": "secure-dev-key-123"
+    }
+
+    def get(self, path, accept):
+        headers = {**self.HEADERS, "Accept": accept}
+        return requests.get(f"{self.BASE_URL}/v1/{path}", headers=headers, timeout=120)
+
+    def test_customers_csv_exports_every_match(self):
+        """Test GET /v1/customers as CSV streams more than one JSON page"""
+        print("\n📄 Testing: GET /v1/customers Accept: text/csv")
+        response = self.get("customers?active=all", "text/csv")
+        self.assertEqual(response.status_code, 200, response.text)
+        self.assertTrue(response.headers["Content-Type"].startswith("text/csv"))
+        rows = list(csv.DictReader(io.StringIO(response.text)))
+        self.assertGreater(len(rows), 100)
+        self.assertIn("email", rows[0])
+        print(f"✅ {len(rows)} customers exported as CSV")
+
+    def test_rentals_ndjson(self):
+        """Test GET /v1/rentals as NDJSON, one object per line"""
+        response = self.get("rentals?late=true", "application/x-ndjson")
+        self.assertEqual(response.status_code, 200, response.text)
+        lines = response.text.splitlines()
+        self.assertGreater(len(lines), 0)
+        self.assertIn("title", json.loads(lines[0]))
+        print(f"\n✅ {len(lines)} late rentals exported as NDJSON")
+
+    def test_payments_json_and_csv(self):
+        """Test GET /v1/payments pages JSON and exports CSV"""
+        response = self.get("payments?customer_id=1&limit=5", "application/json")
+        self.assertEqual(response.status_code, 200, response.text)
+        self.assertEqual(len(response.json()), 5)
+        total = int(response.headers["X-Total-Count"])
+
+        response = self.get("payments?customer_id=1", "text/csv")
+        self.assertEqual(response.status_code, 200, response.text)
+        rows = list(csv.DictReader(io.StringIO(response.text)))
+        self.assertEqual(len(rows), total)
+        self.assertTrue(all(row["customer_id"] == "1" for row in rows))
+        print(f"\n✅ {total} payments for customer 1 exported as CSV")
+
+    def test_report_csv(self):
+        """Test a report as CSV"""
+        response = self.get("reports/top-films?limit=3", "text/csv")
+        self.assertEqual(response.status_code, 200, response.text)
+        lines = response.text.splitlines()
+        self.assertEqual(lines[0], "film_id,title,rentals,revenue")
+        self.assertEqual(len(lines), 4)
+        print("\n✅ Top films exported as CSV")
+
+    def test_export_errors_are_plain(self):
+        """Test errors before the first row still get a status code"""
+        response = self.get("reports/revenue?store_id=999999", "text/csv")
+        self.assertEqual(response.status_code, 404)
+        print("\n✅ Unknown store in an export returns 404")
+
+if __name__ == "__main__":
+    unittest.main()
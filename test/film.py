@@ -52,6 +52,19 @@ class FilmTests(unittest.TestCase):
         self.assertGreater(len(enriched_film), 0, "Expected non-empty enriched film data")
         print("✅ Enriched film data retrieved successfully")
 
+    def test_film_reads_are_cached(self):
+        """Test a repeated GET /v1/films/2 is a catalog cache hit"""
+        print("\n🗄️ Testing: GET /v1/films/2 twice, then /health/cache")
+        url = f"{self.BASE_URL}/v1/films/2"
+        first = requests.get(url, headers=self.HEADERS, timeout=60)
+        before = requests.get(f"{self.BASE_URL}/health/cache", timeout=60).json()
+        second = requests.get(url, headers=self.HEADERS, timeout=60)
+        after = requests.get(f"{self.BASE_URL}/health/cache", timeout=60).json()
+
+        self.assertEqual(second.json(), first.json())
+        self.assertGreaterEqual(after["hits"], before["hits"] + 1, "Expected a cache hit")
+        print(f"✅ {after['backend']} cache at {after['hits']} hits, {after['misses']} misses")
+
 if __name__ == "__main__":
     print("\n===== STARTING Film Tests =====")
     unittest.main()
@@ -0,0 +1,109 @@
+import unittest
+import requests
+
+class GraphQLTests(unittest.TestCase):
+    BASE_URL = "http://localhost:8080"
+    HEADERS = {
+        "Content-Type": "application/json",
+        "X-API-Key": "secure-dev-key-123"
+    }
+
+    def query(self, query, variables=None, headers=None):
+        body = {"query": query}
+        if variables is not None:
+            body["variables"] = variables
+        return requests.post(f"{self.BASE_URL}/graphql", json=body, headers=headers or self.HEADERS, timeout=60)
+
+    def test_requires_api_key(self):
+        """Test POST /graphql without an API key is rejected"""
+        print("\n🔒 Testing: POST /graphql without X-API-Key")
+        response = self.query("{ stores { id } }", headers={"Content-Type": "application/json"})
+        self.assertEqual(response.status_code, 401)
+        print("✅ Missing API key rejected")
+
+    def test_customer_with_rentals(self):
+        """Test a customer with their rentals, films and stores"""
+        print("\n🧾 Testing: customer(id: 1) with rentals")
+        response = self.query("""
+            query Customer($id: Int!) {
+              customer(id: $id) {
+                id
+                firstName
+                store { id }
+                rentals(limit: 5) {
+                  total
+                  items { id rentalDate film { id title actors { name } } inventory { id store { id } } }
+                }
+                summary { lifetimeRentals favoriteCategories { category { name } rentals } }
+              }
+            }
+        """, {"id": 1})
+        self.assertEqual(response.status_code, 200)
+        body = response.json()
+        self.assertNotIn("errors", body)
+        customer = body["data"]["customer"]
+        self.assertEqual(customer["id"], 1)
+        self.assertGreater(customer["rentals"]["total"], 0)
+        self.assertLessEqual(len(customer["rentals"]["items"]), 5)
+        for rental in customer["rentals"]["items"]:
+            self.assertTrue(rental["film"]["title"])
+            self.assertIsNotNone(rental["inventory"]["store"])
+        print("✅ Customer, rentals and films resolved in one request")
+
+    def test_unknown_customer_is_null(self):
+        """Test an unknown customer id resolves to null"""
+        print("\n🔍 Testing: customer(id: 99999)")
+        response = self.query("{ customer(id: 99999) { id } }")
+        self.assertEqual(response.status_code, 200)
+        self.assertEqual(response.json(), {"data": {"customer": None}})
+        print("✅ Unknown customer is null")
+
+    def test_film_availability(self):
+        """Test a film with its categories and availability per store"""
+        print("\n🎬 Testing: film(id: 1) availability")
+        response = self.query("""
+            { film(id: 1) { title categories { name } availability { totalCopies stores { store { id } availableCopies availableInventory { id } } } } }
+        """)
+        self.assertEqual(response.status_code, 200)
+        film = response.json()["data"]["film"]
+        self.assertEqual(film["title"], "ACADEMY DINOSAUR")
+        self.assertGreater(len(film["categories"]), 0)
+        stores = film["availability"]["stores"]
+        self.assertGreater(film["availability"]["totalCopies"], 0)
+        for store in stores:
+            self.assertEqual(store["availableCopies"], len(store["availableInventory"]))
+        print("✅ Film availability resolved")
+
+    def test_payments_page(self):
+        """Test a page of payments with customers and stores"""
+        print("\n💳 Testing: payments(limit: 10)")
+        response = self.query("{ payments(limit: 10) { total items { id amount customer { id } store { id } } } }")
+        self.assertEqual(response.status_code, 200)
+        page = response.json()["data"]["payments"]
+        self.assertGreater(page["total"], 0)
+        self.assertEqual(len(page["items"]), 10)
+        for p in page["items"]:
+            self.assertIsNotNone(p["customer"])
+            self.assertIsNotNone(p["store"])
+        print("✅ Payments page resolved")
+
+    def test_limit_out_of_range(self):
+        """Test a limit over 100 is an error"""
+        print("\n🚫 Testing: customers(limit: 500)")
+        response = self.query("{ customers(limit: 500) { total } }")
+        self.assertEqual(response.status_code, 200)
+        errors = response.json()["errors"]
+        self.assertEqual(errors[0]["message"], "limit must be between 1 and 100")
+        print("✅ Out of range limit rejected")
+
+    def test_invalid_json(self):
+        """Test a body that isn't JSON is a 400"""
+        print("\n🧱 Testing: POST /graphql with invalid JSON")
+        response = requests.post(f"{self.BASE_URL}/graphql", data="{", headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 400)
+        print("✅ Invalid JSON rejected")
+
+if __name__ == "__main__":
+    print("\n===== STARTING GraphQL Tests =====")
+    unittest.main()
+    print("\n===== FINISHED GraphQL Tests =====\n")
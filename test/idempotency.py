@@ -0,0 +1,57 @@
+import json
+import os
+import unittest
+import uuid
+import requests
+
+class IdempotencyTests(unittest.TestCase):
+    BASE_URL = "http://localhost:8080"
+    HEADERS = {
+        "Content-Type": "application/json",
+        "X-API-Key": "secure-dev-key-123"
+    }
+
+    def payment(self):
+        with open(os.path.join(os.path.dirname(__file__), "payloads/payment.json")) as f:
+            return json.load(f)
+
+    def test_retried_payment_is_replayed(self):
+        """Test a retried POST /v1/payments with the same key only pays once"""
+        print("\n⏳ Testing: POST /v1/payments with Idempotency-Key")
+        headers = {**self.HEADERS, "Idempotency-Key": str(uuid.uuid4())}
+        body = self.payment()
+
+        first = requests.post(f"{self.BASE_URL}/v1/payments", json=body, headers=headers, timeout=10)
+        self.assertEqual(first.status_code, 201, first.text)
+        self.assertNotIn("Idempotent-Replayed", first.headers)
+
+        retry = requests.post(f"{self.BASE_URL}/v1/payments", json=body, headers=headers, timeout=10)
+        self.assertEqual(retry.status_code, 201, retry.text)
+        self.assertEqual(retry.headers.get("Idempotent-Replayed"), "true")
+        self.assertEqual(retry.json(), first.json())
+        self.assertEqual(retry.headers["Location"], first.headers["Location"])
+        print(f"✅ Retry replayed payment {first.json()}")
+
+    def test_key_reused_for_different_request(self):
+        """Test reusing a key with a different body is a 422"""
+        headers = {**self.HEADERS, "Idempotency-Key": str(uuid.uuid4())}
+        body = self.payment()
+        first = requests.post(f"{self.BASE_URL}/v1/payments", json=body, headers=headers, timeout=10)
+        self.assertEqual(first.status_code, 201, first.text)
+
+        body["amount"] = 1.99
+        response = requests.post(f"{self.BASE_URL}/v1/payments", json=body, headers=headers, timeout=10)
+        self.assertEqual(response.status_code, 422, response.text)
+        print("\n✅ Key reused for a different request rejected")
+
+    def test_validation_error_is_replayed(self):
+        """Test a 4xx response is stored too"""
+        headers = {**self.HEADERS, "Idempotency-Key": str(uuid.uuid4())}
+        for _ in range(2):
+            response = requests.post(f"{self.BASE_URL}/v1/payments", json={"amount": 1}, headers=headers, timeout=10)
+            self.assertEqual(response.status_code, 400, response.text)
+        self.assertEqual(response.headers.get("Idempotent-Replayed"), "true")
+        print("\n✅ 400 replayed")
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,83 @@
+import time
+import unittest
+import requests
+
+class ImportTests(unittest.TestCase):
+    BASE_URL = "http://localhost:8080"
+    HEADERS = {
+        "Content-Type": "text/csv",
+        "X-API-Key": "secure-dev-key-123"
+    }
+    CUSTOMER_HEADER = "first_name,last_name,email,store_id,address,district,city_name,country,postal_code,phone\n"
+
+    def post(self, path, body, dry_run=False):
+        params = {"dry_run": "true"} if dry_run else {}
+        return requests.post(f"{self.BASE_URL}/v1/imports/{path}", headers=self.HEADERS,
+                             params=params, data=body.encode(), timeout=60)
+
+    def customer_rows(self):
+        stamp = int(time.time() * 1000)
+        return (
+            f"Ada,Lovelace,ada.{stamp}@example.com,1,12 Analytical Way,London,London,United Kingdom,N19GU,+445550100\n"
+            f"Alan,Turing,alan.{stamp}@example.com,2,3 Bletchley Rd,Bucks,Milton Keynes,United Kingdom,MK36EB,+445550101\n"
+        )
+
+    def test_customers_dry_run_then_import(self):
+        """Test POST /v1/imports/customers with and without dry_run"""
+        print("\n📥 Testing: POST /v1/imports/customers")
+        body = self.CUSTOMER_HEADER + self.customer_rows()
+
+        response = self.post("customers", body, dry_run=True)
+        self.assertEqual(response.status_code, 200, response.text)
+        report = response.json()
+        self.assertEqual(report, {"dry_run": True, "rows": 2, "imported": 0, "errors": []})
+
+        response = self.post("customers", body)
+        self.assertEqual(response.status_code, 200, response.text)
+        self.assertEqual(response.json()["imported"], 2)
+        print("✅ Dry run checked 2 customers, import created them")
+
+        # the same file again is all duplicate emails
+        response = self.post("customers", body)
+        self.assertEqual(response.status_code, 400, response.text)
+        errors = response.json()["errors"]
+        self.assertEqual([e["row"] for e in errors], [2, 3])
+        self.assertTrue(all(e["field"] == "email" for e in errors))
+        print("✅ Importing the file twice reports duplicate emails")
+
+    def test_customers_row_errors(self):
+        """Test POST /v1/imports/customers reports every bad row and imports nothing"""
+        rows = self.customer_rows().splitlines()
+        body = (self.CUSTOMER_HEADER + rows[0] + "\n"
+                + "Bad,Email,not-an-email,1,1 Street,District,London,,12345,+15550100\n"
+                + f"No,Store,nostore.{time.time()}@example.com,9999,1 Street,District,Nowhere{int(time.time())},,12345,+15550100\n"
+                + "Short,Row\n")
+        response = self.post("customers", body)
+        self.assertEqual(response.status_code, 400, response.text)
+        report = response.json()
+        self.assertEqual(report["rows"], 4)
+        self.assertEqual(report["imported"], 0)
+        fields = {(e["row"], e.get("field", "")) for e in report["errors"]}
+        self.assertEqual(fields, {(3, "email"), (4, "store_id"), (4, "city_name"), (5, "")})
+        print("\n✅ Row errors reported, nothing imported")
+
+    def test_invalid_header(self):
+        """Test a file with an unknown column is rejected"""
+        response = self.post("customers", "first_name,age\nAda,36\n")
+        self.assertEqual(response.status_code, 400)
+        self.assertIn("unknown column", response.text)
+        print("\n✅ Unknown column returns 400")
+
+    def test_inventory_import(self):
+        """Test POST /v1/imports/inventory adds copies"""
+        response = self.post("inventory", "film_id,store_id,copies\n1,1,2\n1,2\n")
+        self.assertEqual(response.status_code, 200, response.text)
+        self.assertEqual(response.json()["imported"], 3)
+
+        response = self.post("inventory", "film_id,store_id\n999999,1\n", dry_run=True)
+        self.assertEqual(response.status_code, 400, response.text)
+        self.assertEqual(response.json()["errors"], [{"row": 2, "field": "film_id", "message": "unknown film"}])
+        print(f"\n✅ 3 copies imported, unknown film rejected")
+
+if __name__ == "__main__":
+    unittest.main()
@@ -41,5 +41,41 @@ class InventoryTests(unittest.TestCase):
         self.assertGreater(len(inventory), 0, "Expected non-empty available inventory list")
         print("✅ Available inventory retrieved successfully")
 
+    def test_available_inventory_lists_every_copy(self):
+        """Test GET /v1/inventory/available returns every available copy and counts"""
+        url = f"{self.BASE_URL}/v1/inventory/available?film_id=1&store_id=2"
+        response = requests.get(url, headers=self.HEADERS, timeout=60)
+        self.assertIn(response.status_code, [200, 404])
+        availability = response.json()
+        self.assertEqual(availability["available_copies"], len(availability["available_inventory_ids"]))
+        self.assertLessEqual(availability["available_copies"], availability["total_copies"])
+        print(f"\n✅ {availability['available_copies']} of {availability['total_copies']} copies available")
+
+    def test_available_inventory_bad_params(self):
+        """Test GET /v1/inventory/available without film_id returns 400"""
+        url = f"{self.BASE_URL}/v1/inventory/available?store_id=2"
+        response = requests.get(url, headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 400)
+        print("\n✅ Missing film_id returns 400")
+
+    def test_film_availability_across_stores(self):
+        """Test GET /v1/films/1/availability shows every store"""
+        print("\n🎞️ Testing: GET /v1/films/1/availability")
+        url = f"{self.BASE_URL}/v1/films/1/availability"
+        response = requests.get(url, headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 200)
+        availability = response.json()
+        self.assertEqual(availability["film_id"], 1)
+        self.assertGreater(len(availability["stores"]), 0)
+        self.assertEqual(availability["total_copies"], sum(s["total_copies"] for s in availability["stores"]))
+        print(f"✅ Film available at {len(availability['stores'])} stores")
+
+    def test_film_availability_unknown_film(self):
+        """Test GET /v1/films/{id}/availability returns 404 for unknown films"""
+        url = f"{self.BASE_URL}/v1/films/999999/availability"
+        response = requests.get(url, headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 404)
+        print("\n✅ Unknown film returns 404")
+
 if __name__ == "__main__":
     unittest.main()
\ No newline at end of file
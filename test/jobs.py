@@ -0,0 +1,92 @@
+import csv
+import io
+import time
+import unittest
+import requests
+
+class JobTests(unittest.TestCase):
+    BASE_URL = "http://localhost:8080"
+    HEADERS = {
+        "Content-Type": "application/json",
+        "X-API-Key": "secure-dev-key-123"
+    }
+
+    def wait(self, location, timeout=30):
+        """Poll a job until it finishes"""
+        deadline = time.time() + timeout
+        while time.time() < deadline:
+            response = requests.get(f"{self.BASE_URL}{location}", headers=self.HEADERS, timeout=10)
+            self.assertEqual(response.status_code, 200, response.text)
+            job = response.json()
+            if job["status"] in ("succeeded", "failed", "cancelled"):
+                return job
+            time.sleep(0.5)
+        self.fail(f"job {location} did not finish in {timeout}s")
+
+    def test_async_payment_export(self):
+        """Test GET /v1/payments?async=true runs the export as a job"""
+        print("\n⏳ Testing: GET /v1/payments?async=true")
+        headers = {**self.HEADERS, "Accept": "text/csv"}
+        response = requests.get(f"{self.BASE_URL}/v1/payments?customer_id=1&async=true", headers=headers, timeout=10)
+        self.assertEqual(response.status_code, 202, response.text)
+        location = response.headers["Location"]
+        self.assertEqual(response.json()["kind"], "export-payments")
+
+        job = self.wait(location)
+        self.assertEqual(job["status"], "succeeded", job)
+        self.assertEqual(job["progress"], 100)
+
+        result = requests.get(f"{self.BASE_URL}{job['result_url']}", headers=self.HEADERS, timeout=10)
+        self.assertEqual(result.status_code, 200)
+        self.assertTrue(result.headers["Content-Type"].startswith("text/csv"))
+        rows = list(csv.DictReader(io.StringIO(result.text)))
+        self.assertGreater(len(rows), 0)
+        self.assertTrue(all(row["customer_id"] == "1" for row in rows))
+        print(f"✅ Export job {job['id']} produced {len(rows)} payments")
+
+    def test_async_report(self):
+        """Test a report run as a job"""
+        response = requests.get(f"{self.BASE_URL}/v1/reports/top-films?limit=3&async=true", headers=self.HEADERS, timeout=10)
+        self.assertEqual(response.status_code, 202, response.text)
+        job = self.wait(response.headers["Location"])
+        self.assertEqual(job["status"], "succeeded", job)
+        result = requests.get(f"{self.BASE_URL}{job['result_url']}", headers=self.HEADERS, timeout=10)
+        self.assertEqual(len(result.json()), 3)
+        print("\n✅ Report job succeeded")
+
+    def test_async_report_unknown_store_fails(self):
+        """Test a job that can't succeed fails without retrying"""
+        response = requests.get(f"{self.BASE_URL}/v1/reports/revenue?store_id=9999&async=true", headers=self.HEADERS, timeout=10)
+        self.assertEqual(response.status_code, 202, response.text)
+        job = self.wait(response.headers["Location"])
+        self.assertEqual(job["status"], "failed")
+        self.assertEqual(job["attempts"], 1)
+        self.assertIn("store not found", job["error"])
+
+        result = requests.get(f"{self.BASE_URL}/v1/jobs/{job['id']}/result", headers=self.HEADERS, timeout=10)
+        self.assertEqual(result.status_code, 409)
+
+        response = requests.delete(f"{self.BASE_URL}/v1/jobs/{job['id']}", headers=self.HEADERS, timeout=10)
+        self.assertEqual(response.status_code, 409)
+        print("\n✅ Failed job has no result and can't be cancelled")
+
+    def test_async_import_dry_run(self):
+        """Test POST /v1/imports/inventory?async=true&dry_run=true"""
+        headers = {**self.HEADERS, "Content-Type": "text/csv"}
+        response = requests.post(f"{self.BASE_URL}/v1/imports/inventory?async=true&dry_run=true",
+                                 headers=headers, data=b"film_id,store_id\n1,1\n", timeout=10)
+        self.assertEqual(response.status_code, 202, response.text)
+        job = self.wait(response.headers["Location"])
+        self.assertEqual(job["status"], "succeeded", job)
+        report = requests.get(f"{self.BASE_URL}{job['result_url']}", headers=self.HEADERS, timeout=10).json()
+        self.assertEqual(report, {"dry_run": True, "rows": 1, "imported": 0, "errors": []})
+        print("\n✅ Import dry run ran as a job")
+
+    def test_unknown_job(self):
+        """Test GET /v1/jobs/{id} for a job that doesn't exist"""
+        response = requests.get(f"{self.BASE_URL}/v1/jobs/999999999", headers=self.HEADERS, timeout=10)
+        self.assertEqual(response.status_code, 404)
+        print("\n✅ Unknown job returns 404")
+
+if __name__ == "__main__":
+    unittest.main()
@@ -23,7 +23,7 @@ class APITestCase(unittest.TestCase):
         """Test renting and returning a movie"""
         # Step 1: Rent a movie
         rent_url = f"{self.BASE_URL}/v1/rentals"
-        body = self.read_json("payloads/rental.json")
+        body = self.with_store_staff(self.read_json("payloads/rental.json"))
         rent_response = requests.post(rent_url, json=body, headers=self.HEADERS, timeout=60)
 
         self.assertEqual(rent_response.status_code, 201, f"Rent failed: {rent_response.text}")
@@ -43,7 +43,7 @@ class APITestCase(unittest.TestCase):
     def test_rent_checked_out_movie(self):
         """Test renting a checked out movie"""
         rent_url = f"{self.BASE_URL}/v1/rentals"
-        body = self.read_json("payloads/rental-2.json")
+        body = self.with_store_staff(self.read_json("payloads/rental-2.json"))
 
         # First rental should succeed
         rent_response_1 = requests.post(rent_url, json=body, headers=self.HEADERS, timeout=60)
@@ -87,6 +87,23 @@ class APITestCase(unittest.TestCase):
         self.assertGreater(len(rentals), 0, "Expected non-empty late rentals list")
         print("✅ Late rentals list retrieved successfully")
 
+    def test_rent_with_staff_from_other_store(self):
+        """Test renting with staff from another store is refused"""
+        body = self.read_json("payloads/rental.json")
+        # Pagila has two stores, managed by staff 1 and 2
+        body["staff_id"] = self.with_store_staff(dict(body))["staff_id"] % 2 + 1
+        response = requests.post(f"{self.BASE_URL}/v1/rentals", json=body, headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 403, response.text)
+        print("\n✅ Staff from another store refused with 403")
+
+    def with_store_staff(self, body):
+        """Use the manager of the store that owns the copy as the staff member"""
+        inventory = requests.get(f"{self.BASE_URL}/v1/inventory", headers=self.HEADERS, timeout=60).json()
+        store_id = next(i["store_id"] for i in inventory if i["inventory_id"] == body["inventory_id"])
+        store = requests.get(f"{self.BASE_URL}/v1/stores/{store_id}", headers=self.HEADERS, timeout=60).json()
+        body["staff_id"] = store["manager"]["id"]
+        return body
+
     def read_json(self, file_name):
         """Helper to read and parse JSON file"""
         try:
@@ -0,0 +1,79 @@
+import unittest
+import requests
+
+class ReportTests(unittest.TestCase):
+    BASE_URL = "http://localhost:8080"
+    HEADERS = {
+        "Content-Type": "application/json",
+        "X-API-Key": "secure-dev-key-123"
+    }
+
+    def get(self, path):
+        return requests.get(f"{self.BASE_URL}/v1/reports/{path}", headers=self.HEADERS, timeout=60)
+
+    def test_revenue_by_store(self):
+        """Test GET /v1/reports/revenue filtered to one store"""
+        print("\n📊 Testing: GET /v1/reports/revenue?store_id=1")
+        response = self.get("revenue?store_id=1")
+        self.assertEqual(response.status_code, 200, response.text)
+        rows = response.json()
+        self.assertGreater(len(rows), 0)
+        self.assertTrue(all(row["store_id"] == 1 for row in rows))
+        print(f"✅ {len(rows)} months of revenue for store 1")
+
+    def test_top_films_limit(self):
+        """Test GET /v1/reports/top-films honours limit and ranks by rentals"""
+        response = self.get("top-films?limit=5")
+        self.assertEqual(response.status_code, 200, response.text)
+        films = response.json()
+        self.assertEqual(len(films), 5)
+        rentals = [f["rentals"] for f in films]
+        self.assertEqual(rentals, sorted(rentals, reverse=True))
+        print(f"\n✅ Top film is {films[0]['title']}")
+
+        response = self.get("top-films?limit=0")
+        self.assertEqual(response.status_code, 400)
+        print("✅ Bad limit returns 400")
+
+    def test_top_customers(self):
+        """Test GET /v1/reports/top-customers ranks by spend"""
+        response = self.get("top-customers")
+        self.assertEqual(response.status_code, 200, response.text)
+        spend = [c["total_spend"] for c in response.json()]
+        self.assertEqual(spend, sorted(spend, reverse=True))
+        print("\n✅ Top customers retrieved")
+
+    def test_categories_share(self):
+        """Test GET /v1/reports/categories shares add up"""
+        response = self.get("categories")
+        self.assertEqual(response.status_code, 200, response.text)
+        total = sum(c["share"] for c in response.json())
+        self.assertAlmostEqual(total, 1.0, places=2)
+        print("\n✅ Category shares add up to 1")
+
+    def test_late_returns(self):
+        """Test GET /v1/reports/late-returns per store"""
+        response = self.get("late-returns")
+        self.assertEqual(response.status_code, 200, response.text)
+        for row in response.json():
+            self.assertLessEqual(row["late"], row["rentals"])
+            self.assertLessEqual(row["overdue"], row["late"])
+        print("\n✅ Late return rates retrieved")
+
+    def test_utilization_paged(self):
+        """Test GET /v1/reports/utilization is paged with a total"""
+        response = self.get("utilization?store_id=2&limit=10")
+        self.assertEqual(response.status_code, 200, response.text)
+        self.assertEqual(len(response.json()), 10)
+        self.assertGreater(int(response.headers["X-Total-Count"]), 10)
+        print("\n✅ Utilization paged")
+
+    def test_filters_rejected(self):
+        """Test bad dates and unknown stores"""
+        self.assertEqual(self.get("revenue?from=2022-06-01&to=2022-05-01").status_code, 400)
+        self.assertEqual(self.get("revenue?from=yesterday").status_code, 400)
+        self.assertEqual(self.get("revenue?store_id=999999").status_code, 404)
+        print("\n✅ Bad date range returns 400, unknown store 404")
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,58 @@
+import unittest
+import requests
+
+class APITestCase(unittest.TestCase):
+    BASE_URL = "http://localhost:8080"
+    HEADERS = {
+        "Content-Type": "application/json",
+        "X-API-Key": "secure-dev-key-123"
+    }
+
+    def test_reserve_hold_and_cancel(self):
+        """Test a reservation holds a free copy for its customer only"""
+        url = f"{self.BASE_URL}/v1/reservations"
+        body = {"customer_id": 5, "film_id": 1, "store_id": 1}
+        response = requests.post(url, json=body, headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 201, f"Reserve failed: {response.text}")
+        reservation = response.json()
+        self.assertIn(reservation["status"], ["waiting", "held"])
+        print(f"\n✅ Reservation {reservation['id']} created ({reservation['status']})")
+
+        # A second open reservation for the same film and store is rejected
+        response = requests.post(url, json=body, headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 409, f"Expected 409: {response.text}")
+        print("✅ Duplicate reservation rejected with 409")
+
+        # Nobody else can rent the held copy
+        if reservation["status"] == "held":
+            rent = {"inventory_id": reservation["inventory_id"], "customer_id": 6, "staff_id": 1}
+            response = requests.post(f"{self.BASE_URL}/v1/rentals", json=rent, headers=self.HEADERS, timeout=60)
+            self.assertEqual(response.status_code, 409, f"Expected 409: {response.text}")
+            print(f"✅ Held copy {reservation['inventory_id']} refused to another customer")
+
+        # Cancel, then cancelling again conflicts
+        response = requests.delete(f"{url}/{reservation['id']}", headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 204, f"Cancel failed: {response.text}")
+        response = requests.delete(f"{url}/{reservation['id']}", headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 409)
+        print("✅ Reservation cancelled")
+
+    def test_list_reservations(self):
+        """Test GET /v1/reservations filters and pages"""
+        url = f"{self.BASE_URL}/v1/reservations?status=cancelled&limit=5"
+        response = requests.get(url, headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 200)
+        reservations = response.json()
+        self.assertIsInstance(reservations, list)
+        self.assertLessEqual(len(reservations), 5)
+        self.assertIn("X-Total-Count", response.headers)
+        print(f"\n✅ Reservations listed, {response.headers['X-Total-Count']} cancelled")
+
+    def test_get_unknown_reservation(self):
+        """Test GET /v1/reservations/{id} returns 404 for unknown ids"""
+        response = requests.get(f"{self.BASE_URL}/v1/reservations/999999", headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 404)
+        print("\n✅ Unknown reservation returns 404")
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,138 @@
+import base64
+import os
+import subprocess
+import unittest
+import uuid
+import requests
+
+# 1x1 transparent PNG
+PIXEL = base64.b64decode("iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAQAAAC1HAwCAAAAC0lEQVR42mNkYAAAAAYAAjCB0C8AAAAASUVORK5CYII=")
+
+ROOT = os.path.join(os.path.dirname(os.path.abspath(__file__)), "..")
+
+class StaffTests(unittest.TestCase):
+    BASE_URL = "http://localhost:8080"
+    HEADERS = {
+        "Content-Type": "application/json",
+        "X-API-Key": "secure-dev-key-123"
+    }
+
+    def create_staff(self, store_id=1):
+        body = {
+            "first_name": "Test",
+            "last_name": "Staff",
+            "email": f"{uuid.uuid4().hex[:8]}@example.com",
+            "store_id": store_id,
+            "username": uuid.uuid4().hex[:12],
+            "password": "password123",
+        }
+        response = requests.post(f"{self.BASE_URL}/v1/staff", json=body, headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 201, response.text)
+        return body, response.json()
+
+    def login(self, username, password):
+        """Open a session and return headers that act as the staff member"""
+        body = {"username": username, "password": password}
+        response = requests.post(f"{self.BASE_URL}/v1/staff/sessions", json=body, headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 201, response.text)
+        return {"X-Staff-Token": response.json()["token"]}
+
+    def manager_session(self, store_id):
+        """Set the store manager's password with vrctl, on $DATABASE_URL, and log them in"""
+        manager = requests.get(f"{self.BASE_URL}/v1/stores/{store_id}", headers=self.HEADERS, timeout=60).json()["manager"]
+        staff = requests.get(f"{self.BASE_URL}/v1/staff/{manager['id']}", headers=self.HEADERS, timeout=60).json()
+        password = "correct horse battery"
+        subprocess.run(["go", "run", "./cmd/vrctl", "staff", "set-password", str(staff["id"])],
+                       input=password + "\n", text=True, capture_output=True, check=True, cwd=ROOT)
+        return staff["id"], self.login(staff["username"], password)
+
+    def if_match(self, url):
+        """Headers for a write against the current version of url"""
+        response = requests.get(url, headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 200, response.text)
+        return {**self.HEADERS, "If-Match": response.headers["ETag"]}
+
+    def test_create_move_and_deactivate_staff(self):
+        """Test creating, moving and deactivating a staff member"""
+        body, staff = self.create_staff()
+        self.assertNotIn("password", staff)
+        self.assertTrue(staff["active"])
+        print(f"\n✅ Staff {staff['id']} created")
+
+        response = requests.post(f"{self.BASE_URL}/v1/staff", json=body, headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 409)
+        print("✅ Duplicate username rejected with 409")
+
+        url = f"{self.BASE_URL}/v1/staff/{staff['id']}"
+        response = requests.put(f"{url}/password", json={"password": "another-secret"}, headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 401)
+        print("✅ Password change with only the API key rejected with 401")
+
+        other = self.login(self.create_staff()[0]["username"], "password123")
+        response = requests.put(f"{url}/password", json={"password": "another-secret"}, headers={**self.HEADERS, **other}, timeout=60)
+        self.assertEqual(response.status_code, 403)
+        print("✅ Password change by other staff rejected with 403")
+
+        session = self.login(body["username"], body["password"])
+        response = requests.put(f"{url}/store", json={"store_id": 2}, headers={**self.HEADERS, **session}, timeout=60)
+        self.assertEqual(response.status_code, 428)
+        versioned = {**self.if_match(url), **session}
+        response = requests.put(f"{url}/store", json={"store_id": 2}, headers=versioned, timeout=60)
+        self.assertEqual(response.status_code, 200, response.text)
+        self.assertEqual(response.json()["store_id"], 2)
+        print("✅ Staff moved to store 2")
+
+        response = requests.put(f"{url}/store", json={"store_id": 1}, headers=versioned, timeout=60)
+        self.assertEqual(response.status_code, 412)
+        print("✅ Move with a stale If-Match rejected with 412")
+
+        response = requests.put(f"{url}/password", json={"password": "another-secret"}, headers={**self.HEADERS, **session}, timeout=60)
+        self.assertEqual(response.status_code, 204)
+        print("✅ Password changed")
+
+        # the password change ended the session, the new manager deactivates them
+        _, manager = self.manager_session(2)
+        response = requests.delete(url, headers={**self.if_match(url), **manager}, timeout=60)
+        self.assertEqual(response.status_code, 204)
+        self.assertFalse(requests.get(url, headers=self.HEADERS, timeout=60).json()["active"])
+        print("✅ Staff deactivated")
+
+    def test_deactivate_manager(self):
+        """Test a store manager can't be deactivated"""
+        manager_id, session = self.manager_session(1)
+        url = f"{self.BASE_URL}/v1/staff/{manager_id}"
+        response = requests.delete(url, headers={**self.if_match(url), **session}, timeout=60)
+        self.assertEqual(response.status_code, 409)
+        print("\n✅ Manager deactivation rejected with 409")
+
+    def test_upload_picture(self):
+        """Test uploading and downloading a staff picture"""
+        _, staff = self.create_staff()
+        url = f"{self.BASE_URL}/v1/staff/{staff['id']}/picture"
+
+        response = requests.get(url, headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 404)
+
+        headers = {**self.HEADERS, "Content-Type": "image/png"}
+        response = requests.put(url, data=b"not an image", headers=headers, timeout=60)
+        self.assertEqual(response.status_code, 415)
+
+        response = requests.put(url, data=PIXEL, headers=headers, timeout=60)
+        self.assertEqual(response.status_code, 204, response.text)
+
+        response = requests.get(url, headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 200)
+        self.assertEqual(response.headers["Content-Type"], "image/png")
+        self.assertEqual(response.content, PIXEL)
+        print("\n✅ Picture uploaded and downloaded")
+
+    def test_list_staff(self):
+        """Test GET /v1/staff?store_id=1"""
+        response = requests.get(f"{self.BASE_URL}/v1/staff?store_id=1", headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 200)
+        self.assertTrue(all(s["store_id"] == 1 for s in response.json()))
+        self.assertIn("X-Total-Count", response.headers)
+        print("\n✅ Staff listed by store")
+
+if __name__ == "__main__":
+    unittest.main()
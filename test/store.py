@@ -1,6 +1,10 @@
+import os
+import subprocess
 import unittest
 import requests
 
+ROOT = os.path.join(os.path.dirname(os.path.abspath(__file__)), "..")
+
 class StoreTests(unittest.TestCase):
     BASE_URL = "http://localhost:8080"
     HEADERS = {
@@ -8,6 +12,18 @@ class StoreTests(unittest.TestCase):
         "X-API-Key": "secure-dev-key-123"
     }
 
+    def staff_token(self, staff_id):
+        """Set a staff member's password with vrctl, on $DATABASE_URL, and open a session for them"""
+        url = f"{self.BASE_URL}/v1/staff/{staff_id}"
+        staff = requests.get(url, headers=self.HEADERS, timeout=60).json()
+        password = "correct horse battery"
+        subprocess.run(["go", "run", "./cmd/vrctl", "staff", "set-password", str(staff_id)],
+                       input=password + "\n", text=True, capture_output=True, check=True, cwd=ROOT)
+        body = {"username": staff["username"], "password": password}
+        response = requests.post(f"{self.BASE_URL}/v1/staff/sessions", json=body, headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 201, response.text)
+        return response.json()["token"]
+
     def test_get_store_inventory_summary(self):
         """Test GET /v1/stores/1/inventory/summary returns non-empty list"""
         print("\n🏪 Testing: GET /v1/stores/1/inventory/summary")
@@ -19,5 +35,85 @@ class StoreTests(unittest.TestCase):
         self.assertGreater(len(summary), 0, "Expected non-empty inventory summary list")
         print("✅ Store inventory summary retrieved successfully")
 
+    def test_get_stores(self):
+        """Test GET /v1/stores returns stores with address and manager"""
+        print("\n🏪 Testing: GET /v1/stores")
+        response = requests.get(f"{self.BASE_URL}/v1/stores", headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 200)
+        stores = response.json()
+        self.assertGreater(len(stores), 0)
+        self.assertIn("manager", stores[0])
+        self.assertIn("city", stores[0]["address"])
+        print(f"✅ {len(stores)} stores retrieved")
+
+    def test_get_store_and_staff(self):
+        """Test GET /v1/stores/1 and its staff roster"""
+        response = requests.get(f"{self.BASE_URL}/v1/stores/1", headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 200)
+        manager_id = response.json()["manager"]["id"]
+
+        response = requests.get(f"{self.BASE_URL}/v1/stores/1/staff", headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 200)
+        managers = [s["id"] for s in response.json() if s["is_manager"]]
+        self.assertEqual(managers, [manager_id])
+        print(f"\n✅ Store 1 managed by staff {manager_id}")
+
+        response = requests.get(f"{self.BASE_URL}/v1/stores/999999", headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 404)
+        print("✅ Unknown store returns 404")
+
+    def test_get_store_not_modified(self):
+        """Test GET /v1/stores/1 answers 304 for the ETag the client has"""
+        response = requests.get(f"{self.BASE_URL}/v1/stores/1", headers=self.HEADERS, timeout=60)
+        headers = {**self.HEADERS, "If-None-Match": response.headers["ETag"]}
+        response = requests.get(f"{self.BASE_URL}/v1/stores/1", headers=headers, timeout=60)
+        self.assertEqual(response.status_code, 304)
+        self.assertEqual(response.content, b"")
+        print("\n✅ Unchanged store answered 304")
+
+    def test_update_store_manager_only(self):
+        """Test PATCH /v1/stores/1 is limited to the store manager"""
+        response = requests.get(f"{self.BASE_URL}/v1/stores/1", headers=self.HEADERS, timeout=60)
+        store = response.json()
+        manager_id = store["manager"]["id"]
+        body = {"phone": store["address"]["phone"]}
+        url = f"{self.BASE_URL}/v1/stores/1"
+        versioned = {**self.HEADERS, "If-Match": response.headers["ETag"]}
+
+        response = requests.patch(url, json=body, headers=versioned, timeout=60)
+        self.assertEqual(response.status_code, 401)
+
+        forged = {**versioned, "X-Staff-Token": "not-a-session"}
+        response = requests.patch(url, json=body, headers=forged, timeout=60)
+        self.assertEqual(response.status_code, 401)
+
+        other = {**versioned, "X-Staff-Token": self.staff_token(manager_id + 1)}
+        response = requests.patch(url, json=body, headers=other, timeout=60)
+        self.assertEqual(response.status_code, 403)
+
+        manager = {**self.HEADERS, "X-Staff-Token": self.staff_token(manager_id)}
+        response = requests.patch(url, json=body, headers=manager, timeout=60)
+        self.assertEqual(response.status_code, 428)
+
+        response = requests.patch(url, json=body, headers={**manager, **versioned}, timeout=60)
+        self.assertEqual(response.status_code, 200, response.text)
+        self.assertEqual(response.json()["address"]["phone"], store["address"]["phone"])
+        print("\n✅ Only the manager can update the store")
+
+        response = requests.patch(url, json=body, headers={**manager, **versioned}, timeout=60)
+        self.assertEqual(response.status_code, 412)
+        print("✅ Update with a stale If-Match rejected with 412")
+
+    def test_reassign_manager_to_other_store_staff(self):
+        """Test PUT /v1/stores/1/manager rejects staff from another store"""
+        store = requests.get(f"{self.BASE_URL}/v1/stores/2", headers=self.HEADERS, timeout=60).json()
+        response = requests.get(f"{self.BASE_URL}/v1/stores/1", headers=self.HEADERS, timeout=60)
+        manager = {**self.HEADERS, "X-Staff-Token": self.staff_token(response.json()["manager"]["id"]),
+                   "If-Match": response.headers["ETag"]}
+        body = {"staff_id": store["manager"]["id"]}
+        response = requests.put(f"{self.BASE_URL}/v1/stores/1/manager", json=body, headers=manager, timeout=60)
+        self.assertEqual(response.status_code, 400, response.text)
+        print("\n✅ Manager must be staff of the store")
+
 if __name__ == "__main__":
     unittest.main()
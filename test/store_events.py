@@ -0,0 +1,87 @@
+import json
+import os
+import queue
+import threading
+import unittest
+import requests
+
+class StoreEventsTests(unittest.TestCase):
+    BASE_URL = "http://localhost:8080"
+    HEADERS = {
+        "Content-Type": "application/json",
+        "X-API-Key": "secure-dev-key-123"
+    }
+
+    def stream(self, store_id, events, headers=None):
+        """Read SSE messages from /v1/stores/{id}/events onto a queue"""
+        response = requests.get(f"{self.BASE_URL}/v1/stores/{store_id}/events",
+                                headers={**self.HEADERS, **(headers or {})}, stream=True, timeout=60)
+        self.assertEqual(response.status_code, 200)
+        self.assertEqual(response.headers["Content-Type"], "text/event-stream")
+
+        def read():
+            message = {}
+            for line in response.iter_lines(decode_unicode=True):
+                if line == "":
+                    if "id" in message:
+                        events.put(message)
+                    message = {}
+                elif not line.startswith(":"):
+                    field, _, value = line.partition(": ")
+                    message[field] = value
+        threading.Thread(target=read, daemon=True).start()
+        return response
+
+    def next_event(self, events, event_type):
+        while True:
+            message = events.get(timeout=30)
+            if message["event"] == event_type:
+                return message
+
+    def test_rent_and_return_are_streamed(self):
+        """Test a rental and its return show up on the store's stream and replay from Last-Event-ID"""
+        print("\n📡 Testing: GET /v1/stores/{id}/events")
+        body = self.read_json("payloads/rental.json")
+        inventory = requests.get(f"{self.BASE_URL}/v1/inventory", headers=self.HEADERS, timeout=60).json()
+        store_id = next(i["store_id"] for i in inventory if i["inventory_id"] == body["inventory_id"])
+        store = requests.get(f"{self.BASE_URL}/v1/stores/{store_id}", headers=self.HEADERS, timeout=60).json()
+        body["staff_id"] = store["manager"]["id"]
+
+        events = queue.Queue()
+        live = self.stream(store_id, events)
+
+        rented = requests.post(f"{self.BASE_URL}/v1/rentals", json=body, headers=self.HEADERS, timeout=60)
+        self.assertEqual(rented.status_code, 201, rented.text)
+        rental_id = rented.json()["id"]
+        returned = requests.post(f"{self.BASE_URL}/v1/rentals/{rental_id}/return", headers=self.HEADERS, timeout=60)
+        self.assertEqual(returned.status_code, 204)
+
+        created = self.next_event(events, "rental.created")
+        self.assertEqual(json.loads(created["data"])["data"]["rental_id"], rental_id)
+        self.next_event(events, "rental.returned")
+        live.close()
+        print("✅ Rental and return streamed")
+
+        replayed = queue.Queue()
+        resumed = self.stream(store_id, replayed, {"Last-Event-ID": created["id"]})
+        message = self.next_event(replayed, "rental.returned")
+        self.assertEqual(json.loads(message["data"])["data"]["rental_id"], rental_id)
+        resumed.close()
+        print("✅ Return replayed after Last-Event-ID")
+
+    def test_unknown_store(self):
+        """Test an unknown store is a 404"""
+        print("\n🔍 Testing: GET /v1/stores/99999/events")
+        response = requests.get(f"{self.BASE_URL}/v1/stores/99999/events", headers=self.HEADERS, timeout=60)
+        self.assertEqual(response.status_code, 404)
+        print("✅ Unknown store rejected")
+
+    def read_json(self, file_name):
+        base_path = os.path.dirname(__file__)
+        with open(os.path.join(base_path, file_name)) as f:
+            return json.load(f)
+
+if __name__ == "__main__":
+    print("\n===== STARTING Store Events Tests =====")
+    unittest.main()
+    print("\n===== FINISHED Store Events Tests =====\n")
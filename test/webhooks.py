@@ -0,0 +1,99 @@
+import hashlib
+import hmac
+import json
+import os
+import queue
+import threading
+import time
+import unittest
+from http.server import BaseHTTPRequestHandler, HTTPServer
+import requests
+
+# The API server POSTs deliveries here, set WEBHOOK_RECEIVER_HOST when it
+# runs in a container, e.g. host.docker.internal. The server needs
+# WEBHOOK_ALLOW_PRIVATE_URLS=true to deliver to this machine.
+RECEIVER_HOST = os.environ.get("WEBHOOK_RECEIVER_HOST", "localhost")
+received = queue.Queue()
+
+class Receiver(BaseHTTPRequestHandler):
+    def do_POST(self):
+        body = self.rfile.read(int(self.headers["Content-Length"]))
+        received.put((dict(self.headers), body))
+        self.send_response(204)
+        self.end_headers()
+
+    def log_message(self, *args):
+        pass
+
+class WebhookTests(unittest.TestCase):
+    BASE_URL = "http://localhost:8080"
+    HEADERS = {
+        "Content-Type": "application/json",
+        "X-API-Key": "secure-dev-key-123"
+    }
+    SECRET = "integration-test-secret"
+
+    @classmethod
+    def setUpClass(cls):
+        cls.server = HTTPServer(("0.0.0.0", 0), Receiver)
+        threading.Thread(target=cls.server.serve_forever, daemon=True).start()
+        url = f"http://{RECEIVER_HOST}:{cls.server.server_port}/hook"
+        response = requests.post(f"{cls.BASE_URL}/v1/webhooks", headers=cls.HEADERS, timeout=10,
+                                 json={"url": url, "events": ["payment.created"], "secret": cls.SECRET})
+        assert response.status_code == 201, response.text
+        cls.subscription = response.json()
+
+    @classmethod
+    def tearDownClass(cls):
+        requests.delete(f"{cls.BASE_URL}/v1/webhooks/{cls.subscription['id']}", headers=cls.HEADERS, timeout=10)
+        cls.server.shutdown()
+
+    def test_create_returns_secret_once(self):
+        """Test the secret is only returned when the subscription is created"""
+        self.assertEqual(self.subscription["secret"], self.SECRET)
+        response = requests.get(f"{self.BASE_URL}/v1/webhooks/{self.subscription['id']}", headers=self.HEADERS, timeout=10)
+        self.assertEqual(response.status_code, 200)
+        self.assertNotIn("secret", response.json())
+        print("\n✅ Secret only returned on create")
+
+    def test_unknown_event_rejected(self):
+        """Test subscribing to an unknown event is a 400"""
+        response = requests.post(f"{self.BASE_URL}/v1/webhooks", headers=self.HEADERS, timeout=10,
+                                 json={"url": "https://example.com/hook", "events": ["film.created"]})
+        self.assertEqual(response.status_code, 400, response.text)
+        print("\n✅ Unknown event rejected")
+
+    def test_payment_delivered_signed(self):
+        """Test POST /v1/payments is delivered as a signed payment.created event"""
+        print("\n⏳ Testing: payment.created delivery")
+        with open(os.path.join(os.path.dirname(__file__), "payloads/payment.json")) as f:
+            body = json.load(f)
+        response = requests.post(f"{self.BASE_URL}/v1/payments", json=body, headers=self.HEADERS, timeout=10)
+        self.assertEqual(response.status_code, 201, response.text)
+        payment_id = response.json()
+
+        deadline = time.time() + 30
+        while True:
+            headers, raw = received.get(timeout=max(deadline - time.time(), 0.1))
+            event = json.loads(raw)
+            if event["data"]["id"] == payment_id:
+                break
+
+        self.assertEqual(event["type"], "payment.created")
+        self.assertEqual(headers["X-Webhook-Event"], "payment.created")
+        timestamp, signature = [part.split("=", 1)[1] for part in headers["X-Webhook-Signature"].split(",")]
+        expected = hmac.new(self.SECRET.encode(), f"{timestamp}.".encode() + raw, hashlib.sha256).hexdigest()
+        self.assertTrue(hmac.compare_digest(signature, expected), "signature does not match")
+        print(f"✅ payment.created delivered for payment {payment_id}")
+
+        # the delivery log records it
+        time.sleep(1)
+        url = f"{self.BASE_URL}/v1/webhooks/{self.subscription['id']}/deliveries?status=delivered"
+        response = requests.get(url, headers=self.HEADERS, timeout=10)
+        self.assertEqual(response.status_code, 200, response.text)
+        self.assertGreater(int(response.headers["X-Total-Count"]), 0)
+        self.assertEqual(response.json()[0]["event_type"], "payment.created")
+        print("✅ Delivery log lists the delivery")
+
+if __name__ == "__main__":
+    unittest.main()